@@ -1,16 +1,18 @@
 package main
 
 import (
-	"blreynolds4/event-race-timer/cmd/placer/internal/places"
 	"blreynolds4/event-race-timer/internal/competitors"
 	"blreynolds4/event-race-timer/internal/config"
+	"blreynolds4/event-race-timer/internal/places"
 	"blreynolds4/event-race-timer/internal/raceevents"
-	"blreynolds4/event-race-timer/internal/redis_stream"
+	"blreynolds4/event-race-timer/internal/stream_backend"
+	"context"
 	"flag"
 	"log/slog"
 	"os"
-
-	redis "github.com/redis/go-redis/v9"
+	"os/signal"
+	"syscall"
+	"time"
 )
 
 func newLogger() *slog.Logger {
@@ -29,28 +31,26 @@ func main() {
 	var claRacename string
 	var claConfigPath string
 	var claCompetitorsPath string
+	var claStreamBackend string
+	var claStreamDir string
+	var claGroup string
+	var claConsumer string
+	var claClaimIdle time.Duration
 
 	flag.StringVar(&claDbAddress, "dbAddress", "localhost:6379", "The host and port ie localhost:6379")
 	flag.IntVar(&claDbNumber, "dbNumber", 0, "The database to use, defaults to 0")
 	flag.StringVar(&claRacename, "raceName", "race", "The name of the race being timed (no spaces)")
 	flag.StringVar(&claConfigPath, "config", "", "The path to the config file (json)")
 	flag.StringVar(&claCompetitorsPath, "competitors", "", "The path to the competitor lookup file (json)")
+	flag.StringVar(&claStreamBackend, "streamBackend", "", "Where race streams are kept: redis, file or memory (defaults to the config, then redis)")
+	flag.StringVar(&claStreamDir, "streamDir", "", "The directory for file streams")
+	flag.StringVar(&claGroup, "group", "", "Read the race as this consumer group and resume from the last acknowledged event (redis only)")
+	flag.StringVar(&claConsumer, "consumer", stream_backend.DefaultConsumerName(), "The consumer name within the group")
+	flag.DurationVar(&claClaimIdle, "claimIdle", 0, "Claim group events other consumers left unacknowledged this long, ie 1m (0 doesn't claim)")
 
 	// parse command line
 	flag.Parse()
 
-	// connect to redis
-	rdb := redis.NewClient(&redis.Options{
-		Addr:     claDbAddress,
-		Password: "", // no password set
-		DB:       0,  // use default DB
-	})
-
-	defer rdb.Close()
-
-	rawStream := redis_stream.NewRedisStream(rdb, claRacename)
-	eventStream := raceevents.NewEventStream(rawStream)
-
 	var raceConfig config.RaceConfig
 	err := config.LoadConfigData(claConfigPath, &raceConfig)
 	if err != nil {
@@ -58,6 +58,36 @@ func main() {
 		os.Exit(1)
 	}
 
+	// connect to the stream backend
+	backend, err := stream_backend.NewBackend(stream_backend.OptionsFromConfig(stream_backend.Options{
+		Backend:       claStreamBackend,
+		RedisAddress:  claDbAddress,
+		RedisDbNumber: claDbNumber,
+		StreamDir:     claStreamDir,
+	}, raceConfig))
+	if err != nil {
+		logger.Error("ERROR creating stream backend", "error", err)
+		os.Exit(1)
+	}
+	defer backend.Close()
+
+	var eventStream raceevents.EventStream
+	if claGroup != "" {
+		groupStream, err := backend.GroupStream(claRacename, claGroup, claConsumer, claClaimIdle)
+		if err != nil {
+			logger.Error("ERROR opening race stream group", "raceName", claRacename, "group", claGroup, "error", err)
+			os.Exit(1)
+		}
+		eventStream = raceevents.NewEventGroupStream(groupStream)
+	} else {
+		rawStream, err := backend.Stream(claRacename)
+		if err != nil {
+			logger.Error("ERROR opening race stream", "raceName", claRacename, "error", err)
+			os.Exit(1)
+		}
+		eventStream = raceevents.NewEventStream(rawStream)
+	}
+
 	athletes := make(competitors.CompetitorLookup)
 	err = competitors.LoadCompetitorLookup(claCompetitorsPath, athletes)
 	if err != nil {
@@ -65,9 +95,13 @@ func main() {
 		os.Exit(1)
 	}
 
-	placer := places.NewPlaceGenerator(eventStream, logger)
+	placer := places.NewPlaceGenerator(eventStream, raceConfig.ClockOffsets, logger)
+
+	// stop placing on ctrl-c or a kill, places for the events already read are sent first
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	err = placer.GeneratePlaces(athletes, raceConfig.SourceRanks)
+	err = placer.GeneratePlaces(ctx, athletes, raceConfig.RankingPolicy())
 	if err != nil {
 		logger.Error("ERROR generating places", "error", err)
 	}
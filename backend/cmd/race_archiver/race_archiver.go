@@ -1,15 +1,17 @@
 package main
 
 import (
-	"blreynolds4/event-race-timer/cmd/race_archiver/internal/racearchive/archiver"
-	"blreynolds4/event-race-timer/cmd/race_archiver/internal/racearchive/restorer"
+	"blreynolds4/event-race-timer/internal/racearchive/archiver"
+	"blreynolds4/event-race-timer/internal/racearchive/restorer"
 	"blreynolds4/event-race-timer/internal/raceevents"
-	"blreynolds4/event-race-timer/internal/redis_stream"
+	"blreynolds4/event-race-timer/internal/stream_backend"
+	"context"
 	"flag"
 	"log"
 	"os"
-
-	redis "github.com/redis/go-redis/v9"
+	"os/signal"
+	"syscall"
+	"time"
 )
 
 const (
@@ -28,28 +30,61 @@ func main() {
 	var claDbNumber int
 	var claRacename string
 	var claAction string
+	var claStreamBackend string
+	var claStreamDir string
+	var claGroup string
+	var claConsumer string
+	var claClaimIdle time.Duration
 
 	flag.StringVar(&claDbAddress, "dbAddress", "localhost:6379", "The host and port ie localhost:6379")
 	flag.IntVar(&claDbNumber, "dbNumber", 0, "The database to use, defaults to 0")
 	flag.StringVar(&claRacename, "raceName", "race", "The name of the race being timed (no spaces)")
 	flag.StringVar(&claAction, "action", saveAction, "The action to take:  save or restore")
+	flag.StringVar(&claStreamBackend, "streamBackend", stream_backend.RedisBackend, "Where race streams are kept: redis, file or memory")
+	flag.StringVar(&claStreamDir, "streamDir", stream_backend.DefaultStreamDir, "The directory for file streams")
+	flag.StringVar(&claGroup, "group", "", "Read the race as this consumer group and resume from the last acknowledged event (redis only)")
+	flag.StringVar(&claConsumer, "consumer", stream_backend.DefaultConsumerName(), "The consumer name within the group")
+	flag.DurationVar(&claClaimIdle, "claimIdle", 0, "Claim group events other consumers left unacknowledged this long, ie 1m (0 doesn't claim)")
 
 	// parse command line
 	flag.Parse()
 
-	// connect to redis
-	rdb := redis.NewClient(&redis.Options{
-		Addr:     claDbAddress,
-		Password: "", // no password set
-		DB:       0,  // use default DB
+	// connect to the stream backend
+	backend, err := stream_backend.NewBackend(stream_backend.Options{
+		Backend:       claStreamBackend,
+		RedisAddress:  claDbAddress,
+		RedisDbNumber: claDbNumber,
+		StreamDir:     claStreamDir,
 	})
-	defer rdb.Close()
+	if err != nil {
+		log.Fatalf("error creating stream backend: %s\n", err)
+	}
+	defer backend.Close()
+
+	// ctrl-c or a kill stops reading the race, a group archive still saves what it read
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
 	switch {
 
+	case claAction == saveAction && claGroup != "":
+		// add events the group hasn't archived yet to the archive file
+		groupStream, err := backend.GroupStream(claRacename, claGroup, claConsumer, claClaimIdle)
+		if err != nil {
+			log.Fatalf("error opening race stream %s group %s: %s\n", claRacename, claGroup, err)
+		}
+
+		archiver := archiver.NewGroupFileArchiver(claRacename + archiveExtension)
+		err = archiver.Archive(ctx, raceevents.NewEventGroupStream(groupStream))
+		if err != nil {
+			log.Fatalf("error archiving %s: %s\n", claRacename, err)
+		}
 	case claAction == saveAction:
 		// create the race event stream to archive
-		rawStream := redis_stream.NewRedisStream(rdb, claRacename)
+		rawStream, err := backend.Stream(claRacename)
+		if err != nil {
+			log.Fatalf("error opening race stream %s: %s\n", claRacename, err)
+		}
 		eventStream := raceevents.NewEventStream(rawStream)
 
 		// create the file for the events
@@ -61,12 +96,15 @@ func main() {
 
 		// archive to the file
 		archiver := archiver.NewJsonFileArchiver(f)
-		err = archiver.Archive(eventStream)
+		err = archiver.Archive(ctx, eventStream)
 		if err != nil {
 			log.Fatalf("error archiving %s: %s\n", claRacename, err)
 		}
 	case claAction == restoreAction:
-		rawStream := redis_stream.NewRedisStream(rdb, claRacename+restoredExtension)
+		rawStream, err := backend.Stream(claRacename + restoredExtension)
+		if err != nil {
+			log.Fatalf("error opening race stream %s: %s\n", claRacename+restoredExtension, err)
+		}
 		eventStream := raceevents.NewEventStream(rawStream)
 
 		f, err := os.Open(claRacename + archiveExtension)
@@ -76,7 +114,7 @@ func main() {
 		defer f.Close()
 
 		restorer := restorer.NewRestorer()
-		err = restorer.Restore(f, eventStream)
+		err = restorer.Restore(ctx, f, eventStream)
 		if err != nil {
 			log.Fatalf("error restoring %s: %s\n", claRacename, err)
 		}
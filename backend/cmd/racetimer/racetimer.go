@@ -0,0 +1,144 @@
+package main
+
+import (
+	"blreynolds4/event-race-timer/internal/cli"
+	"blreynolds4/event-race-timer/internal/config"
+	"blreynolds4/event-race-timer/internal/liveresults"
+	"blreynolds4/event-race-timer/internal/meets"
+	"blreynolds4/event-race-timer/internal/stream_backend"
+	"blreynolds4/event-race-timer/internal/supervisor"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	_ "github.com/lib/pq" // PostgreSQL driver
+)
+
+const (
+	runCommand = "run"
+
+	minRestartDelay = time.Second
+	maxRestartDelay = time.Minute
+)
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: racetimer run -config race_config.json [flags]")
+	fmt.Fprintln(os.Stderr, "  runs the web receiver, and a placer, result builder and scorer for each race, with the manual cli in the foreground")
+}
+
+func main() {
+	if len(os.Args) < 2 || os.Args[1] != runCommand {
+		usage()
+		os.Exit(2)
+	}
+
+	var claConfigPath string
+	var claRacename string
+	var claLogFile string
+	var claCli bool
+	var claDebug bool
+
+	runFlags := flag.NewFlagSet(runCommand, flag.ExitOnError)
+	runFlags.StringVar(&claConfigPath, "config", "", "The race config file (json) with the race, redis, postgres, sources and scoring")
+	runFlags.StringVar(&claRacename, "raceName", "", "The name of the race being timed, or a comma separated list of races timed together, overrides the config")
+	runFlags.StringVar(&claLogFile, "logFile", "racetimer.log", "Where every service logs, - for stderr")
+	runFlags.BoolVar(&claCli, "cli", true, "Run the manual cli in the foreground, without it racetimer runs until it's killed")
+	runFlags.BoolVar(&claDebug, "debug", false, "Log at debug level")
+	runFlags.Parse(os.Args[2:])
+
+	var raceConfig config.RaceConfig
+	err := config.LoadConfigData(claConfigPath, &raceConfig)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error loading config", claConfigPath, err)
+		os.Exit(1)
+	}
+	if claRacename != "" {
+		raceConfig.RaceNames = config.ParseRaceNames(claRacename)
+	}
+	raceNames := raceConfig.Races()
+	if len(raceNames) == 0 {
+		fmt.Fprintln(os.Stderr, "raceName is required in the config or on the command line")
+		os.Exit(1)
+	}
+
+	logOutput := io.Writer(os.Stderr)
+	if claLogFile != "-" {
+		f, err := os.OpenFile(claLogFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "error opening log file", claLogFile, err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		logOutput = f
+	}
+	level := slog.LevelInfo
+	if claDebug {
+		level = slog.LevelDebug
+	}
+	logger := slog.New(slog.NewTextHandler(logOutput, &slog.HandlerOptions{Level: level})).With("races", strings.Join(raceNames, ","))
+
+	// every service shares the backend so a memory backend works in one process
+	backend, err := stream_backend.NewBackend(stream_backend.OptionsFromConfig(stream_backend.Options{}, raceConfig))
+	if err != nil {
+		logger.Error("ERROR creating stream backend", "error", err)
+		os.Exit(1)
+	}
+	defer backend.Close()
+
+	// a kill stops the race, so does ctrl-c without the cli.  With the cli ctrl-c
+	// stops the command that's running and the race is stopped with quit.
+	stopSignals := []os.Signal{syscall.SIGTERM}
+	if !claCli {
+		stopSignals = append(stopSignals, os.Interrupt)
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), stopSignals...)
+	defer stop()
+
+	if claCli {
+		interrupts := make(chan os.Signal, 1)
+		signal.Notify(interrupts, os.Interrupt)
+		defer signal.Stop(interrupts)
+		go func() {
+			for range interrupts {
+				fmt.Println("\nctrl-c stops a running command, quit stops the race")
+			}
+		}()
+
+		go func() {
+			// the race stops cleanly if the cli can't start
+			err := cli.NewCliApp().Run(backend, raceNames, raceConfig.PgConnect)
+			if err != nil {
+				logger.Error("ERROR starting the cli", "error", err)
+			}
+			stop()
+		}()
+	}
+
+	// the web receiver's live results are reloaded as the result builders save them
+	raceReader, err := meets.NewRaceReader(raceConfig.PgConnect)
+	if err != nil {
+		logger.Error("ERROR creating race reader", "error", err)
+		os.Exit(1)
+	}
+	defer raceReader.Close()
+	live := liveresults.NewHub(liveresults.PostgresReaders(raceReader, raceConfig.PgConnect), logger)
+	defer live.Close()
+
+	s := supervisor.NewSupervisor(logger, minRestartDelay, maxRestartDelay)
+	s.Add("web", webService{config: raceConfig, backend: backend, races: raceNames, live: live}.run)
+	s.Add("live-results", liveService{config: raceConfig, live: live}.run)
+	for _, raceName := range raceNames {
+		raceServices{config: raceConfig, backend: backend, race: raceName}.addServices(s)
+	}
+
+	logger.Info("racetimer running")
+	s.Run(ctx)
+	logger.Info("racetimer stopped")
+}
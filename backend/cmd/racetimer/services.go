@@ -0,0 +1,315 @@
+package main
+
+import (
+	"blreynolds4/event-race-timer/internal/competitors"
+	"blreynolds4/event-race-timer/internal/config"
+	"blreynolds4/event-race-timer/internal/liveresults"
+	"blreynolds4/event-race-timer/internal/meets"
+	"blreynolds4/event-race-timer/internal/overall"
+	"blreynolds4/event-race-timer/internal/places"
+	"blreynolds4/event-race-timer/internal/raceevents"
+	"blreynolds4/event-race-timer/internal/raceweb"
+	"blreynolds4/event-race-timer/internal/resultbuilder"
+	"blreynolds4/event-race-timer/internal/stream_backend"
+	"blreynolds4/event-race-timer/internal/supervisor"
+	"blreynolds4/event-race-timer/internal/xc"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"time"
+)
+
+const (
+	defaultWebAddress = ":8080"
+
+	// consumer groups let a restarted placer or result builder carry on where it stopped
+	placerGroup        = "placer"
+	resultBuilderGroup = "result-builder"
+
+	scoreInterval = 2 * time.Second
+
+	// the least time between reloading a race's athletes for bibs the placer or builder doesn't know
+	athleteRefreshInterval = 10 * time.Second
+)
+
+// raceServices are the services that time a race, each service opens its own database
+// connections so a restart starts clean
+type raceServices struct {
+	config  config.RaceConfig
+	backend stream_backend.Backend
+	race    string
+}
+
+// eventStream reads the race as group when the backend supports consumer groups
+func (rs raceServices) eventStream(group string) (raceevents.EventStream, error) {
+	groupStream, err := rs.backend.GroupStream(rs.race, group, stream_backend.DefaultConsumerName(), 0)
+	if err == nil {
+		return raceevents.NewEventGroupStream(groupStream), nil
+	}
+	if !errors.Is(err, stream_backend.ErrGroupsNotSupported) {
+		return nil, err
+	}
+
+	rawStream, err := rs.backend.Stream(rs.race)
+	if err != nil {
+		return nil, err
+	}
+	return raceevents.NewEventStream(rawStream), nil
+}
+
+func (rs raceServices) loadAthletes() (meets.AthleteLookup, meets.WaveLookup, error) {
+	athletes := make(meets.AthleteLookup)
+	err := meets.LoadAthleteLookup(rs.config.PgConnect, rs.race, athletes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	waves := make(meets.WaveLookup)
+	err = meets.LoadWaveLookup(rs.config.PgConnect, rs.race, waves)
+	if err != nil {
+		return nil, nil, err
+	}
+	return athletes, waves, nil
+}
+
+// athleteRefresh reloads the race's athletes, refill puts them in the lookups the placer or
+// builder was given so bibs added to the race during the meet are timed
+func (rs raceServices) athleteRefresh(l *slog.Logger, refill func(meets.AthleteLookup, meets.WaveLookup)) *meets.AthleteRefresh {
+	return meets.NewAthleteRefresh(func() error {
+		athletes, waves, err := rs.loadAthletes()
+		if err != nil {
+			return err
+		}
+		refill(athletes, waves)
+		l.Info("reloaded race athletes", "race", rs.race, "bibs", len(athletes))
+		return nil
+	}, athleteRefreshInterval, l)
+}
+
+// addServices adds the placer, result builder and scorer for the race to s
+func (rs raceServices) addServices(s *supervisor.Supervisor) {
+	s.Add("placer/"+rs.race, rs.runPlacer)
+	s.Add("result-builder/"+rs.race, rs.runResultBuilder)
+	if rs.config.Scoring.Overall || rs.config.Scoring.XC {
+		s.Add("scorer/"+rs.race, rs.runScorer)
+	}
+}
+
+// webService is the one web receiver for every race being timed, reads are sent to
+// the race in the meet their bib is in
+type webService struct {
+	config  config.RaceConfig
+	backend stream_backend.Backend
+	races   []string
+	live    *liveresults.Hub
+}
+
+func (ws webService) run(ctx context.Context, l *slog.Logger) error {
+	raceReader, err := meets.NewRaceReader(ws.config.PgConnect)
+	if err != nil {
+		return err
+	}
+	defer raceReader.Close()
+
+	race, err := raceReader.GetRaceByName(ws.races[0])
+	if err != nil {
+		return err
+	}
+	if race == nil {
+		return fmt.Errorf("race %s not found", ws.races[0])
+	}
+
+	meetReader, err := meets.NewMeetReader(ws.config.PgConnect)
+	if err != nil {
+		return err
+	}
+	defer meetReader.Close()
+
+	athleteReader, err := meets.NewAthleteReader(ws.config.PgConnect)
+	if err != nil {
+		return err
+	}
+	defer athleteReader.Close()
+
+	openStream := func(raceName string) (raceevents.EventStream, error) {
+		rawStream, err := ws.backend.Stream(raceName)
+		if err != nil {
+			return nil, err
+		}
+		return raceevents.NewEventStream(rawStream), nil
+	}
+	races, err := raceweb.NewMeetRaces(race.Meet(), ws.races, meetReader, athleteReader, openStream, ws.config.Sources.Filters, ws.config.RankingPolicy(), ws.config.ClockOffsets, l)
+	if err != nil {
+		return err
+	}
+
+	orphans, err := meets.NewOrphanReadStore(race.Meet(), ws.config.PgConnect)
+	if err != nil {
+		return err
+	}
+	defer orphans.Close()
+
+	address := ws.config.WebAddress
+	if address == "" {
+		address = defaultWebAddress
+	}
+
+	results := raceweb.NewRaceResults(meetReader, raceReader, ws.config.PgConnect)
+	defer results.Close()
+
+	admin, err := raceweb.OpenMeetAdmin(ws.config.PgConnect)
+	if err != nil {
+		return err
+	}
+	defer admin.Close()
+
+	app := raceweb.NewApplication(raceweb.Services{
+		Sources:   ws.config.Sources,
+		Races:     races,
+		Orphans:   orphans,
+		Streams:   races,
+		Meets:     meetReader,
+		Admin:     admin,
+		Results:   results,
+		Conflicts: results,
+		Live:      ws.live,
+	}, l)
+	return app.Run(ctx, address)
+}
+
+// liveService tells the web receiver's live results when the result builders save results
+type liveService struct {
+	config config.RaceConfig
+	live   *liveresults.Hub
+}
+
+func (ls liveService) run(ctx context.Context, l *slog.Logger) error {
+	return meets.ListenForResults(ctx, ls.config.PgConnect, ls.live.Changed)
+}
+
+func (rs raceServices) runPlacer(ctx context.Context, l *slog.Logger) error {
+	athletes, _, err := rs.loadAthletes()
+	if err != nil {
+		return err
+	}
+
+	eventStream, err := rs.eventStream(placerGroup)
+	if err != nil {
+		return err
+	}
+
+	lookup := competitorLookup(athletes)
+	refresh := rs.athleteRefresh(l, func(athletes meets.AthleteLookup, _ meets.WaveLookup) {
+		clear(lookup)
+		maps.Copy(lookup, competitorLookup(athletes))
+	})
+	return places.NewRefreshingPlaceGenerator(eventStream, rs.config.ClockOffsets, refresh, l).GeneratePlaces(ctx, lookup, rs.config.RankingPolicy())
+}
+
+func (rs raceServices) runResultBuilder(ctx context.Context, l *slog.Logger) error {
+	athletes, waves, err := rs.loadAthletes()
+	if err != nil {
+		return err
+	}
+
+	raceReader, err := meets.NewRaceReader(rs.config.PgConnect)
+	if err != nil {
+		return err
+	}
+	defer raceReader.Close()
+
+	race, err := raceReader.GetRaceByName(rs.race)
+	if err != nil {
+		return err
+	}
+
+	resultsWriter, err := meets.NewRaceResultWriter(race, rs.config.PgConnect)
+	if err != nil {
+		return err
+	}
+	defer resultsWriter.Close()
+
+	checkpoints, err := meets.NewCheckpointStore(race, rs.config.PgConnect)
+	if err != nil {
+		return err
+	}
+	defer checkpoints.Close()
+
+	eventStream, err := rs.eventStream(resultBuilderGroup)
+	if err != nil {
+		return err
+	}
+
+	refresh := rs.athleteRefresh(l, func(reloaded meets.AthleteLookup, reloadedWaves meets.WaveLookup) {
+		clear(athletes)
+		maps.Copy(athletes, reloaded)
+		clear(waves)
+		maps.Copy(waves, reloadedWaves)
+	})
+	builder := resultbuilder.NewRefreshingRaceResultBuilder(l, checkpoints, waves, rs.config.ClockOffsets, refresh)
+	return builder.BuildRaceResults(ctx, eventStream, athletes, rs.config.RankingPolicy(), resultsWriter)
+}
+
+func (rs raceServices) runScorer(ctx context.Context, l *slog.Logger) error {
+	rankBy := rs.config.Scoring.RankBy
+	if rankBy == "" {
+		rankBy = meets.RankByGun
+	}
+
+	raceReader, err := meets.NewRaceReader(rs.config.PgConnect)
+	if err != nil {
+		return err
+	}
+	defer raceReader.Close()
+
+	race, err := raceReader.GetRaceByName(rs.race)
+	if err != nil {
+		return err
+	}
+
+	resultsReader, err := meets.NewRaceResultReader(race, rs.config.PgConnect)
+	if err != nil {
+		return err
+	}
+	defer resultsReader.Close()
+
+	t := time.NewTicker(scoreInterval)
+	defer t.Stop()
+	for {
+		if rs.config.Scoring.XC {
+			// the scores are logged, stdout is the cli's
+			xcScorer := xc.NewXCTeamScorer(race, l)
+			xcScorer.Quiet = true
+			err := xcScorer.ScoreResults(resultsReader)
+			if err != nil {
+				l.Error("ERROR scoring xc results", "error", err)
+			}
+		}
+
+		if rs.config.Scoring.Overall {
+			overallScorer := overall.NewOverallRaceResults(race, rankBy, rs.config.Course, l)
+			overallScorer.Quiet = true
+			err := overallScorer.ScoreResults(context.WithoutCancel(ctx), resultsReader)
+			if err != nil {
+				l.Error("ERROR scoring overall race results", "error", err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-t.C:
+		}
+	}
+}
+
+// competitorLookup makes the placer's competitors from the race's athletes
+func competitorLookup(athletes meets.AthleteLookup) competitors.CompetitorLookup {
+	lookup := make(competitors.CompetitorLookup, len(athletes))
+	for bib, athlete := range athletes {
+		lookup[bib] = competitors.NewCompetitor(athlete.Name(), athlete.Team, 0, athlete.Grade)
+	}
+	return lookup
+}
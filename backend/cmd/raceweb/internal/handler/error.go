@@ -1,5 +0,0 @@
-package handler
-
-type ErrorResponse struct {
-	Error string `json:"error"`
-}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"blreynolds4/event-race-timer/internal/competitors"
+	"blreynolds4/event-race-timer/internal/config"
+	"blreynolds4/event-race-timer/internal/meets"
+	"blreynolds4/event-race-timer/internal/racearchive"
+	"blreynolds4/event-race-timer/internal/replay"
+	"context"
+	"encoding/json"
+	"flag"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+func newLogger(debug bool) *slog.Logger {
+	level := slog.LevelWarn
+	if debug {
+		level = slog.LevelDebug
+	}
+	return slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level}))
+}
+
+func main() {
+	var claArchive string
+	var claCompetitorsPath string
+	var claWavesPath string
+	var claConfigPath string
+	var claOutput string
+	var claDebug bool
+
+	flag.StringVar(&claArchive, "archive", "", "The race archive file written by race_archiver (json)")
+	flag.StringVar(&claCompetitorsPath, "competitors", "", "The path to the competitor lookup file (json)")
+	flag.StringVar(&claWavesPath, "waves", "", "The path to a file mapping bibs to waves (json), everyone starts together without it")
+	flag.StringVar(&claConfigPath, "config", "", "The path to the config file (json) for source ranks and clock offsets")
+	flag.StringVar(&claOutput, "output", "", "Write the results to this file (json) instead of printing them")
+	flag.BoolVar(&claDebug, "debug", false, "Log every event the placer and result builder handle")
+
+	// parse command line
+	flag.Parse()
+
+	logger := newLogger(claDebug)
+
+	archiveData, err := os.ReadFile(claArchive)
+	if err != nil {
+		logger.Error("ERROR reading archive", "fileName", claArchive, "error", err)
+		os.Exit(1)
+	}
+	var archive racearchive.RaceArchive
+	err = json.Unmarshal(archiveData, &archive)
+	if err != nil {
+		logger.Error("ERROR decoding archive", "fileName", claArchive, "error", err)
+		os.Exit(1)
+	}
+
+	race := replay.Race{
+		Athletes: make(competitors.CompetitorLookup),
+		Waves:    make(meets.WaveLookup),
+	}
+	err = competitors.LoadCompetitorLookup(claCompetitorsPath, race.Athletes)
+	if err != nil {
+		logger.Error("ERROR loading competitors from", "fileName", claCompetitorsPath, "error", err)
+		os.Exit(1)
+	}
+
+	if claWavesPath != "" {
+		wavesData, err := os.ReadFile(claWavesPath)
+		if err == nil {
+			err = json.Unmarshal(wavesData, &race.Waves)
+		}
+		if err != nil {
+			logger.Error("ERROR loading waves from", "fileName", claWavesPath, "error", err)
+			os.Exit(1)
+		}
+	}
+
+	if claConfigPath != "" {
+		err = config.LoadConfigData(claConfigPath, &race.Config)
+		if err != nil {
+			logger.Error("ERROR loading config", "filename", claConfigPath, "error", err)
+			os.Exit(1)
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	results, err := replay.Replay(ctx, archive.RaceEvents, race, logger)
+	if err != nil {
+		logger.Error("ERROR replaying archive", "fileName", claArchive, "error", err)
+		os.Exit(1)
+	}
+
+	if claOutput == "" {
+		replay.WriteResults(os.Stdout, results)
+		return
+	}
+
+	data, err := json.MarshalIndent(results, "", "  ")
+	if err == nil {
+		err = os.WriteFile(claOutput, data, 0644)
+	}
+	if err != nil {
+		logger.Error("ERROR writing results", "fileName", claOutput, "error", err)
+		os.Exit(1)
+	}
+}
@@ -1,19 +1,21 @@
 package main
 
 import (
-	"blreynolds4/event-race-timer/cmd/result_builder/internal/resultbuilder"
 	"blreynolds4/event-race-timer/internal/config"
 	"blreynolds4/event-race-timer/internal/meets"
 	"blreynolds4/event-race-timer/internal/raceevents"
-	"blreynolds4/event-race-timer/internal/redis_stream"
+	"blreynolds4/event-race-timer/internal/resultbuilder"
+	"blreynolds4/event-race-timer/internal/stream_backend"
+	"context"
 	"flag"
 	"log/slog"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
+	"time"
 
 	_ "github.com/lib/pq" // PostgreSQL driver
-
-	redis "github.com/redis/go-redis/v9"
 )
 
 func newLogger() *slog.Logger {
@@ -33,7 +35,13 @@ func main() {
 	var claRacename string
 	var claConfigPath string
 	var claDebug bool
-	var claPlaceFile string
+	var claJSONFile string
+	var claStreamBackend string
+	var claStreamDir string
+	var claGroup string
+	var claConsumer string
+	var claClaimIdle time.Duration
+	var claRebuild bool
 
 	flag.StringVar(&claDbAddress, "redisAddress", "localhost:6379", "The host and port ie localhost:6379")
 	flag.IntVar(&claDbNumber, "redisDbNumber", 0, "The database to use, defaults to 0")
@@ -41,7 +49,13 @@ func main() {
 	flag.StringVar(&claRacename, "raceName", "", "The name of the race being timed")
 	flag.StringVar(&claConfigPath, "config", "", "The path to the config file (json)")
 	flag.BoolVar(&claDebug, "debug", false, "Flag to debug")
-	flag.StringVar(&claPlaceFile, "places", "", "The path to the place file")
+	flag.StringVar(&claJSONFile, "jsonFile", "", "Also keep the latest results in this json file")
+	flag.StringVar(&claStreamBackend, "streamBackend", "", "Where race streams are kept: redis, file or memory (defaults to the config, then redis)")
+	flag.StringVar(&claStreamDir, "streamDir", "", "The directory for file streams")
+	flag.StringVar(&claGroup, "group", "", "Read the race as this consumer group and resume from the last acknowledged event (redis only)")
+	flag.StringVar(&claConsumer, "consumer", stream_backend.DefaultConsumerName(), "The consumer name within the group")
+	flag.DurationVar(&claClaimIdle, "claimIdle", 0, "Claim group events other consumers left unacknowledged this long, ie 1m (0 doesn't claim)")
+	flag.BoolVar(&claRebuild, "rebuild", false, "Ignore the saved checkpoint and build results from the start of the race stream (not with -group)")
 
 	// parse command line
 	flag.Parse()
@@ -51,14 +65,11 @@ func main() {
 		os.Exit(1)
 	}
 
-	// connect to redis
-	rdb := redis.NewClient(&redis.Options{
-		Addr:     claDbAddress,
-		Password: "", // no password set
-		DB:       0,  // use default DB
-	})
-
-	defer rdb.Close()
+	// a group resumes from the events it acknowledged, it doesn't use the checkpoint
+	if claRebuild && claGroup != "" {
+		logger.Error("rebuild can't be used with a consumer group, rebuild without -group")
+		os.Exit(1)
+	}
 
 	raceReader, err := meets.NewRaceReader(claPostgresConnect)
 	if err != nil {
@@ -79,6 +90,21 @@ func main() {
 		os.Exit(1)
 	}
 
+	checkpoints, err := meets.NewCheckpointStore(race, claPostgresConnect)
+	if err != nil {
+		logger.Error("ERROR creating checkpoint store", "error", err)
+		os.Exit(1)
+	}
+	defer checkpoints.Close()
+
+	if claRebuild {
+		err = checkpoints.DeleteCheckpoint()
+		if err != nil {
+			logger.Error("ERROR removing checkpoint for rebuild", "race", claRacename, "error", err)
+			os.Exit(1)
+		}
+	}
+
 	athletes := make(meets.AthleteLookup)
 	err = meets.LoadAthleteLookup(claPostgresConnect, claRacename, athletes)
 	if err != nil {
@@ -88,6 +114,13 @@ func main() {
 
 	logger.Info("Loaded athletes", "count", len(athletes), "athletes", athletes)
 
+	waves := make(meets.WaveLookup)
+	err = meets.LoadWaveLookup(claPostgresConnect, claRacename, waves)
+	if err != nil {
+		logger.Error("error loading waves", "error", err)
+		os.Exit(1)
+	}
+
 	var raceConfig config.RaceConfig
 	err = config.LoadConfigData(claConfigPath, &raceConfig)
 	if err != nil {
@@ -95,12 +128,50 @@ func main() {
 		os.Exit(1)
 	}
 
-	rawStream := redis_stream.NewRedisStream(rdb, claRacename)
-	eventStream := raceevents.NewEventStream(rawStream)
+	// connect to the stream backend
+	backend, err := stream_backend.NewBackend(stream_backend.OptionsFromConfig(stream_backend.Options{
+		Backend:       claStreamBackend,
+		RedisAddress:  claDbAddress,
+		RedisDbNumber: claDbNumber,
+		StreamDir:     claStreamDir,
+	}, raceConfig))
+	if err != nil {
+		logger.Error("ERROR creating stream backend", "error", err)
+		os.Exit(1)
+	}
+	defer backend.Close()
+
+	var eventStream raceevents.EventStream
+	if claGroup != "" {
+		groupStream, err := backend.GroupStream(claRacename, claGroup, claConsumer, claClaimIdle)
+		if err != nil {
+			logger.Error("ERROR opening race stream group", "raceName", claRacename, "group", claGroup, "error", err)
+			os.Exit(1)
+		}
+		eventStream = raceevents.NewEventGroupStream(groupStream)
+	} else {
+		rawStream, err := backend.Stream(claRacename)
+		if err != nil {
+			logger.Error("ERROR opening race stream", "raceName", claRacename, "error", err)
+			os.Exit(1)
+		}
+		eventStream = raceevents.NewEventStream(rawStream)
+	}
+
+	var resultSink meets.RaceResultWriter = resultsWriter
+	if claJSONFile != "" {
+		resultSink = resultbuilder.NewMultiSink(resultsWriter, resultbuilder.NewJSONFileSink(claJSONFile))
+	}
+	defer resultSink.Close()
+
+	resultBuilder := resultbuilder.NewRaceResultBuilder(logger, checkpoints, waves, raceConfig.ClockOffsets)
 
-	resultBuilder := resultbuilder.NewRaceResultBuilder(logger)
+	// stop building on ctrl-c or a kill, the results and checkpoint for the events
+	// already read are saved and the sinks closed first
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	err = resultBuilder.BuildRaceResults(eventStream, athletes, raceConfig.SourceRanks, resultsWriter)
+	err = resultBuilder.BuildRaceResults(ctx, eventStream, athletes, raceConfig.RankingPolicy(), resultSink)
 	if err != nil {
 		logger.Error("ERROR generating results", "error", err)
 	}
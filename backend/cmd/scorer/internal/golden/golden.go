@@ -0,0 +1,280 @@
+package golden
+
+import (
+	"blreynolds4/event-race-timer/internal/competitors"
+	"blreynolds4/event-race-timer/internal/config"
+	"blreynolds4/event-race-timer/internal/eventgen"
+	"blreynolds4/event-race-timer/internal/meets"
+	"blreynolds4/event-race-timer/internal/overall"
+	"blreynolds4/event-race-timer/internal/raceevents"
+	"blreynolds4/event-race-timer/internal/replay"
+	"blreynolds4/event-race-timer/internal/xc"
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	eventsSuffix = "_events.txt"
+	actualSuffix = "_actual.txt"
+	teamSuffix   = "_team.txt"
+)
+
+// RaceFiles are the files for one race in a test event directory
+type RaceFiles struct {
+	Name string
+	// the race result file the event generator reads
+	Events string
+	// the official placings
+	Actual string
+	// the official team scores, empty when the race doesn't have them
+	Team string
+}
+
+// FindRaces returns every race in dir with an events file and official placings
+func FindRaces(dir string) ([]RaceFiles, error) {
+	eventFiles, err := filepath.Glob(filepath.Join(dir, "*"+eventsSuffix))
+	if err != nil {
+		return nil, err
+	}
+
+	races := make([]RaceFiles, 0, len(eventFiles))
+	for _, eventFile := range eventFiles {
+		name := strings.TrimSuffix(filepath.Base(eventFile), eventsSuffix)
+		race := RaceFiles{
+			Name:   name,
+			Events: eventFile,
+			Actual: filepath.Join(dir, name+actualSuffix),
+			Team:   filepath.Join(dir, name+teamSuffix),
+		}
+		if _, err := os.Stat(race.Actual); err != nil {
+			continue
+		}
+		if _, err := os.Stat(race.Team); err != nil {
+			race.Team = ""
+		}
+		races = append(races, race)
+	}
+	return races, nil
+}
+
+// ActualPlace is a line of the official placings
+type ActualPlace struct {
+	Place int
+	Name  string
+	Grade int
+	Team  string
+	Time  time.Duration
+}
+
+// ReadActualPlaces reads official placings in the overall scorer's layout:
+// place, name, grade, team and time in fixed width columns under a ===== line
+func ReadActualPlaces(r io.Reader) ([]ActualPlace, error) {
+	places := make([]ActualPlace, 0)
+	scanner := bufio.NewScanner(r)
+	inPlaces := false
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !inPlaces {
+			inPlaces = strings.HasPrefix(line, "=====")
+			continue
+		}
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if len(line) < 79 {
+			return nil, fmt.Errorf("short place line %q", line)
+		}
+
+		var p ActualPlace
+		var err error
+		p.Place, err = strconv.Atoi(strings.TrimSpace(line[0:5]))
+		if err != nil {
+			return nil, fmt.Errorf("bad place in %q: %w", line, err)
+		}
+		p.Name = strings.TrimSpace(line[6:38])
+		p.Grade, _ = strconv.Atoi(strings.TrimSpace(line[39:44]))
+		p.Team = strings.TrimSpace(line[45:77])
+		p.Time, err = time.ParseDuration(strings.TrimSpace(line[78:]))
+		if err != nil {
+			return nil, fmt.Errorf("bad time in %q: %w", line, err)
+		}
+		places = append(places, p)
+	}
+	return places, scanner.Err()
+}
+
+// TeamScore is a team's line of the official team scores
+type TeamScore struct {
+	Rank  int
+	Team  string
+	Total int
+	// the place points of each scoring runner, including the displacers
+	Scores []int
+}
+
+// rank, team name, total and then the runner scores
+var teamScoreLine = regexp.MustCompile(`^\s*(\d+)\s+(\S.*?)\s+(\d+)((?:\s+\d+)+)\s*$`)
+
+// ReadTeamScores reads official team scores, lines that aren't a team's score are skipped
+func ReadTeamScores(r io.Reader) ([]TeamScore, error) {
+	scores := make([]TeamScore, 0)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		match := teamScoreLine.FindStringSubmatch(scanner.Text())
+		if match == nil {
+			continue
+		}
+
+		ts := TeamScore{Team: match[2]}
+		ts.Rank, _ = strconv.Atoi(match[1])
+		ts.Total, _ = strconv.Atoi(match[3])
+		for _, field := range strings.Fields(match[4]) {
+			score, _ := strconv.Atoi(field)
+			ts.Scores = append(ts.Scores, score)
+		}
+		scores = append(scores, ts)
+	}
+	return scores, scanner.Err()
+}
+
+// Results are the scored results of a replayed race
+type Results struct {
+	Overall []overall.OverallResult
+	Teams   []*xc.XCTeamResult
+}
+
+// goldenConfig trusts manual places over the placer and the finish reader over the
+// extra reads the generator adds
+var goldenConfig = config.RaceConfig{
+	SourceRanks: map[string]int{
+		eventgen.PlaceSource:  1,
+		eventgen.ReaderSource: 2,
+		eventgen.FastSource:   3,
+		eventgen.SlowSource:   4,
+	},
+}
+
+// Run generates the events for a race result file like the event generator does, replays
+// them through the placer and result builder in memory and scores the results with the
+// overall and xc team scorers.  seed makes the generator's extra reads repeatable.
+// The overall scorer writes its html to the working directory.
+func Run(ctx context.Context, name, eventsPath string, seed int64, l *slog.Logger) (Results, error) {
+	f, err := os.Open(eventsPath)
+	if err != nil {
+		return Results{}, err
+	}
+	defer f.Close()
+
+	finishers, err := eventgen.ReadFinishers(f)
+	if err != nil {
+		return Results{}, err
+	}
+
+	startTime := time.Date(2026, 10, 3, 10, 0, 0, 0, time.UTC)
+	events := []raceevents.Event{
+		{EventTime: startTime, Data: raceevents.StartEvent{Source: eventgen.StartSource, StartTime: startTime}},
+	}
+	athletes := make(competitors.CompetitorLookup)
+	rnd := rand.New(rand.NewSource(seed))
+	for i, finisher := range finishers {
+		athletes[finisher.Bib] = competitors.NewCompetitor(finisher.FirstName+" "+finisher.LastName, finisher.Team, 0, finisher.Grade)
+		events = append(events, eventgen.FinishEvents(finisher, i+1, startTime, rnd)...)
+	}
+
+	raceResults, err := replay.Replay(ctx, events, replay.Race{Athletes: athletes, Config: goldenConfig}, l)
+	if err != nil {
+		return Results{}, err
+	}
+
+	reader := &meets.MockResultReader{Results: make([]*meets.RaceResult, len(raceResults))}
+	for i := range raceResults {
+		reader.Results[i] = &raceResults[i]
+	}
+
+	race := &meets.Race{Name: name}
+	overallScorer := overall.NewOverallRaceResults(race, meets.RankByGun, config.Course{}, l)
+	err = overallScorer.ScoreResults(ctx, reader)
+	if err != nil {
+		return Results{}, err
+	}
+
+	teamScorer := xc.NewXCTeamScorer(race, l)
+	err = teamScorer.ScoreResults(reader)
+	if err != nil {
+		return Results{}, err
+	}
+
+	return Results{Overall: overallScorer.Results, Teams: teamScorer.Results}, nil
+}
+
+// ComparePlaces describes every difference between the overall results and the official placings
+func ComparePlaces(actual []ActualPlace, results []overall.OverallResult) []string {
+	mismatches := make([]string, 0)
+	byPlace := make(map[int]overall.OverallResult, len(results))
+	for _, r := range results {
+		byPlace[r.Place] = r
+	}
+
+	for _, a := range actual {
+		r, found := byPlace[a.Place]
+		if !found {
+			mismatches = append(mismatches, fmt.Sprintf("place %d: expected %s of %s, no result", a.Place, a.Name, a.Team))
+			continue
+		}
+		delete(byPlace, a.Place)
+
+		if r.Athlete.Name() != a.Name || r.Athlete.Team != a.Team {
+			mismatches = append(mismatches, fmt.Sprintf("place %d: expected %s of %s, got %s of %s", a.Place, a.Name, a.Team, r.Athlete.Name(), r.Athlete.Team))
+		}
+		if r.Finishtime != a.Time {
+			mismatches = append(mismatches, fmt.Sprintf("place %d: expected time %s for %s, got %s", a.Place, a.Time, a.Name, r.Finishtime))
+		}
+	}
+
+	for _, r := range results {
+		if _, extra := byPlace[r.Place]; extra {
+			mismatches = append(mismatches, fmt.Sprintf("place %d: %s of %s isn't in the official placings", r.Place, r.Athlete.Name(), r.Athlete.Team))
+		}
+	}
+	return mismatches
+}
+
+// CompareTeams describes every difference between the xc team results and the official team scores
+func CompareTeams(actual []TeamScore, teams []*xc.XCTeamResult) []string {
+	mismatches := make([]string, 0)
+	if len(actual) != len(teams) {
+		mismatches = append(mismatches, fmt.Sprintf("expected %d scored teams, got %d", len(actual), len(teams)))
+	}
+
+	for i := 0; i < len(actual) && i < len(teams); i++ {
+		a, team := actual[i], teams[i]
+		if a.Team != team.Name {
+			mismatches = append(mismatches, fmt.Sprintf("rank %d: expected %s, got %s", a.Rank, a.Team, team.Name))
+			continue
+		}
+		if a.Total != int(team.TeamScore) {
+			mismatches = append(mismatches, fmt.Sprintf("rank %d %s: expected total %d, got %d", a.Rank, a.Team, a.Total, team.TeamScore))
+		}
+
+		scores := make([]int, 0, len(a.Scores))
+		for _, finisher := range team.Finishers {
+			if finisher.Score > 0 {
+				scores = append(scores, int(finisher.Score))
+			}
+		}
+		if fmt.Sprint(scores) != fmt.Sprint(a.Scores) {
+			mismatches = append(mismatches, fmt.Sprintf("rank %d %s: expected runner scores %v, got %v", a.Rank, a.Team, a.Scores, scores))
+		}
+	}
+	return mismatches
+}
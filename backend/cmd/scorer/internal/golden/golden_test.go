@@ -0,0 +1,111 @@
+package golden
+
+import (
+	"blreynolds4/event-race-timer/internal/meets"
+	"blreynolds4/event-race-timer/internal/overall"
+	"blreynolds4/event-race-timer/internal/xc"
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testEventsDir = "../../../../../test_events/d2"
+
+func TestGoldenRaces(t *testing.T) {
+	dir, err := filepath.Abs(testEventsDir)
+	assert.NoError(t, err)
+	races, err := FindRaces(dir)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, races)
+
+	// the overall scorer writes its html to the working directory
+	t.Chdir(t.TempDir())
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	for _, race := range races {
+		t.Run(race.Name, func(t *testing.T) {
+			results, err := Run(context.TODO(), race.Name, race.Events, 1, logger)
+			assert.NoError(t, err)
+
+			f, err := os.Open(race.Actual)
+			assert.NoError(t, err)
+			defer f.Close()
+			actual, err := ReadActualPlaces(f)
+			assert.NoError(t, err)
+			assert.NotEmpty(t, actual)
+			for _, mismatch := range ComparePlaces(actual, results.Overall) {
+				t.Error(mismatch)
+			}
+
+			if race.Team == "" {
+				return
+			}
+			tf, err := os.Open(race.Team)
+			assert.NoError(t, err)
+			defer tf.Close()
+			teams, err := ReadTeamScores(tf)
+			assert.NoError(t, err)
+			assert.NotEmpty(t, teams)
+			for _, mismatch := range CompareTeams(teams, results.Teams) {
+				t.Error(mismatch)
+			}
+		})
+	}
+}
+
+func TestComparePlacesReportsMismatches(t *testing.T) {
+	actual := []ActualPlace{
+		{Place: 1, Name: "David Reynolds", Team: "Merrimack Valley", Time: 16 * time.Minute},
+		{Place: 2, Name: "Andrew O'Brien", Team: "Oyster River", Time: 17 * time.Minute},
+		{Place: 3, Name: "Dawson Dubois", Team: "Coe-Brown", Time: 18 * time.Minute},
+	}
+	results := []overall.OverallResult{
+		{Place: 1, Athlete: meets.NewAthlete("David", "Reynolds", "Merrimack Valley", "", 12, "m"), Finishtime: 16 * time.Minute},
+		{Place: 2, Athlete: meets.NewAthlete("Dawson", "Dubois", "Coe-Brown", "", 11, "m"), Finishtime: 17 * time.Minute},
+		{Place: 4, Athlete: meets.NewAthlete("Andrew", "O'Brien", "Oyster River", "", 10, "m"), Finishtime: 17*time.Minute + time.Second},
+	}
+
+	mismatches := ComparePlaces(actual, results)
+	assert.Equal(t, 3, len(mismatches))
+	assert.True(t, strings.HasPrefix(mismatches[0], "place 2:"))
+	assert.True(t, strings.HasPrefix(mismatches[1], "place 3:"))
+	assert.True(t, strings.HasPrefix(mismatches[2], "place 4:"))
+}
+
+func TestCompareTeamsReportsMismatches(t *testing.T) {
+	actual, err := ReadTeamScores(strings.NewReader(`
+Rank Team                      Total    1    2    3    4    5   *6   *7   *8   *9
+   1 Coe-Brown                    15    1    2    3    4    5
+      Total Time:  1:25:48.50
+   2 St. Thomas Aquinas           40    6    7    8    9   10
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, []TeamScore{
+		{Rank: 1, Team: "Coe-Brown", Total: 15, Scores: []int{1, 2, 3, 4, 5}},
+		{Rank: 2, Team: "St. Thomas Aquinas", Total: 40, Scores: []int{6, 7, 8, 9, 10}},
+	}, actual)
+
+	finishers := func(scores ...int16) []*xc.XCResult {
+		result := make([]*xc.XCResult, len(scores))
+		for i, score := range scores {
+			result[i] = &xc.XCResult{Score: score}
+		}
+		return result
+	}
+	teams := []*xc.XCTeamResult{
+		{Name: "Coe-Brown", TeamScore: 15, Finishers: finishers(1, 2, 3, 4, 5)},
+		{Name: "St. Thomas Aquinas", TeamScore: 41, Finishers: finishers(6, 7, 8, 9, 11)},
+	}
+
+	mismatches := CompareTeams(actual, teams)
+	assert.Equal(t, 2, len(mismatches))
+	assert.Contains(t, mismatches[0], "expected total 40, got 41")
+	assert.Contains(t, mismatches[1], "expected runner scores")
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"blreynolds4/event-race-timer/internal/competitors"
+	"blreynolds4/event-race-timer/internal/raceevents"
+	"blreynolds4/event-race-timer/internal/stream_backend"
+	"blreynolds4/event-race-timer/internal/workouts"
+	"context"
+	"flag"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+func newLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(os.Stderr, nil))
+}
+
+func main() {
+	// create a logger
+	logger := newLogger()
+
+	var claDbAddress string
+	var claDbNumber int
+	var claWorkout string
+	var claCompetitorsPath string
+	var claStreamBackend string
+	var claStreamDir string
+	var claFollow bool
+
+	flag.StringVar(&claDbAddress, "dbAddress", "localhost:6379", "The host and port ie localhost:6379")
+	flag.IntVar(&claDbNumber, "dbNumber", 0, "The database to use, defaults to 0")
+	flag.StringVar(&claWorkout, "workout", "", "The workout session file (json)")
+	flag.StringVar(&claCompetitorsPath, "competitors", "", "The path to the competitor lookup file (json)")
+	flag.StringVar(&claStreamBackend, "streamBackend", stream_backend.RedisBackend, "Where streams are kept: redis, file or memory")
+	flag.StringVar(&claStreamDir, "streamDir", stream_backend.DefaultStreamDir, "The directory for file streams")
+	flag.BoolVar(&claFollow, "follow", false, "Keep printing the report as reps come in")
+
+	// parse command line
+	flag.Parse()
+
+	var session workouts.Session
+	err := workouts.LoadSession(claWorkout, &session)
+	if err != nil {
+		logger.Error("ERROR loading workout session", "fileName", claWorkout, "error", err)
+		os.Exit(1)
+	}
+
+	athletes := make(competitors.CompetitorLookup)
+	if claCompetitorsPath != "" {
+		err = competitors.LoadCompetitorLookup(claCompetitorsPath, athletes)
+		if err != nil {
+			logger.Error("ERROR loading competitors from", "fileName", claCompetitorsPath, "error", err)
+			os.Exit(1)
+		}
+	}
+
+	backend, err := stream_backend.NewBackend(stream_backend.Options{
+		Backend:       claStreamBackend,
+		RedisAddress:  claDbAddress,
+		RedisDbNumber: claDbNumber,
+		StreamDir:     claStreamDir,
+	})
+	if err != nil {
+		logger.Error("ERROR creating stream backend", "error", err)
+		os.Exit(1)
+	}
+	defer backend.Close()
+
+	rawStream, err := backend.Stream(session.Name)
+	if err != nil {
+		logger.Error("ERROR opening workout stream", "session", session.Name, "error", err)
+		os.Exit(1)
+	}
+	eventStream := raceevents.NewEventStream(rawStream)
+
+	// follow until ctrl-c or a kill
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	t := time.NewTicker(time.Second * 2)
+	defer t.Stop()
+	for {
+		results, err := workouts.ReadReps(ctx, eventStream, session)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			logger.Error("ERROR reading workout reps", "error", err)
+			os.Exit(1)
+		}
+		workouts.WriteReport(os.Stdout, session, athletes, results)
+
+		if !claFollow {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
@@ -0,0 +1,208 @@
+package cli
+
+import (
+	"blreynolds4/event-race-timer/internal/cli/command"
+	"blreynolds4/event-race-timer/internal/cli/repl"
+	"blreynolds4/event-race-timer/internal/meets"
+	"blreynolds4/event-race-timer/internal/raceevents"
+	"blreynolds4/event-race-timer/internal/stream_backend"
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+const sourceName = "manual"
+
+type CliApp struct {
+	replCommands map[string]command.Command
+	backend      stream_backend.Backend
+	pgConnect    string
+	prompt       repl.ReadEvalPrintLoop
+	orphans      meets.OrphanReadStore
+	raceReader   meets.RaceReader
+	// the saved results of the race the commands are using
+	results   meets.RaceResultReader
+	conflicts meets.PlaceConflictReader
+}
+
+func NewCliApp() *CliApp {
+	return &CliApp{
+		replCommands: make(map[string]command.Command),
+	}
+}
+
+// Run reads commands from stdin until quit or the end of the input.  Commands go to
+// the first race, the race command switches to another.  An error setting up the commands
+// is returned before any are read, the caller decides whether to stop.
+func (ca *CliApp) Run(backend stream_backend.Backend, raceNames []string, pgConnect string) error {
+	ca.backend = backend
+	ca.pgConnect = pgConnect
+
+	// create the command map
+	err := ca.createCommandMap(raceNames)
+	if err != nil {
+		return err
+	}
+	ca.prompt = repl.NewReadEvalPrintLoop("race-cli", os.Stdin, ca.commandRunner)
+	err = ca.useRace(raceNames[0])
+	if err != nil {
+		return fmt.Errorf("error opening race stream: %w", err)
+	}
+
+	ca.prompt.Run()
+	return nil
+}
+
+func (ca *CliApp) createCommandMap(raceNames []string) error {
+	athleteReader, err := meets.NewAthleteReader(ca.pgConnect)
+	if err != nil {
+		return fmt.Errorf("error creating athlete reader: %w", err)
+	}
+
+	raceReader, err := meets.NewRaceReader(ca.pgConnect)
+	if err != nil {
+		return fmt.Errorf("error creating race reader: %w", err)
+	}
+	ca.raceReader = raceReader
+
+	raceWriter, err := meets.NewRaceWriter(ca.pgConnect)
+	if err != nil {
+		return fmt.Errorf("error creating race writer: %w", err)
+	}
+
+	ca.replCommands["quit"] = command.NewQuitCommand()
+	ca.replCommands["q"] = ca.replCommands["quit"]
+	ca.replCommands["exit"] = ca.replCommands["quit"]
+	ca.replCommands["stop"] = ca.replCommands["quit"]
+
+	ca.replCommands["ping"] = command.NewPingCommand(ca.backend)
+
+	ca.replCommands["race"] = command.NewRaceCommand(raceNames, ca.useRace)
+
+	ca.replCommands["removeAthleteFromRace"] = command.NewDeleteAthleteFromRaceCommand(athleteReader, raceReader, raceWriter)
+	ca.replCommands["rar"] = ca.replCommands["removeAthleteFromRace"]
+
+	ca.replCommands["addAthleteToRace"] = command.NewAddAthleteToRaceCommand(athleteReader, raceReader, raceWriter)
+	ca.replCommands["aar"] = ca.replCommands["addAthleteToRace"]
+
+	// reads of unknown bibs are kept for the meet the races are in
+	race, err := raceReader.GetRaceByName(raceNames[0])
+	if err != nil || race == nil {
+		fmt.Println("race", raceNames[0], "not found, orphan reads can't be reassigned")
+		return nil
+	}
+	ca.orphans, err = meets.NewOrphanReadStore(race.Meet(), ca.pgConnect)
+	if err != nil {
+		return fmt.Errorf("error creating orphan read store: %w", err)
+	}
+	ca.replCommands["orphans"] = command.NewOrphanReadsCommand(ca.orphans)
+	return nil
+}
+
+// useRace points the commands that send race events at the race's stream
+func (ca *CliApp) useRace(raceName string) error {
+	rawStream, err := ca.backend.Stream(raceName)
+	if err != nil {
+		return err
+	}
+	eventStream := raceevents.NewEventStream(rawStream)
+
+	ca.replCommands["start"] = command.NewStartCommand(sourceName, eventStream)
+	ca.replCommands["s"] = ca.replCommands["start"]
+
+	ca.replCommands["place"] = command.NewPlaceCommand(sourceName, eventStream)
+	ca.replCommands["p"] = ca.replCommands["place"]
+
+	ca.replCommands["placeRange"] = command.NewPlaceRangeCommand(sourceName, eventStream)
+	ca.replCommands["pr"] = ca.replCommands["placeRange"]
+
+	ca.replCommands["list"] = command.NewListFinishCommand(eventStream)
+
+	ca.replCommands["bib"] = command.NewAddBibCommand(eventStream)
+
+	ca.replCommands["finish"] = command.NewFinishCommand(sourceName, eventStream)
+	ca.replCommands["f"] = ca.replCommands["finish"]
+
+	ca.replCommands["status"] = command.NewStatusCommand(sourceName, eventStream)
+
+	ca.replCommands["void"] = command.NewVoidCommand(sourceName, eventStream)
+
+	ca.replCommands["review"] = command.NewReviewCommand(eventStream)
+	ca.replCommands["accept"] = command.NewAcceptReadCommand(eventStream)
+
+	ca.replCommands["clock"] = command.NewClockOffsetCommand(eventStream)
+
+	if ca.orphans != nil {
+		ca.replCommands["reassign"] = command.NewReassignOrphanCommand(ca.orphans, eventStream)
+	}
+
+	err = ca.useRaceResults(raceName, eventStream)
+	if err != nil {
+		return err
+	}
+
+	ca.prompt.SetName(fmt.Sprintf("race-cli:%s", raceName))
+	return nil
+}
+
+// useRaceResults points the place conflict commands at the race's saved results, the race
+// needs to be in the database for them
+func (ca *CliApp) useRaceResults(raceName string, eventStream raceevents.EventStream) error {
+	if ca.results != nil {
+		ca.results.Close()
+		ca.conflicts.Close()
+		ca.results, ca.conflicts = nil, nil
+	}
+	for _, cmd := range []string{"conflicts", "renumber", "movePlace", "removePlace"} {
+		delete(ca.replCommands, cmd)
+	}
+
+	race, err := ca.raceReader.GetRaceByName(raceName)
+	if err != nil {
+		return err
+	}
+	if race == nil {
+		fmt.Println("race", raceName, "not found, place conflicts can't be fixed")
+		return nil
+	}
+	ca.results, err = meets.NewRaceResultReader(race, ca.pgConnect)
+	if err != nil {
+		return err
+	}
+	ca.conflicts, err = meets.NewPlaceConflictReader(race, ca.pgConnect)
+	if err != nil {
+		ca.results.Close()
+		ca.results = nil
+		return err
+	}
+
+	ca.replCommands["conflicts"] = command.NewConflictsCommand(ca.conflicts)
+	ca.replCommands["renumber"] = command.NewRenumberCommand(sourceName, ca.results, eventStream)
+	ca.replCommands["movePlace"] = command.NewMovePlaceCommand(sourceName, ca.results, eventStream)
+	ca.replCommands["removePlace"] = command.NewRemovePlaceCommand(sourceName, ca.results, eventStream)
+	return nil
+}
+
+func (ca *CliApp) commandRunner(args []string) bool {
+	if len(args) > 0 {
+		cmd := args[0]
+		cmdFunc, found := ca.replCommands[cmd]
+		if found {
+			// ctrl-c stops the command and comes back to the prompt
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			done, err := cmdFunc.Run(ctx, args[1:])
+			stop()
+			if err != nil {
+				fmt.Println("error during", cmd, ":", err.Error())
+			}
+			return done
+		} else {
+			// default to a finish event and send cmd as the bib
+			fmt.Println("unknown command", cmd, "skipping")
+		}
+	}
+
+	return false
+}
@@ -2,13 +2,14 @@ package command
 
 import (
 	"blreynolds4/event-race-timer/internal/meets"
+	"context"
 	"strconv"
 )
 
 func NewAddAthleteToRaceCommand(athleteReader meets.AthleteReader, raceReader meets.RaceReader, raceWriter meets.RaceWriter) Command {
 	return &noStateCommand{
-		CmdFunc: func(args []string) (bool, error) {
-			// command line is raceName daid bib
+		CmdFunc: func(ctx context.Context, args []string) (bool, error) {
+			// command line is raceName daid bib [wave]
 
 			race, err := raceReader.GetRaceByName(args[0])
 			if err != nil || race == nil {
@@ -34,6 +35,13 @@ func NewAddAthleteToRaceCommand(athleteReader meets.AthleteReader, raceReader me
 				return false, err
 			}
 
+			if len(args) > 3 {
+				err = raceWriter.SetAthleteWave(race, bibNumber, args[3])
+				if err != nil {
+					return false, err
+				}
+			}
+
 			return false, nil
 		},
 	}
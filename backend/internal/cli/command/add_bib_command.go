@@ -9,7 +9,7 @@ import (
 
 func NewAddBibCommand(eventStream raceevents.EventStream) Command {
 	return &noStateCommand{
-		CmdFunc: func(args []string) (bool, error) {
+		CmdFunc: func(ctx context.Context, args []string) (bool, error) {
 			//get the event with the event id and resend it with a bib attached
 			if len(args) < 2 {
 				return false, fmt.Errorf("add bib requires to arguments:  <finish event id> <bib>")
@@ -21,7 +21,7 @@ func NewAddBibCommand(eventStream raceevents.EventStream) Command {
 			}
 
 			msgBuffer := make([]raceevents.Event, 5)
-			countRead, err := eventStream.GetRaceEventRange(context.TODO(), args[0], args[0], msgBuffer)
+			countRead, err := eventStream.GetRaceEventRange(ctx, args[0], args[0], msgBuffer)
 			if err != nil {
 				return false, err
 			}
@@ -35,7 +35,7 @@ func NewAddBibCommand(eventStream raceevents.EventStream) Command {
 			}
 
 			// create updated event with new bib
-			eventStream.SendFinishEvent(context.TODO(), raceevents.FinishEvent{
+			eventStream.SendFinishEvent(ctx, raceevents.FinishEvent{
 				Source:     finishEvent.Source,
 				Bib:        bib,
 				FinishTime: finishEvent.FinishTime,
@@ -1,6 +1,7 @@
 package command
 
 import (
+	"blreynolds4/event-race-timer/internal/memory_stream"
 	"blreynolds4/event-race-timer/internal/raceevents"
 	"context"
 	"fmt"
@@ -15,7 +16,7 @@ func TestAddBibMissingArgs(t *testing.T) {
 
 	list := NewAddBibCommand(inputEvents)
 	// missing
-	q, err := list.Run([]string{})
+	q, err := list.Run(context.TODO(), []string{})
 	assert.Error(t, err)
 	assert.False(t, q)
 }
@@ -24,7 +25,7 @@ func TestAddBibMissingBadBib(t *testing.T) {
 	inputEvents := &raceevents.MockEventStream{}
 
 	list := NewAddBibCommand(inputEvents)
-	q, err := list.Run([]string{"x", "y"})
+	q, err := list.Run(context.TODO(), []string{"x", "y"})
 	assert.Error(t, err)
 	assert.False(t, q)
 }
@@ -39,7 +40,7 @@ func TestAddBibMissingBibRangeFails(t *testing.T) {
 	}
 
 	ab := NewAddBibCommand(inputEvents)
-	q, err := ab.Run([]string{"msgid", "1"})
+	q, err := ab.Run(context.TODO(), []string{"msgid", "1"})
 	assert.Equal(t, expErr, err)
 	assert.False(t, q)
 }
@@ -54,7 +55,7 @@ func TestAddBibMissingEvent(t *testing.T) {
 	}
 
 	ab := NewAddBibCommand(inputEvents)
-	q, err := ab.Run([]string{"msgid", "1"})
+	q, err := ab.Run(context.TODO(), []string{"msgid", "1"})
 	assert.Equal(t, expErr, err)
 	assert.False(t, q)
 }
@@ -69,7 +70,7 @@ func TestAddBibWrongEventType(t *testing.T) {
 	}
 
 	ab := NewAddBibCommand(inputEvents)
-	q, err := ab.Run([]string{"msgid", "1"})
+	q, err := ab.Run(context.TODO(), []string{"msgid", "1"})
 	assert.Equal(t, expErr, err)
 	assert.False(t, q)
 }
@@ -88,7 +89,7 @@ func TestAddBib(t *testing.T) {
 	}
 
 	ab := NewAddBibCommand(inputEvents)
-	q, err := ab.Run([]string{"msgid", "1"})
+	q, err := ab.Run(context.TODO(), []string{"msgid", "1"})
 	assert.NoError(t, err)
 	assert.False(t, q)
 	assert.Equal(t, 1, len(inputEvents.Events))
@@ -96,3 +97,38 @@ func TestAddBib(t *testing.T) {
 	fe := inputEvents.Events[0].Data.(raceevents.FinishEvent)
 	assert.Equal(t, 1, fe.Bib)
 }
+
+func TestAddBibPicksEventById(t *testing.T) {
+	raw := memory_stream.NewMemoryStream(memory_stream.NewStore(), t.Name())
+	eventStream := raceevents.NewEventStream(raw)
+
+	finishTime := time.Now().UTC()
+	for i := 0; i < 3; i++ {
+		err := eventStream.SendFinishEvent(context.TODO(), raceevents.FinishEvent{
+			Source:     t.Name(),
+			FinishTime: finishTime.Add(time.Duration(i) * time.Second),
+			Bib:        raceevents.NoBib,
+		})
+		assert.NoError(t, err)
+	}
+
+	sent := make([]raceevents.Event, 3)
+	count, err := eventStream.GetRaceEventRange(context.TODO(), eventStream.RangeQueryMin(), eventStream.RangeQueryMax(), sent)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	ab := NewAddBibCommand(eventStream)
+	q, err := ab.Run(context.TODO(), []string{sent[1].ID, "7"})
+	assert.NoError(t, err)
+	assert.False(t, q)
+
+	// the new finish is the last event on the stream and has the time of the second finish
+	added := make([]raceevents.Event, 5)
+	count, err = eventStream.GetRaceEventRange(context.TODO(), eventStream.ExclusiveQueryStart(sent[2].ID), eventStream.RangeQueryMax(), added)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	fe := added[0].Data.(raceevents.FinishEvent)
+	assert.Equal(t, 7, fe.Bib)
+	assert.True(t, finishTime.Add(time.Second).Equal(fe.FinishTime))
+}
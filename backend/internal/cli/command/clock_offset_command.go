@@ -0,0 +1,96 @@
+package command
+
+import (
+	"blreynolds4/event-race-timer/internal/raceevents"
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// NewClockOffsetCommand measures how far a source's clock is ahead of a reference source.
+// Bibs both sources saw finish are synced up, the offset is the median difference of their times.
+func NewClockOffsetCommand(eventStream raceevents.EventStream) Command {
+	return &noStateCommand{
+		CmdFunc: func(ctx context.Context, args []string) (bool, error) {
+			if len(args) < 2 {
+				return false, fmt.Errorf("clock requires two arguments: <reference source> <source>")
+			}
+
+			finishes := make([]raceevents.FinishEvent, 0)
+			buffer := make([]raceevents.Event, reviewBufferSize)
+			startId := eventStream.RangeQueryMin()
+			count, err := eventStream.GetRaceEventRange(ctx, startId, eventStream.RangeQueryMax(), buffer)
+			if err != nil {
+				return false, err
+			}
+
+			for count > 0 {
+				for _, e := range buffer[:count] {
+					if fe, ok := e.Data.(raceevents.FinishEvent); ok {
+						finishes = append(finishes, fe)
+					}
+				}
+
+				startId = eventStream.ExclusiveQueryStart(buffer[count-1].ID)
+				count, err = eventStream.GetRaceEventRange(ctx, startId, eventStream.RangeQueryMax(), buffer)
+				if err != nil {
+					return false, err
+				}
+			}
+
+			offset, synced := measureClockOffset(finishes, args[0], args[1])
+			if synced == 0 {
+				return false, fmt.Errorf("no bibs finished with both %s and %s", args[0], args[1])
+			}
+
+			fmt.Printf("%s is %dms ahead of %s, from %d bibs\n", args[1], offset.Milliseconds(), args[0], synced)
+			fmt.Printf("race config: \"ClockOffsets\": {\"%s\": %d}\n", args[1], offset.Milliseconds())
+			return false, nil
+		},
+	}
+}
+
+// measureClockOffset returns the median of source's finish time minus reference's for each bib
+// both sources have a finish for, and how many bibs that was.  Each source's first finish for a bib is used.
+func measureClockOffset(finishes []raceevents.FinishEvent, reference, source string) (time.Duration, int) {
+	referenceTimes := make(map[int]time.Time)
+	sourceTimes := make(map[int]time.Time)
+	for _, fe := range finishes {
+		if fe.Bib == raceevents.NoBib {
+			continue
+		}
+
+		var times map[int]time.Time
+		switch fe.Source {
+		case reference:
+			times = referenceTimes
+		case source:
+			times = sourceTimes
+		default:
+			continue
+		}
+		if _, found := times[fe.Bib]; !found {
+			times[fe.Bib] = fe.FinishTime
+		}
+	}
+
+	diffs := make([]time.Duration, 0, len(sourceTimes))
+	for bib, sourceTime := range sourceTimes {
+		if referenceTime, found := referenceTimes[bib]; found {
+			diffs = append(diffs, sourceTime.Sub(referenceTime))
+		}
+	}
+	if len(diffs) == 0 {
+		return 0, 0
+	}
+
+	sort.Slice(diffs, func(i, j int) bool {
+		return diffs[i] < diffs[j]
+	})
+	middle := len(diffs) / 2
+	if len(diffs)%2 == 0 {
+		return (diffs[middle-1] + diffs[middle]) / 2, len(diffs)
+	}
+	return diffs[middle], len(diffs)
+}
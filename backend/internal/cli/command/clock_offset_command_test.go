@@ -0,0 +1,69 @@
+package command
+
+import (
+	"blreynolds4/event-race-timer/internal/raceevents"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMeasureClockOffset(t *testing.T) {
+	now := time.Now().UTC()
+	finishes := []raceevents.FinishEvent{
+		{Source: "manual", Bib: 1, FinishTime: now},
+		{Source: "mat", Bib: 1, FinishTime: now.Add(400 * time.Millisecond)},
+		{Source: "mat", Bib: 1, FinishTime: now.Add(2 * time.Second)}, // second read
+		{Source: "manual", Bib: 2, FinishTime: now.Add(time.Minute)},
+		{Source: "mat", Bib: 2, FinishTime: now.Add(time.Minute + 300*time.Millisecond)},
+		{Source: "manual", Bib: 3, FinishTime: now.Add(2 * time.Minute)},
+		{Source: "mat", Bib: 3, FinishTime: now.Add(2*time.Minute + 5*time.Second)}, // slow hand time
+		{Source: "mat", Bib: 4, FinishTime: now.Add(3 * time.Minute)},
+		{Source: "chute", Bib: 1, FinishTime: now},
+		{Source: "manual", Bib: raceevents.NoBib, FinishTime: now},
+	}
+
+	offset, synced := measureClockOffset(finishes, "manual", "mat")
+	assert.Equal(t, 400*time.Millisecond, offset)
+	assert.Equal(t, 3, synced)
+
+	offset, synced = measureClockOffset(finishes[:5], "manual", "mat")
+	assert.Equal(t, 350*time.Millisecond, offset)
+	assert.Equal(t, 2, synced)
+
+	_, synced = measureClockOffset(finishes, "manual", "gun")
+	assert.Equal(t, 0, synced)
+}
+
+func TestClockOffsetCommand(t *testing.T) {
+	now := time.Now().UTC()
+	seeded := []raceevents.Event{
+		{ID: "1-0", Data: raceevents.FinishEvent{Source: "manual", Bib: 1, FinishTime: now}},
+		{ID: "2-0", Data: raceevents.FinishEvent{Source: "mat", Bib: 1, FinishTime: now.Add(-time.Second)}},
+		{ID: "3-0", Data: raceevents.FinishEvent{Source: "chute", Bib: 2, FinishTime: now}},
+	}
+	// every run reads the whole stream like redis does, the events aren't used up
+	events := &raceevents.MockEventStream{
+		Range: func(ctx context.Context, startId, endId string, msgs []raceevents.Event) (int, error) {
+			if startId != "-" {
+				return 0, nil
+			}
+			return copy(msgs, seeded), nil
+		},
+	}
+
+	clock := NewClockOffsetCommand(events)
+	_, err := clock.Run(context.TODO(), []string{"manual"})
+	assert.Error(t, err)
+
+	q, err := clock.Run(context.TODO(), []string{"manual", "mat"})
+	assert.NoError(t, err)
+	assert.False(t, q)
+	_, err = clock.Run(context.TODO(), []string{"mat", "manual"})
+	assert.NoError(t, err)
+
+	// chute's bib didn't finish on the mat, there's nothing to sync
+	_, err = clock.Run(context.TODO(), []string{"mat", "chute"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,15 @@
+package command
+
+import "context"
+
+type Command interface {
+	Run(ctx context.Context, args []string) (bool, error)
+}
+
+type noStateCommand struct {
+	CmdFunc func(ctx context.Context, args []string) (bool, error)
+}
+
+func (nsc *noStateCommand) Run(ctx context.Context, args []string) (bool, error) {
+	return nsc.CmdFunc(ctx, args)
+}
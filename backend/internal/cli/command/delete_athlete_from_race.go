@@ -2,12 +2,13 @@ package command
 
 import (
 	"blreynolds4/event-race-timer/internal/meets"
+	"context"
 	"strconv"
 )
 
 func NewDeleteAthleteFromRaceCommand(athleteReader meets.AthleteReader, raceReader meets.RaceReader, raceWriter meets.RaceWriter) Command {
 	return &noStateCommand{
-		CmdFunc: func(args []string) (bool, error) {
+		CmdFunc: func(ctx context.Context, args []string) (bool, error) {
 			// command line is raceName bib
 
 			// get bib number
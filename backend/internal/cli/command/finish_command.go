@@ -10,7 +10,7 @@ import (
 
 func NewFinishCommand(sourceName string, eventTarget raceevents.EventStream) Command {
 	return &noStateCommand{
-		CmdFunc: func(args []string) (bool, error) {
+		CmdFunc: func(ctx context.Context, args []string) (bool, error) {
 			var err error
 			bib := raceevents.NoBib
 			if len(args) > 0 && len(args[0]) > 0 {
@@ -30,7 +30,7 @@ func NewFinishCommand(sourceName string, eventTarget raceevents.EventStream) Com
 				}
 			}
 
-			return false, eventTarget.SendFinishEvent(context.TODO(), raceevents.FinishEvent{
+			return false, eventTarget.SendFinishEvent(ctx, raceevents.FinishEvent{
 				Source:     sourceName,
 				FinishTime: finish,
 				Bib:        bib,
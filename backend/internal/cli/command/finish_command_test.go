@@ -2,6 +2,7 @@ package command
 
 import (
 	"blreynolds4/event-race-timer/internal/raceevents"
+	"context"
 	"testing"
 	"time"
 
@@ -15,7 +16,7 @@ func TestFinishCommandNoBib(t *testing.T) {
 
 	eventSource := t.Name()
 	place := NewFinishCommand(eventSource, inputEvents)
-	q, err := place.Run([]string{})
+	q, err := place.Run(context.TODO(), []string{})
 	assert.NoError(t, err)
 	assert.False(t, q)
 	assert.Equal(t, 1, len(inputEvents.Events))
@@ -33,7 +34,7 @@ func TestFinishCommandWithBib(t *testing.T) {
 
 	eventSource := t.Name()
 	place := NewFinishCommand(eventSource, inputEvents)
-	q, err := place.Run([]string{"1"})
+	q, err := place.Run(context.TODO(), []string{"1"})
 	assert.NoError(t, err)
 	assert.False(t, q)
 	assert.Equal(t, 1, len(inputEvents.Events))
@@ -52,7 +53,7 @@ func TestFinishCommandWithBibAndTimeString(t *testing.T) {
 	eventSource := t.Name()
 	expTime := time.Now().UTC().Add(time.Minute)
 	place := NewFinishCommand(eventSource, inputEvents)
-	q, err := place.Run([]string{"1", expTime.Format(time.RFC3339Nano)})
+	q, err := place.Run(context.TODO(), []string{"1", expTime.Format(time.RFC3339Nano)})
 	assert.NoError(t, err)
 	assert.False(t, q)
 	assert.Equal(t, 1, len(inputEvents.Events))
@@ -71,7 +72,7 @@ func TestFinishCommandWithBibAndBadTimeString(t *testing.T) {
 
 	eventSource := t.Name()
 	place := NewFinishCommand(eventSource, inputEvents)
-	q, err := place.Run([]string{"1", "bad time"})
+	q, err := place.Run(context.TODO(), []string{"1", "bad time"})
 	assert.Error(t, err)
 	assert.False(t, q)
 }
@@ -82,7 +83,7 @@ func TestFinishCommandWithBadBib(t *testing.T) {
 	}
 
 	place := NewFinishCommand(t.Name(), inputEvents)
-	q, err := place.Run([]string{"x"})
+	q, err := place.Run(context.TODO(), []string{"x"})
 	assert.Error(t, err)
 	assert.False(t, q)
 	assert.Equal(t, 0, len(inputEvents.Events))
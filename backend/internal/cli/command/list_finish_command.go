@@ -9,15 +9,15 @@ import (
 
 func NewListFinishCommand(eventSource raceevents.EventStream) Command {
 	return &noStateCommand{
-		CmdFunc: func(args []string) (bool, error) {
+		CmdFunc: func(ctx context.Context, args []string) (bool, error) {
 			var err error
 			var startEvent raceevents.StartEvent
 			finishes := make([]raceevents.Event, 0, 100)
 			hasStart := false
 			// read all the events and print them out
 			var current raceevents.Event
-			readEvent, err := eventSource.GetRaceEvent(context.TODO(), time.Second, &current)
-			if err != nil {
+			readEvent, err := eventSource.GetRaceEvent(ctx, time.Second, &current)
+			if err != nil && ctx.Err() == nil {
 				return false, err
 			}
 
@@ -31,11 +31,16 @@ func NewListFinishCommand(eventSource raceevents.EventStream) Command {
 				default:
 				}
 
-				readEvent, err = eventSource.GetRaceEvent(context.TODO(), time.Second, &current)
-				if err != nil {
+				readEvent, err = eventSource.GetRaceEvent(ctx, time.Second, &current)
+				if err != nil && ctx.Err() == nil {
 					return false, err
 				}
 			}
+			if ctx.Err() != nil {
+				// interrupted, list the finishes read so far
+				fmt.Println("interrupted, listing the finishes read so far")
+				err = nil
+			}
 
 			// print the finish events in order with a duration base on the start event
 			// can't print finishes with out a start event
@@ -31,7 +31,7 @@ func TestListFinishes(t *testing.T) {
 	list := NewListFinishCommand(inputEvents)
 
 	// no seed duration arugment
-	q, err := list.Run([]string{})
+	q, err := list.Run(context.TODO(), []string{})
 	assert.NoError(t, err)
 	assert.False(t, q)
 }
@@ -47,7 +47,7 @@ func TestListFinishesFailFirstGet(t *testing.T) {
 
 	list := NewListFinishCommand(inputEvents)
 	// no seed duration arugment
-	q, err := list.Run([]string{})
+	q, err := list.Run(context.TODO(), []string{})
 	assert.Equal(t, expErr, err)
 	assert.False(t, q)
 }
@@ -77,7 +77,38 @@ func TestListFinishesFailSecondGet(t *testing.T) {
 
 	list := NewListFinishCommand(inputEvents)
 	// no seed duration arugment
-	q, err := list.Run([]string{})
+	q, err := list.Run(context.TODO(), []string{})
 	assert.Equal(t, expErr, err)
 	assert.False(t, q)
 }
+
+func TestListFinishesInterrupted(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	raceMessages := []raceevents.Event{
+		{
+			EventTime: time.Now().UTC(),
+			Data: raceevents.StartEvent{
+				Source:    t.Name(),
+				StartTime: time.Now().UTC(),
+			},
+		},
+	}
+	inputEvents := &raceevents.MockEventStream{
+		Get: func(ctx context.Context, timeout time.Duration, msg *raceevents.Event) (bool, error) {
+			if len(raceMessages) > 0 {
+				*msg = raceMessages[0]
+				raceMessages = raceMessages[1:]
+				return true, nil
+			}
+			// ctrl-c while waiting for more events
+			cancel()
+			return false, ctx.Err()
+		},
+		Events: make([]raceevents.Event, 0),
+	}
+
+	list := NewListFinishCommand(inputEvents)
+	q, err := list.Run(ctx, []string{})
+	assert.NoError(t, err)
+	assert.False(t, q)
+}
@@ -0,0 +1,79 @@
+package command
+
+import (
+	"blreynolds4/event-race-timer/internal/meets"
+	"blreynolds4/event-race-timer/internal/raceevents"
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// captureModeStart is the capture mode of reads from the start mat
+const captureModeStart = "start"
+
+// NewOrphanReadsCommand lists the reads of bibs that weren't in a race being timed
+func NewOrphanReadsCommand(orphans meets.OrphanReadStore) Command {
+	return &noStateCommand{
+		CmdFunc: func(ctx context.Context, args []string) (bool, error) {
+			reads, err := orphans.GetOrphanReads()
+			if err != nil {
+				return false, err
+			}
+
+			for _, or := range reads {
+				fmt.Printf("%d: bib %d %s %s %s %s\n", or.ID, or.Bib, or.ReadTime.Local().Format(time.TimeOnly), or.CaptureMode, or.TimingPoint, or.Host)
+			}
+			fmt.Println(len(reads), "orphan reads")
+			return false, nil
+		},
+	}
+}
+
+// NewReassignOrphanCommand sends an orphan read to the race's eventTarget as the read of
+// its bib, or another bib when the read's bib was wrong, and removes it from the orphans
+func NewReassignOrphanCommand(orphans meets.OrphanReadStore, eventTarget raceevents.EventStream) Command {
+	return &noStateCommand{
+		CmdFunc: func(ctx context.Context, args []string) (bool, error) {
+			if len(args) == 0 {
+				return false, fmt.Errorf("usage: reassign <orphan id> [bib]")
+			}
+			id, err := strconv.ParseInt(args[0], 10, 64)
+			if err != nil {
+				return false, err
+			}
+
+			or, err := orphans.GetOrphanRead(id)
+			if err != nil {
+				return false, err
+			}
+			if or == nil {
+				return false, fmt.Errorf("no orphan read %d", id)
+			}
+
+			bib := or.Bib
+			if len(args) > 1 {
+				bib, err = strconv.Atoi(args[1])
+				if err != nil {
+					return false, err
+				}
+			}
+
+			// send the read the way the web receiver would have
+			switch {
+			case or.CaptureMode == captureModeStart:
+				err = eventTarget.SendChipStartEvent(ctx, raceevents.ChipStartEvent{Source: or.Source, Bib: bib, StartTime: or.ReadTime})
+			case or.TimingPoint != "":
+				err = eventTarget.SendSplitEvent(ctx, raceevents.SplitEvent{Source: or.Source, Bib: bib, TimingPoint: or.TimingPoint, SplitTime: or.ReadTime})
+			default:
+				err = eventTarget.SendFinishEvent(ctx, raceevents.FinishEvent{Source: or.Source, Bib: bib, FinishTime: or.ReadTime})
+			}
+			if err != nil {
+				return false, err
+			}
+
+			fmt.Println("reassigned orphan read", id, "to bib", bib)
+			return false, orphans.DeleteOrphanRead(id)
+		},
+	}
+}
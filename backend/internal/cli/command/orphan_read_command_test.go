@@ -0,0 +1,53 @@
+package command
+
+import (
+	"blreynolds4/event-race-timer/internal/meets"
+	"blreynolds4/event-race-timer/internal/raceevents"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrphanReadsCommand(t *testing.T) {
+	orphans := &meets.MockOrphanReadStore{}
+	orphans.SaveOrphanRead(&meets.OrphanRead{Bib: 999, Source: "chip", Host: "reader1", CaptureMode: "finish", ReadTime: time.Now().UTC()})
+
+	list := NewOrphanReadsCommand(orphans)
+	q, err := list.Run(context.TODO(), []string{})
+	assert.NoError(t, err)
+	assert.False(t, q)
+}
+
+func TestReassignOrphanCommand(t *testing.T) {
+	readTime := time.Now().UTC()
+	orphans := &meets.MockOrphanReadStore{}
+	finish, _ := orphans.SaveOrphanRead(&meets.OrphanRead{Bib: 999, Source: "chip", Host: "reader1", CaptureMode: "finish", ReadTime: readTime})
+	split, _ := orphans.SaveOrphanRead(&meets.OrphanRead{Bib: 998, Source: "chip", Host: "mile1", CaptureMode: "finish", TimingPoint: "1 mile", ReadTime: readTime})
+	start, _ := orphans.SaveOrphanRead(&meets.OrphanRead{Bib: 997, Source: "chip", Host: "start1", CaptureMode: "start", ReadTime: readTime})
+	events := &raceevents.MockEventStream{Events: make([]raceevents.Event, 0)}
+
+	reassign := NewReassignOrphanCommand(orphans, events)
+	for _, args := range [][]string{
+		{"1"},
+		{"2", "198"},
+		{"3", "197"},
+	} {
+		q, err := reassign.Run(context.TODO(), args)
+		assert.NoError(t, err)
+		assert.False(t, q)
+	}
+
+	assert.Equal(t, 3, len(events.Events))
+	assert.Equal(t, raceevents.FinishEvent{Source: finish.Source, Bib: 999, FinishTime: readTime}, events.Events[0].Data)
+	assert.Equal(t, raceevents.SplitEvent{Source: split.Source, Bib: 198, TimingPoint: "1 mile", SplitTime: readTime}, events.Events[1].Data)
+	assert.Equal(t, raceevents.ChipStartEvent{Source: start.Source, Bib: 197, StartTime: readTime}, events.Events[2].Data)
+	assert.Empty(t, orphans.Reads)
+
+	// the read is gone once it's reassigned
+	q, err := reassign.Run(context.TODO(), []string{"1"})
+	assert.Error(t, err)
+	assert.False(t, q)
+	assert.Equal(t, 3, len(events.Events))
+}
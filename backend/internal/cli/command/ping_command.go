@@ -0,0 +1,21 @@
+package command
+
+import (
+	"context"
+	"fmt"
+)
+
+// Pinger checks the stream backend is alive
+type Pinger interface {
+	Ping(ctx context.Context) (string, error)
+}
+
+func NewPingCommand(p Pinger) Command {
+	return &noStateCommand{
+		CmdFunc: func(ctx context.Context, args []string) (bool, error) {
+			reply, err := p.Ping(ctx)
+			fmt.Println(reply)
+			return false, err
+		},
+	}
+}
@@ -0,0 +1,40 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockPinger struct {
+	reply string
+	err   error
+	calls int
+}
+
+func (mp *mockPinger) Ping(ctx context.Context) (string, error) {
+	mp.calls++
+	return mp.reply, mp.err
+}
+
+func TestPingCommand(t *testing.T) {
+	pinger := &mockPinger{reply: "pong"}
+
+	ping := NewPingCommand(pinger)
+	q, err := ping.Run(context.TODO(), []string{})
+	assert.NoError(t, err)
+	assert.False(t, q)
+	assert.Equal(t, 1, pinger.calls)
+}
+
+func TestPingCommandFails(t *testing.T) {
+	expErr := fmt.Errorf("fail")
+	pinger := &mockPinger{err: expErr}
+
+	ping := NewPingCommand(pinger)
+	q, err := ping.Run(context.TODO(), []string{})
+	assert.Equal(t, expErr, err)
+	assert.False(t, q)
+}
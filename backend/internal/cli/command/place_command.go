@@ -9,7 +9,7 @@ import (
 
 func NewPlaceCommand(sourceName string, eventTarget raceevents.EventStream) Command {
 	return &noStateCommand{
-		CmdFunc: func(args []string) (bool, error) {
+		CmdFunc: func(ctx context.Context, args []string) (bool, error) {
 			var err error
 			bib, place := raceevents.NoBib, 0
 			if len(args) > 1 {
@@ -23,7 +23,7 @@ func NewPlaceCommand(sourceName string, eventTarget raceevents.EventStream) Comm
 					return false, err
 				}
 
-				return false, eventTarget.SendPlaceEvent(context.TODO(), raceevents.PlaceEvent{
+				return false, eventTarget.SendPlaceEvent(ctx, raceevents.PlaceEvent{
 					Source: sourceName,
 					Bib:    bib,
 					Place:  place,
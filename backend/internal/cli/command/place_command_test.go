@@ -2,6 +2,7 @@ package command
 
 import (
 	"blreynolds4/event-race-timer/internal/raceevents"
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -14,7 +15,7 @@ func TestPlaceCommandNoBib(t *testing.T) {
 
 	eventSource := t.Name()
 	place := NewPlaceCommand(eventSource, inputEvents)
-	q, err := place.Run([]string{"1", "1"})
+	q, err := place.Run(context.TODO(), []string{"1", "1"})
 	assert.NoError(t, err)
 	assert.False(t, q)
 	assert.Equal(t, 1, len(inputEvents.Events))
@@ -32,7 +33,7 @@ func TestPlaceCommandMissingArg(t *testing.T) {
 	}
 
 	place := NewPlaceCommand(t.Name(), inputEvents)
-	q, err := place.Run([]string{"1"})
+	q, err := place.Run(context.TODO(), []string{"1"})
 	assert.Error(t, err)
 	assert.False(t, q)
 }
@@ -43,7 +44,7 @@ func TestPlaceCommandBadBib(t *testing.T) {
 	}
 
 	place := NewPlaceCommand(t.Name(), inputEvents)
-	q, err := place.Run([]string{"x", "1"})
+	q, err := place.Run(context.TODO(), []string{"x", "1"})
 	assert.Error(t, err)
 	assert.False(t, q)
 }
@@ -54,7 +55,7 @@ func TestPlaceCommandBadPlace(t *testing.T) {
 	}
 
 	place := NewPlaceCommand(t.Name(), inputEvents)
-	q, err := place.Run([]string{"1", "x"})
+	q, err := place.Run(context.TODO(), []string{"1", "x"})
 	assert.Error(t, err)
 	assert.False(t, q)
 }
@@ -0,0 +1,119 @@
+package command
+
+import (
+	"blreynolds4/event-race-timer/internal/meets"
+	"blreynolds4/event-race-timer/internal/places"
+	"blreynolds4/event-race-timer/internal/raceevents"
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// NewConflictsCommand lists the duplicate places, gaps, finishes without a place and places
+// without a finish in the race's saved results
+func NewConflictsCommand(conflictReader meets.PlaceConflictReader) Command {
+	return &noStateCommand{
+		CmdFunc: func(ctx context.Context, args []string) (bool, error) {
+			conflicts, err := conflictReader.GetPlaceConflicts()
+			if err != nil {
+				return false, err
+			}
+
+			fmt.Printf("%10s %6s %6s %-30s %12s %10s\n", "Conflict", "Place", "Bib", "Name", "Time", "Source")
+			for _, pc := range conflicts {
+				if len(pc.Results) == 0 {
+					fmt.Printf("%10s %6d\n", pc.Kind, pc.Place)
+				}
+				for _, rr := range pc.Results {
+					name := rr.Athlete.FirstName + " " + rr.Athlete.LastName
+					fmt.Printf("%10s %6d %6d %-30s %12s %10s\n", pc.Kind, pc.Place, rr.Bib, name, rr.GunTime.Round(time.Millisecond), rr.PlaceSource)
+				}
+			}
+			fmt.Println(len(conflicts), "place conflicts")
+			return false, nil
+		},
+	}
+}
+
+// NewRenumberCommand places the runners with a place 1 to n so every place is used once,
+// runners tied for a place keep their finish time order
+func NewRenumberCommand(source string, resultReader meets.RaceResultReader, eventTarget raceevents.EventStream) Command {
+	return &noStateCommand{
+		CmdFunc: func(ctx context.Context, args []string) (bool, error) {
+			results, err := resultReader.GetRaceResults()
+			if err != nil {
+				return false, err
+			}
+			return false, sendCorrections(ctx, eventTarget, places.Renumber(results, source))
+		},
+	}
+}
+
+// NewMovePlaceCommand gives a bib a place and moves the runners in between up or down one
+func NewMovePlaceCommand(source string, resultReader meets.RaceResultReader, eventTarget raceevents.EventStream) Command {
+	return &noStateCommand{
+		CmdFunc: func(ctx context.Context, args []string) (bool, error) {
+			if len(args) < 2 {
+				return false, fmt.Errorf("usage: movePlace <bib> <place>")
+			}
+			bib, err := strconv.Atoi(args[0])
+			if err != nil {
+				return false, err
+			}
+			place, err := strconv.Atoi(args[1])
+			if err != nil {
+				return false, err
+			}
+
+			results, err := resultReader.GetRaceResults()
+			if err != nil {
+				return false, err
+			}
+			corrections, err := places.MovePlace(results, bib, place, source)
+			if err != nil {
+				return false, err
+			}
+			return false, sendCorrections(ctx, eventTarget, corrections)
+		},
+	}
+}
+
+// NewRemovePlaceCommand takes a bib's place away and moves the runners behind it up one
+func NewRemovePlaceCommand(source string, resultReader meets.RaceResultReader, eventTarget raceevents.EventStream) Command {
+	return &noStateCommand{
+		CmdFunc: func(ctx context.Context, args []string) (bool, error) {
+			if len(args) < 1 {
+				return false, fmt.Errorf("usage: removePlace <bib>")
+			}
+			bib, err := strconv.Atoi(args[0])
+			if err != nil {
+				return false, err
+			}
+
+			results, err := resultReader.GetRaceResults()
+			if err != nil {
+				return false, err
+			}
+			corrections, err := places.RemovePlace(results, bib, source)
+			if err != nil {
+				return false, err
+			}
+			return false, sendCorrections(ctx, eventTarget, corrections)
+		},
+	}
+}
+
+// sendCorrections sends the corrections and prints them
+func sendCorrections(ctx context.Context, eventTarget raceevents.EventStream, corrections []places.Correction) error {
+	err := places.SendCorrections(ctx, eventTarget, corrections)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range corrections {
+		fmt.Printf("bib %d place %d -> %d (%s)\n", c.Bib, c.OldPlace, c.Place, c.Source)
+	}
+	fmt.Println(len(corrections), "places corrected")
+	return nil
+}
@@ -0,0 +1,78 @@
+package command
+
+import (
+	"blreynolds4/event-race-timer/internal/meets"
+	"blreynolds4/event-race-timer/internal/raceevents"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func tiedResults() *meets.MockResultReader {
+	athlete := &meets.Athlete{FirstName: "Test", LastName: "Runner"}
+	return &meets.MockResultReader{Results: []*meets.RaceResult{
+		{Bib: 11, Athlete: athlete, Place: 1, PlaceSource: "default-placer", GunTime: 10 * time.Minute},
+		{Bib: 12, Athlete: athlete, Place: 1, PlaceSource: "manual", GunTime: 11 * time.Minute},
+		{Bib: 13, Athlete: athlete, Place: 3, PlaceSource: "default-placer", GunTime: 12 * time.Minute},
+	}}
+}
+
+func TestConflictsCommand(t *testing.T) {
+	results := tiedResults().Results
+	conflictReader := &meets.MockPlaceConflictReader{Conflicts: []meets.PlaceConflict{
+		{Kind: meets.DuplicatePlace, Place: 1, Results: results[:2]},
+		{Kind: meets.PlaceGap, Place: 2},
+	}}
+
+	conflicts := NewConflictsCommand(conflictReader)
+	q, err := conflicts.Run(context.TODO(), []string{})
+	assert.NoError(t, err)
+	assert.False(t, q)
+}
+
+func TestRenumberCommand(t *testing.T) {
+	events := &raceevents.MockEventStream{Events: make([]raceevents.Event, 0)}
+
+	renumber := NewRenumberCommand("manual", tiedResults(), events)
+	q, err := renumber.Run(context.TODO(), []string{})
+	assert.NoError(t, err)
+	assert.False(t, q)
+
+	// only the slower of the tied runners moves, into the gap at 2nd
+	assert.Equal(t, 1, len(events.Events))
+	assert.Equal(t, raceevents.PlaceEvent{Source: "manual", Bib: 12, Place: 2}, events.Events[0].Data)
+}
+
+func TestMovePlaceCommand(t *testing.T) {
+	events := &raceevents.MockEventStream{Events: make([]raceevents.Event, 0)}
+
+	movePlace := NewMovePlaceCommand("manual", tiedResults(), events)
+	q, err := movePlace.Run(context.TODO(), []string{"13", "1"})
+	assert.NoError(t, err)
+	assert.False(t, q)
+	assert.Equal(t, 3, len(events.Events))
+	assert.Equal(t, raceevents.PlaceEvent{Source: "manual", Bib: 13, Place: 1}, events.Events[0].Data)
+
+	for _, args := range [][]string{{}, {"13"}, {"x", "1"}, {"99", "1"}, {"13", "9"}} {
+		_, err = movePlace.Run(context.TODO(), args)
+		assert.Error(t, err)
+	}
+	assert.Equal(t, 3, len(events.Events))
+}
+
+func TestRemovePlaceCommand(t *testing.T) {
+	events := &raceevents.MockEventStream{Events: make([]raceevents.Event, 0)}
+
+	removePlace := NewRemovePlaceCommand("manual", tiedResults(), events)
+	q, err := removePlace.Run(context.TODO(), []string{"11"})
+	assert.NoError(t, err)
+	assert.False(t, q)
+	assert.Equal(t, 2, len(events.Events))
+	assert.Equal(t, raceevents.PlaceEvent{Source: "manual", Bib: 11, Place: 0}, events.Events[0].Data)
+	assert.Equal(t, raceevents.PlaceEvent{Source: "manual", Bib: 13, Place: 2}, events.Events[1].Data)
+
+	_, err = removePlace.Run(context.TODO(), []string{})
+	assert.Error(t, err)
+}
@@ -1,7 +1,7 @@
 package command
 
 import (
-	"blreynolds4/event-race-timer/cmd/cli/internal/repl"
+	"blreynolds4/event-race-timer/internal/cli/repl"
 	"blreynolds4/event-race-timer/internal/raceevents"
 	"context"
 	"fmt"
@@ -25,7 +25,7 @@ func (pr *placeRangeCommand) String() string {
 	return "PlaceRangeCommand"
 }
 
-func (pr *placeRangeCommand) Run(args []string) (bool, error) {
+func (pr *placeRangeCommand) Run(ctx context.Context, args []string) (bool, error) {
 	// create a repl with place range command and quit command
 	var err error
 	nextPlace := 1
@@ -43,8 +43,13 @@ func (pr *placeRangeCommand) Run(args []string) (bool, error) {
 			if args[0] == "q" || args[0] == "quit" {
 				return true
 			}
+			if ctx.Err() != nil {
+				// ctrl-c leaves place range mode
+				fmt.Println("interrupted, no place sent for", args[0])
+				return true
+			}
 
-			done, err := placeCmd.Run(args)
+			done, err := placeCmd.Run(ctx, args)
 			if err != nil {
 				fmt.Println("error in place command", err)
 			}
@@ -74,7 +79,7 @@ type placeRangePlaceCommand struct {
 	eventTarget raceevents.EventStream
 }
 
-func (prc *placeRangePlaceCommand) Run(args []string) (bool, error) {
+func (prc *placeRangePlaceCommand) Run(ctx context.Context, args []string) (bool, error) {
 	var err error
 	bib := raceevents.NoBib
 	if len(args) > 0 {
@@ -84,7 +89,7 @@ func (prc *placeRangePlaceCommand) Run(args []string) (bool, error) {
 		}
 
 		prc.nextPlace++
-		err := prc.eventTarget.SendPlaceEvent(context.TODO(), raceevents.PlaceEvent{
+		err := prc.eventTarget.SendPlaceEvent(ctx, raceevents.PlaceEvent{
 			Source: prc.source,
 			Bib:    bib,
 			Place:  prc.nextPlace,
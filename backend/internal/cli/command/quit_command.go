@@ -1,10 +1,13 @@
 package command
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+)
 
 func NewQuitCommand() Command {
 	return &noStateCommand{
-		CmdFunc: func(args []string) (bool, error) {
+		CmdFunc: func(ctx context.Context, args []string) (bool, error) {
 			fmt.Println("quitting...")
 			return true, nil
 		},
@@ -1,6 +1,7 @@
 package command
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -8,7 +9,7 @@ import (
 
 func TestQuitCommand(t *testing.T) {
 	quit := NewQuitCommand()
-	q, err := quit.Run([]string{})
+	q, err := quit.Run(context.TODO(), []string{})
 	assert.NoError(t, err)
 	assert.True(t, q)
 }
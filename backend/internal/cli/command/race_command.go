@@ -0,0 +1,27 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"slices"
+)
+
+// NewRaceCommand lists the races being timed, or with a race name sends the commands
+// that follow to that race
+func NewRaceCommand(races []string, useRace func(raceName string) error) Command {
+	return &noStateCommand{
+		CmdFunc: func(ctx context.Context, args []string) (bool, error) {
+			if len(args) == 0 {
+				for _, race := range races {
+					fmt.Println(race)
+				}
+				return false, nil
+			}
+
+			if !slices.Contains(races, args[0]) {
+				return false, fmt.Errorf("%s isn't being timed", args[0])
+			}
+			return false, useRace(args[0])
+		},
+	}
+}
@@ -0,0 +1,40 @@
+package command
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRaceCommand(t *testing.T) {
+	used := ""
+	race := NewRaceCommand([]string{"jv", "varsity"}, func(raceName string) error {
+		used = raceName
+		return nil
+	})
+
+	// no race lists them
+	q, err := race.Run(context.TODO(), []string{})
+	assert.NoError(t, err)
+	assert.False(t, q)
+	assert.Equal(t, "", used)
+
+	q, err = race.Run(context.TODO(), []string{"varsity"})
+	assert.NoError(t, err)
+	assert.False(t, q)
+	assert.Equal(t, "varsity", used)
+}
+
+func TestRaceCommandUnknownRace(t *testing.T) {
+	used := ""
+	race := NewRaceCommand([]string{"jv", "varsity"}, func(raceName string) error {
+		used = raceName
+		return nil
+	})
+
+	q, err := race.Run(context.TODO(), []string{"middle school"})
+	assert.Error(t, err)
+	assert.False(t, q)
+	assert.Equal(t, "", used)
+}
@@ -0,0 +1,79 @@
+package command
+
+import (
+	"blreynolds4/event-race-timer/internal/raceevents"
+	"context"
+	"fmt"
+	"time"
+)
+
+const reviewBufferSize = 100
+
+// NewReviewCommand lists the finish reads the read filter rejected
+func NewReviewCommand(eventStream raceevents.EventStream) Command {
+	return &noStateCommand{
+		CmdFunc: func(ctx context.Context, args []string) (bool, error) {
+			rejected := make([]raceevents.Event, 0)
+			buffer := make([]raceevents.Event, reviewBufferSize)
+			startId := eventStream.RangeQueryMin()
+			count, err := eventStream.GetRaceEventRange(ctx, startId, eventStream.RangeQueryMax(), buffer)
+			if err != nil {
+				return false, err
+			}
+
+			for count > 0 {
+				for _, e := range buffer[:count] {
+					if _, ok := e.Data.(raceevents.RejectedReadEvent); ok {
+						rejected = append(rejected, e)
+					}
+				}
+
+				startId = eventStream.ExclusiveQueryStart(buffer[count-1].ID)
+				count, err = eventStream.GetRaceEventRange(ctx, startId, eventStream.RangeQueryMax(), buffer)
+				if err != nil {
+					return false, err
+				}
+			}
+
+			fmt.Printf("%20s %6s %10s %35s %s\n", "Event ID", "Bib", "Source", "Read Time", "Reason")
+			for _, e := range rejected {
+				rre := e.Data.(raceevents.RejectedReadEvent)
+				fmt.Printf("%20s %6d %10s %35s %s\n", e.ID, rre.Bib, rre.Source, rre.ReadTime.Format(time.RFC3339Nano), rre.Reason)
+			}
+
+			return false, nil
+		},
+	}
+}
+
+// NewAcceptReadCommand sends a rejected read as the finish it really was
+func NewAcceptReadCommand(eventStream raceevents.EventStream) Command {
+	return &noStateCommand{
+		CmdFunc: func(ctx context.Context, args []string) (bool, error) {
+			if len(args) < 1 {
+				return false, fmt.Errorf("accept requires the rejected read's event id")
+			}
+
+			msgBuffer := make([]raceevents.Event, 5)
+			countRead, err := eventStream.GetRaceEventRange(ctx, args[0], args[0], msgBuffer)
+			if err != nil {
+				return false, err
+			}
+			if countRead != 1 {
+				return false, fmt.Errorf("event id did not return 1 event")
+			}
+
+			rejected, ok := msgBuffer[0].Data.(raceevents.RejectedReadEvent)
+			if !ok {
+				return false, fmt.Errorf("expected event id to be for a rejected read, skipping")
+			}
+
+			// the finish keeps the reader's source so source ranks still apply
+			return false, eventStream.SendFinishEvent(ctx, raceevents.FinishEvent{
+				Source:     rejected.Source,
+				Bib:        rejected.Bib,
+				FinishTime: rejected.ReadTime,
+			})
+		},
+	}
+}
@@ -0,0 +1,44 @@
+package command
+
+import (
+	"blreynolds4/event-race-timer/internal/raceevents"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReviewCommand(t *testing.T) {
+	events := &raceevents.MockEventStream{Events: []raceevents.Event{
+		{ID: "1-0", Data: raceevents.StartEvent{Source: t.Name(), StartTime: time.Now().UTC()}},
+		{ID: "2-0", Data: raceevents.RejectedReadEvent{Source: "chip", Bib: 1, ReadTime: time.Now().UTC(), Reason: "debounce"}},
+	}}
+
+	review := NewReviewCommand(events)
+	q, err := review.Run(context.TODO(), []string{})
+	assert.NoError(t, err)
+	assert.False(t, q)
+	assert.Equal(t, 0, len(events.Events))
+}
+
+func TestAcceptReadCommand(t *testing.T) {
+	readTime := time.Now().UTC()
+	events := &raceevents.MockEventStream{Events: []raceevents.Event{
+		{ID: "2-0", Data: raceevents.RejectedReadEvent{Source: "chip", Bib: 1, ReadTime: readTime, Reason: "debounce"}},
+	}}
+
+	accept := NewAcceptReadCommand(events)
+	q, err := accept.Run(context.TODO(), []string{})
+	assert.Error(t, err)
+	assert.False(t, q)
+
+	q, err = accept.Run(context.TODO(), []string{"2-0"})
+	assert.NoError(t, err)
+	assert.False(t, q)
+	assert.Equal(t, []raceevents.Event{{EventTime: readTime, Data: raceevents.FinishEvent{Source: "chip", Bib: 1, FinishTime: readTime}}}, events.Events)
+
+	// only rejected reads can be accepted
+	_, err = accept.Run(context.TODO(), []string{"3-0"})
+	assert.Error(t, err)
+}
@@ -6,9 +6,10 @@ import (
 	"time"
 )
 
+// NewStartCommand starts the race or one wave of it: start [seed duration] [wave]
 func NewStartCommand(sourceName string, eventTarget raceevents.EventStream) Command {
 	return &noStateCommand{
-		CmdFunc: func(args []string) (bool, error) {
+		CmdFunc: func(ctx context.Context, args []string) (bool, error) {
 			startTime := time.Now().UTC()
 			seedTime := "0s"
 			if len(args) > 0 {
@@ -18,10 +19,15 @@ func NewStartCommand(sourceName string, eventTarget raceevents.EventStream) Comm
 			if err != nil {
 				return false, err
 			}
+			wave := ""
+			if len(args) > 1 {
+				wave = args[1]
+			}
 
-			return false, eventTarget.SendStartEvent(context.TODO(), raceevents.StartEvent{
+			return false, eventTarget.SendStartEvent(ctx, raceevents.StartEvent{
 				Source:    sourceName,
 				StartTime: startTime.Add(-seedDuration),
+				Wave:      wave,
 			})
 		},
 	}
@@ -2,6 +2,7 @@ package command
 
 import (
 	"blreynolds4/event-race-timer/internal/raceevents"
+	"context"
 	"testing"
 	"time"
 
@@ -16,7 +17,7 @@ func TestStartCommandNoTime(t *testing.T) {
 	eventSource := t.Name()
 	start := NewStartCommand(eventSource, inputEvents)
 	// no seed duration arugment
-	q, err := start.Run([]string{})
+	q, err := start.Run(context.TODO(), []string{})
 	assert.NoError(t, err)
 	assert.False(t, q)
 	assert.Equal(t, 1, len(inputEvents.Events))
@@ -38,7 +39,7 @@ func TestStartCommandWithTime(t *testing.T) {
 	eventSource := t.Name()
 	start := NewStartCommand(eventSource, inputEvents)
 	// with duration argument
-	q, err := start.Run([]string{time.Minute.String()})
+	q, err := start.Run(context.TODO(), []string{time.Minute.String()})
 	assert.NoError(t, err)
 	assert.False(t, q)
 	assert.Equal(t, 1, len(inputEvents.Events))
@@ -56,8 +57,24 @@ func TestStartCommandWithBadTime(t *testing.T) {
 
 	start := NewStartCommand(t.Name(), inputEvents)
 	// with duration argument
-	q, err := start.Run([]string{"bad"})
+	q, err := start.Run(context.TODO(), []string{"bad"})
 	assert.Error(t, err)
 	assert.False(t, q)
 	assert.Equal(t, 0, len(inputEvents.Events))
 }
+
+func TestStartCommandWithWave(t *testing.T) {
+	inputEvents := &raceevents.MockEventStream{
+		Events: make([]raceevents.Event, 0),
+	}
+
+	start := NewStartCommand(t.Name(), inputEvents)
+	q, err := start.Run(context.TODO(), []string{"0s", "girls"})
+	assert.NoError(t, err)
+	assert.False(t, q)
+	assert.Equal(t, 1, len(inputEvents.Events))
+
+	se, ok := inputEvents.Events[0].Data.(raceevents.StartEvent)
+	assert.True(t, ok)
+	assert.Equal(t, "girls", se.Wave)
+}
@@ -0,0 +1,44 @@
+package command
+
+import (
+	"blreynolds4/event-race-timer/internal/raceevents"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// clearStatus puts a runner back in the results
+const clearStatus = "clear"
+
+// NewStatusCommand records a DNF, DNS or DQ for a bib: status <bib> <DNF|DNS|DQ|clear> [reason]
+func NewStatusCommand(sourceName string, eventTarget raceevents.EventStream) Command {
+	return &noStateCommand{
+		CmdFunc: func(ctx context.Context, args []string) (bool, error) {
+			if len(args) < 2 {
+				return false, fmt.Errorf("missing bib or status argument")
+			}
+
+			bib, err := strconv.Atoi(args[0])
+			if err != nil {
+				return false, err
+			}
+
+			status := strings.ToUpper(args[1])
+			if strings.EqualFold(args[1], clearStatus) {
+				status = ""
+			}
+			if !raceevents.IsValidStatus(status) {
+				return false, fmt.Errorf("status must be %s, %s, %s or %s", raceevents.StatusDNF, raceevents.StatusDNS, raceevents.StatusDQ, clearStatus)
+			}
+
+			return false, eventTarget.SendStatusEvent(ctx, raceevents.StatusEvent{
+				Source:   sourceName,
+				Bib:      bib,
+				Status:   status,
+				Reason:   strings.Join(args[2:], " "),
+				Official: sourceName,
+			})
+		},
+	}
+}
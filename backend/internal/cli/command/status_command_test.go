@@ -0,0 +1,51 @@
+package command
+
+import (
+	"blreynolds4/event-race-timer/internal/raceevents"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusCommand(t *testing.T) {
+	inputEvents := &raceevents.MockEventStream{
+		Events: make([]raceevents.Event, 0),
+	}
+
+	status := NewStatusCommand(t.Name(), inputEvents)
+	q, err := status.Run(context.TODO(), []string{"412", "dq", "cut", "the", "course"})
+	assert.NoError(t, err)
+	assert.False(t, q)
+	assert.Equal(t, 1, len(inputEvents.Events))
+
+	se, ok := inputEvents.Events[0].Data.(raceevents.StatusEvent)
+	assert.True(t, ok)
+	assert.Equal(t, raceevents.StatusEvent{
+		Source:   t.Name(),
+		Bib:      412,
+		Status:   raceevents.StatusDQ,
+		Reason:   "cut the course",
+		Official: t.Name(),
+	}, se)
+
+	q, err = status.Run(context.TODO(), []string{"412", "clear"})
+	assert.NoError(t, err)
+	assert.False(t, q)
+	se = inputEvents.Events[1].Data.(raceevents.StatusEvent)
+	assert.Equal(t, "", se.Status)
+}
+
+func TestStatusCommandBadArgs(t *testing.T) {
+	inputEvents := &raceevents.MockEventStream{
+		Events: make([]raceevents.Event, 0),
+	}
+
+	status := NewStatusCommand(t.Name(), inputEvents)
+	for _, args := range [][]string{{"412"}, {"x", "DNF"}, {"412", "LATE"}} {
+		q, err := status.Run(context.TODO(), args)
+		assert.Error(t, err, args)
+		assert.False(t, q)
+	}
+	assert.Equal(t, 0, len(inputEvents.Events))
+}
@@ -0,0 +1,25 @@
+package command
+
+import (
+	"blreynolds4/event-race-timer/internal/raceevents"
+	"context"
+	"fmt"
+	"strings"
+)
+
+// NewVoidCommand retracts an earlier event by its id, ie a walk by read: void <event id> [reason]
+func NewVoidCommand(sourceName string, eventTarget raceevents.EventStream) Command {
+	return &noStateCommand{
+		CmdFunc: func(ctx context.Context, args []string) (bool, error) {
+			if len(args) < 1 {
+				return false, fmt.Errorf("missing event id argument")
+			}
+
+			return false, eventTarget.SendVoidEvent(ctx, raceevents.VoidEvent{
+				Source:  sourceName,
+				EventID: args[0],
+				Reason:  strings.Join(args[1:], " "),
+			})
+		},
+	}
+}
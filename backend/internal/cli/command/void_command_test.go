@@ -0,0 +1,31 @@
+package command
+
+import (
+	"blreynolds4/event-race-timer/internal/raceevents"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVoidCommand(t *testing.T) {
+	inputEvents := &raceevents.MockEventStream{
+		Events: make([]raceevents.Event, 0),
+	}
+
+	void := NewVoidCommand(t.Name(), inputEvents)
+	q, err := void.Run(context.TODO(), []string{"1700000000000-0", "walk", "by"})
+	assert.NoError(t, err)
+	assert.False(t, q)
+	assert.Equal(t, 1, len(inputEvents.Events))
+	assert.Equal(t, raceevents.VoidEvent{
+		Source:  t.Name(),
+		EventID: "1700000000000-0",
+		Reason:  "walk by",
+	}, inputEvents.Events[0].Data)
+
+	q, err = void.Run(context.TODO(), []string{})
+	assert.Error(t, err)
+	assert.False(t, q)
+	assert.Equal(t, 1, len(inputEvents.Events))
+}
@@ -10,6 +10,7 @@ import (
 
 type ReadEvalPrintLoop interface {
 	GetName() string
+	SetName(name string)
 	Run()
 }
 
@@ -33,19 +34,26 @@ func (r *repl) GetName() string {
 	return r.name
 }
 
+func (r *repl) SetName(name string) {
+	r.name = name
+}
+
 func (r *repl) Run() {
 	scanner := bufio.NewScanner(r.input)
 	done := false
 	for !done {
 		// read a line of input into an array of strings
 		fmt.Printf("%s>", r.name)
-		if scanner.Scan() {
+		if !scanner.Scan() {
+			// the input is closed
 			fmt.Println()
-			line := scanner.Text()
-			// look up the first string as the command and pass the rest to the command if one is found.
-			cmdArgs := parseString(line)
-			done = r.cmdRun(cmdArgs)
+			return
 		}
+		fmt.Println()
+		line := scanner.Text()
+		// look up the first string as the command and pass the rest to the command if one is found.
+		cmdArgs := parseString(line)
+		done = r.cmdRun(cmdArgs)
 	}
 }
 
@@ -5,14 +5,86 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
+	"time"
 )
 
 type RaceConfig struct {
-	RaceName      string
+	RaceName string
+	// RaceNames are races timed together with the same readers, like overlapping JV and
+	// Varsity races, RaceName is timed alone when it's empty
+	RaceNames     []string
 	RedisAddress  string
 	RedisDbNumber int
 	PgConnect     string
 	SourceRanks   map[string]int
+	// Ranking ranks sources separately for finishes, places and starts
+	Ranking RankingPolicy
+	// StreamBackend is redis (default), file or memory
+	StreamBackend string
+	// StreamDir is where file streams are kept
+	StreamDir string
+	// Course is the race distance and split points, used for splits and pace
+	Course Course
+	// ClockOffsets corrects the time each source stamps on its events,
+	// results built with other offsets need a rebuild
+	ClockOffsets ClockOffsets
+	// Sources maps the readers posting to the web receiver to sources, used by racetimer
+	Sources SourceConfig
+	// WebAddress is where racetimer's web receiver listens, :8080 when it's empty
+	WebAddress string
+	// Scoring is the scores racetimer keeps up to date
+	Scoring Scoring
+}
+
+// Races returns the names of the races to time
+func (rc RaceConfig) Races() []string {
+	if len(rc.RaceNames) > 0 {
+		return rc.RaceNames
+	}
+	if strings.TrimSpace(rc.RaceName) == "" {
+		return nil
+	}
+	return []string{rc.RaceName}
+}
+
+// ParseRaceNames splits a comma separated list of race names from the command line
+func ParseRaceNames(names string) []string {
+	races := make([]string, 0)
+	for _, name := range strings.Split(names, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			races = append(races, name)
+		}
+	}
+	return races
+}
+
+// Scoring turns on the scorers, RankBy is gun (default) or net
+type Scoring struct {
+	Overall bool
+	XC      bool
+	RankBy  string
+}
+
+// ClockOffsets is source name to the milliseconds the source's clock is ahead of the reference clock
+type ClockOffsets map[string]int64
+
+// Adjust converts a time from source's clock to the reference clock
+func (co ClockOffsets) Adjust(source string, t time.Time) time.Time {
+	return t.Add(-time.Duration(co[source]) * time.Millisecond)
+}
+
+// Course is the race distance in miles and its timing points in the order runners pass them
+type Course struct {
+	Distance     float64
+	TimingPoints []TimingPoint
+}
+
+// TimingPoint is a named place on the course where splits are read, ie "1 mile" or "lap 2"
+type TimingPoint struct {
+	Name string
+	// Distance from the start in miles, used for the pace at the split
+	Distance float64
 }
 
 func LoadConfigData(configPath string, raceConfig *RaceConfig) error {
@@ -29,7 +101,12 @@ func LoadConfigData(configPath string, raceConfig *RaceConfig) error {
 		return err
 	}
 
-	return json.Unmarshal(fileContent, raceConfig)
+	err = json.Unmarshal(fileContent, raceConfig)
+	if err != nil {
+		return err
+	}
+
+	return raceConfig.Ranking.Validate()
 }
 
 func GetConfigData(rc RaceConfig) ([]byte, error) {
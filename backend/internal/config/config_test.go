@@ -0,0 +1,45 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRaces(t *testing.T) {
+	assert.Nil(t, RaceConfig{}.Races())
+	assert.Equal(t, []string{"Varsity"}, RaceConfig{RaceName: "Varsity"}.Races())
+	assert.Equal(t, []string{"JV", "Varsity"}, RaceConfig{RaceName: "Varsity", RaceNames: []string{"JV", "Varsity"}}.Races())
+}
+
+func TestParseRaceNames(t *testing.T) {
+	assert.Equal(t, []string{"Boys JV", "Boys Varsity"}, ParseRaceNames("Boys JV, Boys Varsity,"))
+	assert.Equal(t, []string{}, ParseRaceNames(" "))
+}
+
+func TestManualSource(t *testing.T) {
+	manual := ManualConfig{Tokens: map[string]string{"chute-secret": "chute tablet", "timer-secret": ""}}
+
+	source, found := manual.Source("chute-secret")
+	assert.True(t, found)
+	assert.Equal(t, "chute tablet", source)
+	source, found = manual.Source("timer-secret")
+	assert.True(t, found)
+	assert.Equal(t, DefaultManualSource, source)
+	_, found = manual.Source("guess")
+	assert.False(t, found)
+	_, found = ManualConfig{}.Source("")
+	assert.False(t, found)
+}
+
+func TestAdminUser(t *testing.T) {
+	admin := AdminConfig{Tokens: map[string]string{"meet-secret": "meet director"}}
+
+	user, found := admin.User("meet-secret")
+	assert.True(t, found)
+	assert.Equal(t, "meet director", user)
+	_, found = admin.User("guess")
+	assert.False(t, found)
+	_, found = AdminConfig{Tokens: map[string]string{"": "nobody"}}.User("")
+	assert.False(t, found)
+}
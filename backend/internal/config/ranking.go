@@ -0,0 +1,114 @@
+package config
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+)
+
+// RankedField is the part of a result a source's events are ranked for
+type RankedField string
+
+const (
+	FinishField RankedField = "finish"
+	PlaceField  RankedField = "place"
+	StartField  RankedField = "start"
+)
+
+// how events from a source without a rank for the field are handled
+const (
+	UnrankedLowest = "lowest" // used, but any ranked source replaces them
+	UnrankedLog    = "log"    // like lowest and each one is logged
+	UnrankedReject = "reject" // ignored
+)
+
+// RankingPolicy decides which source's value a result keeps when several sources send one.
+// Each field is source name to rank, 1 is the best.  A field without ranks uses SourceRanks.
+type RankingPolicy struct {
+	Finish map[string]int
+	Place  map[string]int
+	Start  map[string]int
+	// Unranked is lowest, log or reject, empty is lowest
+	Unranked string
+}
+
+// NewRankingPolicy ranks sources the same for every field
+func NewRankingPolicy(sourceRanks map[string]int) RankingPolicy {
+	return RankingPolicy{
+		Finish:   sourceRanks,
+		Place:    sourceRanks,
+		Start:    sourceRanks,
+		Unranked: UnrankedLowest,
+	}
+}
+
+// RankingPolicy returns the race's ranking with SourceRanks filling in fields it doesn't rank
+func (rc RaceConfig) RankingPolicy() RankingPolicy {
+	policy := rc.Ranking
+	if policy.Finish == nil {
+		policy.Finish = rc.SourceRanks
+	}
+	if policy.Place == nil {
+		policy.Place = rc.SourceRanks
+	}
+	if policy.Start == nil {
+		policy.Start = rc.SourceRanks
+	}
+	if policy.Unranked == "" {
+		policy.Unranked = UnrankedLowest
+	}
+	return policy
+}
+
+func (rp RankingPolicy) Validate() error {
+	switch rp.Unranked {
+	case "", UnrankedLowest, UnrankedLog, UnrankedReject:
+		return nil
+	}
+	return fmt.Errorf("unranked sources must be %s, %s or %s, not %q", UnrankedLowest, UnrankedLog, UnrankedReject, rp.Unranked)
+}
+
+func (rp RankingPolicy) ranks(field RankedField) map[string]int {
+	switch field {
+	case FinishField:
+		return rp.Finish
+	case PlaceField:
+		return rp.Place
+	case StartField:
+		return rp.Start
+	}
+	return nil
+}
+
+func (rp RankingPolicy) rank(field RankedField, source string) int {
+	if rank, found := rp.ranks(field)[source]; found && rank > 0 {
+		return rank
+	}
+	return math.MaxInt
+}
+
+// UnrankedHandling returns how an event from source is handled, or "" when the source is ranked for field
+func (rp RankingPolicy) UnrankedHandling(field RankedField, source string) string {
+	if rp.rank(field, source) != math.MaxInt {
+		return ""
+	}
+	if rp.Unranked == "" {
+		return UnrankedLowest
+	}
+	return rp.Unranked
+}
+
+// Compare is negative when source a ranks better than b for field, 0 when they rank
+// the same and positive when b is better.  No source ("") ranks below every source.
+func (rp RankingPolicy) Compare(field RankedField, a, b string) int {
+	if a == b {
+		return 0
+	}
+	if b == "" {
+		return -1
+	}
+	if a == "" {
+		return 1
+	}
+	return cmp.Compare(rp.rank(field, a), rp.rank(field, b))
+}
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRankingPolicyFallsBackToSourceRanks(t *testing.T) {
+	rc := RaceConfig{
+		SourceRanks: map[string]int{"chip": 1, "manual": 2},
+		Ranking:     RankingPolicy{Place: map[string]int{"placer": 1}},
+	}
+
+	policy := rc.RankingPolicy()
+	assert.Equal(t, rc.SourceRanks, policy.Finish)
+	assert.Equal(t, rc.SourceRanks, policy.Start)
+	assert.Equal(t, map[string]int{"placer": 1}, policy.Place)
+	assert.Equal(t, UnrankedLowest, policy.Unranked)
+}
+
+func TestRankingPolicyCompare(t *testing.T) {
+	policy := RankingPolicy{
+		Finish: map[string]int{"chip": 1, "manual": 2},
+		Start:  map[string]int{"manual": 1},
+	}
+
+	assert.Negative(t, policy.Compare(FinishField, "chip", "manual"))
+	assert.Positive(t, policy.Compare(FinishField, "manual", "chip"))
+	assert.Zero(t, policy.Compare(FinishField, "chip", "chip"))
+	// each field has its own order
+	assert.Negative(t, policy.Compare(StartField, "manual", "chip"))
+
+	// unranked sources are below ranked ones but above no source
+	assert.Positive(t, policy.Compare(FinishField, "other", "manual"))
+	assert.Zero(t, policy.Compare(FinishField, "other", "unknown"))
+	assert.Negative(t, policy.Compare(FinishField, "other", ""))
+	assert.Negative(t, policy.Compare(PlaceField, "chip", ""))
+}
+
+func TestRankingPolicyUnranked(t *testing.T) {
+	policy := RankingPolicy{Finish: map[string]int{"chip": 1}}
+	assert.Equal(t, "", policy.UnrankedHandling(FinishField, "chip"))
+	assert.Equal(t, UnrankedLowest, policy.UnrankedHandling(FinishField, "manual"))
+
+	policy.Unranked = UnrankedReject
+	assert.Equal(t, UnrankedReject, policy.UnrankedHandling(PlaceField, "chip"))
+
+	assert.NoError(t, policy.Validate())
+	policy.Unranked = "best"
+	assert.Error(t, policy.Validate())
+}
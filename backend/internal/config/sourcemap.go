@@ -1,6 +1,7 @@
 package config
 
 import (
+	"crypto/subtle"
 	"encoding/json"
 	"io/ioutil"
 )
@@ -8,6 +9,67 @@ import (
 type SourceConfig struct {
 	// map is hostname to source name
 	SourceMap map[string]string `json:"sources"`
+	// map is hostname to timing point name for readers out on the course,
+	// reads from hosts not in the map are finishes
+	TimingPoints map[string]string `json:"timingPoints"`
+	// map is source name to the filter for that source's finish reads
+	Filters map[string]ReadFilter `json:"filters"`
+	// Manual lets a tablet at the chute or a second timer send starts, finishes and places
+	Manual ManualConfig `json:"manual"`
+	// Admin lets the meet's organizers set up meets, races and entries
+	Admin AdminConfig `json:"admin"`
+}
+
+// DefaultManualSource is the source name of manual events, it's the cli's source name
+const DefaultManualSource = "manual"
+
+// ManualConfig is who can send manual events over http, the manual api isn't served without tokens
+type ManualConfig struct {
+	// map is bearer token to the source name its events are sent with, "" is DefaultManualSource
+	Tokens map[string]string `json:"tokens"`
+}
+
+// Source is the source name for token's events, false when token isn't allowed
+func (mc ManualConfig) Source(token string) (string, bool) {
+	source, found := findToken(mc.Tokens, token)
+	if source == "" {
+		source = DefaultManualSource
+	}
+	return source, found
+}
+
+// AdminConfig is who can manage meets over http, the meet admin api isn't served without tokens
+type AdminConfig struct {
+	// map is bearer token to who it was given to, for the logs
+	Tokens map[string]string `json:"tokens"`
+}
+
+// User is who token was given to, false when token isn't allowed
+func (ac AdminConfig) User(token string) (string, bool) {
+	return findToken(ac.Tokens, token)
+}
+
+// findToken is the value for token in tokens, an empty token is never found
+func findToken(tokens map[string]string, token string) (string, bool) {
+	found := false
+	value := ""
+	// every token is compared so how long it takes doesn't give a token away
+	for t, v := range tokens {
+		if subtle.ConstantTimeCompare([]byte(t), []byte(token)) == 1 {
+			found, value = true, v
+		}
+	}
+	return value, found && token != ""
+}
+
+// ReadFilter drops finish reads that can't be real finishes, a zero value lets every read through
+type ReadFilter struct {
+	// reads of a bib closer together than this are the same crossing
+	DebounceSeconds float64 `json:"debounceSeconds"`
+	// reads sooner than this after the athlete's start are walk-bys
+	MinFinishSeconds float64 `json:"minFinishSeconds"`
+	// only the first accepted finish for a bib counts
+	FirstFinishOnly bool `json:"firstFinishOnly"`
 }
 
 func LoadAnyConfigData[CT any](configPath string, c *CT) error {
@@ -0,0 +1,141 @@
+package eventgen
+
+import (
+	"blreynolds4/event-race-timer/internal/raceevents"
+	"bufio"
+	"fmt"
+	"io"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// the sources of generated events
+const (
+	StartSource  = "manual"
+	ReaderSource = "reader-1"
+	PlaceSource  = "manual"
+	SlowSource   = "generator-slow"
+	FastSource   = "generator-fast"
+)
+
+// Finisher is a line of a race result file
+type Finisher struct {
+	Place     int
+	Bib       int
+	LastName  string
+	FirstName string
+	Grade     int
+	Team      string
+	Time      time.Duration
+}
+
+// ParseFinisher parses a line of a race result file.
+// The columns are:  place|bib|last|first|grade|school|time|score
+// and times are minutes:seconds.tenths
+func ParseFinisher(line string) (Finisher, error) {
+	split := strings.Split(line, "|")
+	if len(split) < 7 {
+		return Finisher{}, fmt.Errorf("expected at least 7 columns in %q", line)
+	}
+
+	var f Finisher
+	var err error
+	f.Place, _ = strconv.Atoi(split[0])
+	f.Bib, err = strconv.Atoi(split[1])
+	if err != nil {
+		return f, fmt.Errorf("error getting bib from %s: %w", split[1], err)
+	}
+	f.LastName = split[2]
+	f.FirstName = split[3]
+	f.Grade, _ = strconv.Atoi(split[4])
+	f.Team = split[5]
+
+	// convert to go duration format
+	durationString := strings.Replace(split[6], ":", "m", 1) + "s"
+	f.Time, err = time.ParseDuration(durationString)
+	if err != nil {
+		return f, fmt.Errorf("error getting duration from %s: %s -> %s %w", split[0], split[6], durationString, err)
+	}
+
+	return f, nil
+}
+
+// ReadFinishers reads the finishers in a race result file in file order, lines starting with # are comments
+func ReadFinishers(r io.Reader) ([]Finisher, error) {
+	finishers := make([]Finisher, 0)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "#") || strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		f, err := ParseFinisher(line)
+		if err != nil {
+			return nil, err
+		}
+		finishers = append(finishers, f)
+	}
+
+	return finishers, scanner.Err()
+}
+
+// FinishEvents returns the events for the finishCount'th finisher of a race that
+// started at startTime:  a finish read, a manual place and then up to two more reads
+// from other sources a little slower and faster, which sometimes miss the bib.
+func FinishEvents(f Finisher, finishCount int, startTime time.Time, rnd *rand.Rand) []raceevents.Event {
+	finishTime := startTime.Add(f.Time)
+	events := []raceevents.Event{
+		{
+			EventTime: finishTime,
+			Data: raceevents.FinishEvent{
+				Source:     ReaderSource,
+				FinishTime: finishTime,
+				Bib:        f.Bib,
+			},
+		},
+		{
+			EventTime: finishTime,
+			Data: raceevents.PlaceEvent{
+				Source: PlaceSource,
+				Bib:    f.Bib,
+				Place:  finishCount,
+			},
+		},
+	}
+
+	// get a random number 1 - 3 to decide on additional finish events for the athlete
+	random := rnd.Intn(3)
+	bib := f.Bib
+	if random >= 1 {
+		// add a another event a little slower than first event with no bib
+		// set a bib about half the time
+		if rnd.Intn(2) > 0 {
+			bib = raceevents.NoBib
+		}
+		events = append(events, raceevents.Event{
+			EventTime: finishTime,
+			Data: raceevents.FinishEvent{
+				Source:     SlowSource,
+				FinishTime: finishTime.Add(time.Millisecond * 500),
+				Bib:        bib,
+			},
+		})
+	}
+
+	if random >= 2 {
+		// add a third reader event a little faster
+		events = append(events, raceevents.Event{
+			EventTime: finishTime,
+			Data: raceevents.FinishEvent{
+				Source:     FastSource,
+				FinishTime: finishTime.Add(time.Millisecond * -500),
+				Bib:        bib,
+			},
+		})
+	}
+
+	return events
+}
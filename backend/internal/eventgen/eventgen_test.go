@@ -0,0 +1,54 @@
+package eventgen
+
+import (
+	"blreynolds4/event-race-timer/internal/raceevents"
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadFinishers(t *testing.T) {
+	file := `# comment
+1|515|Reynolds|David|12|Merrimack Valley|16:44.8|1
+2|627|O'Brien|Andrew|10|Oyster River|16:48.6|2
+`
+	finishers, err := ReadFinishers(strings.NewReader(file))
+	assert.NoError(t, err)
+	assert.Equal(t, []Finisher{
+		{Place: 1, Bib: 515, LastName: "Reynolds", FirstName: "David", Grade: 12, Team: "Merrimack Valley", Time: 16*time.Minute + 44800*time.Millisecond},
+		{Place: 2, Bib: 627, LastName: "O'Brien", FirstName: "Andrew", Grade: 10, Team: "Oyster River", Time: 16*time.Minute + 48600*time.Millisecond},
+	}, finishers)
+
+	_, err = ReadFinishers(strings.NewReader("1|bad|Reynolds|David|12|MV|16:44.8|1"))
+	assert.Error(t, err)
+	_, err = ReadFinishers(strings.NewReader("1|515|Reynolds|David|12|MV|16-44|1"))
+	assert.Error(t, err)
+	_, err = ReadFinishers(strings.NewReader("1|515|Reynolds"))
+	assert.Error(t, err)
+}
+
+func TestFinishEvents(t *testing.T) {
+	start := time.Date(2026, 10, 3, 10, 0, 0, 0, time.UTC)
+	f := Finisher{Bib: 515, Time: 16 * time.Minute}
+
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 20; i++ {
+		events := FinishEvents(f, 3, start, rnd)
+		assert.GreaterOrEqual(t, len(events), 2)
+		assert.LessOrEqual(t, len(events), 4)
+
+		assert.Equal(t, raceevents.FinishEvent{Source: ReaderSource, Bib: 515, FinishTime: start.Add(16 * time.Minute)}, events[0].Data)
+		assert.Equal(t, raceevents.PlaceEvent{Source: PlaceSource, Bib: 515, Place: 3}, events[1].Data)
+		for _, e := range events[2:] {
+			fe := e.Data.(raceevents.FinishEvent)
+			assert.Contains(t, []string{SlowSource, FastSource}, fe.Source)
+			assert.Contains(t, []int{515, raceevents.NoBib}, fe.Bib)
+		}
+	}
+
+	// the same seed generates the same events
+	assert.Equal(t, FinishEvents(f, 3, start, rand.New(rand.NewSource(7))), FinishEvents(f, 3, start, rand.New(rand.NewSource(7))))
+}
@@ -0,0 +1,427 @@
+package file_stream
+
+import (
+	"blreynolds4/event-race-timer/internal/stream"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	logExtension   = ".log"
+	indexExtension = ".idx"
+
+	// log record header: data length, data crc, id ms, id seq
+	recordHeaderSize = 4 + 4 + 8 + 8
+	// index entry: id ms, id seq, log offset
+	indexEntrySize = 8 + 8 + 8
+
+	// how often a blocked GetMessage looks for new messages
+	pollInterval = 25 * time.Millisecond
+)
+
+type indexEntry struct {
+	id     stream.MessageID
+	offset int64
+}
+
+// FileStream is a stream.ReaderWriter backed by an append only log file per race.
+// Every message is fsync'd before SendMessage returns.  An index file maps ids
+// to log offsets so ranges can be read without scanning the log.
+// Several processes can read and write the same race files, writers take a file lock.
+type FileStream struct {
+	mu        sync.Mutex
+	log       *os.File
+	index     *os.File
+	entries   []indexEntry
+	lastMsgId stream.MessageID
+}
+
+func NewFileStream(dir, name string) (*FileStream, error) {
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return nil, err
+	}
+
+	fs := &FileStream{}
+	fs.log, err = os.OpenFile(filepath.Join(dir, name+logExtension), os.O_RDWR|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	fs.index, err = os.OpenFile(filepath.Join(dir, name+indexExtension), os.O_RDWR|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		fs.log.Close()
+		return nil, err
+	}
+
+	err = fs.recover()
+	if err != nil {
+		fs.Close()
+		return nil, err
+	}
+
+	return fs, nil
+}
+
+func (fs *FileStream) Close() error {
+	var err error
+	if fs.log != nil {
+		err = fs.log.Close()
+		fs.log = nil
+	}
+	if fs.index != nil {
+		if indexErr := fs.index.Close(); err == nil {
+			err = indexErr
+		}
+		fs.index = nil
+	}
+	return err
+}
+
+// recover makes the index match the log after a crash.  Records written to the
+// log but not the index are indexed and a partly written record is truncated.
+func (fs *FileStream) recover() error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	err := lockFile(fs.log)
+	if err != nil {
+		return err
+	}
+	defer unlockFile(fs.log)
+
+	// drop any partial index entry
+	indexInfo, err := fs.index.Stat()
+	if err != nil {
+		return err
+	}
+	if indexInfo.Size()%indexEntrySize != 0 {
+		err = fs.index.Truncate(indexInfo.Size() - indexInfo.Size()%indexEntrySize)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = fs.refreshIndex()
+	if err != nil {
+		return err
+	}
+
+	logInfo, err := fs.log.Stat()
+	if err != nil {
+		return err
+	}
+
+	// the index can't point past the end of the log
+	for len(fs.entries) > 0 && fs.entries[len(fs.entries)-1].offset >= logInfo.Size() {
+		fs.entries = fs.entries[:len(fs.entries)-1]
+	}
+	err = fs.index.Truncate(int64(len(fs.entries)) * indexEntrySize)
+	if err != nil {
+		return err
+	}
+
+	// scan the log after the last indexed record
+	offset := int64(0)
+	if len(fs.entries) > 0 {
+		last := fs.entries[len(fs.entries)-1]
+		_, size, err := fs.readRecord(last.offset)
+		if err != nil {
+			return err
+		}
+		offset = last.offset + size
+	}
+
+	for offset < logInfo.Size() {
+		msg, size, err := fs.readRecord(offset)
+		if err != nil {
+			// torn write at the end of the log
+			break
+		}
+		id, _ := stream.ParseMessageID(msg.ID)
+		err = fs.appendIndex(indexEntry{id: id, offset: offset})
+		if err != nil {
+			return err
+		}
+		offset += size
+	}
+
+	if offset < logInfo.Size() {
+		err = fs.log.Truncate(offset)
+		if err != nil {
+			return err
+		}
+	}
+
+	return fs.index.Sync()
+}
+
+// refreshIndex reads index entries added since the last refresh, possibly by another process
+func (fs *FileStream) refreshIndex() error {
+	info, err := fs.index.Stat()
+	if err != nil {
+		return err
+	}
+
+	known := int64(len(fs.entries)) * indexEntrySize
+	count := (info.Size() - known) / indexEntrySize
+	if count <= 0 {
+		return nil
+	}
+
+	buffer := make([]byte, count*indexEntrySize)
+	_, err = fs.index.ReadAt(buffer, known)
+	if err != nil {
+		return err
+	}
+
+	for i := int64(0); i < count; i++ {
+		entry := buffer[i*indexEntrySize:]
+		fs.entries = append(fs.entries, indexEntry{
+			id: stream.MessageID{
+				Ms:  binary.BigEndian.Uint64(entry[0:]),
+				Seq: binary.BigEndian.Uint64(entry[8:]),
+			},
+			offset: int64(binary.BigEndian.Uint64(entry[16:])),
+		})
+	}
+
+	return nil
+}
+
+func (fs *FileStream) appendIndex(e indexEntry) error {
+	entry := make([]byte, indexEntrySize)
+	binary.BigEndian.PutUint64(entry[0:], e.id.Ms)
+	binary.BigEndian.PutUint64(entry[8:], e.id.Seq)
+	binary.BigEndian.PutUint64(entry[16:], uint64(e.offset))
+
+	_, err := fs.index.Write(entry)
+	if err != nil {
+		return err
+	}
+
+	fs.entries = append(fs.entries, e)
+	return nil
+}
+
+// readRecord returns the message at offset and the size of the record in the log
+func (fs *FileStream) readRecord(offset int64) (stream.Message, int64, error) {
+	header := make([]byte, recordHeaderSize)
+	_, err := fs.log.ReadAt(header, offset)
+	if err != nil {
+		return stream.Message{}, 0, err
+	}
+
+	dataLen := binary.BigEndian.Uint32(header[0:])
+	dataCrc := binary.BigEndian.Uint32(header[4:])
+	id := stream.MessageID{
+		Ms:  binary.BigEndian.Uint64(header[8:]),
+		Seq: binary.BigEndian.Uint64(header[16:]),
+	}
+
+	data := make([]byte, dataLen)
+	_, err = fs.log.ReadAt(data, offset+recordHeaderSize)
+	if err != nil {
+		return stream.Message{}, 0, err
+	}
+	if crc32.ChecksumIEEE(data) != dataCrc {
+		return stream.Message{}, 0, fmt.Errorf("corrupt record for id %s at offset %d", id, offset)
+	}
+
+	return stream.Message{ID: id.String(), Data: data}, recordHeaderSize + int64(dataLen), nil
+}
+
+func (fs *FileStream) SendMessage(ctx context.Context, sm stream.Message) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	err := lockFile(fs.log)
+	if err != nil {
+		return err
+	}
+	defer unlockFile(fs.log)
+
+	// another process may have written since we last looked
+	err = fs.refreshIndex()
+	if err != nil {
+		return err
+	}
+
+	var last stream.MessageID
+	if len(fs.entries) > 0 {
+		last = fs.entries[len(fs.entries)-1].id
+	}
+
+	id := stream.NextMessageID(last, time.Now())
+	if sm.ID != "" && sm.ID != "*" {
+		id, err = stream.ParseMessageID(sm.ID)
+		if err != nil {
+			return err
+		}
+		if id.IsZero() || !last.Less(id) {
+			return fmt.Errorf("stream id %s must be greater than %s", id, last)
+		}
+	}
+
+	info, err := fs.log.Stat()
+	if err != nil {
+		return err
+	}
+	offset := info.Size()
+
+	record := make([]byte, recordHeaderSize+len(sm.Data))
+	binary.BigEndian.PutUint32(record[0:], uint32(len(sm.Data)))
+	binary.BigEndian.PutUint32(record[4:], crc32.ChecksumIEEE(sm.Data))
+	binary.BigEndian.PutUint64(record[8:], id.Ms)
+	binary.BigEndian.PutUint64(record[16:], id.Seq)
+	copy(record[recordHeaderSize:], sm.Data)
+
+	// the log is synced before the index so the index never points at a partial record
+	_, err = fs.log.Write(record)
+	if err != nil {
+		return err
+	}
+	err = fs.log.Sync()
+	if err != nil {
+		return err
+	}
+
+	err = fs.appendIndex(indexEntry{id: id, offset: offset})
+	if err != nil {
+		return err
+	}
+	return fs.index.Sync()
+}
+
+// GetMessage returns the next message after the last one this stream read.
+// Like redis XREAD BLOCK, a timeout of 0 waits forever and a negative timeout doesn't wait.
+func (fs *FileStream) GetMessage(ctx context.Context, timeout time.Duration, resultMsg *stream.Message) (bool, error) {
+	var expired <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		found, err := fs.readNext(resultMsg)
+		if err != nil || found {
+			return found, err
+		}
+
+		if timeout < 0 {
+			return false, nil
+		}
+
+		select {
+		case <-ticker.C:
+		case <-expired:
+			return false, nil
+		case <-ctx.Done():
+			return false, ctx.Err()
+		}
+	}
+}
+
+func (fs *FileStream) readNext(resultMsg *stream.Message) (bool, error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	err := fs.refreshIndex()
+	if err != nil {
+		return false, err
+	}
+
+	i := sort.Search(len(fs.entries), func(i int) bool {
+		return fs.lastMsgId.Less(fs.entries[i].id)
+	})
+	if i == len(fs.entries) {
+		return false, nil
+	}
+
+	msg, _, err := fs.readRecord(fs.entries[i].offset)
+	if err != nil {
+		return false, err
+	}
+
+	*resultMsg = msg
+	fs.lastMsgId = fs.entries[i].id
+	return true, nil
+}
+
+func (fs *FileStream) SeekAfter(id string) error {
+	seekId, err := stream.ParseMessageID(id)
+	if err != nil {
+		return err
+	}
+
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	fs.lastMsgId = seekId
+	return nil
+}
+
+func (fs *FileStream) RangeQueryMin() string {
+	return stream.RangeQueryMin()
+}
+
+func (fs *FileStream) ExclusiveQueryStart(id string) string {
+	return stream.ExclusiveQueryStart(id)
+}
+
+func (fs *FileStream) RangeQueryMax() string {
+	return stream.RangeQueryMax()
+}
+
+func (fs *FileStream) GetMessageRange(ctx context.Context, startId, endId string, resultMessages []stream.Message) (int, error) {
+	if len(resultMessages) == 0 {
+		return 0, fmt.Errorf("can't get message range with empty buffer")
+	}
+
+	start, end, err := stream.RangeBounds(startId, endId)
+	if err != nil {
+		return 0, err
+	}
+
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	err = fs.refreshIndex()
+	if err != nil {
+		return 0, err
+	}
+
+	resultCount := 0
+	i := sort.Search(len(fs.entries), func(i int) bool {
+		return !fs.entries[i].id.Less(start)
+	})
+	for ; i < len(fs.entries) && resultCount < len(resultMessages); i++ {
+		if end.Less(fs.entries[i].id) {
+			break
+		}
+
+		msg, _, err := fs.readRecord(fs.entries[i].offset)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return 0, fmt.Errorf("index points past end of log for id %s", fs.entries[i].id)
+			}
+			return 0, err
+		}
+		resultMessages[resultCount] = msg
+		resultCount++
+	}
+
+	return resultCount, nil
+}
@@ -0,0 +1,188 @@
+package file_stream
+
+import (
+	"blreynolds4/event-race-timer/internal/stream"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestStream(t *testing.T, dir string) *FileStream {
+	fs, err := NewFileStream(dir, "race")
+	assert.NoError(t, err)
+	t.Cleanup(func() { fs.Close() })
+	return fs
+}
+
+func sendMessages(t *testing.T, fs *FileStream, count int) {
+	for i := 0; i < count; i++ {
+		err := fs.SendMessage(context.TODO(), stream.Message{Data: []byte(fmt.Sprintf("msg%d", i))})
+		assert.NoError(t, err)
+	}
+}
+
+func readAll(t *testing.T, fs *FileStream) []stream.Message {
+	buffer := make([]stream.Message, 100)
+	count, err := fs.GetMessageRange(context.TODO(), fs.RangeQueryMin(), fs.RangeQueryMax(), buffer)
+	assert.NoError(t, err)
+	return buffer[:count]
+}
+
+func TestSendGetMessage(t *testing.T) {
+	fs := newTestStream(t, t.TempDir())
+	sendMessages(t, fs, 2)
+
+	var first, second, none stream.Message
+	gotMsg, err := fs.GetMessage(context.TODO(), time.Second, &first)
+	assert.NoError(t, err)
+	assert.True(t, gotMsg)
+	assert.Equal(t, []byte("msg0"), first.Data)
+
+	gotMsg, err = fs.GetMessage(context.TODO(), time.Second, &second)
+	assert.NoError(t, err)
+	assert.True(t, gotMsg)
+	assert.Equal(t, []byte("msg1"), second.Data)
+
+	gotMsg, err = fs.GetMessage(context.TODO(), -1, &none)
+	assert.NoError(t, err)
+	assert.False(t, gotMsg)
+}
+
+func TestGetMessageTimeoutAndCancel(t *testing.T) {
+	fs := newTestStream(t, t.TempDir())
+
+	var msg stream.Message
+	gotMsg, err := fs.GetMessage(context.TODO(), 10*time.Millisecond, &msg)
+	assert.NoError(t, err)
+	assert.False(t, gotMsg)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	gotMsg, err = fs.GetMessage(ctx, 0, &msg)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.False(t, gotMsg)
+}
+
+func TestReaderSeesOtherWriter(t *testing.T) {
+	dir := t.TempDir()
+	reader := newTestStream(t, dir)
+	writer := newTestStream(t, dir)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	var msg stream.Message
+	var gotMsg bool
+	var err error
+	go func() {
+		defer wg.Done()
+		gotMsg, err = reader.GetMessage(context.TODO(), 0, &msg)
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	sendMessages(t, writer, 1)
+	wg.Wait()
+
+	assert.NoError(t, err)
+	assert.True(t, gotMsg)
+	assert.Equal(t, []byte("msg0"), msg.Data)
+}
+
+func TestMessagesSurviveReopen(t *testing.T) {
+	dir := t.TempDir()
+	fs := newTestStream(t, dir)
+	sendMessages(t, fs, 3)
+	sent := readAll(t, fs)
+	fs.Close()
+
+	reopened := newTestStream(t, dir)
+	assert.Equal(t, sent, readAll(t, reopened))
+
+	// new ids keep increasing after a reopen
+	sendMessages(t, reopened, 1)
+	all := readAll(t, reopened)
+	assert.Equal(t, 4, len(all))
+	lastId, _ := stream.ParseMessageID(all[2].ID)
+	newId, _ := stream.ParseMessageID(all[3].ID)
+	assert.True(t, lastId.Less(newId))
+}
+
+func TestRecoverTornWriteAndMissingIndex(t *testing.T) {
+	dir := t.TempDir()
+	fs := newTestStream(t, dir)
+	sendMessages(t, fs, 3)
+	sent := readAll(t, fs)
+	fs.Close()
+
+	// lose the last index entry and tear a record onto the end of the log
+	indexPath := filepath.Join(dir, "race"+indexExtension)
+	assert.NoError(t, os.Truncate(indexPath, 2*indexEntrySize+5))
+	logFile, err := os.OpenFile(filepath.Join(dir, "race"+logExtension), os.O_WRONLY|os.O_APPEND, 0644)
+	assert.NoError(t, err)
+	_, err = logFile.Write([]byte{0, 0, 0, 9, 1, 2})
+	assert.NoError(t, err)
+	logFile.Close()
+
+	recovered := newTestStream(t, dir)
+	assert.Equal(t, sent, readAll(t, recovered))
+
+	sendMessages(t, recovered, 1)
+	assert.Equal(t, 4, len(readAll(t, recovered)))
+}
+
+func TestGetMessageRange(t *testing.T) {
+	fs := newTestStream(t, t.TempDir())
+	for _, id := range []string{"1-0", "1-1", "2-0", "3-5"} {
+		err := fs.SendMessage(context.TODO(), stream.Message{ID: id, Data: []byte(id)})
+		assert.NoError(t, err)
+	}
+
+	err := fs.SendMessage(context.TODO(), stream.Message{ID: "3-5", Data: []byte("dup")})
+	assert.Error(t, err)
+
+	tests := []struct {
+		start    string
+		end      string
+		expected []string
+	}{
+		{fs.RangeQueryMin(), fs.RangeQueryMax(), []string{"1-0", "1-1", "2-0", "3-5"}},
+		{"1-1", "2-0", []string{"1-1", "2-0"}},
+		{fs.ExclusiveQueryStart("1-1"), fs.RangeQueryMax(), []string{"2-0", "3-5"}},
+		{"1", "1", []string{"1-0", "1-1"}},
+		{fs.ExclusiveQueryStart("3-5"), fs.RangeQueryMax(), []string{}},
+	}
+
+	for _, test := range tests {
+		buffer := make([]stream.Message, 10)
+		count, err := fs.GetMessageRange(context.TODO(), test.start, test.end, buffer)
+		assert.NoError(t, err)
+		actual := make([]string, count)
+		for i := 0; i < count; i++ {
+			actual[i] = buffer[i].ID
+			assert.Equal(t, []byte(buffer[i].ID), buffer[i].Data)
+		}
+		assert.Equal(t, test.expected, actual, "range %s %s", test.start, test.end)
+	}
+
+	_, err = fs.GetMessageRange(context.TODO(), fs.RangeQueryMin(), fs.RangeQueryMax(), []stream.Message{})
+	assert.Error(t, err)
+}
+
+func TestSeekAfter(t *testing.T) {
+	fs := newTestStream(t, t.TempDir())
+	for _, id := range []string{"1-0", "2-0", "3-0"} {
+		assert.NoError(t, fs.SendMessage(context.TODO(), stream.Message{ID: id, Data: []byte(id)}))
+	}
+
+	assert.NoError(t, fs.SeekAfter("2-0"))
+	var msg stream.Message
+	gotMsg, err := fs.GetMessage(context.TODO(), -1, &msg)
+	assert.NoError(t, err)
+	assert.True(t, gotMsg)
+	assert.Equal(t, "3-0", msg.ID)
+}
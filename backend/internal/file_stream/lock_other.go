@@ -0,0 +1,14 @@
+//go:build !unix
+
+package file_stream
+
+import "os"
+
+// without flock only writers in this process are serialized
+func lockFile(f *os.File) error {
+	return nil
+}
+
+func unlockFile(f *os.File) error {
+	return nil
+}
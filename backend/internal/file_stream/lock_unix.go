@@ -0,0 +1,17 @@
+//go:build unix
+
+package file_stream
+
+import (
+	"os"
+	"syscall"
+)
+
+// lockFile takes an exclusive lock so writers in other processes wait their turn
+func lockFile(f *os.File) error {
+	return syscall.Flock(int(f.Fd()), syscall.LOCK_EX)
+}
+
+func unlockFile(f *os.File) error {
+	return syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+}
@@ -0,0 +1,280 @@
+package liveresults
+
+import (
+	"blreynolds4/event-race-timer/internal/meets"
+	"errors"
+	"log/slog"
+	"reflect"
+	"sort"
+	"sync"
+)
+
+// update types, a snapshot has every result and changes have the results that changed since the last update
+const (
+	UpdateSnapshot = "snapshot"
+	UpdateChanges  = "changes"
+)
+
+// updateBuffer is how many updates a subscriber can fall behind before it's dropped
+const updateBuffer = 16
+
+var ErrUnknownRace = errors.New("unknown race")
+
+// Result is a race result as it's shown live, times are in milliseconds
+type Result struct {
+	Bib       int              `json:"bib"`
+	Place     int              `json:"place"`
+	XcPlace   int              `json:"xcPlace"`
+	FirstName string           `json:"firstName"`
+	LastName  string           `json:"lastName"`
+	Team      string           `json:"team"`
+	Grade     int              `json:"grade"`
+	Gender    string           `json:"gender"`
+	GunTime   int64            `json:"gunTime"`
+	NetTime   int64            `json:"netTime"`
+	Status    string           `json:"status,omitempty"`
+	Splits    map[string]int64 `json:"splits,omitempty"`
+}
+
+func NewResult(rr *meets.RaceResult) Result {
+	result := Result{
+		Bib:     rr.Bib,
+		Place:   rr.Place,
+		XcPlace: rr.XcPlace,
+		GunTime: rr.GunTime.Milliseconds(),
+		NetTime: rr.NetTime.Milliseconds(),
+		Status:  rr.Status,
+	}
+	if rr.Athlete != nil {
+		result.FirstName = rr.Athlete.FirstName
+		result.LastName = rr.Athlete.LastName
+		result.Team = rr.Athlete.Team
+		result.Grade = rr.Athlete.Grade
+		result.Gender = rr.Athlete.Gender
+	}
+	if len(rr.Splits) > 0 {
+		result.Splits = make(map[string]int64, len(rr.Splits))
+		for timingPoint, split := range rr.Splits {
+			result.Splits[timingPoint] = split.Milliseconds()
+		}
+	}
+	return result
+}
+
+// Update is sent to subscribers, Removed has the bibs whose results were removed
+type Update struct {
+	Type    string   `json:"type"`
+	Race    string   `json:"race"`
+	Results []Result `json:"results"`
+	Removed []int    `json:"removed,omitempty"`
+}
+
+// Subscription gets a snapshot of the race's results then their changes on Updates.
+// Updates is closed when the subscriber falls too far behind, it can subscribe again
+// for a new snapshot.
+type Subscription struct {
+	Updates <-chan Update
+	updates chan Update
+	feed    *feed
+}
+
+// Close stops the updates
+func (s *Subscription) Close() {
+	s.feed.unsubscribe(s)
+}
+
+// OpenReader opens the result reader for a race, nil when there's no race with the name
+type OpenReader func(raceName string) (meets.RaceResultReader, error)
+
+// PostgresReaders opens the result readers for races found by name with raceReader
+func PostgresReaders(raceReader meets.RaceReader, connectStr string) OpenReader {
+	return func(raceName string) (meets.RaceResultReader, error) {
+		race, err := raceReader.GetRaceByName(raceName)
+		if err != nil || race == nil {
+			return nil, err
+		}
+		return meets.NewRaceResultReader(race, connectStr)
+	}
+}
+
+// Hub keeps the latest results of each race that's been subscribed to and sends their
+// changes to the subscribers
+type Hub struct {
+	mu         sync.Mutex
+	openReader OpenReader
+	feeds      map[string]*feed
+	logger     *slog.Logger
+}
+
+func NewHub(openReader OpenReader, logger *slog.Logger) *Hub {
+	return &Hub{
+		openReader: openReader,
+		feeds:      make(map[string]*feed),
+		logger:     logger,
+	}
+}
+
+// Subscribe to a race's results, ErrUnknownRace when there's no race with the name
+func (h *Hub) Subscribe(raceName string) (*Subscription, error) {
+	f, err := h.feed(raceName)
+	if err != nil {
+		return nil, err
+	}
+	return f.subscribe(), nil
+}
+
+// Changed reloads the race's results and sends the changes, "" reloads every race
+func (h *Hub) Changed(raceName string) {
+	h.mu.Lock()
+	feeds := make([]*feed, 0, len(h.feeds))
+	for name, f := range h.feeds {
+		if raceName == "" || name == raceName {
+			feeds = append(feeds, f)
+		}
+	}
+	h.mu.Unlock()
+
+	for _, f := range feeds {
+		err := f.refresh()
+		if err != nil {
+			h.logger.Error("error reloading live results", "race", f.race, "error", err)
+		}
+	}
+}
+
+// Close closes the result readers, subscriptions aren't updated after it's closed
+func (h *Hub) Close() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var errs []error
+	for _, f := range h.feeds {
+		errs = append(errs, f.reader.Close())
+	}
+	clear(h.feeds)
+	return errors.Join(errs...)
+}
+
+func (h *Hub) feed(raceName string) (*feed, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if f, found := h.feeds[raceName]; found {
+		return f, nil
+	}
+
+	reader, err := h.openReader(raceName)
+	if err != nil {
+		return nil, err
+	}
+	if reader == nil {
+		return nil, ErrUnknownRace
+	}
+
+	f := &feed{
+		race:        raceName,
+		reader:      reader,
+		results:     make(map[int]Result),
+		subscribers: make(map[*Subscription]struct{}),
+	}
+	err = f.refresh()
+	if err != nil {
+		reader.Close()
+		return nil, err
+	}
+	h.feeds[raceName] = f
+	return f, nil
+}
+
+// feed is the latest results of one race
+type feed struct {
+	mu          sync.Mutex
+	race        string
+	reader      meets.RaceResultReader
+	results     map[int]Result
+	subscribers map[*Subscription]struct{}
+}
+
+func (f *feed) subscribe() *Subscription {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	updates := make(chan Update, updateBuffer)
+	s := &Subscription{Updates: updates, updates: updates, feed: f}
+	updates <- Update{Type: UpdateSnapshot, Race: f.race, Results: ordered(f.results)}
+	f.subscribers[s] = struct{}{}
+	return s
+}
+
+func (f *feed) unsubscribe(s *Subscription) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, found := f.subscribers[s]; found {
+		delete(f.subscribers, s)
+		close(s.updates)
+	}
+}
+
+// refresh reloads the results and sends the ones that changed, a reshuffle of the
+// places sends every result whose place moved
+func (f *feed) refresh() error {
+	raceResults, err := f.reader.GetRaceResults()
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	latest := make(map[int]Result, len(raceResults))
+	changed := make(map[int]Result)
+	for _, rr := range raceResults {
+		result := NewResult(rr)
+		latest[result.Bib] = result
+		if last, found := f.results[result.Bib]; !found || !reflect.DeepEqual(last, result) {
+			changed[result.Bib] = result
+		}
+	}
+	removed := make([]int, 0)
+	for bib := range f.results {
+		if _, found := latest[bib]; !found {
+			removed = append(removed, bib)
+		}
+	}
+	sort.Ints(removed)
+	f.results = latest
+
+	if len(changed) == 0 && len(removed) == 0 {
+		return nil
+	}
+	update := Update{Type: UpdateChanges, Race: f.race, Results: ordered(changed), Removed: removed}
+	for s := range f.subscribers {
+		select {
+		case s.updates <- update:
+		default:
+			// the subscriber isn't keeping up, it has to start again with a snapshot
+			delete(f.subscribers, s)
+			close(s.updates)
+		}
+	}
+	return nil
+}
+
+// ordered puts results in place order, results without a place go last in bib order
+func ordered(results map[int]Result) []Result {
+	list := make([]Result, 0, len(results))
+	for _, result := range results {
+		list = append(list, result)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if (list[i].Place == 0) != (list[j].Place == 0) {
+			return list[j].Place == 0
+		}
+		if list[i].Place != list[j].Place {
+			return list[i].Place < list[j].Place
+		}
+		return list[i].Bib < list[j].Bib
+	})
+	return list
+}
@@ -0,0 +1,115 @@
+package liveresults
+
+import (
+	"blreynolds4/event-race-timer/internal/meets"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func raceResult(bib, place int, gunTime time.Duration) *meets.RaceResult {
+	return &meets.RaceResult{
+		Bib:     bib,
+		Athlete: meets.NewAthlete("A", "B", "WPI", "", 10, "m"),
+		Place:   place,
+		GunTime: gunTime,
+	}
+}
+
+func newTestHub(readers map[string]*meets.MockResultReader) *Hub {
+	return NewHub(func(raceName string) (meets.RaceResultReader, error) {
+		reader, found := readers[raceName]
+		if !found {
+			return nil, nil
+		}
+		return reader, nil
+	}, slog.New(slog.DiscardHandler))
+}
+
+func bibs(results []Result) []int {
+	list := make([]int, len(results))
+	for i, result := range results {
+		list[i] = result.Bib
+	}
+	return list
+}
+
+func TestHubSendsSnapshotThenChanges(t *testing.T) {
+	reader := &meets.MockResultReader{Results: []*meets.RaceResult{
+		raceResult(1, 1, 16*time.Minute),
+		raceResult(2, 2, 17*time.Minute),
+		raceResult(3, 0, 18*time.Minute),
+	}}
+	hub := newTestHub(map[string]*meets.MockResultReader{"Varsity": reader})
+
+	sub, err := hub.Subscribe("Varsity")
+	assert.NoError(t, err)
+	defer sub.Close()
+
+	// the snapshot is in place order, results without a place go last
+	snapshot := <-sub.Updates
+	assert.Equal(t, UpdateSnapshot, snapshot.Type)
+	assert.Equal(t, "Varsity", snapshot.Race)
+	assert.Equal(t, []int{1, 2, 3}, bibs(snapshot.Results))
+	assert.Equal(t, int64(16*time.Minute/time.Millisecond), snapshot.Results[0].GunTime)
+
+	// bib 3 is placed ahead of bib 2 and bib 1 is removed
+	reader.Results = []*meets.RaceResult{
+		raceResult(2, 3, 17*time.Minute),
+		raceResult(3, 2, 18*time.Minute),
+	}
+	hub.Changed("Varsity")
+	changes := <-sub.Updates
+	assert.Equal(t, UpdateChanges, changes.Type)
+	assert.Equal(t, []int{3, 2}, bibs(changes.Results))
+	assert.Equal(t, []int{1}, changes.Removed)
+
+	// nothing changed so nothing is sent
+	hub.Changed("")
+	select {
+	case update := <-sub.Updates:
+		t.Fatalf("unexpected update %v", update)
+	default:
+	}
+
+	// another race's changes aren't sent
+	hub.Changed("JV")
+	assert.Equal(t, 0, len(sub.Updates))
+}
+
+func TestHubDropsSlowSubscribers(t *testing.T) {
+	reader := &meets.MockResultReader{}
+	hub := newTestHub(map[string]*meets.MockResultReader{"Varsity": reader})
+
+	sub, err := hub.Subscribe("Varsity")
+	assert.NoError(t, err)
+
+	for place := 1; place <= updateBuffer; place++ {
+		reader.Results = append(reader.Results, raceResult(place, place, time.Duration(place)*time.Minute))
+		hub.Changed("Varsity")
+	}
+
+	// the snapshot and the changes that fit are sent, then the updates are closed
+	count := 0
+	for range sub.Updates {
+		count++
+	}
+	assert.Equal(t, updateBuffer, count)
+	sub.Close()
+
+	// subscribing again gets everything in the snapshot
+	sub, err = hub.Subscribe("Varsity")
+	assert.NoError(t, err)
+	defer sub.Close()
+	snapshot := <-sub.Updates
+	assert.Equal(t, updateBuffer, len(snapshot.Results))
+}
+
+func TestHubUnknownRace(t *testing.T) {
+	hub := newTestHub(map[string]*meets.MockResultReader{})
+
+	_, err := hub.Subscribe("Varsity")
+	assert.ErrorIs(t, err, ErrUnknownRace)
+}
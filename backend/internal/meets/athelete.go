@@ -1,6 +1,9 @@
 package meets
 
-import "io"
+import (
+	"fmt"
+	"io"
+)
 
 type Athlete struct {
 	id        int64
@@ -15,10 +18,29 @@ type Athlete struct {
 type RaceAthlete struct {
 	Athlete Athlete
 	Bib     int
+	Wave    string
 }
 
 type AthleteLookup map[int]*Athlete
 
+// WaveLookup maps a bib to the wave it starts in, bibs not in the lookup start in the "" wave
+type WaveLookup map[int]string
+
+// BibRaceLookup maps a bib to the race it's running when several races are timed at once
+type BibRaceLookup map[int]string
+
+// AddRace adds the bibs in a race, a bib already in another race is an error because
+// its reads can't be sent to the right race
+func (brl BibRaceLookup) AddRace(raceName string, athletes AthleteLookup) error {
+	for bib := range athletes {
+		if other, found := brl[bib]; found && other != raceName {
+			return fmt.Errorf("bib %d is in races %s and %s", bib, other, raceName)
+		}
+		brl[bib] = raceName
+	}
+	return nil
+}
+
 type AthleteWriter interface {
 	SaveAthlete(athlete *Athlete) (*Athlete, error)
 	DeleteAthlete(athlete *Athlete) error
@@ -0,0 +1,18 @@
+package meets
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBibRaceLookupAddRace(t *testing.T) {
+	bibRaces := make(BibRaceLookup)
+	assert.NoError(t, bibRaces.AddRace("JV", AthleteLookup{1: NewAthlete("A", "B", "T", "1", 9, "m"), 2: NewAthlete("C", "D", "T", "2", 10, "m")}))
+	assert.NoError(t, bibRaces.AddRace("Varsity", AthleteLookup{3: NewAthlete("E", "F", "T", "3", 12, "m")}))
+	assert.Equal(t, BibRaceLookup{1: "JV", 2: "JV", 3: "Varsity"}, bibRaces)
+
+	// adding a race again is fine, a bib in two races isn't
+	assert.NoError(t, bibRaces.AddRace("JV", AthleteLookup{1: NewAthlete("A", "B", "T", "1", 9, "m")}))
+	assert.Error(t, bibRaces.AddRace("Varsity", AthleteLookup{2: NewAthlete("C", "D", "T", "2", 10, "m")}))
+}
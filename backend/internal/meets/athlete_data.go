@@ -67,10 +67,20 @@ func (ad *athleteData) SaveAthlete(athlete *Athlete) (*Athlete, error) {
 
 func (ad *athleteData) DeleteAthlete(athlete *Athlete) error {
 	query := `
-		DELETE FROM athlete_race
+		DELETE FROM split
 		WHERE athlete_id = $1
 	`
 	_, err := ad.db.Exec(query, athlete.id)
+	if err != nil {
+		slog.Error("Error deleting athlete splits", slog.String("error", err.Error()))
+		return err
+	}
+
+	query = `
+		DELETE FROM athlete_race
+		WHERE athlete_id = $1
+	`
+	_, err = ad.db.Exec(query, athlete.id)
 	if err != nil {
 		slog.Error("Error deleting athlete from all races", slog.String("error", err.Error()))
 		return err
@@ -113,6 +123,7 @@ func (ad *athleteData) GetRaceAthletes(r *Race) ([]*RaceAthlete, error) {
 	query := `
 	SELECT
 		ar.bib,
+		ar.wave,
 		a.id,
 		a.da_id,
 		a.first_name,
@@ -135,7 +146,7 @@ func (ad *athleteData) GetRaceAthletes(r *Race) ([]*RaceAthlete, error) {
 
 	for rows.Next() {
 		athlete := new(RaceAthlete)
-		err := rows.Scan(&athlete.Bib, &athlete.Athlete.id, &athlete.Athlete.DaID, &athlete.Athlete.FirstName, &athlete.Athlete.LastName, &athlete.Athlete.Team, &athlete.Athlete.Grade, &athlete.Athlete.Gender)
+		err := rows.Scan(&athlete.Bib, &athlete.Wave, &athlete.Athlete.id, &athlete.Athlete.DaID, &athlete.Athlete.FirstName, &athlete.Athlete.LastName, &athlete.Athlete.Team, &athlete.Athlete.Grade, &athlete.Athlete.Gender)
 		if err != nil {
 			slog.Error("Error scanning athlete row", slog.String("error", err.Error()))
 			return nil, err
@@ -157,6 +168,7 @@ func (ad *athleteData) GetRaceAthlete(r *Race, bib int) (*RaceAthlete, error) {
 	query := `
 	SELECT
 		ar.bib,
+		ar.wave,
 		a.id,
 		a.da_id,
 		a.first_name,
@@ -173,7 +185,7 @@ func (ad *athleteData) GetRaceAthlete(r *Race, bib int) (*RaceAthlete, error) {
 	row := ad.db.QueryRow(query, r.meet.id, r.id, bib)
 
 	athlete := new(RaceAthlete)
-	err := row.Scan(&athlete.Bib, &athlete.Athlete.id, &athlete.Athlete.DaID, &athlete.Athlete.FirstName, &athlete.Athlete.LastName, &athlete.Athlete.Team, &athlete.Athlete.Grade, &athlete.Athlete.Gender)
+	err := row.Scan(&athlete.Bib, &athlete.Wave, &athlete.Athlete.id, &athlete.Athlete.DaID, &athlete.Athlete.FirstName, &athlete.Athlete.LastName, &athlete.Athlete.Team, &athlete.Athlete.Grade, &athlete.Athlete.Gender)
 	if err != nil {
 		slog.Error("Error scanning athlete row", slog.String("error", err.Error()))
 		return nil, err
@@ -0,0 +1,42 @@
+package meets
+
+import (
+	"log/slog"
+	"time"
+)
+
+// AthleteRefresh reloads a race's athletes when a bib isn't found, so athletes added to the
+// race during the meet are timed.  It reloads at most once an interval so a stray bib doesn't
+// reload for every read.  A nil AthleteRefresh never reloads.
+type AthleteRefresh struct {
+	reload    func() error
+	interval  time.Duration
+	logger    *slog.Logger
+	refreshed time.Time
+}
+
+// NewAthleteRefresh calls reload to refill the lookups the race's athletes are in
+func NewAthleteRefresh(reload func() error, interval time.Duration, l *slog.Logger) *AthleteRefresh {
+	return &AthleteRefresh{
+		reload:    reload,
+		interval:  interval,
+		logger:    l,
+		refreshed: time.Now(),
+	}
+}
+
+// Refresh reloads the athletes unless they were reloaded less than the interval ago,
+// true when they were.  A reload that fails keeps the athletes already loaded.
+func (ar *AthleteRefresh) Refresh() bool {
+	if ar == nil || time.Since(ar.refreshed) < ar.interval {
+		return false
+	}
+
+	ar.refreshed = time.Now()
+	err := ar.reload()
+	if err != nil {
+		ar.logger.Error("error reloading race athletes", "error", err)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,38 @@
+package meets
+
+import (
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAthleteRefresh(t *testing.T) {
+	reloads := 0
+	var reloadErr error
+	refresh := NewAthleteRefresh(func() error {
+		reloads++
+		return reloadErr
+	}, time.Hour, slog.New(slog.DiscardHandler))
+
+	// the athletes were just loaded
+	assert.False(t, refresh.Refresh())
+	assert.Equal(t, 0, reloads)
+
+	refresh.refreshed = time.Now().Add(-time.Hour)
+	assert.True(t, refresh.Refresh())
+	assert.False(t, refresh.Refresh())
+	assert.Equal(t, 1, reloads)
+
+	// a failed reload waits for the interval too
+	reloadErr = errors.New("no database")
+	refresh.refreshed = time.Now().Add(-time.Hour)
+	assert.False(t, refresh.Refresh())
+	assert.False(t, refresh.Refresh())
+	assert.Equal(t, 2, reloads)
+
+	var none *AthleteRefresh
+	assert.False(t, none.Refresh())
+}
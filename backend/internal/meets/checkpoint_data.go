@@ -0,0 +1,145 @@
+package meets
+
+import (
+	"database/sql"
+	"io"
+	"log/slog"
+)
+
+// ResultCheckpoint is how far the result builder got in a race's event stream.
+// State is whatever the builder needs to carry on from LastEventID, as json.
+type ResultCheckpoint struct {
+	LastEventID string
+	State       []byte
+	// History is the events the builder keeps to rebuild results, as json.  SaveCheckpoint
+	// adds these to the history already saved, LoadCheckpoint returns all of it in order.
+	History [][]byte
+}
+
+type CheckpointStore interface {
+	// LoadCheckpoint returns nil when the race has no checkpoint
+	LoadCheckpoint() (*ResultCheckpoint, error)
+	SaveCheckpoint(cp ResultCheckpoint) error
+	DeleteCheckpoint() error
+	io.Closer
+}
+
+func NewCheckpointStore(r *Race, connectStr string) (CheckpointStore, error) {
+	cd := &checkpointData{
+		race: r,
+	}
+
+	var err error
+	cd.db, err = sql.Open("postgres", connectStr)
+	if err != nil {
+		slog.Error("Failed to connect to database", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return cd, nil
+}
+
+type checkpointData struct {
+	db   *sql.DB
+	race *Race
+}
+
+func (cd *checkpointData) Close() error {
+	var err error
+	if cd.db != nil {
+		err = cd.db.Close()
+		cd.db = nil
+	}
+	return err
+}
+
+func (cd *checkpointData) LoadCheckpoint() (*ResultCheckpoint, error) {
+	query := `SELECT last_event_id, state FROM result_checkpoint WHERE race_id = $1`
+
+	cp := new(ResultCheckpoint)
+	err := cd.db.QueryRow(query, cd.race.id).Scan(&cp.LastEventID, &cp.State)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		slog.Error("Error loading checkpoint", slog.String("race", cd.race.Name), slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	rows, err := cd.db.Query(`SELECT event FROM result_history WHERE race_id = $1 ORDER BY id`, cd.race.id)
+	if err != nil {
+		slog.Error("Error loading result history", slog.String("race", cd.race.Name), slog.String("error", err.Error()))
+		return nil, err
+	}
+	defer rows.Close()
+
+	cp.History = make([][]byte, 0)
+	for rows.Next() {
+		var event []byte
+		err = rows.Scan(&event)
+		if err != nil {
+			slog.Error("Error scanning result history row", slog.String("race", cd.race.Name), slog.String("error", err.Error()))
+			return nil, err
+		}
+		cp.History = append(cp.History, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return cp, nil
+}
+
+// SaveCheckpoint replaces the checkpoint and adds its history in one transaction, so the
+// history always matches the checkpoint
+func (cd *checkpointData) SaveCheckpoint(cp ResultCheckpoint) error {
+	tx, err := cd.db.Begin()
+	if err != nil {
+		slog.Error("Error starting checkpoint transaction", slog.String("error", err.Error()))
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, event := range cp.History {
+		_, err = tx.Exec(`INSERT INTO result_history (race_id, event) VALUES ($1, $2)`, cd.race.id, event)
+		if err != nil {
+			slog.Error("Error saving result history", slog.String("race", cd.race.Name), slog.String("error", err.Error()))
+			return err
+		}
+	}
+
+	query := `
+		INSERT INTO result_checkpoint (race_id, last_event_id, state, updated_at)
+		VALUES ($1, $2, $3, now())
+		ON CONFLICT (race_id) DO UPDATE SET last_event_id = $2, state = $3, updated_at = now()
+	`
+	_, err = tx.Exec(query, cd.race.id, cp.LastEventID, cp.State)
+	if err != nil {
+		slog.Error("Error saving checkpoint", slog.String("race", cd.race.Name), slog.String("error", err.Error()))
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (cd *checkpointData) DeleteCheckpoint() error {
+	tx, err := cd.db.Begin()
+	if err != nil {
+		slog.Error("Error starting checkpoint transaction", slog.String("error", err.Error()))
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, query := range []string{
+		`DELETE FROM result_history WHERE race_id = $1`,
+		`DELETE FROM result_checkpoint WHERE race_id = $1`,
+	} {
+		_, err = tx.Exec(query, cd.race.id)
+		if err != nil {
+			slog.Error("Error deleting checkpoint", slog.String("race", cd.race.Name), slog.String("error", err.Error()))
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,64 @@
+package meets
+
+import (
+	"testing"
+
+	_ "github.com/lib/pq" // PostgreSQL driver
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSaveLoadCheckpoint(t *testing.T) {
+	requireDB(t)
+
+	meetWriter, err := NewMeetWriter(connectStr)
+	if err != nil {
+		t.Fatalf("Failed to create meet writer: %v", err)
+	}
+	defer meetWriter.Close()
+
+	raceWriter, err := NewRaceWriter(connectStr)
+	if err != nil {
+		t.Fatalf("Failed to create race writer: %v", err)
+	}
+	defer raceWriter.Close()
+
+	meet, err := meetWriter.SaveMeet(&Meet{Name: "Checkpoint Meet"})
+	if err != nil {
+		t.Fatalf("Failed to save meet: %v", err)
+	}
+	defer func() {
+		meetWriter.DeleteMeet(meet)
+	}()
+
+	race, err := raceWriter.SaveRace(&Race{Name: "Checkpoint Race"}, meet)
+	assert.Nil(t, err)
+
+	store, err := NewCheckpointStore(race, connectStr)
+	assert.Nil(t, err)
+	defer store.Close()
+
+	// no checkpoint yet
+	cp, err := store.LoadCheckpoint()
+	assert.Nil(t, err)
+	assert.Nil(t, cp)
+
+	err = store.SaveCheckpoint(ResultCheckpoint{LastEventID: "1-0", State: []byte(`{"a":1}`), History: [][]byte{[]byte(`{"e":1}`)}})
+	assert.Nil(t, err)
+	err = store.SaveCheckpoint(ResultCheckpoint{LastEventID: "2-0", State: []byte(`{"a":2}`), History: [][]byte{[]byte(`{"e":2}`)}})
+	assert.Nil(t, err)
+
+	// each checkpoint adds to the history
+	cp, err = store.LoadCheckpoint()
+	assert.Nil(t, err)
+	assert.Equal(t, "2-0", cp.LastEventID)
+	assert.JSONEq(t, `{"a":2}`, string(cp.State))
+	assert.Equal(t, 2, len(cp.History))
+	assert.JSONEq(t, `{"e":1}`, string(cp.History[0]))
+	assert.JSONEq(t, `{"e":2}`, string(cp.History[1]))
+
+	err = store.DeleteCheckpoint()
+	assert.Nil(t, err)
+	cp, err = store.LoadCheckpoint()
+	assert.Nil(t, err)
+	assert.Nil(t, cp)
+}
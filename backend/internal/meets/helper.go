@@ -1,7 +1,11 @@
 package meets
 
+import "slices"
+
 type MockResultWriter struct {
 	SavedResults []RaceResult
+	// ClearedBibs are the bibs whose results were cleared
+	ClearedBibs []int
 }
 
 func NewMockResultWriter() *MockResultWriter {
@@ -15,6 +19,106 @@ func (mrw *MockResultWriter) SaveResult(rr *RaceResult) (*RaceResult, error) {
 	return rr, nil
 }
 
+func (mrw *MockResultWriter) ClearResult(bib int) error {
+	mrw.ClearedBibs = append(mrw.ClearedBibs, bib)
+	return nil
+}
+
 func (mrw *MockResultWriter) Close() error {
 	return nil
 }
+
+// MockResultReader returns Results from GetRaceResults
+type MockResultReader struct {
+	Results []*RaceResult
+}
+
+func (mrr *MockResultReader) GetRaceResults() ([]*RaceResult, error) {
+	return mrr.Results, nil
+}
+
+func (mrr *MockResultReader) Close() error {
+	return nil
+}
+
+// MockCheckpointStore keeps the latest checkpoint in Checkpoint
+type MockCheckpointStore struct {
+	Checkpoint *ResultCheckpoint
+	Saves      int
+}
+
+func (mcs *MockCheckpointStore) LoadCheckpoint() (*ResultCheckpoint, error) {
+	return mcs.Checkpoint, nil
+}
+
+// SaveCheckpoint adds the checkpoint's history to the history already saved
+func (mcs *MockCheckpointStore) SaveCheckpoint(cp ResultCheckpoint) error {
+	if mcs.Checkpoint != nil {
+		cp.History = append(slices.Clone(mcs.Checkpoint.History), cp.History...)
+	}
+	mcs.Checkpoint = &cp
+	mcs.Saves++
+	return nil
+}
+
+func (mcs *MockCheckpointStore) DeleteCheckpoint() error {
+	mcs.Checkpoint = nil
+	return nil
+}
+
+func (mcs *MockCheckpointStore) Close() error {
+	return nil
+}
+
+// MockOrphanReadStore keeps orphan reads in Reads
+type MockOrphanReadStore struct {
+	Reads  []*OrphanRead
+	nextID int64
+}
+
+func (mos *MockOrphanReadStore) SaveOrphanRead(or *OrphanRead) (*OrphanRead, error) {
+	mos.nextID++
+	or.ID = mos.nextID
+	mos.Reads = append(mos.Reads, or)
+	return or, nil
+}
+
+func (mos *MockOrphanReadStore) GetOrphanReads() ([]*OrphanRead, error) {
+	return mos.Reads, nil
+}
+
+func (mos *MockOrphanReadStore) GetOrphanRead(id int64) (*OrphanRead, error) {
+	for _, or := range mos.Reads {
+		if or.ID == id {
+			return or, nil
+		}
+	}
+	return nil, nil
+}
+
+func (mos *MockOrphanReadStore) DeleteOrphanRead(id int64) error {
+	for i, or := range mos.Reads {
+		if or.ID == id {
+			mos.Reads = append(mos.Reads[:i], mos.Reads[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (mos *MockOrphanReadStore) Close() error {
+	return nil
+}
+
+// MockPlaceConflictReader returns Conflicts from GetPlaceConflicts
+type MockPlaceConflictReader struct {
+	Conflicts []PlaceConflict
+}
+
+func (mpc *MockPlaceConflictReader) GetPlaceConflicts() ([]PlaceConflict, error) {
+	return mpc.Conflicts, nil
+}
+
+func (mpc *MockPlaceConflictReader) Close() error {
+	return nil
+}
@@ -36,11 +36,20 @@ type RaceReader interface {
 type RaceWriter interface {
 	SaveRace(r *Race, m *Meet) (*Race, error)
 	AddAthlete(r *Race, a *Athlete, bib int) error
+	SetAthleteWave(r *Race, bib int, wave string) error
+	// MoveAthlete moves the athlete with bib in r to race to with a new bib, the athlete's
+	// splits in r are deleted when they change races
+	MoveAthlete(r *Race, bib int, to *Race, toBib int) error
 	RemoveAthlete(r *Race, a *Athlete) error
 	DeleteRace(r *Race) error
 	io.Closer
 }
 
+// Meet is the meet the race is in, nil for a race that wasn't loaded with its meet
+func (r *Race) Meet() *Meet {
+	return r.meet
+}
+
 func (m *Meet) AddRace(race *Race) *Race {
 	// check if race already exists
 	for _, r := range m.races {
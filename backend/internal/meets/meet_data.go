@@ -209,12 +209,87 @@ func (md *meetData) AddAthlete(r *Race, a *Athlete, bib int) error {
 	return nil
 }
 
+func (md *meetData) SetAthleteWave(r *Race, bib int, wave string) error {
+	query := `
+		UPDATE athlete_race
+		SET wave = $3
+		WHERE race_id = $1 AND bib = $2
+	`
+	res, err := md.db.Exec(query, r.id, bib, wave)
+	if err != nil {
+		slog.Error("Error setting athlete wave", slog.String("error", err.Error()))
+		return err
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return fmt.Errorf("no athlete with bib %d in race %s", bib, r.Name)
+	}
+
+	return nil
+}
+
+func (md *meetData) MoveAthlete(r *Race, bib int, to *Race, toBib int) error {
+	tx, err := md.db.Begin()
+	if err != nil {
+		slog.Error("Error starting athlete move", slog.String("error", err.Error()))
+		return err
+	}
+	defer tx.Rollback()
+
+	if r.id != to.id {
+		query := `
+			DELETE FROM split
+			WHERE race_id = $1 AND bib = $2
+		`
+		_, err = tx.Exec(query, r.id, bib)
+		if err != nil {
+			slog.Error("Error removing moved athlete splits", slog.String("error", err.Error()))
+			return err
+		}
+	}
+
+	query := `
+		UPDATE athlete_race
+		SET race_id = $3, bib = $4
+		WHERE race_id = $1 AND bib = $2
+	`
+	res, err := tx.Exec(query, r.id, bib, to.id, toBib)
+	if err != nil {
+		slog.Error("Error moving athlete", slog.String("error", err.Error()))
+		return err
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return fmt.Errorf("no athlete with bib %d in race %s", bib, r.Name)
+	}
+
+	return tx.Commit()
+}
+
 func (md *meetData) RemoveAthlete(r *Race, a *Athlete) error {
 	query := `
-		DELETE FROM athlete_race
+		DELETE FROM split
 		WHERE race_id = $1 AND athlete_id = $2
 	`
 	_, err := md.db.Exec(query, r.id, a.id)
+	if err != nil {
+		slog.Error("Error removing athlete splits", slog.String("error", err.Error()))
+		return err
+	}
+
+	query = `
+		DELETE FROM athlete_race
+		WHERE race_id = $1 AND athlete_id = $2
+	`
+	_, err = md.db.Exec(query, r.id, a.id)
 	if err != nil {
 		slog.Error("Error removing athlete", slog.String("error", err.Error()))
 		return err
@@ -276,11 +351,33 @@ func (md *meetData) GetRaceByName(raceName string) (*Race, error) {
 
 func (md *meetData) DeleteRace(r *Race) error {
 
+	// the result builder's progress goes with the race
+	for _, query := range []string{
+		`DELETE FROM result_history WHERE race_id = $1`,
+		`DELETE FROM result_checkpoint WHERE race_id = $1`,
+	} {
+		_, err := md.db.Exec(query, r.id)
+		if err != nil {
+			slog.Error("Error deleting race checkpoint", slog.String("error", err.Error()))
+			return err
+		}
+	}
+
 	query := `
-		DELETE FROM athlete_race
+		DELETE FROM split
 		WHERE race_id = $1
 	`
 	_, err := md.db.Exec(query, r.id)
+	if err != nil {
+		slog.Error("Error deleting race splits", slog.String("error", err.Error()))
+		return err
+	}
+
+	query = `
+		DELETE FROM athlete_race
+		WHERE race_id = $1
+	`
+	_, err = md.db.Exec(query, r.id)
 	if err != nil {
 		slog.Error("Error deleting race athletes", slog.String("error", err.Error()))
 		return err
@@ -308,13 +405,19 @@ func (md *meetData) DeleteMeet(m *Meet) error {
 		}
 	}
 
+	_, err := md.db.Exec(`DELETE FROM orphan_read WHERE meet_id = $1`, m.id)
+	if err != nil {
+		slog.Error("Error deleting meet orphan reads", slog.String("error", err.Error()))
+		return err
+	}
+
 	// delete the meet
 	query := `
 		DELETE from meet
 		WHERE name = $1
 	`
 
-	_, err := md.db.Exec(query, m.Name)
+	_, err = md.db.Exec(query, m.Name)
 	if err != nil {
 		slog.Error("Error deleting meet", slog.String("error", err.Error()))
 		return err
@@ -324,37 +427,57 @@ func (md *meetData) DeleteMeet(m *Meet) error {
 }
 
 func LoadAthleteLookup(connectStr, raceName string, athletes AthleteLookup) error {
-	athleteReader, err := NewAthleteReader(connectStr)
+	raceAthletes, err := loadRaceAthletes(connectStr, raceName)
+	if err != nil {
+		return err
+	}
+
+	// populate the provided athlete lookup
+	for _, ra := range raceAthletes {
+		athletes[ra.Bib] = &ra.Athlete
+	}
+
+	// success, return nil for error
+	return nil
+}
+
+// LoadWaveLookup fills waves with the wave of every athlete in the race that has one
+func LoadWaveLookup(connectStr, raceName string, waves WaveLookup) error {
+	raceAthletes, err := loadRaceAthletes(connectStr, raceName)
 	if err != nil {
 		return err
 	}
+
+	for _, ra := range raceAthletes {
+		if ra.Wave != "" {
+			waves[ra.Bib] = ra.Wave
+		}
+	}
+
+	return nil
+}
+
+func loadRaceAthletes(connectStr, raceName string) ([]*RaceAthlete, error) {
+	athleteReader, err := NewAthleteReader(connectStr)
+	if err != nil {
+		return nil, err
+	}
 	defer athleteReader.Close()
 
 	RaceReader, err := NewRaceReader(connectStr)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer RaceReader.Close()
 
 	// get the race by name
 	race, err := RaceReader.GetRaceByName(raceName)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	fmt.Println("Loading athletes for race:", raceName)
 
 	// get all athletes for the race
-	raceAthletes, err := athleteReader.GetRaceAthletes(race)
-	if err != nil {
-		return err
-	}
-
-	// populate the provided athlete lookup
-	for _, ra := range raceAthletes {
-		athletes[ra.Bib] = &ra.Athlete
-	}
-
-	// success, return nil for error
-	return nil
+	return athleteReader.GetRaceAthletes(race)
 }
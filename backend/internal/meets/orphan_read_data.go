@@ -0,0 +1,139 @@
+package meets
+
+import (
+	"database/sql"
+	"io"
+	"log/slog"
+	"time"
+)
+
+// OrphanRead is a reader's read of a bib that wasn't in any race being timed.  It's kept
+// so it can be reassigned to a race once the bib is known.
+type OrphanRead struct {
+	ID          int64
+	Bib         int
+	Source      string
+	Host        string
+	Antenna     int
+	CaptureMode string
+	TimingPoint string
+	ReadTime    time.Time
+}
+
+type OrphanReadStore interface {
+	SaveOrphanRead(or *OrphanRead) (*OrphanRead, error)
+	GetOrphanReads() ([]*OrphanRead, error)
+	// GetOrphanRead returns nil when there's no read with the id
+	GetOrphanRead(id int64) (*OrphanRead, error)
+	DeleteOrphanRead(id int64) error
+	io.Closer
+}
+
+// NewOrphanReadStore keeps the orphan reads for a meet
+func NewOrphanReadStore(m *Meet, connectStr string) (OrphanReadStore, error) {
+	ord := &orphanReadData{
+		meet: m,
+	}
+
+	var err error
+	ord.db, err = sql.Open("postgres", connectStr)
+	if err != nil {
+		slog.Error("Failed to connect to database", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return ord, nil
+}
+
+type orphanReadData struct {
+	db   *sql.DB
+	meet *Meet
+}
+
+func (ord *orphanReadData) Close() error {
+	var err error
+	if ord.db != nil {
+		err = ord.db.Close()
+		ord.db = nil
+	}
+	return err
+}
+
+func (ord *orphanReadData) SaveOrphanRead(or *OrphanRead) (*OrphanRead, error) {
+	query := `
+		INSERT INTO orphan_read (meet_id, bib, source, host, antenna, capture_mode, timing_point, read_time)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id
+	`
+	err := ord.db.QueryRow(query, ord.meet.id, or.Bib, or.Source, or.Host, or.Antenna, or.CaptureMode, or.TimingPoint, or.ReadTime.UTC()).Scan(&or.ID)
+	if err != nil {
+		slog.Error("Error saving orphan read", slog.String("meet", ord.meet.Name), slog.Int("bib", or.Bib), slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return or, nil
+}
+
+func (ord *orphanReadData) GetOrphanReads() ([]*OrphanRead, error) {
+	rows, err := ord.db.Query(`
+		SELECT id, bib, source, host, antenna, capture_mode, timing_point, read_time
+		FROM orphan_read
+		WHERE meet_id = $1
+		ORDER BY read_time
+	`, ord.meet.id)
+	if err != nil {
+		slog.Error("Error querying orphan reads", slog.String("meet", ord.meet.Name), slog.String("error", err.Error()))
+		return nil, err
+	}
+	defer rows.Close()
+
+	reads := make([]*OrphanRead, 0)
+	for rows.Next() {
+		or, err := scanOrphanRead(rows)
+		if err != nil {
+			slog.Error("Error scanning orphan read row", slog.String("error", err.Error()))
+			return nil, err
+		}
+		reads = append(reads, or)
+	}
+
+	return reads, rows.Err()
+}
+
+func (ord *orphanReadData) GetOrphanRead(id int64) (*OrphanRead, error) {
+	row := ord.db.QueryRow(`
+		SELECT id, bib, source, host, antenna, capture_mode, timing_point, read_time
+		FROM orphan_read
+		WHERE meet_id = $1 and id = $2
+	`, ord.meet.id, id)
+	or, err := scanOrphanRead(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		slog.Error("Error loading orphan read", slog.String("meet", ord.meet.Name), slog.Int64("id", id), slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	return or, nil
+}
+
+func (ord *orphanReadData) DeleteOrphanRead(id int64) error {
+	_, err := ord.db.Exec(`DELETE FROM orphan_read WHERE meet_id = $1 and id = $2`, ord.meet.id, id)
+	if err != nil {
+		slog.Error("Error deleting orphan read", slog.String("meet", ord.meet.Name), slog.Int64("id", id), slog.String("error", err.Error()))
+		return err
+	}
+
+	return nil
+}
+
+func scanOrphanRead(row interface{ Scan(dest ...any) error }) (*OrphanRead, error) {
+	or := new(OrphanRead)
+	err := row.Scan(&or.ID, &or.Bib, &or.Source, &or.Host, &or.Antenna, &or.CaptureMode, &or.TimingPoint, &or.ReadTime)
+	if err != nil {
+		return nil, err
+	}
+	or.ReadTime = or.ReadTime.UTC()
+	return or, nil
+}
@@ -0,0 +1,50 @@
+package meets
+
+import (
+	"testing"
+	"time"
+
+	_ "github.com/lib/pq" // PostgreSQL driver
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSaveGetDeleteOrphanReads(t *testing.T) {
+	requireDB(t)
+
+	meetWriter, err := NewMeetWriter(connectStr)
+	if err != nil {
+		t.Fatalf("Failed to create meet writer: %v", err)
+	}
+	defer meetWriter.Close()
+
+	meet, err := meetWriter.SaveMeet(&Meet{Name: "Orphan Meet"})
+	assert.Nil(t, err)
+	defer func() {
+		meetWriter.DeleteMeet(meet)
+	}()
+
+	store, err := NewOrphanReadStore(meet, connectStr)
+	assert.Nil(t, err)
+	defer store.Close()
+
+	readTime := time.Date(2025, 10, 4, 10, 30, 0, 0, time.UTC)
+	first, err := store.SaveOrphanRead(&OrphanRead{Bib: 999, Source: "chip", Host: "reader1", Antenna: 2, CaptureMode: "finish", ReadTime: readTime})
+	assert.Nil(t, err)
+	second, err := store.SaveOrphanRead(&OrphanRead{Bib: 998, Source: "chip", Host: "mile1", Antenna: 1, CaptureMode: "finish", TimingPoint: "1 mile", ReadTime: readTime.Add(time.Second)})
+	assert.Nil(t, err)
+
+	reads, err := store.GetOrphanReads()
+	assert.Nil(t, err)
+	assert.Equal(t, []*OrphanRead{first, second}, reads)
+
+	read, err := store.GetOrphanRead(second.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, second, read)
+
+	err = store.DeleteOrphanRead(first.ID)
+	assert.Nil(t, err)
+
+	read, err = store.GetOrphanRead(first.ID)
+	assert.Nil(t, err)
+	assert.Nil(t, read)
+}
@@ -0,0 +1,128 @@
+package meets
+
+import (
+	"database/sql"
+	"io"
+	"log/slog"
+)
+
+// the kinds of place conflict in a race's results
+const (
+	// DuplicatePlace is a place more than one runner has
+	DuplicatePlace = "duplicate"
+	// PlaceGap is a place nobody has before the last place given out
+	PlaceGap = "gap"
+	// NoPlace is a runner with a finish time and no place
+	NoPlace = "noPlace"
+	// NoFinish is a runner with a place and no finish time
+	NoFinish = "noFinish"
+)
+
+// PlaceConflict is a place that needs fixing before the results are final, runners with a
+// DNF, DNS or DQ aren't placed so they're never in a conflict
+type PlaceConflict struct {
+	Kind string
+	// Place is the duplicated, missing or finish-less place, 0 for a runner with no place
+	Place int
+	// Results are the runners in the conflict, a gap has none
+	Results []*RaceResult
+}
+
+// PlaceConflictReader finds the place conflicts in a race's saved results
+type PlaceConflictReader interface {
+	// GetPlaceConflicts returns the conflicts in place order, the ones without a place last
+	GetPlaceConflicts() ([]PlaceConflict, error)
+	io.Closer
+}
+
+func NewPlaceConflictReader(r *Race, connectStr string) (PlaceConflictReader, error) {
+	return buildResultData(r, connectStr)
+}
+
+func (rd *resultData) GetPlaceConflicts() ([]PlaceConflict, error) {
+	// a row for each runner in a conflict, a gap's row has no bib
+	query := `
+	WITH placed AS (
+		SELECT bib, coalesce(place, 0) AS place, coalesce(finish_time, 0) AS finish_time
+		FROM athlete_race
+		WHERE race_id = $1 AND status = ''
+	)
+	SELECT $2::text, p.place, p.bib
+	FROM placed p
+	WHERE p.place IN (SELECT place FROM placed WHERE place > 0 GROUP BY place HAVING count(*) > 1)
+	UNION ALL
+	SELECT $3::text, g.place, NULL
+	FROM generate_series(1, (SELECT coalesce(max(place), 0) FROM placed)) AS g(place)
+	WHERE NOT EXISTS (SELECT 1 FROM placed p WHERE p.place = g.place)
+	UNION ALL
+	SELECT $4::text, 0, p.bib
+	FROM placed p
+	WHERE p.place = 0 AND p.finish_time > 0
+	UNION ALL
+	SELECT $5::text, p.place, p.bib
+	FROM placed p
+	WHERE p.place > 0 AND p.finish_time = 0
+	ORDER BY 2, 1, 3
+	`
+	rows, err := rd.db.Query(query, rd.race.id, DuplicatePlace, PlaceGap, NoPlace, NoFinish)
+	if err != nil {
+		slog.Error("Error querying place conflicts", slog.String("race", rd.race.Name), slog.String("error", err.Error()))
+		return nil, err
+	}
+	defer rows.Close()
+
+	results, err := rd.GetRaceResults()
+	if err != nil {
+		return nil, err
+	}
+	byBib := make(map[int]*RaceResult, len(results))
+	for _, result := range results {
+		byBib[result.Bib] = result
+	}
+
+	conflicts := make([]PlaceConflict, 0)
+	for rows.Next() {
+		var kind string
+		var place int
+		var bib sql.NullInt64
+		err := rows.Scan(&kind, &place, &bib)
+		if err != nil {
+			slog.Error("Error scanning place conflict row", slog.String("race", rd.race.Name), slog.String("error", err.Error()))
+			return nil, err
+		}
+		result, found := byBib[int(bib.Int64)]
+		if bib.Valid && !found {
+			// the result couldn't be read, GetRaceResults logged why
+			continue
+		}
+		conflicts = addConflict(conflicts, kind, place, result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// runners without a place sort first by place, they're listed last
+	unplaced := 0
+	for unplaced < len(conflicts) && conflicts[unplaced].Kind == NoPlace {
+		unplaced++
+	}
+	return append(conflicts[unplaced:], conflicts[:unplaced]...), nil
+}
+
+// addConflict adds a runner to the conflict for their place, a duplicate place is one
+// conflict with every runner that has it and the other kinds are a conflict per runner
+func addConflict(conflicts []PlaceConflict, kind string, place int, result *RaceResult) []PlaceConflict {
+	if kind == DuplicatePlace && len(conflicts) > 0 {
+		last := &conflicts[len(conflicts)-1]
+		if last.Kind == kind && last.Place == place {
+			last.Results = append(last.Results, result)
+			return conflicts
+		}
+	}
+
+	conflict := PlaceConflict{Kind: kind, Place: place}
+	if result != nil {
+		conflict.Results = []*RaceResult{result}
+	}
+	return append(conflicts, conflict)
+}
@@ -0,0 +1,90 @@
+package meets
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	_ "github.com/lib/pq" // PostgreSQL driver
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetPlaceConflicts(t *testing.T) {
+	requireDB(t)
+
+	meetWriter, err := NewMeetWriter(connectStr)
+	if err != nil {
+		t.Fatalf("Failed to create meet writer: %v", err)
+	}
+	defer meetWriter.Close()
+
+	raceWriter, err := NewRaceWriter(connectStr)
+	if err != nil {
+		t.Fatalf("Failed to create race writer: %v", err)
+	}
+	defer raceWriter.Close()
+
+	athleteWriter, err := NewAthleteWriter(connectStr)
+	assert.Nil(t, err)
+	defer athleteWriter.Close()
+
+	meet, err := meetWriter.SaveMeet(&Meet{Name: "Conflict Meet"})
+	assert.Nil(t, err)
+	defer func() {
+		meetWriter.DeleteMeet(meet)
+	}()
+
+	race, err := raceWriter.SaveRace(&Race{Name: "Conflict Race"}, meet)
+	assert.Nil(t, err)
+
+	resultWriter, err := NewRaceResultWriter(race, connectStr)
+	assert.Nil(t, err)
+	defer resultWriter.Close()
+
+	// 1 and 2 tie for 1st, nobody is 3rd, 4 has a place and no finish, 5 finished without a
+	// place, 6 dropped out and 7 hasn't finished
+	results := []*RaceResult{
+		{Bib: 1, Place: 1, PlaceSource: "manual", GunTime: 10 * time.Minute},
+		{Bib: 2, Place: 1, PlaceSource: "manual", GunTime: 11 * time.Minute},
+		{Bib: 3, Place: 2, PlaceSource: "manual", GunTime: 12 * time.Minute},
+		{Bib: 4, Place: 4, PlaceSource: "manual"},
+		{Bib: 5, GunTime: 13 * time.Minute},
+		{Bib: 6, Status: "DNF"},
+		{Bib: 7},
+	}
+	for _, rr := range results {
+		athlete, err := athleteWriter.SaveAthlete(&Athlete{FirstName: "Bib", LastName: fmt.Sprint(rr.Bib), Team: "Test Team", DaID: fmt.Sprintf("CONFLICT%d", rr.Bib), Grade: 1, Gender: "m"})
+		assert.Nil(t, err)
+		defer athleteWriter.DeleteAthlete(athlete)
+
+		err = raceWriter.AddAthlete(race, athlete, rr.Bib)
+		assert.Nil(t, err)
+		if rr.Bib != 7 {
+			rr.Athlete = athlete
+			_, err = resultWriter.SaveResult(rr)
+			assert.Nil(t, err)
+		}
+	}
+
+	conflictReader, err := NewPlaceConflictReader(race, connectStr)
+	assert.Nil(t, err)
+	defer conflictReader.Close()
+
+	conflicts, err := conflictReader.GetPlaceConflicts()
+	assert.Nil(t, err)
+
+	kinds := make([]string, 0, len(conflicts))
+	for _, pc := range conflicts {
+		bibs := make([]int, 0, len(pc.Results))
+		for _, rr := range pc.Results {
+			bibs = append(bibs, rr.Bib)
+		}
+		kinds = append(kinds, fmt.Sprintf("%s %d %v", pc.Kind, pc.Place, bibs))
+	}
+	assert.Equal(t, []string{
+		"duplicate 1 [1 2]",
+		"gap 3 []",
+		"noFinish 4 [4]",
+		"noPlace 0 [5]",
+	}, kinds)
+}
@@ -4,15 +4,29 @@ import (
 	"time"
 )
 
+// what orders the overall results, the gun time (default) or the net time
+const (
+	RankByGun = "gun"
+	RankByNet = "net"
+)
+
 // A RaceResult that can be filled in as events arrive and sent when IsComplete() is true
 type RaceResult struct {
-	Bib          int
-	Athlete      *Athlete
-	Place        int
-	XcPlace      int
-	Time         time.Duration
+	Bib     int
+	Athlete *Athlete
+	Place   int
+	XcPlace int
+	// GunTime is from the start of the athlete's wave, NetTime is from when they
+	// crossed the start mat.  NetTime is the GunTime when there is no start mat read.
+	GunTime      time.Duration
+	NetTime      time.Duration
 	FinishSource string
 	PlaceSource  string
+	// DNF, DNS or DQ, empty for a runner still in the results
+	Status string
+	// Splits is the time from the start of the athlete's wave to each timing point they passed,
+	// keyed by timing point name.  It's nil until there is a split.
+	Splits map[string]time.Duration
 }
 
 func (rr RaceResult) IsComplete() bool {
@@ -24,3 +38,54 @@ func (rr RaceResult) IsComplete() bool {
 		(rr.Place > 0) &&
 		(rr.PlaceSource != "")
 }
+
+// RemovedResult is sent on a result stream when bib's result is removed, readers drop the bib
+func RemovedResult(bib int) RaceResult {
+	return RaceResult{Bib: bib}
+}
+
+// IsRemoved is true for a RemovedResult
+func (rr RaceResult) IsRemoved() bool {
+	return rr.Athlete == nil
+}
+
+// RankTime is the time used to order results when ranking by rankBy.
+// Results without a net time use their gun time.
+func (rr RaceResult) RankTime(rankBy string) time.Duration {
+	if rankBy == RankByNet && rr.NetTime > 0 {
+		return rr.NetTime
+	}
+	return rr.GunTime
+}
+
+// IsValidRankBy is true for the values RankTime understands
+func IsValidRankBy(rankBy string) bool {
+	return rankBy == RankByGun || rankBy == RankByNet
+}
+
+// Equal is true when both results have the same values, the athlete is compared by pointer
+func (rr RaceResult) Equal(other RaceResult) bool {
+	if len(rr.Splits) != len(other.Splits) {
+		return false
+	}
+	for name, split := range rr.Splits {
+		if otherSplit, found := other.Splits[name]; !found || otherSplit != split {
+			return false
+		}
+	}
+
+	return rr.Bib == other.Bib &&
+		rr.Athlete == other.Athlete &&
+		rr.Place == other.Place &&
+		rr.XcPlace == other.XcPlace &&
+		rr.GunTime == other.GunTime &&
+		rr.NetTime == other.NetTime &&
+		rr.FinishSource == other.FinishSource &&
+		rr.PlaceSource == other.PlaceSource &&
+		rr.Status == other.Status
+}
+
+// HasStatus is true when the runner didn't start, didn't finish or was disqualified
+func (rr RaceResult) HasStatus() bool {
+	return rr.Status != ""
+}
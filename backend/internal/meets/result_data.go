@@ -2,6 +2,7 @@ package meets
 
 import (
 	"database/sql"
+	"fmt"
 	"io"
 	"log/slog"
 	"time"
@@ -9,6 +10,8 @@ import (
 
 type RaceResultWriter interface {
 	SaveResult(rr *RaceResult) (*RaceResult, error)
+	// ClearResult removes bib's result, the athlete stays entered in the race
+	ClearResult(bib int) error
 	io.Closer
 }
 
@@ -55,23 +58,103 @@ func buildResultData(r *Race, connectStr string) (*resultData, error) {
 }
 
 func (rd *resultData) SaveResult(rr *RaceResult) (*RaceResult, error) {
+	if rr.Athlete == nil {
+		return nil, fmt.Errorf("result for bib %d has no athlete", rr.Bib)
+	}
+
 	// race result will be save to athlete_race table
 	// the key is race id, bib, athlete id
 	slog.Info("Saving race result", "athlete id", rr.Athlete.id, "raceResult", slog.AnyValue(rr))
 	query := `
-		INSERT INTO athlete_race (race_id, athlete_id, bib, finish_time, place, xc_place, finish_source, place_source)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-		ON CONFLICT (athlete_id, race_id, bib) DO UPDATE SET finish_time = $4, place = $5, xc_place = $6, finish_source = $7, place_source = $8	
+		INSERT INTO athlete_race (race_id, athlete_id, bib, finish_time, net_time, place, xc_place, finish_source, place_source, status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (athlete_id, race_id, bib) DO UPDATE SET finish_time = $4, net_time = $5, place = $6, xc_place = $7, finish_source = $8, place_source = $9, status = $10
 	`
-	_, err := rd.db.Exec(query, rd.race.id, rr.Athlete.id, rr.Bib, rr.Time.Milliseconds(), rr.Place, rr.XcPlace, rr.FinishSource, rr.PlaceSource)
+	tx, err := rd.db.Begin()
+	if err != nil {
+		slog.Error("Error starting race result transaction", slog.String("error", err.Error()))
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(query, rd.race.id, rr.Athlete.id, rr.Bib, rr.GunTime.Milliseconds(), rr.NetTime.Milliseconds(), rr.Place, rr.XcPlace, rr.FinishSource, rr.PlaceSource, rr.Status)
 	if err != nil {
 		slog.Error("Error saving race result", slog.String("error", err.Error()))
 		return nil, err
 	}
 
+	// the result has all the athlete's splits, replace what was saved before
+	_, err = tx.Exec(`DELETE FROM split WHERE race_id = $1 AND athlete_id = $2`, rd.race.id, rr.Athlete.id)
+	if err != nil {
+		slog.Error("Error removing splits", slog.String("error", err.Error()))
+		return nil, err
+	}
+	for timingPoint, splitTime := range rr.Splits {
+		_, err = tx.Exec(`
+			INSERT INTO split (race_id, athlete_id, bib, timing_point, split_time)
+			VALUES ($1, $2, $3, $4, $5)
+		`, rd.race.id, rr.Athlete.id, rr.Bib, timingPoint, splitTime.Milliseconds())
+		if err != nil {
+			slog.Error("Error saving split", slog.String("error", err.Error()), slog.String("timingPoint", timingPoint))
+			return nil, err
+		}
+	}
+
+	// listeners are told when the result is committed
+	_, err = tx.Exec(`SELECT pg_notify($1, $2)`, resultsChannel, rd.race.Name)
+	if err != nil {
+		slog.Error("Error notifying result listeners", slog.String("error", err.Error()))
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		slog.Error("Error committing race result", slog.String("error", err.Error()))
+		return nil, err
+	}
+
 	return rr, nil
 }
 
+// ClearResult sets the bib's result back to how it was when the athlete was entered, a row
+// without a finish time isn't read as a result
+func (rd *resultData) ClearResult(bib int) error {
+	tx, err := rd.db.Begin()
+	if err != nil {
+		slog.Error("Error starting race result transaction", slog.String("error", err.Error()))
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		UPDATE athlete_race
+		SET finish_time = NULL, net_time = NULL, place = NULL, xc_place = NULL, finish_source = NULL, place_source = NULL, status = ''
+		WHERE race_id = $1 AND bib = $2
+	`, rd.race.id, bib)
+	if err != nil {
+		slog.Error("Error clearing race result", slog.String("error", err.Error()), slog.Int("bib", bib))
+		return err
+	}
+	_, err = tx.Exec(`DELETE FROM split WHERE race_id = $1 AND bib = $2`, rd.race.id, bib)
+	if err != nil {
+		slog.Error("Error removing splits", slog.String("error", err.Error()))
+		return err
+	}
+
+	_, err = tx.Exec(`SELECT pg_notify($1, $2)`, resultsChannel, rd.race.Name)
+	if err != nil {
+		slog.Error("Error notifying result listeners", slog.String("error", err.Error()))
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		slog.Error("Error committing race result", slog.String("error", err.Error()))
+		return err
+	}
+	return nil
+}
+
 func (rd *resultData) GetRaceResults() ([]*RaceResult, error) {
 	query := `
 	select 
@@ -83,7 +166,7 @@ func (rd *resultData) GetRaceResults() ([]*RaceResult, error) {
 		a.team,
 		a.grade,
 		a.gender,
-		ar.finish_time, ar.place, ar.xc_place, ar.finish_source, ar.place_source
+		ar.finish_time, coalesce(ar.net_time, 0), ar.place, ar.xc_place, ar.finish_source, ar.place_source, ar.status
 	FROM athlete a
 		JOIN athlete_race ar ON a.id = ar.athlete_id
 		inner join race r on ar.race_id = r.id
@@ -103,19 +186,62 @@ func (rd *resultData) GetRaceResults() ([]*RaceResult, error) {
 		athlete := new(Athlete)
 		raceResult := new(RaceResult)
 		timeInMillis := int64(0)
+		netInMillis := int64(0)
 		err := rows.Scan(&raceResult.Bib, &athlete.id, &athlete.DaID, &athlete.FirstName, &athlete.LastName, &athlete.Team, &athlete.Grade, &athlete.Gender,
-			&timeInMillis, &raceResult.Place, &raceResult.XcPlace, &raceResult.FinishSource, &raceResult.PlaceSource)
+			&timeInMillis, &netInMillis, &raceResult.Place, &raceResult.XcPlace, &raceResult.FinishSource, &raceResult.PlaceSource, &raceResult.Status)
 		if err != nil {
 			slog.Error("Error scanning athlete result row", slog.String("meet", rd.race.meet.Name), slog.String("race", rd.race.Name), slog.String("error", err.Error()))
 			continue
 		}
-		raceResult.Time = time.Duration(timeInMillis) * time.Millisecond
+		raceResult.GunTime = time.Duration(timeInMillis) * time.Millisecond
+		raceResult.NetTime = time.Duration(netInMillis) * time.Millisecond
 
 		raceResult.Athlete = athlete
 		raceResults = append(raceResults, raceResult)
 	}
 
+	err = rd.loadSplits(raceResults)
+	if err != nil {
+		return nil, err
+	}
+
 	slog.Info("Finished getting results for race", slog.String("meet", rd.race.meet.Name), slog.String("race", rd.race.Name), slog.Int("count", len(raceResults)))
 
 	return raceResults, nil
 }
+
+// loadSplits fills in the splits for results read from the race
+func (rd *resultData) loadSplits(raceResults []*RaceResult) error {
+	rows, err := rd.db.Query(`SELECT athlete_id, timing_point, split_time FROM split WHERE race_id = $1`, rd.race.id)
+	if err != nil {
+		slog.Error("Error querying splits", slog.String("race", rd.race.Name), slog.String("error", err.Error()))
+		return err
+	}
+	defer rows.Close()
+
+	byAthlete := make(map[int64]*RaceResult, len(raceResults))
+	for _, result := range raceResults {
+		byAthlete[result.Athlete.id] = result
+	}
+
+	for rows.Next() {
+		var athleteId, splitInMillis int64
+		var timingPoint string
+		err := rows.Scan(&athleteId, &timingPoint, &splitInMillis)
+		if err != nil {
+			slog.Error("Error scanning split row", slog.String("race", rd.race.Name), slog.String("error", err.Error()))
+			return err
+		}
+
+		result, found := byAthlete[athleteId]
+		if !found {
+			continue
+		}
+		if result.Splits == nil {
+			result.Splits = make(map[string]time.Duration)
+		}
+		result.Splits[timingPoint] = time.Duration(splitInMillis) * time.Millisecond
+	}
+
+	return rows.Err()
+}
@@ -9,6 +9,8 @@ import (
 )
 
 func TestSaveResult(t *testing.T) {
+	requireDB(t)
+
 	// create a meet
 	meetWriter, err := NewMeetWriter(connectStr)
 	if err != nil {
@@ -68,7 +70,9 @@ func TestSaveResult(t *testing.T) {
 		Athlete:     athlete,
 		Place:       1,
 		PlaceSource: "manual",
-		Time:        time.Second * 10,
+		GunTime:     time.Second * 10,
+		NetTime:     time.Second * 8,
+		Splits:      map[string]time.Duration{"1 mile": time.Second * 4},
 	}
 
 	// create race result writer
@@ -93,5 +97,7 @@ func TestSaveResult(t *testing.T) {
 	assert.Equal(t, raceResult.Bib, results[0].Bib)
 	assert.Equal(t, raceResult.Athlete.id, results[0].Athlete.id)
 	assert.Equal(t, raceResult.Place, results[0].Place)
-	assert.Equal(t, raceResult.Time, results[0].Time)
+	assert.Equal(t, raceResult.GunTime, results[0].GunTime)
+	assert.Equal(t, raceResult.NetTime, results[0].NetTime)
+	assert.Equal(t, raceResult.Splits, results[0].Splits)
 }
@@ -0,0 +1,65 @@
+package meets
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// resultsChannel is the postgres channel SaveResult notifies with the race's name
+const resultsChannel = "race_results"
+
+const (
+	listenMinReconnect = time.Second
+	listenMaxReconnect = time.Minute
+	// listenPingInterval checks the listener's connection is still alive between notifications
+	listenPingInterval = time.Minute
+)
+
+// ListenForResults calls changed with the race name each time a result is saved, until ctx
+// is cancelled.  Notifications sent while the connection was lost are missed, so changed
+// is called with "" after reconnecting and any race's results may have changed.
+func ListenForResults(ctx context.Context, connectStr string, changed func(raceName string)) error {
+	listener := pq.NewListener(connectStr, listenMinReconnect, listenMaxReconnect, func(event pq.ListenerEventType, err error) {
+		if err != nil {
+			slog.Error("Result listener connection error", slog.String("error", err.Error()))
+		}
+	})
+	// closing the listener stops Listen waiting for a connection
+	stop := context.AfterFunc(ctx, func() {
+		listener.Close()
+	})
+	defer stop()
+
+	err := listener.Listen(resultsChannel)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+		listener.Close()
+		return err
+	}
+
+	ping := time.NewTicker(listenPingInterval)
+	defer ping.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case n, ok := <-listener.Notify:
+			if !ok {
+				return nil
+			}
+			if n == nil {
+				changed("")
+				continue
+			}
+			changed(n.Extra)
+		case <-ping.C:
+			// a failed ping makes the listener reconnect
+			listener.Ping()
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package memory_stream
+
+import (
+	"blreynolds4/event-race-timer/internal/stream"
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Store holds named in memory streams. It plays the part of the redis server,
+// every MemoryStream created from the same store and name sees the same messages.
+type Store struct {
+	mu      sync.Mutex
+	streams map[string]*memoryLog
+}
+
+func NewStore() *Store {
+	return &Store{
+		streams: make(map[string]*memoryLog),
+	}
+}
+
+func (s *Store) getLog(name string) *memoryLog {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	l, found := s.streams[name]
+	if !found {
+		l = &memoryLog{
+			added: make(chan struct{}),
+		}
+		s.streams[name] = l
+	}
+	return l
+}
+
+type storedMessage struct {
+	id   stream.MessageID
+	data []byte
+}
+
+// message returns a copy of the stored message, a reader changing its data doesn't change the stream
+func (sm storedMessage) message() stream.Message {
+	data := make([]byte, len(sm.data))
+	copy(data, sm.data)
+	return stream.Message{ID: sm.id.String(), Data: data}
+}
+
+// memoryLog is the append only list of messages for one stream name
+type memoryLog struct {
+	mu       sync.Mutex
+	messages []storedMessage
+	// closed and replaced each time a message is added to wake blocked readers
+	added chan struct{}
+}
+
+func (ml *memoryLog) append(id string) (stream.MessageID, error) {
+	var last stream.MessageID
+	if len(ml.messages) > 0 {
+		last = ml.messages[len(ml.messages)-1].id
+	}
+
+	if id == "" || id == "*" {
+		return stream.NextMessageID(last, time.Now()), nil
+	}
+
+	newId, err := stream.ParseMessageID(id)
+	if err != nil {
+		return newId, err
+	}
+	if newId.IsZero() || !last.Less(newId) {
+		return newId, fmt.Errorf("stream id %s must be greater than %s", newId, last)
+	}
+	return newId, nil
+}
+
+// after returns the index of the first message with an id greater than id
+func (ml *memoryLog) after(id stream.MessageID) int {
+	return sort.Search(len(ml.messages), func(i int) bool {
+		return id.Less(ml.messages[i].id)
+	})
+}
+
+// MemoryStream is a stream.ReaderWriter that keeps messages in memory.
+// Each MemoryStream has its own read position like a RedisStream does,
+// so several readers of the same stream don't interfere with each other.
+type MemoryStream struct {
+	log       *memoryLog
+	lastMsgId stream.MessageID
+}
+
+func NewMemoryStream(s *Store, name string) *MemoryStream {
+	return &MemoryStream{
+		log: s.getLog(name),
+	}
+}
+
+func (ms *MemoryStream) SendMessage(ctx context.Context, sm stream.Message) error {
+	ms.log.mu.Lock()
+	defer ms.log.mu.Unlock()
+
+	id, err := ms.log.append(sm.ID)
+	if err != nil {
+		return err
+	}
+
+	// copy the data so the caller can reuse their buffer
+	data := make([]byte, len(sm.Data))
+	copy(data, sm.Data)
+	ms.log.messages = append(ms.log.messages, storedMessage{id: id, data: data})
+
+	close(ms.log.added)
+	ms.log.added = make(chan struct{})
+
+	return nil
+}
+
+// GetMessage returns the next message after the last one this stream read.
+// Like redis XREAD BLOCK, a timeout of 0 waits forever and a negative timeout doesn't wait.
+func (ms *MemoryStream) GetMessage(ctx context.Context, timeout time.Duration, resultMsg *stream.Message) (bool, error) {
+	var expired <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+
+	for {
+		ms.log.mu.Lock()
+		i := ms.log.after(ms.lastMsgId)
+		if i < len(ms.log.messages) {
+			found := ms.log.messages[i]
+			ms.log.mu.Unlock()
+
+			*resultMsg = found.message()
+			ms.lastMsgId = found.id
+			return true, nil
+		}
+		added := ms.log.added
+		ms.log.mu.Unlock()
+
+		if timeout < 0 {
+			return false, nil
+		}
+
+		select {
+		case <-added:
+		case <-expired:
+			return false, nil
+		case <-ctx.Done():
+			return false, ctx.Err()
+		}
+	}
+}
+
+func (ms *MemoryStream) SeekAfter(id string) error {
+	seekId, err := stream.ParseMessageID(id)
+	if err != nil {
+		return err
+	}
+	ms.lastMsgId = seekId
+	return nil
+}
+
+func (ms *MemoryStream) RangeQueryMin() string {
+	return stream.RangeQueryMin()
+}
+
+func (ms *MemoryStream) ExclusiveQueryStart(id string) string {
+	return stream.ExclusiveQueryStart(id)
+}
+
+func (ms *MemoryStream) RangeQueryMax() string {
+	return stream.RangeQueryMax()
+}
+
+func (ms *MemoryStream) GetMessageRange(ctx context.Context, startId, endId string, resultMessages []stream.Message) (int, error) {
+	if len(resultMessages) == 0 {
+		return 0, fmt.Errorf("can't get message range with empty buffer")
+	}
+
+	start, end, err := stream.RangeBounds(startId, endId)
+	if err != nil {
+		return 0, err
+	}
+
+	ms.log.mu.Lock()
+	defer ms.log.mu.Unlock()
+
+	resultCount := 0
+	i := sort.Search(len(ms.log.messages), func(i int) bool {
+		return !ms.log.messages[i].id.Less(start)
+	})
+	for ; i < len(ms.log.messages) && resultCount < len(resultMessages); i++ {
+		found := ms.log.messages[i]
+		if end.Less(found.id) {
+			break
+		}
+		resultMessages[resultCount] = found.message()
+		resultCount++
+	}
+
+	return resultCount, nil
+}
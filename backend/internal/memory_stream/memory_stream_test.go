@@ -0,0 +1,280 @@
+package memory_stream
+
+import (
+	"blreynolds4/event-race-timer/internal/stream"
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func sendMessages(t *testing.T, ms *MemoryStream, count int) {
+	for i := 0; i < count; i++ {
+		err := ms.SendMessage(context.TODO(), stream.Message{Data: []byte(fmt.Sprintf("msg%d", i))})
+		assert.NoError(t, err)
+	}
+}
+
+func TestSendGetMessage(t *testing.T) {
+	ms := NewMemoryStream(NewStore(), t.Name())
+
+	sendMessages(t, ms, 2)
+
+	var first, second stream.Message
+	gotMsg, err := ms.GetMessage(context.TODO(), time.Second, &first)
+	assert.NoError(t, err)
+	assert.True(t, gotMsg)
+	assert.Equal(t, []byte("msg0"), first.Data)
+
+	gotMsg, err = ms.GetMessage(context.TODO(), time.Second, &second)
+	assert.NoError(t, err)
+	assert.True(t, gotMsg)
+	assert.Equal(t, []byte("msg1"), second.Data)
+
+	firstId, err := stream.ParseMessageID(first.ID)
+	assert.NoError(t, err)
+	secondId, err := stream.ParseMessageID(second.ID)
+	assert.NoError(t, err)
+	assert.True(t, firstId.Less(secondId))
+}
+
+func TestReadersGetCopies(t *testing.T) {
+	store := NewStore()
+	ms := NewMemoryStream(store, t.Name())
+	sendMessages(t, ms, 1)
+
+	// changing what was read doesn't change the stream for other reads
+	var msg stream.Message
+	gotMsg, err := ms.GetMessage(context.TODO(), time.Second, &msg)
+	assert.NoError(t, err)
+	assert.True(t, gotMsg)
+	msg.Data[0] = 'X'
+
+	messages := make([]stream.Message, 1)
+	count, err := ms.GetMessageRange(context.TODO(), ms.RangeQueryMin(), ms.RangeQueryMax(), messages)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, []byte("msg0"), messages[0].Data)
+	messages[0].Data[0] = 'Y'
+
+	var again stream.Message
+	gotMsg, err = NewMemoryStream(store, t.Name()).GetMessage(context.TODO(), time.Second, &again)
+	assert.NoError(t, err)
+	assert.True(t, gotMsg)
+	assert.Equal(t, []byte("msg0"), again.Data)
+}
+
+func TestGetMessageNoWait(t *testing.T) {
+	ms := NewMemoryStream(NewStore(), t.Name())
+
+	var msg stream.Message
+	gotMsg, err := ms.GetMessage(context.TODO(), -1, &msg)
+	assert.NoError(t, err)
+	assert.False(t, gotMsg)
+}
+
+func TestGetMessageTimeout(t *testing.T) {
+	ms := NewMemoryStream(NewStore(), t.Name())
+
+	var msg stream.Message
+	start := time.Now()
+	gotMsg, err := ms.GetMessage(context.TODO(), 20*time.Millisecond, &msg)
+	assert.NoError(t, err)
+	assert.False(t, gotMsg)
+	assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+}
+
+func TestGetMessageCancelled(t *testing.T) {
+	ms := NewMemoryStream(NewStore(), t.Name())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var msg stream.Message
+	gotMsg, err := ms.GetMessage(ctx, 0, &msg)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.False(t, gotMsg)
+}
+
+func TestGetMessageBlocksUntilSend(t *testing.T) {
+	store := NewStore()
+	reader := NewMemoryStream(store, t.Name())
+	writer := NewMemoryStream(store, t.Name())
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	var msg stream.Message
+	var gotMsg bool
+	var err error
+	go func() {
+		defer wg.Done()
+		gotMsg, err = reader.GetMessage(context.TODO(), 0, &msg)
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	sendMessages(t, writer, 1)
+	wg.Wait()
+
+	assert.NoError(t, err)
+	assert.True(t, gotMsg)
+	assert.Equal(t, []byte("msg0"), msg.Data)
+}
+
+func TestIndependentReaders(t *testing.T) {
+	store := NewStore()
+	writer := NewMemoryStream(store, t.Name())
+	sendMessages(t, writer, 3)
+
+	for _, reader := range []*MemoryStream{NewMemoryStream(store, t.Name()), NewMemoryStream(store, t.Name())} {
+		for i := 0; i < 3; i++ {
+			var msg stream.Message
+			gotMsg, err := reader.GetMessage(context.TODO(), -1, &msg)
+			assert.NoError(t, err)
+			assert.True(t, gotMsg)
+			assert.Equal(t, []byte(fmt.Sprintf("msg%d", i)), msg.Data)
+		}
+	}
+
+	// other stream names are separate
+	other := NewMemoryStream(store, t.Name()+"-other")
+	var msg stream.Message
+	gotMsg, err := other.GetMessage(context.TODO(), -1, &msg)
+	assert.NoError(t, err)
+	assert.False(t, gotMsg)
+}
+
+func TestSendMessageWithId(t *testing.T) {
+	ms := NewMemoryStream(NewStore(), t.Name())
+
+	err := ms.SendMessage(context.TODO(), stream.Message{ID: "5-1", Data: []byte("a")})
+	assert.NoError(t, err)
+
+	// ids have to increase
+	err = ms.SendMessage(context.TODO(), stream.Message{ID: "5-1", Data: []byte("b")})
+	assert.Error(t, err)
+	err = ms.SendMessage(context.TODO(), stream.Message{ID: "4-9", Data: []byte("b")})
+	assert.Error(t, err)
+	err = ms.SendMessage(context.TODO(), stream.Message{ID: "bad", Data: []byte("b")})
+	assert.Error(t, err)
+
+	err = ms.SendMessage(context.TODO(), stream.Message{ID: "6", Data: []byte("c")})
+	assert.NoError(t, err)
+
+	var msg stream.Message
+	ms.GetMessage(context.TODO(), -1, &msg)
+	assert.Equal(t, "5-1", msg.ID)
+	ms.GetMessage(context.TODO(), -1, &msg)
+	assert.Equal(t, "6-0", msg.ID)
+}
+
+func TestGetMessageRange(t *testing.T) {
+	ms := NewMemoryStream(NewStore(), t.Name())
+	for _, id := range []string{"1-0", "1-1", "2-0", "3-5"} {
+		err := ms.SendMessage(context.TODO(), stream.Message{ID: id, Data: []byte(id)})
+		assert.NoError(t, err)
+	}
+
+	tests := []struct {
+		start    string
+		end      string
+		expected []string
+	}{
+		{ms.RangeQueryMin(), ms.RangeQueryMax(), []string{"1-0", "1-1", "2-0", "3-5"}},
+		{"1-1", "2-0", []string{"1-1", "2-0"}},
+		{ms.ExclusiveQueryStart("1-1"), ms.RangeQueryMax(), []string{"2-0", "3-5"}},
+		{"1", "1", []string{"1-0", "1-1"}},
+		{"2-0", "2-0", []string{"2-0"}},
+		{ms.ExclusiveQueryStart("3-5"), ms.RangeQueryMax(), []string{}},
+		{"4", ms.RangeQueryMax(), []string{}},
+	}
+
+	for _, test := range tests {
+		buffer := make([]stream.Message, 10)
+		count, err := ms.GetMessageRange(context.TODO(), test.start, test.end, buffer)
+		assert.NoError(t, err)
+		actual := make([]string, count)
+		for i := 0; i < count; i++ {
+			actual[i] = buffer[i].ID
+			assert.Equal(t, []byte(buffer[i].ID), buffer[i].Data)
+		}
+		assert.Equal(t, test.expected, actual, "range %s %s", test.start, test.end)
+	}
+}
+
+func TestGetMessageRangeLimitedByBuffer(t *testing.T) {
+	ms := NewMemoryStream(NewStore(), t.Name())
+	sendMessages(t, ms, 5)
+
+	buffer := make([]stream.Message, 2)
+	count, err := ms.GetMessageRange(context.TODO(), ms.RangeQueryMin(), ms.RangeQueryMax(), buffer)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	count, err = ms.GetMessageRange(context.TODO(), ms.ExclusiveQueryStart(buffer[1].ID), ms.RangeQueryMax(), buffer)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+	assert.Equal(t, []byte("msg2"), buffer[0].Data)
+}
+
+func TestGetMessageRangeErrors(t *testing.T) {
+	ms := NewMemoryStream(NewStore(), t.Name())
+
+	_, err := ms.GetMessageRange(context.TODO(), ms.RangeQueryMin(), ms.RangeQueryMax(), []stream.Message{})
+	assert.Error(t, err)
+
+	_, err = ms.GetMessageRange(context.TODO(), "bad", ms.RangeQueryMax(), make([]stream.Message, 1))
+	assert.Error(t, err)
+}
+
+func TestConcurrentWriters(t *testing.T) {
+	store := NewStore()
+	var wg sync.WaitGroup
+	for w := 0; w < 5; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			writer := NewMemoryStream(store, t.Name())
+			for i := 0; i < 20; i++ {
+				assert.NoError(t, writer.SendMessage(context.TODO(), stream.Message{Data: []byte("x")}))
+			}
+		}()
+	}
+	wg.Wait()
+
+	reader := NewMemoryStream(store, t.Name())
+	var last stream.MessageID
+	count := 0
+	var msg stream.Message
+	for {
+		gotMsg, err := reader.GetMessage(context.TODO(), -1, &msg)
+		assert.NoError(t, err)
+		if !gotMsg {
+			break
+		}
+		id, err := stream.ParseMessageID(msg.ID)
+		assert.NoError(t, err)
+		assert.True(t, last.Less(id))
+		last = id
+		count++
+	}
+	assert.Equal(t, 100, count)
+}
+
+func TestSeekAfter(t *testing.T) {
+	ms := NewMemoryStream(NewStore(), t.Name())
+	for _, id := range []string{"1-0", "2-0", "3-0"} {
+		assert.NoError(t, ms.SendMessage(context.TODO(), stream.Message{ID: id, Data: []byte(id)}))
+	}
+
+	assert.NoError(t, ms.SeekAfter("2-0"))
+	var msg stream.Message
+	gotMsg, err := ms.GetMessage(context.TODO(), -1, &msg)
+	assert.NoError(t, err)
+	assert.True(t, gotMsg)
+	assert.Equal(t, "3-0", msg.ID)
+
+	assert.Error(t, ms.SeekAfter("bad"))
+}
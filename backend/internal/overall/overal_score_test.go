@@ -15,6 +15,9 @@ import (
 )
 
 func TestOverallResultsSimple(t *testing.T) {
+	// the scorer writes its html results to the working directory
+	t.Chdir(t.TempDir())
+
 	athletes := make(meets.AthleteLookup)
 	athletes[1] = meets.NewAthlete("JS", "1", "JS", "DAID", 1, "m")
 	athletes[10] = meets.NewAthlete("Leb", "1", "Leb", "DAID", 1, "m")
@@ -27,10 +30,10 @@ func TestOverallResultsSimple(t *testing.T) {
 	}
 	mock := results.NewResultStream(mockEventStream)
 
-	mockEventStream.Events = append(mockEventStream.Events, toMsg(meets.RaceResult{Bib: 1, Athlete: athletes[1], Place: 1, Time: durationHelper("25m2s")}))
-	mockEventStream.Events = append(mockEventStream.Events, toMsg(meets.RaceResult{Bib: 10, Athlete: athletes[10], Place: 2, Time: durationHelper("26m40s")}))
-	mockEventStream.Events = append(mockEventStream.Events, toMsg(meets.RaceResult{Bib: 11, Athlete: athletes[11], Place: 3, Time: durationHelper("27m45s")}))
-	mockEventStream.Events = append(mockEventStream.Events, toMsg(meets.RaceResult{Bib: 23, Athlete: athletes[23], Place: 4, Time: durationHelper("37m46s")}))
+	mockEventStream.Events = append(mockEventStream.Events, toMsg(meets.RaceResult{Bib: 1, Athlete: athletes[1], Place: 1, GunTime: durationHelper("25m2s")}))
+	mockEventStream.Events = append(mockEventStream.Events, toMsg(meets.RaceResult{Bib: 10, Athlete: athletes[10], Place: 2, GunTime: durationHelper("26m40s")}))
+	mockEventStream.Events = append(mockEventStream.Events, toMsg(meets.RaceResult{Bib: 11, Athlete: athletes[11], Place: 3, GunTime: durationHelper("27m45s")}))
+	mockEventStream.Events = append(mockEventStream.Events, toMsg(meets.RaceResult{Bib: 23, Athlete: athletes[23], Place: 4, GunTime: durationHelper("37m46s")}))
 
 	// expected scoring
 	// start with just the scores, fill the rest in
@@ -62,7 +65,7 @@ func TestOverallResultsSimple(t *testing.T) {
 	}
 
 	// XCScorer has team results in an array
-	OVR := NewOverallResults(slog.Default())
+	OVR := NewOverallResults(t.Name(), slog.Default())
 	err := OVR.ScoreResults(context.TODO(), mock)
 	assert.NoError(t, err)
 
@@ -70,6 +73,8 @@ func TestOverallResultsSimple(t *testing.T) {
 }
 
 func TestOverallResultsDuplicate(t *testing.T) {
+	t.Chdir(t.TempDir())
+
 	athletes := make(meets.AthleteLookup)
 	athletes[1] = meets.NewAthlete("JS", "1", "JS", "DAID", 1, "m")
 	athletes[10] = meets.NewAthlete("Leb", "1", "Leb", "DAID", 1, "m")
@@ -83,14 +88,14 @@ func TestOverallResultsDuplicate(t *testing.T) {
 	mock := results.NewResultStream(mockEventStream)
 
 	// this way
-	mockEventStream.Events = append(mockEventStream.Events, toMsg(meets.RaceResult{Bib: 1, Athlete: athletes[1], Place: 1, Time: durationHelper("25m2s")}))
+	mockEventStream.Events = append(mockEventStream.Events, toMsg(meets.RaceResult{Bib: 1, Athlete: athletes[1], Place: 1, GunTime: durationHelper("25m2s")}))
 
 	// convert these to ^^^
-	mockEventStream.Events = append(mockEventStream.Events, toMsg(meets.RaceResult{Bib: 1, Athlete: athletes[1], Place: 1, Time: durationHelper("25m2s")}))
-	mockEventStream.Events = append(mockEventStream.Events, toMsg(meets.RaceResult{Bib: 1, Athlete: athletes[1], Place: 1, Time: durationHelper("22m2s")}))
-	mockEventStream.Events = append(mockEventStream.Events, toMsg(meets.RaceResult{Bib: 10, Athlete: athletes[10], Place: 2, Time: durationHelper("26m40s")}))
-	mockEventStream.Events = append(mockEventStream.Events, toMsg(meets.RaceResult{Bib: 11, Athlete: athletes[11], Place: 3, Time: durationHelper("27m45s")}))
-	mockEventStream.Events = append(mockEventStream.Events, toMsg(meets.RaceResult{Bib: 23, Athlete: athletes[23], Place: 4, Time: durationHelper("37m46s")}))
+	mockEventStream.Events = append(mockEventStream.Events, toMsg(meets.RaceResult{Bib: 1, Athlete: athletes[1], Place: 1, GunTime: durationHelper("25m2s")}))
+	mockEventStream.Events = append(mockEventStream.Events, toMsg(meets.RaceResult{Bib: 1, Athlete: athletes[1], Place: 1, GunTime: durationHelper("22m2s")}))
+	mockEventStream.Events = append(mockEventStream.Events, toMsg(meets.RaceResult{Bib: 10, Athlete: athletes[10], Place: 2, GunTime: durationHelper("26m40s")}))
+	mockEventStream.Events = append(mockEventStream.Events, toMsg(meets.RaceResult{Bib: 11, Athlete: athletes[11], Place: 3, GunTime: durationHelper("27m45s")}))
+	mockEventStream.Events = append(mockEventStream.Events, toMsg(meets.RaceResult{Bib: 23, Athlete: athletes[23], Place: 4, GunTime: durationHelper("37m46s")}))
 
 	// expected scoring
 	// start with just the scores, fill the rest in
@@ -122,7 +127,7 @@ func TestOverallResultsDuplicate(t *testing.T) {
 	}
 
 	// XCScorer has team results in an array
-	OVR := NewOverallResults(slog.Default())
+	OVR := NewOverallResults(t.Name(), slog.Default())
 	err := OVR.ScoreResults(context.TODO(), mock)
 	assert.NoError(t, err)
 
@@ -130,6 +135,8 @@ func TestOverallResultsDuplicate(t *testing.T) {
 }
 
 func TestOverallResultsError(t *testing.T) {
+	t.Chdir(t.TempDir())
+
 	athletes := make(meets.AthleteLookup)
 	athletes[1] = meets.NewAthlete("JS", "1", "JS", "DAID", 1, "m")
 	athletes[10] = meets.NewAthlete("Leb", "1", "Leb", "DAID", 1, "m")
@@ -142,14 +149,14 @@ func TestOverallResultsError(t *testing.T) {
 	}
 	mock := results.NewResultStream(mockEventStream)
 
-	mockEventStream.Events = append(mockEventStream.Events, toMsg(meets.RaceResult{Bib: 1, Athlete: athletes[1], Place: 1, Time: durationHelper("25m2s")}))
-	mockEventStream.Events = append(mockEventStream.Events, toMsg(meets.RaceResult{Bib: 1, Athlete: athletes[1], Place: 1, Time: durationHelper("22m2s")}))
-	mockEventStream.Events = append(mockEventStream.Events, toMsg(meets.RaceResult{Bib: 10, Athlete: athletes[10], Place: 2, Time: durationHelper("26m40s")}))
-	mockEventStream.Events = append(mockEventStream.Events, toMsg(meets.RaceResult{Bib: 11, Athlete: athletes[11], Place: 3, Time: durationHelper("27m45s")}))
-	mockEventStream.Events = append(mockEventStream.Events, toMsg(meets.RaceResult{Bib: 23, Athlete: athletes[23], Place: 4, Time: durationHelper("37m46s")}))
+	mockEventStream.Events = append(mockEventStream.Events, toMsg(meets.RaceResult{Bib: 1, Athlete: athletes[1], Place: 1, GunTime: durationHelper("25m2s")}))
+	mockEventStream.Events = append(mockEventStream.Events, toMsg(meets.RaceResult{Bib: 1, Athlete: athletes[1], Place: 1, GunTime: durationHelper("22m2s")}))
+	mockEventStream.Events = append(mockEventStream.Events, toMsg(meets.RaceResult{Bib: 10, Athlete: athletes[10], Place: 2, GunTime: durationHelper("26m40s")}))
+	mockEventStream.Events = append(mockEventStream.Events, toMsg(meets.RaceResult{Bib: 11, Athlete: athletes[11], Place: 3, GunTime: durationHelper("27m45s")}))
+	mockEventStream.Events = append(mockEventStream.Events, toMsg(meets.RaceResult{Bib: 23, Athlete: athletes[23], Place: 4, GunTime: durationHelper("37m46s")}))
 
 	// XCScorer has team results in an array
-	OVR := NewOverallResults(slog.Default())
+	OVR := NewOverallResults(t.Name(), slog.Default())
 	err := OVR.ScoreResults(context.TODO(), mock)
 
 	assert.Error(t, fmt.Errorf("fail"), err)
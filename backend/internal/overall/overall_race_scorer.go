@@ -0,0 +1,176 @@
+package overall
+
+import (
+	"blreynolds4/event-race-timer/internal/config"
+	"blreynolds4/event-race-timer/internal/meets"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// HTMLFile is the file a race's overall results are written to, overall_<race>.html with
+// anything but letters, digits, - and _ in the race name changed to _
+func HTMLFile(raceName string) string {
+	name := strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '-' || r == '_' {
+			return r
+		}
+		return '_'
+	}, raceName)
+	return "overall_" + name + ".html"
+}
+
+type OverallRaceScorer struct {
+	race   *meets.Race
+	rankBy string
+	course config.Course
+	logger *slog.Logger
+	// Results are the placed runners in overall order from the last ScoreResults
+	Results []OverallResult
+	// Quiet only writes the html results, stdout isn't cleared and printed to
+	Quiet bool
+}
+
+// NewOverallRaceResults scores a race in place order, or by net time when rankBy is meets.RankByNet.
+// Each runner's splits and pace are shown for the course's timing points.
+func NewOverallRaceResults(race *meets.Race, rankBy string, course config.Course, l *slog.Logger) OverallRaceScorer {
+	return OverallRaceScorer{
+		race:   race,
+		rankBy: rankBy,
+		course: course,
+		logger: l.With("scorer", "overall"),
+	}
+}
+
+func (ovr *OverallRaceScorer) ScoreResults(ctx context.Context, resultsReader meets.RaceResultReader) error {
+	// get results for the race the resultsReader was created for.
+	// results are returned in place order
+	ovr.logger.Info("Building overall...")
+	raceResults, err := resultsReader.GetRaceResults()
+	if err != nil {
+		ovr.logger.Error("overall race scorer error", "error", err)
+		return fmt.Errorf("overall race scorer error %w", err)
+	}
+
+	// DNF, DNS and DQ runners are listed after the places
+	overallResults, unplacedResults := RankResults(raceResults, ovr.rankBy)
+	ovr.Results = overallResults
+
+	f, err := os.Create(HTMLFile(ovr.race.Name))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	// write an html header just to the file
+	_, err = f.WriteString("<html><head><title>" + ovr.race.Name + " Overall Results</title></head><body><pre>\n")
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = f
+	if !ovr.Quiet {
+		w = io.MultiWriter(f, os.Stdout)
+		fmt.Printf("%s", "\x1Bc") // clear stdout
+		fmt.Printf("Last Updated: %s\n", time.Now().Format("2006-01-02 15:04:05"))
+	}
+	fmt.Fprintf(w, "\n\n\n")
+	header := "Place Bib   Name                             Grade Team                             "
+	underline := "===== ===== ================================ ===== ================================ "
+	if ovr.rankBy == meets.RankByNet {
+		header += "Net Time Gun Time"
+		underline += "======== ========"
+	} else {
+		header += "Time    "
+		underline += "========"
+	}
+	if ovr.course.Distance > 0 {
+		header += fmt.Sprintf(" %-8s", "Pace")
+		underline += " ========"
+	}
+	for _, tp := range ovr.course.TimingPoints {
+		header += fmt.Sprintf(" %-17s", tp.Name)
+		underline += " ================="
+	}
+	fmt.Fprintln(w, strings.TrimRight(header, " "))
+	fmt.Fprintln(w, underline)
+
+	for _, r := range overallResults {
+		fmt.Fprintf(w, "%-5d %-5d %-32s %-5d %-32s %-8s", r.Place, r.Bib, r.Athlete.Name(), r.Athlete.Grade, r.Athlete.Team, formatFinishTime(rankTime(r, ovr.rankBy)))
+		if ovr.rankBy == meets.RankByNet {
+			fmt.Fprintf(w, " %-8s", formatFinishTime(r.Finishtime))
+		}
+		if ovr.course.Distance > 0 {
+			fmt.Fprintf(w, " %-8s", formatPace(rankTime(r, ovr.rankBy), ovr.course.Distance))
+		}
+		// split time and the pace to that point
+		for _, tp := range ovr.course.TimingPoints {
+			split, found := r.Splits[tp.Name]
+			if !found {
+				fmt.Fprintf(w, " %-17s", "")
+				continue
+			}
+			fmt.Fprintf(w, " %-8s %-8s", formatFinishTime(split), formatPace(split, tp.Distance))
+		}
+		fmt.Fprintln(w)
+	}
+	for _, r := range unplacedResults {
+		fmt.Fprintf(w, "%-5s %-5d %-32s %-5d %-32s %-8s\n", r.Status, r.Bib, r.Athlete.Name(), r.Athlete.Grade, r.Athlete.Team, "")
+	}
+
+	_, err = f.WriteString("\n</pre></body></html>\n")
+	if err != nil {
+		return err
+	}
+	ovr.logger.Info("Done Building overall")
+
+	return nil
+}
+
+// RankResults puts a race's results, read in place order, in overall order.  Runners with a
+// DNF, DNS or DQ are returned separately from the placed runners.
+func RankResults(raceResults []*meets.RaceResult, rankBy string) (placed []OverallResult, unplaced []OverallResult) {
+	placed = make([]OverallResult, 0, len(raceResults))
+	unplaced = make([]OverallResult, 0)
+	for _, result := range raceResults {
+		r := OverallResult{Athlete: result.Athlete, Place: result.Place, Finishtime: result.GunTime, NetTime: result.NetTime, Splits: result.Splits, Bib: result.Bib, Status: result.Status}
+		if result.HasStatus() {
+			unplaced = append(unplaced, r)
+		} else {
+			placed = append(placed, r)
+		}
+	}
+
+	// road races are ranked by the time from the start mat, not the order across the line
+	if rankBy == meets.RankByNet {
+		sort.SliceStable(placed, func(i, j int) bool {
+			return rankTime(placed[i], rankBy) < rankTime(placed[j], rankBy)
+		})
+		for i := range placed {
+			placed[i].Place = i + 1
+		}
+	}
+	return placed, unplaced
+}
+
+// formatPace is the time per mile to cover miles in t, blank without a distance
+func formatPace(t time.Duration, miles float64) string {
+	if miles <= 0 {
+		return ""
+	}
+	pace := time.Duration(float64(t) / miles).Round(time.Second)
+	return fmt.Sprintf("%d:%02d/mi", int(pace.Minutes()), int(pace.Seconds())%60)
+}
+
+// rankTime is the net time when ranking by net, results without a start mat read use the gun time
+func rankTime(r OverallResult, rankBy string) time.Duration {
+	if rankBy == meets.RankByNet && r.NetTime > 0 {
+		return r.NetTime
+	}
+	return r.Finishtime
+}
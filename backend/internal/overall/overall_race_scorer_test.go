@@ -0,0 +1,101 @@
+package overall
+
+import (
+	"blreynolds4/event-race-timer/internal/config"
+	"blreynolds4/event-race-timer/internal/meets"
+	"context"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOverallRaceResultsRankByNet(t *testing.T) {
+	// the scorer writes its html results to the working directory
+	t.Chdir(t.TempDir())
+
+	athletes := make(meets.AthleteLookup)
+	athletes[1] = meets.NewAthlete("A", "1", "A", "DAID1", 1, "m")
+	athletes[2] = meets.NewAthlete("B", "2", "B", "DAID2", 1, "m")
+	athletes[3] = meets.NewAthlete("C", "3", "C", "DAID3", 1, "m")
+
+	// bib 2 started at the back so crossed the line second with the fastest net time,
+	// bib 3 has no start mat read
+	reader := &meets.MockResultReader{Results: []*meets.RaceResult{
+		{Bib: 1, Athlete: athletes[1], Place: 1, GunTime: durationHelper("20m0s"), NetTime: durationHelper("19m50s")},
+		{Bib: 2, Athlete: athletes[2], Place: 2, GunTime: durationHelper("20m10s"), NetTime: durationHelper("19m30s")},
+		{Bib: 3, Athlete: athletes[3], Place: 3, GunTime: durationHelper("19m40s")},
+	}}
+
+	scorer := NewOverallRaceResults(&meets.Race{Name: t.Name()}, meets.RankByNet, config.Course{}, slog.Default())
+	err := scorer.ScoreResults(context.TODO(), reader)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 3, len(scorer.Results))
+	assert.Equal(t, []int{2, 3, 1}, []int{scorer.Results[0].Bib, scorer.Results[1].Bib, scorer.Results[2].Bib})
+	assert.Equal(t, []int{1, 2, 3}, []int{scorer.Results[0].Place, scorer.Results[1].Place, scorer.Results[2].Place})
+
+	// gun ranking keeps the finish order
+	scorer = NewOverallRaceResults(&meets.Race{Name: t.Name()}, meets.RankByGun, config.Course{}, slog.Default())
+	err = scorer.ScoreResults(context.TODO(), reader)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, scorer.Results[0].Bib)
+	assert.Equal(t, 2, scorer.Results[1].Bib)
+}
+
+func TestOverallRaceResultsSplits(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	athlete := meets.NewAthlete("A", "1", "A", "DAID1", 1, "m")
+	splits := map[string]time.Duration{"1 mile": durationHelper("5m30s")}
+	reader := &meets.MockResultReader{Results: []*meets.RaceResult{
+		{Bib: 1, Athlete: athlete, Place: 1, GunTime: durationHelper("17m0s"), Splits: splits},
+	}}
+
+	course := config.Course{
+		Distance: 3.1,
+		TimingPoints: []config.TimingPoint{
+			{Name: "1 mile", Distance: 1},
+			{Name: "2 mile", Distance: 2},
+		},
+	}
+	scorer := NewOverallRaceResults(&meets.Race{Name: t.Name()}, meets.RankByGun, course, slog.Default())
+	err := scorer.ScoreResults(context.TODO(), reader)
+	assert.NoError(t, err)
+	assert.Equal(t, splits, scorer.Results[0].Splits)
+}
+
+func TestOverallRaceResultsQuiet(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	athlete := meets.NewAthlete("A", "1", "A", "DAID1", 1, "m")
+	reader := &meets.MockResultReader{Results: []*meets.RaceResult{
+		{Bib: 1, Athlete: athlete, Place: 1, GunTime: durationHelper("17m0s")},
+	}}
+
+	// quiet still writes the html results
+	scorer := NewOverallRaceResults(&meets.Race{Name: t.Name()}, meets.RankByGun, config.Course{}, slog.Default())
+	scorer.Quiet = true
+	err := scorer.ScoreResults(context.TODO(), reader)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(scorer.Results))
+
+	html, err := os.ReadFile(HTMLFile(t.Name()))
+	assert.NoError(t, err)
+	assert.Contains(t, string(html), "A 1")
+	assert.NotContains(t, string(html), "Last Updated")
+}
+
+func TestFormatPace(t *testing.T) {
+	assert.Equal(t, "5:30/mi", formatPace(durationHelper("5m30s"), 1))
+	assert.Equal(t, "5:29/mi", formatPace(durationHelper("17m0s"), 3.1))
+	assert.Equal(t, "", formatPace(durationHelper("17m0s"), 0))
+}
+
+func TestHTMLFile(t *testing.T) {
+	assert.Equal(t, "overall_Varsity.html", HTMLFile("Varsity"))
+	assert.Equal(t, "overall_JV_Girls_5K.html", HTMLFile("JV Girls/5K"))
+	assert.Equal(t, "overall____.html", HTMLFile("../"))
+}
@@ -14,6 +14,7 @@ import (
 const resultChunkSize = 25
 
 type OverallScorer struct {
+	raceName       string
 	logger         *slog.Logger
 	overallResults []OverallResult
 	rawResults     map[int]meets.RaceResult // bib to result, keep latest result
@@ -22,12 +23,17 @@ type OverallScorer struct {
 type OverallResult struct {
 	Athlete    *meets.Athlete
 	Finishtime time.Duration
+	NetTime    time.Duration
+	Splits     map[string]time.Duration
 	Place      int
 	Bib        int
+	Status     string
 }
 
-func NewOverallResults(l *slog.Logger) OverallScorer {
+// NewOverallResults scores the results streamed for a race, the html results are written to HTMLFile(raceName)
+func NewOverallResults(raceName string, l *slog.Logger) OverallScorer {
 	return OverallScorer{
+		raceName:       raceName,
 		logger:         l.With("scorer", "overall"),
 		overallResults: make([]OverallResult, 0),
 		rawResults:     make(map[int]meets.RaceResult),
@@ -50,8 +56,12 @@ func (ovr *OverallScorer) ScoreResults(ctx context.Context, source results.Resul
 		// add any new results read to the raw storage
 		for i := 0; i < resultCount; i++ {
 			newResult := results[i]
+			if newResult.IsRemoved() {
+				delete(ovr.rawResults, newResult.Bib)
+				continue
+			}
 			ovr.rawResults[newResult.Bib] = newResult
-			ovr.logger.Debug("overall scorer adding result", "bib", newResult.Bib, "time", newResult.Time, "place", newResult.Place)
+			ovr.logger.Debug("overall scorer adding result", "bib", newResult.Bib, "time", newResult.GunTime, "place", newResult.Place)
 		}
 
 		resultCount, err = source.GetResults(ctx, results)
@@ -63,20 +73,20 @@ func (ovr *OverallScorer) ScoreResults(ctx context.Context, source results.Resul
 	// no more results, so we can
 	// build the output
 	for _, result := range ovr.rawResults {
-		placeMap[result.Place] = OverallResult{Athlete: result.Athlete, Place: result.Place, Finishtime: result.Time, Bib: result.Bib}
+		placeMap[result.Place] = OverallResult{Athlete: result.Athlete, Place: result.Place, Finishtime: result.GunTime, Bib: result.Bib}
 	}
 
 	//output in the correct order
 	ovr.overallResults = make([]OverallResult, 0)
 
-	f, err := os.Create("overall_results.html")
+	f, err := os.Create(HTMLFile(ovr.raceName))
 	if err != nil {
 		return err
 	}
 	defer f.Close()
 
 	// write an html header just to the file
-	_, err = f.WriteString("<html><head><title>Overall Results</title></head><body><pre>\n")
+	_, err = f.WriteString("<html><head><title>" + ovr.raceName + " Overall Results</title></head><body><pre>\n")
 	if err != nil {
 		return err
 	}
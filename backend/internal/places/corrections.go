@@ -0,0 +1,123 @@
+package places
+
+import (
+	"blreynolds4/event-race-timer/internal/meets"
+	"blreynolds4/event-race-timer/internal/raceevents"
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+)
+
+// Correction is a place event that fixes a runner's place, place 0 takes their place away
+type Correction struct {
+	Bib      int    `json:"bib"`
+	Place    int    `json:"place"`
+	OldPlace int    `json:"oldPlace"`
+	Source   string `json:"source"`
+}
+
+// placedResults are the runners with a place in place order.  Runners with the same place
+// are in finish time order, the ones without a finish time last, then bib order.
+func placedResults(results []*meets.RaceResult) []*meets.RaceResult {
+	placed := make([]*meets.RaceResult, 0, len(results))
+	for _, result := range results {
+		if result.Place > 0 && !result.HasStatus() {
+			placed = append(placed, result)
+		}
+	}
+	slices.SortStableFunc(placed, func(a, b *meets.RaceResult) int {
+		if c := cmp.Compare(a.Place, b.Place); c != 0 {
+			return c
+		}
+		if (a.GunTime == 0) != (b.GunTime == 0) {
+			// no finish time goes after a finish time
+			if a.GunTime == 0 {
+				return 1
+			}
+			return -1
+		}
+		if c := cmp.Compare(a.GunTime, b.GunTime); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Bib, b.Bib)
+	})
+	return placed
+}
+
+// correction is the place event that gives result place.  It's sent with source, the manual
+// source fixing the places, so a placer ranked below it can't put the old place back.
+func correction(result *meets.RaceResult, place int, source string) Correction {
+	return Correction{Bib: result.Bib, Place: place, OldPlace: result.Place, Source: source}
+}
+
+// renumber places the runners 1 to n in the order they're in, only the runners whose place
+// changes get a correction
+func renumber(ordered []*meets.RaceResult, source string) []Correction {
+	corrections := make([]Correction, 0)
+	for i, result := range ordered {
+		if result.Place != i+1 {
+			corrections = append(corrections, correction(result, i+1, source))
+		}
+	}
+	return corrections
+}
+
+// findResult is the result for bib, an error when the runner isn't in the results or can't be placed
+func findResult(results []*meets.RaceResult, bib int) (*meets.RaceResult, error) {
+	i := slices.IndexFunc(results, func(rr *meets.RaceResult) bool { return rr.Bib == bib })
+	if i < 0 {
+		return nil, fmt.Errorf("bib %d has no result", bib)
+	}
+	if results[i].HasStatus() {
+		return nil, fmt.Errorf("bib %d is %s and can't be placed", bib, results[i].Status)
+	}
+	return results[i], nil
+}
+
+// Renumber fixes duplicate places and gaps by placing the runners with a place 1 to n.
+// Runners with the same place keep their finish time order.
+func Renumber(results []*meets.RaceResult, source string) []Correction {
+	return renumber(placedResults(results), source)
+}
+
+// MovePlace gives bib place and moves the runners between its old and new place up or down
+// one so every place is used once.  A bib without a place is put in at place.
+func MovePlace(results []*meets.RaceResult, bib, place int, source string) ([]Correction, error) {
+	moved, err := findResult(results, bib)
+	if err != nil {
+		return nil, err
+	}
+
+	ordered := slices.DeleteFunc(placedResults(results), func(rr *meets.RaceResult) bool { return rr == moved })
+	if place < 1 || place > len(ordered)+1 {
+		return nil, fmt.Errorf("place %d isn't between 1 and %d", place, len(ordered)+1)
+	}
+	return renumber(slices.Insert(ordered, place-1, moved), source), nil
+}
+
+// RemovePlace takes bib's place away and moves the runners behind it up a place, for a place
+// given to the wrong runner or a runner with a place and no finish
+func RemovePlace(results []*meets.RaceResult, bib int, source string) ([]Correction, error) {
+	removed, err := findResult(results, bib)
+	if err != nil {
+		return nil, err
+	}
+	if removed.Place == 0 {
+		return nil, fmt.Errorf("bib %d doesn't have a place", bib)
+	}
+
+	ordered := slices.DeleteFunc(placedResults(results), func(rr *meets.RaceResult) bool { return rr == removed })
+	return append([]Correction{correction(removed, 0, source)}, renumber(ordered, source)...), nil
+}
+
+// SendCorrections sends a place event for each correction, the ones before an error are sent
+func SendCorrections(ctx context.Context, eventTarget raceevents.EventStream, corrections []Correction) error {
+	for i, c := range corrections {
+		err := eventTarget.SendPlaceEvent(ctx, raceevents.PlaceEvent{Source: c.Source, Bib: c.Bib, Place: c.Place})
+		if err != nil {
+			return fmt.Errorf("sent %d of %d corrections: %w", i, len(corrections), err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package places
+
+import (
+	"blreynolds4/event-race-timer/internal/meets"
+	"blreynolds4/event-race-timer/internal/raceevents"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// conflictedResults has 12 and 13 tied for 2nd, nobody 3rd, 15 placed without a finish,
+// 16 finished without a place and 17 dropped out
+func conflictedResults() []*meets.RaceResult {
+	return []*meets.RaceResult{
+		{Bib: 11, Place: 1, PlaceSource: SourceName, GunTime: 10 * time.Minute},
+		{Bib: 13, Place: 2, PlaceSource: "manual", GunTime: 11 * time.Minute},
+		{Bib: 12, Place: 2, PlaceSource: SourceName, GunTime: 10*time.Minute + time.Second},
+		{Bib: 14, Place: 4, PlaceSource: SourceName, GunTime: 12 * time.Minute},
+		{Bib: 15, Place: 5, PlaceSource: "manual"},
+		{Bib: 16, GunTime: 13 * time.Minute},
+		{Bib: 17, Status: "DNF"},
+	}
+}
+
+func TestRenumberFixesDuplicatesAndGaps(t *testing.T) {
+	corrections := Renumber(conflictedResults(), "manual")
+
+	// the tie goes to the faster finish, the slower one fills the gap behind it
+	assert.Equal(t, []Correction{{Bib: 13, Place: 3, OldPlace: 2, Source: "manual"}}, corrections)
+
+	// everyone behind a gap moves up
+	corrections = Renumber(conflictedResults()[3:], "manual")
+	assert.Equal(t, []Correction{
+		{Bib: 14, Place: 1, OldPlace: 4, Source: "manual"},
+		{Bib: 15, Place: 2, OldPlace: 5, Source: "manual"},
+	}, corrections)
+}
+
+func TestMovePlace(t *testing.T) {
+	// a runner without a place goes in and pushes the rest back
+	corrections, err := MovePlace(conflictedResults(), 16, 4, "manual")
+	assert.NoError(t, err)
+	assert.Equal(t, []Correction{
+		{Bib: 13, Place: 3, OldPlace: 2, Source: "manual"},
+		{Bib: 16, Place: 4, OldPlace: 0, Source: "manual"},
+		{Bib: 14, Place: 5, OldPlace: 4, Source: "manual"},
+		{Bib: 15, Place: 6, OldPlace: 5, Source: "manual"},
+	}, corrections)
+
+	// a placed runner moving up pushes the runners in between back
+	corrections, err = MovePlace(conflictedResults(), 14, 1, "manual")
+	assert.NoError(t, err)
+	assert.Equal(t, []Correction{
+		{Bib: 14, Place: 1, OldPlace: 4, Source: "manual"},
+		{Bib: 11, Place: 2, OldPlace: 1, Source: "manual"},
+		{Bib: 12, Place: 3, OldPlace: 2, Source: "manual"},
+		{Bib: 13, Place: 4, OldPlace: 2, Source: "manual"},
+	}, corrections)
+
+	_, err = MovePlace(conflictedResults(), 11, 7, "manual")
+	assert.Error(t, err)
+	_, err = MovePlace(conflictedResults(), 17, 1, "manual")
+	assert.Error(t, err)
+	_, err = MovePlace(conflictedResults(), 99, 1, "manual")
+	assert.Error(t, err)
+}
+
+func TestRemovePlace(t *testing.T) {
+	corrections, err := RemovePlace(conflictedResults(), 12, "manual")
+	assert.NoError(t, err)
+	assert.Equal(t, []Correction{
+		{Bib: 12, Place: 0, OldPlace: 2, Source: "manual"},
+		{Bib: 14, Place: 3, OldPlace: 4, Source: "manual"},
+		{Bib: 15, Place: 4, OldPlace: 5, Source: "manual"},
+	}, corrections)
+
+	_, err = RemovePlace(conflictedResults(), 16, "manual")
+	assert.Error(t, err)
+}
+
+func TestSendCorrections(t *testing.T) {
+	events := &raceevents.MockEventStream{Events: make([]raceevents.Event, 0)}
+	corrections := []Correction{
+		{Bib: 12, Place: 0, OldPlace: 2, Source: SourceName},
+		{Bib: 14, Place: 3, OldPlace: 4, Source: "manual"},
+	}
+
+	assert.NoError(t, SendCorrections(context.TODO(), events, corrections))
+	assert.Equal(t, 2, len(events.Events))
+	assert.Equal(t, raceevents.PlaceEvent{Source: SourceName, Bib: 12, Place: 0}, events.Events[0].Data)
+	assert.Equal(t, raceevents.PlaceEvent{Source: "manual", Bib: 14, Place: 3}, events.Events[1].Data)
+}
@@ -0,0 +1,281 @@
+package places
+
+import (
+	"blreynolds4/event-race-timer/internal/competitors"
+	"blreynolds4/event-race-timer/internal/config"
+	"blreynolds4/event-race-timer/internal/meets"
+	"blreynolds4/event-race-timer/internal/raceevents"
+	"context"
+	"log/slog"
+	"sort"
+)
+
+// SourceName is the source of the place events the placer sends
+const SourceName = "default-placer"
+
+// Write a placer that takes event source and event target
+type PlaceGenerator interface {
+	GeneratePlaces(context.Context, competitors.CompetitorLookup, config.RankingPolicy) error
+}
+
+type defaultPlaceGenerator struct {
+	logger *slog.Logger
+	stream raceevents.EventStream
+	// refresh reloads the competitors when a bib isn't found, nil doesn't reload
+	refresh *meets.AthleteRefresh
+	// offsets put every source's finishes on the same clock before they're ordered
+	offsets      config.ClockOffsets
+	finishCache  map[int]raceevents.FinishEvent
+	finishedBibs []int
+	// bibs with a DNF, DNS or DQ status
+	statuses map[int]string
+	// finishes and statuses so far, a void re-places from these without the voided event
+	history []raceevents.Event
+	voided  map[string]bool
+	// places are sent with this so a shutdown doesn't stop part way through a change
+	sendCtx context.Context
+}
+
+// NewPlaceGenerator places finishes in the order they happened, offsets correct the finish
+// times of sources whose clocks are off like the result builder's
+func NewPlaceGenerator(es raceevents.EventStream, offsets config.ClockOffsets, l *slog.Logger) PlaceGenerator {
+	return NewRefreshingPlaceGenerator(es, offsets, nil, l)
+}
+
+// NewRefreshingPlaceGenerator reloads the competitors with refresh when a finish or status
+// has a bib that isn't found, refresh refills the lookup GeneratePlaces is given
+func NewRefreshingPlaceGenerator(es raceevents.EventStream, offsets config.ClockOffsets, refresh *meets.AthleteRefresh, l *slog.Logger) PlaceGenerator {
+	return &defaultPlaceGenerator{
+		logger:  l.With("placer", SourceName),
+		stream:  es,
+		refresh: refresh,
+		offsets: offsets,
+	}
+}
+
+// preserve order of arrival of the bibs
+
+// GeneratePlaces places finishes until the stream ends.  It stops without an error when
+// ctx is cancelled, the places for every event read before then are sent.
+func (dpg *defaultPlaceGenerator) GeneratePlaces(ctx context.Context, athletes competitors.CompetitorLookup, ranking config.RankingPolicy) error {
+	// cache of finishes with bibs
+	dpg.finishCache = make(map[int]raceevents.FinishEvent)
+	// start sorting with bibs in arrival order so the sort can use arrival to break ties
+	dpg.finishedBibs = make([]int, 0)
+	dpg.statuses = make(map[int]string)
+	dpg.history = make([]raceevents.Event, 0)
+	dpg.voided = make(map[string]bool)
+
+	// in a consumer group the places for acknowledged finishes were sent before a restart,
+	// replay those finishes to rebuild the cache without sending the places again
+	groupEvents, isGroup := dpg.stream.(raceevents.EventGroupReader)
+	if isGroup {
+		err := raceevents.ReplayAcknowledged(ctx, groupEvents, func(e raceevents.Event) error {
+			dpg.processEvent(e, athletes, ranking, false)
+			return nil
+		})
+		if err != nil {
+			return stopped(ctx, err)
+		}
+	}
+
+	// read from the source any finish events with bibs (default_placer consumer group)
+	var event raceevents.Event
+	gotEvent, err := dpg.stream.GetRaceEvent(ctx, 0, &event)
+	if err != nil {
+		return stopped(ctx, err)
+	}
+
+	// places for an event that was read are always sent, sending isn't cancelled
+	dpg.sendCtx = context.WithoutCancel(ctx)
+	for gotEvent {
+		dpg.processEvent(event, athletes, ranking, true)
+
+		if isGroup {
+			err = groupEvents.AckRaceEvent(dpg.sendCtx, event.ID)
+			if err != nil {
+				return err
+			}
+		}
+
+		gotEvent, err = dpg.stream.GetRaceEvent(ctx, 0, &event)
+		if err != nil {
+			return stopped(ctx, err)
+		}
+	}
+
+	return nil
+}
+
+// stopped returns nil for a read that failed because ctx was cancelled, that's a shutdown
+func stopped(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
+
+func (dpg *defaultPlaceGenerator) processEvent(event raceevents.Event, athletes competitors.CompetitorLookup, ranking config.RankingPolicy, sendPlaces bool) {
+	switch data := event.Data.(type) {
+	case raceevents.FinishEvent:
+		if !dpg.voided[event.ID] {
+			dpg.history = append(dpg.history, event)
+			dpg.handleFinish(data, athletes, ranking, sendPlaces)
+		}
+	case raceevents.StatusEvent:
+		if !dpg.voided[event.ID] {
+			dpg.history = append(dpg.history, event)
+			dpg.handleStatus(data, athletes, sendPlaces)
+		}
+	case raceevents.VoidEvent:
+		dpg.handleVoid(data, athletes, ranking, sendPlaces)
+	}
+}
+
+// handleVoid re-places every finish as if the voided event never arrived
+func (dpg *defaultPlaceGenerator) handleVoid(void raceevents.VoidEvent, athletes competitors.CompetitorLookup, ranking config.RankingPolicy, sendPlaces bool) {
+	if dpg.voided[void.EventID] {
+		return
+	}
+	dpg.voided[void.EventID] = true
+
+	before := dpg.placeOrder()
+	dpg.finishCache = make(map[int]raceevents.FinishEvent)
+	dpg.finishedBibs = make([]int, 0)
+	dpg.statuses = make(map[int]string)
+	for _, e := range dpg.history {
+		if dpg.voided[e.ID] {
+			continue
+		}
+		switch data := e.Data.(type) {
+		case raceevents.FinishEvent:
+			dpg.handleFinish(data, athletes, ranking, false)
+		case raceevents.StatusEvent:
+			dpg.handleStatus(data, athletes, false)
+		}
+	}
+	if !sendPlaces {
+		return
+	}
+
+	after := dpg.placeOrder()
+	i := 0
+	for i < len(before) && i < len(after) && before[i] == after[i] {
+		i++
+	}
+	dpg.sendPlaces(after, i)
+
+	// a bib whose only finish was voided isn't placed any more
+	for _, bib := range before {
+		if _, stillFinished := dpg.finishCache[bib]; !stillFinished {
+			dpg.stream.SendPlaceEvent(dpg.sendCtx, raceevents.PlaceEvent{
+				Source: SourceName,
+				Place:  0,
+				Bib:    bib,
+			})
+			dpg.logger.Info("Place removed for bib", "bib", bib)
+		}
+	}
+}
+
+func (dpg *defaultPlaceGenerator) handleFinish(finish raceevents.FinishEvent, athletes competitors.CompetitorLookup, ranking config.RankingPolicy, sendPlaces bool) {
+	if !dpg.knownBib(athletes, finish.Bib) {
+		return
+	}
+
+	switch ranking.UnrankedHandling(config.FinishField, finish.Source) {
+	case config.UnrankedReject:
+		dpg.logger.Info("skipping finish from unranked source", "bib", finish.Bib, "source", finish.Source)
+		return
+	case config.UnrankedLog:
+		dpg.logger.Warn("placing finish from unranked source", "bib", finish.Bib, "source", finish.Source)
+	}
+
+	finish.FinishTime = dpg.offsets.Adjust(finish.Source, finish.FinishTime)
+	previous, existed := dpg.finishCache[finish.Bib]
+	if !existed {
+		dpg.finishedBibs = append(dpg.finishedBibs, finish.Bib)
+		dpg.finishCache[finish.Bib] = finish
+	}
+
+	// only cache finishes with bibs of known athletes for placement
+	// where the new finish is from a better source
+	if ranking.Compare(config.FinishField, finish.Source, previous.Source) < 0 || !existed {
+		dpg.finishCache[finish.Bib] = finish
+		if !sendPlaces {
+			return
+		}
+
+		// send events for the current bib and everything after it
+		sorted := dpg.placeOrder()
+		for i := 0; i < len(sorted); i++ {
+			if sorted[i] == finish.Bib {
+				dpg.sendPlaces(sorted, i)
+				break
+			}
+		}
+	}
+}
+
+// knownBib is true for a competitor's bib, the competitors are reloaded for a bib that isn't found
+func (dpg *defaultPlaceGenerator) knownBib(athletes competitors.CompetitorLookup, bib int) bool {
+	_, found := athletes[bib]
+	if !found && dpg.refresh.Refresh() {
+		_, found = athletes[bib]
+	}
+	return found
+}
+
+// handleStatus takes runners with a DNF, DNS or DQ out of the places, or puts them back
+// when their status is cleared, and sends new places for everyone that moved
+func (dpg *defaultPlaceGenerator) handleStatus(status raceevents.StatusEvent, athletes competitors.CompetitorLookup, sendPlaces bool) {
+	if !dpg.knownBib(athletes, status.Bib) {
+		return
+	}
+
+	before := dpg.placeOrder()
+	if status.Status == "" {
+		delete(dpg.statuses, status.Bib)
+	} else {
+		dpg.statuses[status.Bib] = status.Status
+	}
+	if !sendPlaces {
+		return
+	}
+
+	// places change from the first position where the orders differ
+	after := dpg.placeOrder()
+	i := 0
+	for i < len(before) && i < len(after) && before[i] == after[i] {
+		i++
+	}
+	dpg.sendPlaces(after, i)
+}
+
+// placeOrder returns the bibs to place in finish order, runners with a status aren't placed
+func (dpg *defaultPlaceGenerator) placeOrder() []int {
+	sorted := make([]int, 0, len(dpg.finishedBibs))
+	for _, bib := range dpg.finishedBibs {
+		if _, hasStatus := dpg.statuses[bib]; !hasStatus {
+			sorted = append(sorted, bib)
+		}
+	}
+
+	// start sorting with bibs in arrival order so the sort can use arrival to break ties
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return dpg.finishCache[sorted[i]].FinishTime.Before(dpg.finishCache[sorted[j]].FinishTime)
+	})
+	return sorted
+}
+
+// sendPlaces sends a place event for every bib in sorted from index on
+func (dpg *defaultPlaceGenerator) sendPlaces(sorted []int, from int) {
+	for i := from; i < len(sorted); i++ {
+		dpg.stream.SendPlaceEvent(dpg.sendCtx, raceevents.PlaceEvent{
+			Source: SourceName,
+			Place:  i + 1,
+			Bib:    sorted[i],
+		})
+		dpg.logger.Info("Place sent for bib", "bib", sorted[i], "place", i+1)
+	}
+}
@@ -0,0 +1,776 @@
+package places
+
+import (
+	"blreynolds4/event-race-timer/internal/competitors"
+	"blreynolds4/event-race-timer/internal/config"
+	"blreynolds4/event-race-timer/internal/meets"
+	"blreynolds4/event-race-timer/internal/raceevents"
+	"blreynolds4/event-race-timer/internal/stream"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalPlacingInOrderSkipNoBib(t *testing.T) {
+	// given a set of events on the source
+	// produce the set of events on the target
+	now := time.Now().UTC()
+	// Test data
+	finishTime10 := now.Add(5 * time.Minute)
+	finishTime12 := now.Add(5*time.Minute + (time.Millisecond * 1))
+	finishTime11 := now.Add(5*time.Minute + (time.Second * 5))
+	finishTime13 := now.Add(5*time.Minute + (time.Second * 29))
+
+	athletes := make(competitors.CompetitorLookup)
+	athletes[10] = &competitors.Competitor{
+		Name: "bib 10",
+	}
+	athletes[11] = &competitors.Competitor{
+		Name: "bib 11",
+	}
+	athletes[12] = &competitors.Competitor{
+		Name: "bib 12",
+	}
+	athletes[13] = &competitors.Competitor{
+		Name: "bib 13",
+	}
+
+	sourceRanks := make(map[string]int)
+	bestSource := t.Name()
+	slowSource := t.Name() + "-slow"
+	sourceRanks[bestSource] = 1
+	sourceRanks[slowSource] = 2
+
+	testEvents := []raceevents.Event{
+		{
+			EventTime: now,
+			Data: raceevents.StartEvent{
+				Source:    bestSource,
+				StartTime: now,
+			},
+		},
+		{
+			EventTime: now,
+			Data: raceevents.FinishEvent{
+				Source:     bestSource,
+				FinishTime: finishTime10,
+				Bib:        10,
+			},
+		},
+		{
+			EventTime: now,
+			Data: raceevents.FinishEvent{
+				Source:     bestSource,
+				FinishTime: finishTime12,
+				Bib:        raceevents.NoBib,
+			},
+		},
+		{
+			EventTime: now,
+			Data: raceevents.FinishEvent{
+				Source:     bestSource,
+				FinishTime: finishTime11,
+				Bib:        11,
+			},
+		},
+		{
+			EventTime: now,
+			Data: raceevents.FinishEvent{
+				Source:     bestSource,
+				FinishTime: finishTime13,
+				Bib:        13,
+			},
+		},
+	}
+
+	placesSent := make([]raceevents.Event, 0)
+	mockEventStream := &stream.MockStream{
+		Events: buildEventMessages(testEvents),
+		Send: func(ctx context.Context, sm stream.Message) error {
+			var e raceevents.Event
+			err := json.Unmarshal(sm.Data, &e)
+			if err != nil {
+				panic(err)
+			}
+			placesSent = append(placesSent, e)
+			return nil
+		},
+	}
+	inputEvents := raceevents.NewEventStream(mockEventStream)
+
+	builder := NewPlaceGenerator(inputEvents, nil, slog.Default())
+	err := builder.GeneratePlaces(context.TODO(), athletes, config.NewRankingPolicy(sourceRanks))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(placesSent))
+
+	// verify the bibs and places match what we expect
+	pe, ok := placesSent[0].Data.(raceevents.PlaceEvent)
+	assert.True(t, ok)
+	assert.Equal(t, 10, pe.Bib)
+	assert.Equal(t, 1, pe.Place)
+
+	pe, ok = placesSent[1].Data.(raceevents.PlaceEvent)
+	assert.True(t, ok)
+	assert.Equal(t, 11, pe.Bib)
+	assert.Equal(t, 2, pe.Place)
+
+	pe, ok = placesSent[2].Data.(raceevents.PlaceEvent)
+	assert.True(t, ok)
+	assert.Equal(t, 13, pe.Bib)
+	assert.Equal(t, 3, pe.Place)
+}
+
+func TestNormalPlacingInOrderSkipUnknownBib(t *testing.T) {
+	// given a set of events on the source
+	// produce the set of events on the target
+	now := time.Now().UTC()
+	// Test data
+	finishTime10 := now.Add(5 * time.Minute)
+	finishTime12 := now.Add(5*time.Minute + (time.Millisecond * 1))
+	finishTime11 := now.Add(5*time.Minute + (time.Second * 5))
+	finishTime13 := now.Add(5*time.Minute + (time.Second * 29))
+
+	athletes := make(competitors.CompetitorLookup)
+	athletes[10] = &competitors.Competitor{
+		Name: "bib 10",
+	}
+	athletes[11] = &competitors.Competitor{
+		Name: "bib 11",
+	}
+	athletes[12] = &competitors.Competitor{
+		Name: "bib 12",
+	}
+	athletes[13] = &competitors.Competitor{
+		Name: "bib 13",
+	}
+
+	sourceRanks := make(map[string]int)
+	bestSource := t.Name()
+	slowSource := t.Name() + "-slow"
+	sourceRanks[bestSource] = 1
+	sourceRanks[slowSource] = 2
+
+	testEvents := []raceevents.Event{
+		{
+			EventTime: now,
+			Data: raceevents.StartEvent{
+				Source:    bestSource,
+				StartTime: now,
+			},
+		},
+		{
+			EventTime: now,
+			Data: raceevents.FinishEvent{
+				Source:     bestSource,
+				FinishTime: finishTime10,
+				Bib:        10,
+			},
+		},
+		{
+			EventTime: now,
+			Data: raceevents.FinishEvent{
+				Source:     bestSource,
+				FinishTime: finishTime12,
+				Bib:        999,
+			},
+		},
+		{
+			EventTime: now,
+			Data: raceevents.FinishEvent{
+				Source:     bestSource,
+				FinishTime: finishTime11,
+				Bib:        11,
+			},
+		},
+		{
+			EventTime: now,
+			Data: raceevents.FinishEvent{
+				Source:     bestSource,
+				FinishTime: finishTime13,
+				Bib:        13,
+			},
+		},
+	}
+
+	placesSent := make([]raceevents.Event, 0)
+	mockEventStream := &stream.MockStream{
+		Events: buildEventMessages(testEvents),
+		Send: func(ctx context.Context, sm stream.Message) error {
+			var e raceevents.Event
+			err := json.Unmarshal(sm.Data, &e)
+			if err != nil {
+				panic(err)
+			}
+			placesSent = append(placesSent, e)
+			return nil
+		},
+	}
+	inputEvents := raceevents.NewEventStream(mockEventStream)
+
+	builder := NewPlaceGenerator(inputEvents, nil, slog.Default())
+	err := builder.GeneratePlaces(context.TODO(), athletes, config.NewRankingPolicy(sourceRanks))
+	assert.NoError(t, err)
+	// slice off the beginning of the event stream to get to what places were sent
+	assert.Equal(t, 3, len(placesSent))
+
+	// verify the bibs and places match what we expect
+	pe, ok := placesSent[0].Data.(raceevents.PlaceEvent)
+	assert.True(t, ok)
+	assert.Equal(t, 10, pe.Bib)
+	assert.Equal(t, 1, pe.Place)
+
+	pe, ok = placesSent[1].Data.(raceevents.PlaceEvent)
+	assert.True(t, ok)
+	assert.Equal(t, 11, pe.Bib)
+	assert.Equal(t, 2, pe.Place)
+
+	pe, ok = placesSent[2].Data.(raceevents.PlaceEvent)
+	assert.True(t, ok)
+	assert.Equal(t, 13, pe.Bib)
+	assert.Equal(t, 3, pe.Place)
+}
+
+func TestNoisyMultiSourceEvents(t *testing.T) {
+	// given a set of events on the source
+	// produce the set of events on the target
+	now := time.Now().UTC()
+	// Test data
+	finishTime10 := now.Add(5 * time.Minute)
+	finishTime12 := now.Add(5*time.Minute + (time.Millisecond * 1))
+	finishTime11 := now.Add(5*time.Minute + (time.Second * 5))
+	finishTime13 := now.Add(5*time.Minute + (time.Second * 29))
+
+	athletes := make(competitors.CompetitorLookup)
+	athletes[10] = &competitors.Competitor{
+		Name: "bib 10",
+	}
+	athletes[11] = &competitors.Competitor{
+		Name: "bib 11",
+	}
+	athletes[12] = &competitors.Competitor{
+		Name: "bib 12",
+	}
+	athletes[13] = &competitors.Competitor{
+		Name: "bib 13",
+	}
+
+	sourceRanks := make(map[string]int)
+	bestSource := t.Name()
+	slowSource := t.Name() + "-slow"
+	sourceRanks[bestSource] = 1
+	sourceRanks[slowSource] = 2
+
+	// multiple events per finish, should only use the best time
+	// test getting best time first or second
+	testEvents := []raceevents.Event{
+		{
+			EventTime: now,
+			Data: raceevents.StartEvent{
+				Source:    bestSource,
+				StartTime: now,
+			},
+		},
+		{
+			EventTime: now,
+			Data: raceevents.FinishEvent{
+				Source:     slowSource,
+				FinishTime: finishTime10.Add(time.Second),
+				Bib:        10,
+			},
+		},
+		{
+			EventTime: now,
+			Data: raceevents.FinishEvent{
+				Source:     bestSource,
+				FinishTime: finishTime10,
+				Bib:        10,
+			},
+		},
+		{
+			EventTime: now,
+			Data: raceevents.FinishEvent{
+				Source:     bestSource,
+				FinishTime: finishTime12,
+				Bib:        raceevents.NoBib,
+			},
+		},
+		{
+			EventTime: now,
+			Data: raceevents.FinishEvent{
+				Source:     bestSource,
+				FinishTime: finishTime11,
+				Bib:        11,
+			},
+		},
+		{
+			EventTime: now,
+			Data: raceevents.FinishEvent{
+				Source:     slowSource,
+				FinishTime: finishTime11.Add(time.Minute),
+				Bib:        11,
+			},
+		},
+		{
+			EventTime: now,
+			Data: raceevents.FinishEvent{
+				Source:     bestSource,
+				FinishTime: finishTime13,
+				Bib:        13,
+			},
+		},
+		{
+			EventTime: now,
+			Data: raceevents.FinishEvent{
+				Source:     slowSource,
+				FinishTime: finishTime13.Add(-time.Minute),
+				Bib:        13,
+			},
+		},
+	}
+	placesSent := make([]raceevents.Event, 0)
+	mockEventStream := &stream.MockStream{
+		Events: buildEventMessages(testEvents),
+		Send: func(ctx context.Context, sm stream.Message) error {
+			var e raceevents.Event
+			err := json.Unmarshal(sm.Data, &e)
+			if err != nil {
+				panic(err)
+			}
+			placesSent = append(placesSent, e)
+			return nil
+		},
+	}
+	inputEvents := raceevents.NewEventStream(mockEventStream)
+
+	builder := NewPlaceGenerator(inputEvents, nil, slog.Default())
+	err := builder.GeneratePlaces(context.TODO(), athletes, config.NewRankingPolicy(sourceRanks))
+	assert.NoError(t, err)
+	assert.Equal(t, 4, len(placesSent))
+
+	// verify the bibs and places match what we expect
+	pe, ok := placesSent[0].Data.(raceevents.PlaceEvent)
+	assert.True(t, ok)
+	assert.Equal(t, 10, pe.Bib)
+	assert.Equal(t, 1, pe.Place)
+
+	pe, ok = placesSent[1].Data.(raceevents.PlaceEvent)
+	assert.True(t, ok)
+	assert.Equal(t, 10, pe.Bib)
+	assert.Equal(t, 1, pe.Place)
+
+	pe, ok = placesSent[2].Data.(raceevents.PlaceEvent)
+	assert.True(t, ok)
+	assert.Equal(t, 11, pe.Bib)
+	assert.Equal(t, 2, pe.Place)
+
+	pe, ok = placesSent[3].Data.(raceevents.PlaceEvent)
+	assert.True(t, ok)
+	assert.Equal(t, 13, pe.Bib)
+	assert.Equal(t, 3, pe.Place)
+}
+
+func TestEventsArriveOutOfOrder(t *testing.T) {
+	now := time.Now().UTC()
+	// Test data
+	finishTime10 := now.Add(5 * time.Minute)
+	finishTime11 := now.Add(5*time.Minute + (time.Second * 5))
+	finishTime13 := now.Add(5*time.Minute + (time.Second * 29))
+
+	athletes := make(competitors.CompetitorLookup)
+	athletes[10] = &competitors.Competitor{
+		Name: "bib 10",
+	}
+	athletes[11] = &competitors.Competitor{
+		Name: "bib 11",
+	}
+	athletes[12] = &competitors.Competitor{
+		Name: "bib 12",
+	}
+	athletes[13] = &competitors.Competitor{
+		Name: "bib 13",
+	}
+
+	sourceRanks := make(map[string]int)
+
+	testEvents := []raceevents.Event{
+		{
+			EventTime: now,
+			Data: raceevents.FinishEvent{
+				FinishTime: finishTime13,
+				Bib:        13,
+			},
+		},
+		{
+			EventTime: now,
+			Data: raceevents.FinishEvent{
+				FinishTime: finishTime11,
+				Bib:        11,
+			},
+		},
+		{
+			EventTime: now,
+			Data: raceevents.FinishEvent{
+				FinishTime: finishTime10,
+				Bib:        10,
+			},
+		},
+	}
+	placesSent := make([]raceevents.Event, 0)
+	mockEventStream := &stream.MockStream{
+		Events: buildEventMessages(testEvents),
+		Send: func(ctx context.Context, sm stream.Message) error {
+			var e raceevents.Event
+			err := json.Unmarshal(sm.Data, &e)
+			if err != nil {
+				panic(err)
+			}
+			placesSent = append(placesSent, e)
+			return nil
+		},
+	}
+	inputEvents := raceevents.NewEventStream(mockEventStream)
+
+	builder := NewPlaceGenerator(inputEvents, nil, slog.Default())
+	err := builder.GeneratePlaces(context.TODO(), athletes, config.NewRankingPolicy(sourceRanks))
+	assert.NoError(t, err)
+	assert.Equal(t, 6, len(placesSent))
+
+	// verify the bibs and places match what we expect
+	pe, ok := placesSent[0].Data.(raceevents.PlaceEvent)
+	assert.True(t, ok)
+	assert.Equal(t, 13, pe.Bib)
+	assert.Equal(t, 1, pe.Place)
+
+	pe, ok = placesSent[1].Data.(raceevents.PlaceEvent)
+	assert.True(t, ok)
+	assert.Equal(t, 11, pe.Bib)
+	assert.Equal(t, 1, pe.Place)
+
+	pe, ok = placesSent[2].Data.(raceevents.PlaceEvent)
+	assert.True(t, ok)
+	assert.Equal(t, 13, pe.Bib)
+	assert.Equal(t, 2, pe.Place)
+
+	pe, ok = placesSent[3].Data.(raceevents.PlaceEvent)
+	assert.True(t, ok)
+	assert.Equal(t, 10, pe.Bib)
+	assert.Equal(t, 1, pe.Place)
+
+	pe, ok = placesSent[4].Data.(raceevents.PlaceEvent)
+	assert.True(t, ok)
+	assert.Equal(t, 11, pe.Bib)
+	assert.Equal(t, 2, pe.Place)
+
+	pe, ok = placesSent[5].Data.(raceevents.PlaceEvent)
+	assert.True(t, ok)
+	assert.Equal(t, 13, pe.Bib)
+	assert.Equal(t, 3, pe.Place)
+}
+
+func buildEventMessages(testEvents []raceevents.Event) []stream.Message {
+	result := make([]stream.Message, len(testEvents))
+	for i, e := range testEvents {
+		eData, err := json.Marshal(e)
+		if err != nil {
+			panic(err)
+		}
+		result[i] = stream.Message{
+			ID:   e.ID,
+			Data: eData,
+		}
+	}
+
+	return result
+}
+
+func TestGroupPlacingResumesWithoutResending(t *testing.T) {
+	now := time.Now().UTC()
+
+	athletes := make(competitors.CompetitorLookup)
+	athletes[10] = &competitors.Competitor{Name: "bib 10"}
+	athletes[11] = &competitors.Competitor{Name: "bib 11"}
+	sourceRanks := map[string]int{t.Name(): 1}
+
+	// bib 10 was placed before a restart
+	acknowledged := []raceevents.Event{
+		{
+			ID:   "1-0",
+			Data: raceevents.FinishEvent{Source: t.Name(), FinishTime: now.Add(5 * time.Minute), Bib: 10},
+		},
+	}
+	placesSent := make([]raceevents.PlaceEvent, 0)
+	inputEvents := &raceevents.MockEventGroupStream{
+		MockEventStream: raceevents.MockEventStream{
+			SendStart: func(ctx context.Context, se raceevents.StartEvent) error { return nil },
+			SendPlace: func(ctx context.Context, pe raceevents.PlaceEvent) error {
+				placesSent = append(placesSent, pe)
+				return nil
+			},
+			Range: func(ctx context.Context, startId, endId string, events []raceevents.Event) (int, error) {
+				count := copy(events, acknowledged)
+				acknowledged = acknowledged[count:]
+				return count, nil
+			},
+			Events: []raceevents.Event{
+				{
+					ID:   "2-0",
+					Data: raceevents.FinishEvent{Source: t.Name(), FinishTime: now.Add(6 * time.Minute), Bib: 11},
+				},
+			},
+		},
+		Position: stream.GroupPosition{LastDeliveredID: "1-0"},
+	}
+
+	placer := NewPlaceGenerator(inputEvents, nil, slog.Default())
+	err := placer.GeneratePlaces(context.TODO(), athletes, config.NewRankingPolicy(sourceRanks))
+	assert.NoError(t, err)
+
+	assert.Equal(t, []raceevents.PlaceEvent{{Source: SourceName, Bib: 11, Place: 2}}, placesSent)
+	assert.Equal(t, []string{"2-0"}, inputEvents.Acked)
+}
+
+func TestDisqualifiedRunnerRemovedFromPlaces(t *testing.T) {
+	now := time.Now().UTC()
+
+	athletes := make(competitors.CompetitorLookup)
+	for _, bib := range []int{10, 11, 12} {
+		athletes[bib] = &competitors.Competitor{Name: "bib"}
+	}
+	sourceRanks := map[string]int{t.Name(): 1}
+
+	placesSent := make([]raceevents.PlaceEvent, 0)
+	inputEvents := &raceevents.MockEventStream{
+		SendStart: func(ctx context.Context, se raceevents.StartEvent) error { return nil },
+		SendPlace: func(ctx context.Context, pe raceevents.PlaceEvent) error {
+			placesSent = append(placesSent, pe)
+			return nil
+		},
+		Events: []raceevents.Event{
+			{Data: raceevents.FinishEvent{Source: t.Name(), FinishTime: now.Add(5 * time.Minute), Bib: 10}},
+			{Data: raceevents.FinishEvent{Source: t.Name(), FinishTime: now.Add(6 * time.Minute), Bib: 11}},
+			{Data: raceevents.FinishEvent{Source: t.Name(), FinishTime: now.Add(7 * time.Minute), Bib: 12}},
+			{Data: raceevents.StatusEvent{Source: t.Name(), Bib: 10, Status: raceevents.StatusDQ}},
+			{Data: raceevents.StatusEvent{Source: t.Name(), Bib: 10, Status: ""}},
+		},
+	}
+
+	placer := NewPlaceGenerator(inputEvents, nil, slog.Default())
+	err := placer.GeneratePlaces(context.TODO(), athletes, config.NewRankingPolicy(sourceRanks))
+	assert.NoError(t, err)
+
+	assert.Equal(t, []raceevents.PlaceEvent{
+		{Source: SourceName, Bib: 10, Place: 1},
+		{Source: SourceName, Bib: 11, Place: 2},
+		{Source: SourceName, Bib: 12, Place: 3},
+		// 10 is disqualified, everyone moves up
+		{Source: SourceName, Bib: 11, Place: 1},
+		{Source: SourceName, Bib: 12, Place: 2},
+		// and back in
+		{Source: SourceName, Bib: 10, Place: 1},
+		{Source: SourceName, Bib: 11, Place: 2},
+		{Source: SourceName, Bib: 12, Place: 3},
+	}, placesSent)
+}
+
+func TestVoidedFinishIsReplaced(t *testing.T) {
+	now := time.Now().UTC()
+
+	athletes := make(competitors.CompetitorLookup)
+	for _, bib := range []int{10, 11} {
+		athletes[bib] = &competitors.Competitor{Name: "bib"}
+	}
+	sourceRanks := map[string]int{t.Name(): 1}
+
+	placesSent := make([]raceevents.PlaceEvent, 0)
+	inputEvents := &raceevents.MockEventStream{
+		SendStart: func(ctx context.Context, se raceevents.StartEvent) error { return nil },
+		SendPlace: func(ctx context.Context, pe raceevents.PlaceEvent) error {
+			placesSent = append(placesSent, pe)
+			return nil
+		},
+		Events: []raceevents.Event{
+			// bib 10 walked by the mat early
+			{ID: "1-0", Data: raceevents.FinishEvent{Source: t.Name(), FinishTime: now.Add(time.Minute), Bib: 10}},
+			{ID: "2-0", Data: raceevents.FinishEvent{Source: t.Name(), FinishTime: now.Add(6 * time.Minute), Bib: 11}},
+			{ID: "3-0", Data: raceevents.VoidEvent{Source: t.Name(), EventID: "1-0", Reason: "walk by"}},
+			{ID: "4-0", Data: raceevents.FinishEvent{Source: t.Name(), FinishTime: now.Add(7 * time.Minute), Bib: 10}},
+		},
+	}
+
+	placer := NewPlaceGenerator(inputEvents, nil, slog.Default())
+	err := placer.GeneratePlaces(context.TODO(), athletes, config.NewRankingPolicy(sourceRanks))
+	assert.NoError(t, err)
+
+	assert.Equal(t, []raceevents.PlaceEvent{
+		{Source: SourceName, Bib: 10, Place: 1},
+		{Source: SourceName, Bib: 11, Place: 2},
+		// the void moves 11 up and takes 10 out
+		{Source: SourceName, Bib: 11, Place: 1},
+		{Source: SourceName, Bib: 10, Place: 0},
+		// the real finish
+		{Source: SourceName, Bib: 10, Place: 2},
+	}, placesSent)
+}
+
+func TestClockOffsetsOrderFinishes(t *testing.T) {
+	now := time.Now().UTC()
+
+	athletes := make(competitors.CompetitorLookup)
+	for _, bib := range []int{10, 11} {
+		athletes[bib] = &competitors.Competitor{Name: "bib"}
+	}
+	sourceRanks := map[string]int{"mat": 1, "manual": 2}
+
+	placesSent := make([]raceevents.PlaceEvent, 0)
+	inputEvents := &raceevents.MockEventStream{
+		SendStart: func(ctx context.Context, se raceevents.StartEvent) error { return nil },
+		SendPlace: func(ctx context.Context, pe raceevents.PlaceEvent) error {
+			placesSent = append(placesSent, pe)
+			return nil
+		},
+		Events: []raceevents.Event{
+			// the mat's clock is 2 seconds ahead, 10 finished a second before 11
+			{ID: "1-0", Data: raceevents.FinishEvent{Source: "mat", FinishTime: now.Add(61 * time.Second), Bib: 10}},
+			{ID: "2-0", Data: raceevents.FinishEvent{Source: "manual", FinishTime: now.Add(60 * time.Second), Bib: 11}},
+		},
+	}
+
+	placer := NewPlaceGenerator(inputEvents, config.ClockOffsets{"mat": 2000}, slog.Default())
+	err := placer.GeneratePlaces(context.TODO(), athletes, config.NewRankingPolicy(sourceRanks))
+	assert.NoError(t, err)
+
+	assert.Equal(t, []raceevents.PlaceEvent{
+		{Source: SourceName, Bib: 10, Place: 1},
+		{Source: SourceName, Bib: 11, Place: 2},
+	}, placesSent)
+}
+
+func TestUnknownBibReloadsCompetitors(t *testing.T) {
+	now := time.Now().UTC()
+
+	athletes := competitors.CompetitorLookup{10: &competitors.Competitor{Name: "bib"}}
+	sourceRanks := map[string]int{t.Name(): 1}
+
+	placesSent := make([]raceevents.PlaceEvent, 0)
+	inputEvents := &raceevents.MockEventStream{
+		SendStart: func(ctx context.Context, se raceevents.StartEvent) error { return nil },
+		SendPlace: func(ctx context.Context, pe raceevents.PlaceEvent) error {
+			placesSent = append(placesSent, pe)
+			return nil
+		},
+		Events: []raceevents.Event{
+			{Data: raceevents.FinishEvent{Source: t.Name(), FinishTime: now.Add(5 * time.Minute), Bib: 10}},
+			{Data: raceevents.FinishEvent{Source: t.Name(), FinishTime: now.Add(6 * time.Minute), Bib: 11}},
+			{Data: raceevents.FinishEvent{Source: t.Name(), FinishTime: now.Add(7 * time.Minute), Bib: 12}},
+		},
+	}
+
+	// 11 was added to the race after the placer started, 12 never was
+	reloads := 0
+	refresh := meets.NewAthleteRefresh(func() error {
+		reloads++
+		athletes[11] = &competitors.Competitor{Name: "bib"}
+		return nil
+	}, 0, slog.Default())
+
+	placer := NewRefreshingPlaceGenerator(inputEvents, nil, refresh, slog.Default())
+	err := placer.GeneratePlaces(context.TODO(), athletes, config.NewRankingPolicy(sourceRanks))
+	assert.NoError(t, err)
+
+	assert.Equal(t, 2, reloads)
+	assert.Equal(t, []raceevents.PlaceEvent{
+		{Source: SourceName, Bib: 10, Place: 1},
+		{Source: SourceName, Bib: 11, Place: 2},
+	}, placesSent)
+}
+
+func TestUnrankedFinishSourceRejected(t *testing.T) {
+	now := time.Now().UTC()
+
+	athletes := make(competitors.CompetitorLookup)
+	athletes[10] = &competitors.Competitor{Name: "bib 10"}
+	athletes[11] = &competitors.Competitor{Name: "bib 11"}
+
+	testEvents := []raceevents.Event{
+		{EventTime: now, Data: raceevents.FinishEvent{Source: "walkby", FinishTime: now.Add(time.Minute), Bib: 11}},
+		{EventTime: now, Data: raceevents.FinishEvent{Source: "chip", FinishTime: now.Add(5 * time.Minute), Bib: 10}},
+		{EventTime: now, Data: raceevents.FinishEvent{Source: "chip", FinishTime: now.Add(6 * time.Minute), Bib: 11}},
+	}
+
+	placesSent := make([]raceevents.PlaceEvent, 0)
+	mockEventStream := &stream.MockStream{
+		Events: buildEventMessages(testEvents),
+		Send: func(ctx context.Context, sm stream.Message) error {
+			var e raceevents.Event
+			err := json.Unmarshal(sm.Data, &e)
+			if err != nil {
+				panic(err)
+			}
+			placesSent = append(placesSent, e.Data.(raceevents.PlaceEvent))
+			return nil
+		},
+	}
+
+	ranking := config.RankingPolicy{Finish: map[string]int{"chip": 1}, Unranked: config.UnrankedReject}
+	err := NewPlaceGenerator(raceevents.NewEventStream(mockEventStream), nil, slog.Default()).GeneratePlaces(context.TODO(), athletes, ranking)
+	assert.NoError(t, err)
+	assert.Equal(t, []raceevents.PlaceEvent{
+		{Source: SourceName, Bib: 10, Place: 1},
+		{Source: SourceName, Bib: 11, Place: 2},
+	}, placesSent)
+}
+
+func TestGeneratePlacesStopsWhenCancelled(t *testing.T) {
+	now := time.Now().UTC()
+
+	athletes := make(competitors.CompetitorLookup)
+	athletes[10] = &competitors.Competitor{Name: "bib 10"}
+	athletes[11] = &competitors.Competitor{Name: "bib 11"}
+
+	messages := buildEventMessages([]raceevents.Event{
+		{EventTime: now, Data: raceevents.FinishEvent{Source: t.Name(), FinishTime: now.Add(5 * time.Minute), Bib: 10}},
+		{EventTime: now, Data: raceevents.FinishEvent{Source: t.Name(), FinishTime: now.Add(6 * time.Minute), Bib: 11}},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	placesSent := make([]raceevents.PlaceEvent, 0)
+	mockEventStream := &stream.MockStream{
+		Get: func(ctx context.Context, timeout time.Duration, msg *stream.Message) (bool, error) {
+			if len(messages) == 0 {
+				// wait for more events until ctrl-c
+				<-ctx.Done()
+				return false, ctx.Err()
+			}
+			*msg = messages[0]
+			messages = messages[1:]
+			if len(messages) == 0 {
+				// ctrl-c while the last finish is placed
+				cancel()
+			}
+			return true, nil
+		},
+		Send: func(ctx context.Context, sm stream.Message) error {
+			// places for a finish that was read are still sent
+			assert.NoError(t, ctx.Err())
+
+			var e raceevents.Event
+			err := json.Unmarshal(sm.Data, &e)
+			if err != nil {
+				panic(err)
+			}
+			placesSent = append(placesSent, e.Data.(raceevents.PlaceEvent))
+			return nil
+		},
+	}
+
+	err := NewPlaceGenerator(raceevents.NewEventStream(mockEventStream), nil, slog.Default()).GeneratePlaces(ctx, athletes, config.NewRankingPolicy(map[string]int{t.Name(): 1}))
+	assert.NoError(t, err)
+	assert.Equal(t, []raceevents.PlaceEvent{
+		{Source: SourceName, Bib: 10, Place: 1},
+		{Source: SourceName, Bib: 11, Place: 2},
+	}, placesSent)
+}
@@ -0,0 +1,143 @@
+package archiver
+
+import (
+	"blreynolds4/event-race-timer/internal/racearchive"
+	"blreynolds4/event-race-timer/internal/raceevents"
+
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+const eventBufferSize = 100
+
+type Arciver interface {
+	Archive(context.Context, raceevents.EventStream) error
+}
+
+type jsonFileArchiver struct {
+	output io.Writer
+}
+
+func NewJsonFileArchiver(w io.Writer) Arciver {
+	return jsonFileArchiver{
+		output: w,
+	}
+}
+
+func (jfa jsonFileArchiver) Archive(ctx context.Context, eventStream raceevents.EventStream) error {
+	// read all the raceevents
+	archivedEvents := make([]raceevents.Event, 0, eventBufferSize)
+	raceEventsBuffer := make([]raceevents.Event, eventBufferSize)
+
+	startId := eventStream.RangeQueryMin()
+	count, err := eventStream.GetRaceEventRange(ctx, startId, eventStream.RangeQueryMax(), raceEventsBuffer)
+	if err != nil {
+		return err
+	}
+
+	for count > 0 {
+		for i := 0; i < count; i++ {
+			archivedEvents = append(archivedEvents, raceEventsBuffer[i])
+		}
+
+		startId = eventStream.ExclusiveQueryStart(raceEventsBuffer[count-1].ID)
+		count, err = eventStream.GetRaceEventRange(ctx, startId, eventStream.RangeQueryMax(), raceEventsBuffer)
+		if err != nil {
+			return err
+		}
+	}
+
+	return jfa.write(racearchive.RaceArchive{
+		RaceEvents: archivedEvents,
+	})
+}
+
+func (jfa jsonFileArchiver) write(archive racearchive.RaceArchive) error {
+	encoder := json.NewEncoder(jfa.output)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(archive)
+}
+
+// groupFileArchiver reads the race as a consumer group and adds the events it
+// hasn't seen to an archive file, so running it again only reads new events.
+type groupFileArchiver struct {
+	path string
+}
+
+func NewGroupFileArchiver(path string) Arciver {
+	return groupFileArchiver{
+		path: path,
+	}
+}
+
+// Archive stops reading when ctx is cancelled and still saves the events it read
+func (gfa groupFileArchiver) Archive(ctx context.Context, eventStream raceevents.EventStream) error {
+	groupEvents, isGroup := eventStream.(raceevents.EventGroupReader)
+	if !isGroup {
+		return fmt.Errorf("group archive needs a consumer group stream")
+	}
+
+	var archive racearchive.RaceArchive
+	data, err := os.ReadFile(gfa.path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if err == nil {
+		err = json.Unmarshal(data, &archive)
+		if err != nil {
+			return err
+		}
+	}
+
+	archived := make(map[string]bool, len(archive.RaceEvents))
+	for _, e := range archive.RaceEvents {
+		archived[e.ID] = true
+	}
+
+	// events written to the archive last time but not acknowledged come back, skip those
+	readIds := make([]string, 0)
+	var event raceevents.Event
+	gotEvent, err := groupEvents.GetRaceEvent(ctx, -1, &event)
+	for gotEvent && err == nil {
+		readIds = append(readIds, event.ID)
+		if !archived[event.ID] {
+			archive.RaceEvents = append(archive.RaceEvents, event)
+			archived[event.ID] = true
+		}
+		gotEvent, err = groupEvents.GetRaceEvent(ctx, -1, &event)
+	}
+	if err != nil && ctx.Err() == nil {
+		return err
+	}
+
+	// replace the archive in one step so a crash doesn't leave half a file
+	tmpPath := gfa.path + ".tmp"
+	f, err := os.Create(tmpPath)
+	if err != nil {
+		return err
+	}
+	err = jsonFileArchiver{output: f}.write(archive)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	err = os.Rename(tmpPath, gfa.path)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range readIds {
+		err = groupEvents.AckRaceEvent(context.WithoutCancel(ctx), id)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -1,11 +1,13 @@
 package archiver
 
 import (
-	"blreynolds4/event-race-timer/cmd/race_archiver/internal/racearchive"
+	"blreynolds4/event-race-timer/internal/racearchive"
 	"blreynolds4/event-race-timer/internal/raceevents"
 	"context"
 	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -25,7 +27,7 @@ func TestFirstRangeFails(t *testing.T) {
 
 	w := &strings.Builder{}
 	archiver := NewJsonFileArchiver(w)
-	err := archiver.Archive(mock)
+	err := archiver.Archive(context.TODO(), mock)
 	assert.Error(t, err)
 	assert.Equal(t, 0, w.Len())
 }
@@ -48,7 +50,7 @@ func TestSecondRangeFails(t *testing.T) {
 
 	w := &strings.Builder{}
 	archiver := NewJsonFileArchiver(w)
-	err := archiver.Archive(mock)
+	err := archiver.Archive(context.TODO(), mock)
 	assert.Error(t, err)
 	assert.Equal(t, 0, w.Len())
 }
@@ -58,7 +60,7 @@ func TestFirstRangeReturnsZero(t *testing.T) {
 
 	w := &strings.Builder{}
 	archiver := NewJsonFileArchiver(w)
-	err := archiver.Archive(mock)
+	err := archiver.Archive(context.TODO(), mock)
 	assert.NoError(t, err)
 
 	assert.True(t, w.Len() > 0)
@@ -75,7 +77,7 @@ func TestEncodeFails(t *testing.T) {
 
 	w := badWriter{e: expErr}
 	archiver := NewJsonFileArchiver(w)
-	err := archiver.Archive(mock)
+	err := archiver.Archive(context.TODO(), mock)
 	assert.Error(t, err)
 }
 
@@ -99,7 +101,7 @@ func TestSuccess(t *testing.T) {
 
 	w := &strings.Builder{}
 	archiver := NewJsonFileArchiver(w)
-	err := archiver.Archive(mock)
+	err := archiver.Archive(context.TODO(), mock)
 	assert.NoError(t, err)
 
 	assert.True(t, w.Len() > 0)
@@ -122,3 +124,47 @@ func (bw badWriter) Write(p []byte) (n int, err error) {
 
 	return 0, nil
 }
+
+func TestGroupArchiveAddsNewEvents(t *testing.T) {
+	archivePath := filepath.Join(t.TempDir(), "race.archive.json")
+
+	// 2-0 was archived last time but not acknowledged so it's delivered again
+	existing := racearchive.RaceArchive{
+		RaceEvents: []raceevents.Event{
+			{ID: "1-0", Data: raceevents.StartEvent{Source: t.Name()}},
+			{ID: "2-0", Data: raceevents.FinishEvent{Source: t.Name(), Bib: 1}},
+		},
+	}
+	data, err := json.Marshal(existing)
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(archivePath, data, 0644))
+
+	mock := &raceevents.MockEventGroupStream{
+		MockEventStream: raceevents.MockEventStream{
+			Events: []raceevents.Event{
+				{ID: "2-0", Data: raceevents.FinishEvent{Source: t.Name(), Bib: 1}},
+				{ID: "3-0", Data: raceevents.PlaceEvent{Source: t.Name(), Bib: 1, Place: 1}},
+			},
+		},
+	}
+
+	err = NewGroupFileArchiver(archivePath).Archive(context.TODO(), mock)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"2-0", "3-0"}, mock.Acked)
+
+	data, err = os.ReadFile(archivePath)
+	assert.NoError(t, err)
+	var archive racearchive.RaceArchive
+	assert.NoError(t, json.Unmarshal(data, &archive))
+
+	ids := make([]string, len(archive.RaceEvents))
+	for i, e := range archive.RaceEvents {
+		ids[i] = e.ID
+	}
+	assert.Equal(t, []string{"1-0", "2-0", "3-0"}, ids)
+}
+
+func TestGroupArchiveNeedsGroupStream(t *testing.T) {
+	err := NewGroupFileArchiver(filepath.Join(t.TempDir(), "race.archive.json")).Archive(context.TODO(), &raceevents.MockEventStream{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,63 @@
+package restorer
+
+import (
+	"blreynolds4/event-race-timer/internal/racearchive"
+	"blreynolds4/event-race-timer/internal/raceevents"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+type Restorer interface {
+	Restore(ctx context.Context, r io.Reader, es raceevents.EventStream) error
+}
+
+func NewRestorer() Restorer {
+	return restorer{}
+}
+
+type restorer struct{}
+
+func (r restorer) Restore(ctx context.Context, rdr io.Reader, es raceevents.EventStream) error {
+	// Decode the reader
+	// send the events to the stream
+	decode := json.NewDecoder(rdr)
+
+	var archive racearchive.RaceArchive
+	err := decode.Decode(&archive)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+
+	for i := 0; i < len(archive.RaceEvents); i++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		e := archive.RaceEvents[i]
+		switch t := e.Data.(type) {
+		case raceevents.StartEvent:
+			es.SendStartEvent(ctx, e.Data.(raceevents.StartEvent))
+		case raceevents.FinishEvent:
+			es.SendFinishEvent(ctx, e.Data.(raceevents.FinishEvent))
+		case raceevents.PlaceEvent:
+			es.SendPlaceEvent(ctx, e.Data.(raceevents.PlaceEvent))
+		case raceevents.ChipStartEvent:
+			es.SendChipStartEvent(ctx, e.Data.(raceevents.ChipStartEvent))
+		case raceevents.SplitEvent:
+			es.SendSplitEvent(ctx, e.Data.(raceevents.SplitEvent))
+		case raceevents.StatusEvent:
+			es.SendStatusEvent(ctx, e.Data.(raceevents.StatusEvent))
+		case raceevents.VoidEvent:
+			es.SendVoidEvent(ctx, e.Data.(raceevents.VoidEvent))
+		case raceevents.RejectedReadEvent:
+			es.SendRejectedReadEvent(ctx, e.Data.(raceevents.RejectedReadEvent))
+		default:
+			return fmt.Errorf("unknown type in Event Data %v", t)
+		}
+	}
+
+	return nil
+}
@@ -1,8 +1,9 @@
 package restorer
 
 import (
-	"blreynolds4/event-race-timer/cmd/race_archiver/internal/racearchive"
+	"blreynolds4/event-race-timer/internal/racearchive"
 	"blreynolds4/event-race-timer/internal/raceevents"
+	"context"
 	"encoding/json"
 	"strings"
 	"testing"
@@ -17,7 +18,7 @@ func TestDecodeFailure(t *testing.T) {
 	m := &raceevents.MockEventStream{}
 
 	restore := NewRestorer()
-	err := restore.Restore(badReader, m)
+	err := restore.Restore(context.TODO(), badReader, m)
 	assert.Error(t, err)
 }
 
@@ -27,7 +28,7 @@ func TestEmptyFiile(t *testing.T) {
 	m := &raceevents.MockEventStream{}
 
 	restore := NewRestorer()
-	err := restore.Restore(badReader, m)
+	err := restore.Restore(context.TODO(), badReader, m)
 	assert.NoError(t, err)
 	assert.Equal(t, 0, len(m.Events))
 }
@@ -71,7 +72,7 @@ func TestAllEventTypesSuccess(t *testing.T) {
 	}
 
 	restore := NewRestorer()
-	err = restore.Restore(eventReader, m)
+	err = restore.Restore(context.TODO(), eventReader, m)
 	assert.NoError(t, err)
 	assert.Equal(t, len(expEvents), len(m.Events))
 }
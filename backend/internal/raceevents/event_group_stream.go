@@ -0,0 +1,105 @@
+package raceevents
+
+import (
+	"blreynolds4/event-race-timer/internal/stream"
+	"context"
+	"encoding/json"
+	"time"
+)
+
+const replayBufferSize = 100
+
+// EventGroupReader reads race events as a consumer in a consumer group.
+// Events need to be acknowledged once they're handled or they will be delivered again.
+type EventGroupReader interface {
+	EventStreamReader
+	AckRaceEvent(ctx context.Context, id string) error
+	ClaimRaceEvents(ctx context.Context, minIdle time.Duration, resultEvents []Event) (int, error)
+	GetGroupPosition(ctx context.Context) (stream.GroupPosition, error)
+}
+
+type EventGroupStream interface {
+	EventGroupReader
+	EventStreamWriter
+}
+
+type eventGroupStream struct {
+	*eventStream
+	group stream.GroupReaderWriter
+}
+
+func NewEventGroupStream(s stream.GroupReaderWriter) EventGroupStream {
+	return &eventGroupStream{
+		eventStream: &eventStream{raw: s},
+		group:       s,
+	}
+}
+
+func (egs *eventGroupStream) AckRaceEvent(ctx context.Context, id string) error {
+	return egs.group.AckMessage(ctx, id)
+}
+
+func (egs *eventGroupStream) ClaimRaceEvents(ctx context.Context, minIdle time.Duration, resultEvents []Event) (int, error) {
+	msgs := make([]stream.Message, len(resultEvents))
+	count, err := egs.group.ClaimMessages(ctx, minIdle, msgs)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := 0; i < count; i++ {
+		var e Event
+		err = json.Unmarshal(msgs[i].Data, &e)
+		if err != nil {
+			return 0, err
+		}
+		e.ID = msgs[i].ID
+		resultEvents[i] = e
+	}
+
+	return count, nil
+}
+
+func (egs *eventGroupStream) GetGroupPosition(ctx context.Context) (stream.GroupPosition, error) {
+	return egs.group.GetGroupPosition(ctx)
+}
+
+// ReplayAcknowledged calls handle, oldest first, for every event the group has
+// already delivered and had acknowledged.  Consumers that keep state use it to
+// rebuild that state before they go back to reading new events from the group.
+func ReplayAcknowledged(ctx context.Context, es EventGroupReader, handle func(Event) error) error {
+	position, err := es.GetGroupPosition(ctx)
+	if err != nil {
+		return err
+	}
+
+	lastDelivered, err := stream.ParseMessageID(position.LastDeliveredID)
+	if err != nil || lastDelivered.IsZero() {
+		// nothing delivered yet
+		return nil
+	}
+
+	buffer := make([]Event, replayBufferSize)
+	startId := es.RangeQueryMin()
+	for {
+		count, err := es.GetRaceEventRange(ctx, startId, position.LastDeliveredID, buffer)
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			return nil
+		}
+
+		for i := 0; i < count; i++ {
+			if position.Pending[buffer[i].ID] {
+				// not handled yet, it will be delivered again
+				continue
+			}
+			err = handle(buffer[i])
+			if err != nil {
+				return err
+			}
+		}
+
+		startId = es.ExclusiveQueryStart(buffer[count-1].ID)
+	}
+}
@@ -0,0 +1,49 @@
+package raceevents
+
+import (
+	"blreynolds4/event-race-timer/internal/stream"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplayAcknowledgedSkipsPending(t *testing.T) {
+	mock := &MockEventGroupStream{
+		MockEventStream: MockEventStream{
+			Events: []Event{
+				{ID: "1-0", Data: StartEvent{Source: t.Name()}},
+				{ID: "2-0", Data: FinishEvent{Source: t.Name(), Bib: 1}},
+				{ID: "3-0", Data: FinishEvent{Source: t.Name(), Bib: 2}},
+			},
+		},
+		Position: stream.GroupPosition{
+			LastDeliveredID: "3-0",
+			Pending:         map[string]bool{"2-0": true},
+		},
+	}
+
+	replayed := make([]string, 0)
+	err := ReplayAcknowledged(context.TODO(), mock, func(e Event) error {
+		replayed = append(replayed, e.ID)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1-0", "3-0"}, replayed)
+}
+
+func TestReplayAcknowledgedNothingDelivered(t *testing.T) {
+	mock := &MockEventGroupStream{
+		MockEventStream: MockEventStream{
+			Events: []Event{{ID: "1-0", Data: StartEvent{Source: t.Name()}}},
+		},
+		Position: stream.GroupPosition{LastDeliveredID: "0-0"},
+	}
+
+	err := ReplayAcknowledged(context.TODO(), mock, func(e Event) error {
+		t.Errorf("unexpected replay of %s", e.ID)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(mock.Events))
+}
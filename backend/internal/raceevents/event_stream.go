@@ -21,6 +21,11 @@ type EventStreamWriter interface {
 	SendStartEvent(ctx context.Context, se StartEvent) error
 	SendFinishEvent(ctx context.Context, fe FinishEvent) error
 	SendPlaceEvent(ctx context.Context, pe PlaceEvent) error
+	SendChipStartEvent(ctx context.Context, cse ChipStartEvent) error
+	SendSplitEvent(ctx context.Context, sp SplitEvent) error
+	SendStatusEvent(ctx context.Context, se StatusEvent) error
+	SendVoidEvent(ctx context.Context, ve VoidEvent) error
+	SendRejectedReadEvent(ctx context.Context, rre RejectedReadEvent) error
 }
 
 type EventStream interface {
@@ -28,6 +33,11 @@ type EventStream interface {
 	EventStreamWriter
 }
 
+// EventSeeker moves where GetRaceEvent reads from, the next event read is the first after id
+type EventSeeker interface {
+	SeekAfterEvent(id string) error
+}
+
 // Define the Event Stream, it can support reading and writing
 // Event Stream is the api for sending/getting events
 type eventStream struct {
@@ -56,6 +66,26 @@ func (es *eventStream) SendStartEvent(ctx context.Context, se StartEvent) error
 	})
 }
 
+func (es *eventStream) SendChipStartEvent(ctx context.Context, cse ChipStartEvent) error {
+	// wrap chip start event with event and send
+	return es.sendMessage(ctx, Event{
+		EventTime: time.Now().UTC(),
+		Data:      cse,
+	})
+}
+
+func (es *eventStream) SendSplitEvent(ctx context.Context, sp SplitEvent) error {
+	if sp.TimingPoint == "" {
+		return fmt.Errorf("split event needs a timing point")
+	}
+
+	// wrap split event with event and send
+	return es.sendMessage(ctx, Event{
+		EventTime: time.Now().UTC(),
+		Data:      sp,
+	})
+}
+
 func (es *eventStream) SendFinishEvent(ctx context.Context, fe FinishEvent) error {
 	// wrap finish event with event and send
 	return es.sendMessage(ctx, Event{
@@ -72,6 +102,38 @@ func (es *eventStream) SendPlaceEvent(ctx context.Context, pe PlaceEvent) error
 	})
 }
 
+func (es *eventStream) SendStatusEvent(ctx context.Context, se StatusEvent) error {
+	if !IsValidStatus(se.Status) {
+		return fmt.Errorf("unknown status %q", se.Status)
+	}
+
+	// wrap status event with event and send
+	return es.sendMessage(ctx, Event{
+		EventTime: time.Now().UTC(),
+		Data:      se,
+	})
+}
+
+func (es *eventStream) SendVoidEvent(ctx context.Context, ve VoidEvent) error {
+	if ve.EventID == "" {
+		return fmt.Errorf("void event needs the id of the event to void")
+	}
+
+	// wrap void event with event and send
+	return es.sendMessage(ctx, Event{
+		EventTime: time.Now().UTC(),
+		Data:      ve,
+	})
+}
+
+func (es *eventStream) SendRejectedReadEvent(ctx context.Context, rre RejectedReadEvent) error {
+	// wrap rejected read event with event and send
+	return es.sendMessage(ctx, Event{
+		EventTime: rre.ReadTime,
+		Data:      rre,
+	})
+}
+
 func (es *eventStream) sendMessage(ctx context.Context, e Event) error {
 	eventData, err := json.Marshal(e)
 	if err != nil {
@@ -103,6 +165,14 @@ func (es *eventStream) GetRaceEvent(ctx context.Context, timeout time.Duration,
 	return true, nil
 }
 
+func (es *eventStream) SeekAfterEvent(id string) error {
+	seeker, ok := es.raw.(stream.Seeker)
+	if !ok {
+		return fmt.Errorf("stream can't seek")
+	}
+	return seeker.SeekAfter(id)
+}
+
 func (rs *eventStream) RangeQueryMin() string {
 	return rs.raw.RangeQueryMin()
 }
@@ -60,6 +60,52 @@ func TestSendStartEvent(t *testing.T) {
 	assert.Equal(t, sentEvent, actualStartEvent)
 }
 
+func TestSendChipStartEvent(t *testing.T) {
+	mock := &stream.MockStream{}
+	es := NewEventStream(mock)
+
+	sentEvent := ChipStartEvent{
+		Source:    t.Name(),
+		Bib:       101,
+		StartTime: time.Now().UTC(),
+	}
+
+	err := es.SendChipStartEvent(context.TODO(), sentEvent)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(mock.Events))
+
+	var actualEvent Event
+	read, err := es.GetRaceEvent(context.TODO(), 0, &actualEvent)
+	assert.NoError(t, err)
+	assert.True(t, read)
+	assert.Equal(t, sentEvent, actualEvent.Data)
+}
+
+func TestSendSplitEvent(t *testing.T) {
+	mock := &stream.MockStream{}
+	es := NewEventStream(mock)
+
+	sentEvent := SplitEvent{
+		Source:      t.Name(),
+		Bib:         101,
+		TimingPoint: "1 mile",
+		SplitTime:   time.Now().UTC(),
+	}
+
+	err := es.SendSplitEvent(context.TODO(), sentEvent)
+	assert.NoError(t, err)
+
+	var actualEvent Event
+	read, err := es.GetRaceEvent(context.TODO(), 0, &actualEvent)
+	assert.NoError(t, err)
+	assert.True(t, read)
+	assert.Equal(t, sentEvent, actualEvent.Data)
+
+	// a split has to say where it was read
+	err = es.SendSplitEvent(context.TODO(), SplitEvent{Source: t.Name(), Bib: 101})
+	assert.Error(t, err)
+}
+
 func TestSendFinishEvent(t *testing.T) {
 	mock := &stream.MockStream{}
 	finishTime := time.Now().UTC().Add(time.Minute)
@@ -189,3 +235,65 @@ func TestGetRaceEventRange(t *testing.T) {
 	assert.True(t, isPlaceEvent)
 	assert.Equal(t, sentEvent, pe)
 }
+
+func TestSendStatusEvent(t *testing.T) {
+	mock := &stream.MockStream{}
+	es := NewEventStream(mock)
+
+	sentEvent := StatusEvent{
+		Source:   t.Name(),
+		Bib:      412,
+		Status:   StatusDNF,
+		Reason:   "injured",
+		Official: "head timer",
+	}
+
+	err := es.SendStatusEvent(context.TODO(), sentEvent)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(mock.Events))
+
+	var actualEvent Event
+	read, err := es.GetRaceEvent(context.TODO(), 0, &actualEvent)
+	assert.NoError(t, err)
+	assert.True(t, read)
+	assert.Equal(t, sentEvent, actualEvent.Data)
+
+	err = es.SendStatusEvent(context.TODO(), StatusEvent{Bib: 1, Status: "LATE"})
+	assert.Error(t, err)
+	assert.Equal(t, 0, len(mock.Events))
+}
+
+func TestSendVoidEvent(t *testing.T) {
+	mock := &stream.MockStream{}
+	es := NewEventStream(mock)
+
+	err := es.SendVoidEvent(context.TODO(), VoidEvent{Source: t.Name()})
+	assert.Error(t, err)
+	assert.Equal(t, 0, len(mock.Events))
+
+	sentEvent := VoidEvent{Source: t.Name(), EventID: "1-0", Reason: "walk by"}
+	err = es.SendVoidEvent(context.TODO(), sentEvent)
+	assert.NoError(t, err)
+
+	var actualEvent Event
+	read, err := es.GetRaceEvent(context.TODO(), 0, &actualEvent)
+	assert.NoError(t, err)
+	assert.True(t, read)
+	assert.Equal(t, sentEvent, actualEvent.Data)
+}
+
+func TestSendRejectedReadEvent(t *testing.T) {
+	mock := &stream.MockStream{}
+	es := NewEventStream(mock)
+
+	sentEvent := RejectedReadEvent{Source: t.Name(), Bib: 7, ReadTime: time.Now().UTC(), Reason: "debounce"}
+	err := es.SendRejectedReadEvent(context.TODO(), sentEvent)
+	assert.NoError(t, err)
+
+	var actualEvent Event
+	read, err := es.GetRaceEvent(context.TODO(), 0, &actualEvent)
+	assert.NoError(t, err)
+	assert.True(t, read)
+	assert.Equal(t, sentEvent, actualEvent.Data)
+	assert.Equal(t, sentEvent.ReadTime, actualEvent.EventTime)
+}
@@ -1,6 +1,7 @@
 package raceevents
 
 import (
+	"blreynolds4/event-race-timer/internal/stream"
 	"context"
 	"time"
 )
@@ -8,8 +9,13 @@ import (
 type MockEventStream struct {
 	SendWorkout func(ctx context.Context, se WorkoutEvent) error
 	SendStart   func(ctx context.Context, se StartEvent) error
+	SendChip    func(ctx context.Context, cse ChipStartEvent) error
+	SendSplit   func(ctx context.Context, sp SplitEvent) error
 	SendFinish  func(ctx context.Context, fe FinishEvent) error
 	SendPlace   func(ctx context.Context, pe PlaceEvent) error
+	SendStatus  func(ctx context.Context, se StatusEvent) error
+	SendVoid    func(ctx context.Context, ve VoidEvent) error
+	SendReject  func(ctx context.Context, rre RejectedReadEvent) error
 	Get         func(ctx context.Context, timeout time.Duration, msg *Event) (bool, error)
 	Range       func(ctx context.Context, startId, endId string, msgs []Event) (int, error)
 	Events      []Event
@@ -28,6 +34,30 @@ func (mes *MockEventStream) SendStartEvent(ctx context.Context, se StartEvent) e
 	return nil
 }
 
+func (mes *MockEventStream) SendChipStartEvent(ctx context.Context, cse ChipStartEvent) error {
+	if mes.SendChip != nil {
+		return mes.SendChip(ctx, cse)
+	}
+
+	mes.Events = append(mes.Events, Event{
+		EventTime: cse.StartTime,
+		Data:      cse,
+	})
+	return nil
+}
+
+func (mes *MockEventStream) SendSplitEvent(ctx context.Context, sp SplitEvent) error {
+	if mes.SendSplit != nil {
+		return mes.SendSplit(ctx, sp)
+	}
+
+	mes.Events = append(mes.Events, Event{
+		EventTime: sp.SplitTime,
+		Data:      sp,
+	})
+	return nil
+}
+
 func (mes *MockEventStream) SendFinishEvent(ctx context.Context, fe FinishEvent) error {
 	if mes.SendStart != nil {
 		return mes.SendFinish(ctx, fe)
@@ -52,6 +82,42 @@ func (mes *MockEventStream) SendPlaceEvent(ctx context.Context, pe PlaceEvent) e
 	return nil
 }
 
+func (mes *MockEventStream) SendStatusEvent(ctx context.Context, se StatusEvent) error {
+	if mes.SendStatus != nil {
+		return mes.SendStatus(ctx, se)
+	}
+
+	mes.Events = append(mes.Events, Event{
+		EventTime: time.Now().UTC(),
+		Data:      se,
+	})
+	return nil
+}
+
+func (mes *MockEventStream) SendVoidEvent(ctx context.Context, ve VoidEvent) error {
+	if mes.SendVoid != nil {
+		return mes.SendVoid(ctx, ve)
+	}
+
+	mes.Events = append(mes.Events, Event{
+		EventTime: time.Now().UTC(),
+		Data:      ve,
+	})
+	return nil
+}
+
+func (mes *MockEventStream) SendRejectedReadEvent(ctx context.Context, rre RejectedReadEvent) error {
+	if mes.SendReject != nil {
+		return mes.SendReject(ctx, rre)
+	}
+
+	mes.Events = append(mes.Events, Event{
+		EventTime: rre.ReadTime,
+		Data:      rre,
+	})
+	return nil
+}
+
 func (mes *MockEventStream) SendWorkoutEvent(ctx context.Context, we WorkoutEvent) error {
 	if mes.SendStart != nil {
 		return mes.SendWorkout(ctx, we)
@@ -103,3 +169,24 @@ func (mes *MockEventStream) GetRaceEventRange(ctx context.Context, startId, endI
 
 	return 0, nil
 }
+
+// MockEventGroupStream is a MockEventStream read as a consumer group.
+// Acknowledged event ids are kept in Acked.
+type MockEventGroupStream struct {
+	MockEventStream
+	Position stream.GroupPosition
+	Acked    []string
+}
+
+func (mgs *MockEventGroupStream) AckRaceEvent(ctx context.Context, id string) error {
+	mgs.Acked = append(mgs.Acked, id)
+	return nil
+}
+
+func (mgs *MockEventGroupStream) ClaimRaceEvents(ctx context.Context, minIdle time.Duration, events []Event) (int, error) {
+	return 0, nil
+}
+
+func (mgs *MockEventGroupStream) GetGroupPosition(ctx context.Context) (stream.GroupPosition, error) {
+	return mgs.Position, nil
+}
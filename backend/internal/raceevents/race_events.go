@@ -10,6 +10,13 @@ const (
 	NoBib = -1
 )
 
+// athlete statuses for a StatusEvent, an empty status clears a previous one
+const (
+	StatusDNF = "DNF" // did not finish
+	StatusDNS = "DNS" // did not start
+	StatusDQ  = "DQ"  // disqualified
+)
+
 type Event struct {
 	ID        string
 	EventTime time.Time // needs to come from source event as the time event created at source
@@ -22,9 +29,26 @@ type WorkoutEvent struct {
 	Bib       int
 }
 
+// StartEvent starts the athletes in Wave, the "" wave is everyone not assigned to a wave
 type StartEvent struct {
 	Source    string
 	StartTime time.Time
+	Wave      string
+}
+
+// ChipStartEvent is a bib crossing the start mat, it's used for the athlete's net time
+type ChipStartEvent struct {
+	Source    string
+	Bib       int
+	StartTime time.Time
+}
+
+// SplitEvent is a bib passing an intermediate timing point on the course, ie "1 mile"
+type SplitEvent struct {
+	Source      string
+	Bib         int
+	TimingPoint string
+	SplitTime   time.Time
 }
 
 type FinishEvent struct {
@@ -39,6 +63,42 @@ type PlaceEvent struct {
 	Place  int
 }
 
+// StatusEvent records that a bib did not start, did not finish or was disqualified.
+// Official is who made the call, ie the referee.
+type StatusEvent struct {
+	Source   string
+	Bib      int
+	Status   string
+	Reason   string
+	Official string
+}
+
+// VoidEvent retracts the earlier event with id EventID.
+// Consumers treat the voided event as if it never arrived.
+type VoidEvent struct {
+	Source  string
+	EventID string
+	Reason  string
+}
+
+// RejectedReadEvent is a finish read the read filter kept out of the results.
+// It's kept in the stream so an official can review it and enter the finish if it was real.
+type RejectedReadEvent struct {
+	Source   string
+	Bib      int
+	ReadTime time.Time
+	Reason   string
+}
+
+// IsValidStatus is true for the statuses a StatusEvent can carry
+func IsValidStatus(status string) bool {
+	switch status {
+	case "", StatusDNF, StatusDNS, StatusDQ:
+		return true
+	}
+	return false
+}
+
 type marshalledEvent struct {
 	ID        string
 	EventTime time.Time
@@ -58,10 +118,20 @@ func (e Event) MarshalJSON() ([]byte, error) {
 		actual.DataType = "workout"
 	case StartEvent:
 		actual.DataType = "start"
+	case ChipStartEvent:
+		actual.DataType = "chipstart"
+	case SplitEvent:
+		actual.DataType = "split"
 	case FinishEvent:
 		actual.DataType = "finish"
 	case PlaceEvent:
 		actual.DataType = "place"
+	case StatusEvent:
+		actual.DataType = "status"
+	case VoidEvent:
+		actual.DataType = "void"
+	case RejectedReadEvent:
+		actual.DataType = "rejected"
 	default:
 		return nil, fmt.Errorf("unknown type in Event Data %v", t)
 	}
@@ -101,6 +171,14 @@ func (e *Event) UnmarshalJSON(data []byte) error {
 		var se StartEvent
 		err = json.Unmarshal(*objmap["Data"], &se)
 		e.Data = se
+	case "chipstart":
+		var cse ChipStartEvent
+		err = json.Unmarshal(*objmap["Data"], &cse)
+		e.Data = cse
+	case "split":
+		var sp SplitEvent
+		err = json.Unmarshal(*objmap["Data"], &sp)
+		e.Data = sp
 	case "finish":
 		var fe FinishEvent
 		err = json.Unmarshal(*objmap["Data"], &fe)
@@ -109,6 +187,18 @@ func (e *Event) UnmarshalJSON(data []byte) error {
 		var pe PlaceEvent
 		err = json.Unmarshal(*objmap["Data"], &pe)
 		e.Data = pe
+	case "status":
+		var se StatusEvent
+		err = json.Unmarshal(*objmap["Data"], &se)
+		e.Data = se
+	case "void":
+		var ve VoidEvent
+		err = json.Unmarshal(*objmap["Data"], &ve)
+		e.Data = ve
+	case "rejected":
+		var rre RejectedReadEvent
+		err = json.Unmarshal(*objmap["Data"], &rre)
+		e.Data = rre
 	default:
 		return fmt.Errorf("unknown type in Event Data")
 	}
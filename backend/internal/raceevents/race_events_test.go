@@ -13,6 +13,7 @@ func TestMarshallEventStartEvent(t *testing.T) {
 	startEvent := StartEvent{
 		Source:    t.Name(),
 		StartTime: testTime,
+		Wave:      "girls",
 	}
 
 	testEvent := Event{
@@ -121,3 +122,106 @@ func TestMarshallEventWorkoutEvent(t *testing.T) {
 	assert.Equal(t, bib, workoutEvent.Bib)
 	assert.Equal(t, splitTime, workoutEvent.SplitTime)
 }
+
+func TestMarshallEventStatusEvent(t *testing.T) {
+	testTime := time.Now().UTC()
+	statusEvent := StatusEvent{
+		Source:   t.Name(),
+		Bib:      412,
+		Status:   StatusDQ,
+		Reason:   "cut the course",
+		Official: "referee",
+	}
+
+	testEvent := Event{
+		EventTime: testTime,
+		Data:      statusEvent,
+	}
+
+	data, err := json.Marshal(testEvent)
+	assert.Nil(t, err)
+
+	var loaded Event
+	err = json.Unmarshal(data, &loaded)
+	assert.Nil(t, err)
+	assert.Equal(t, testEvent.EventTime, testTime)
+	actualSe, typeOk := loaded.Data.(StatusEvent)
+	assert.True(t, typeOk)
+	assert.Equal(t, statusEvent, actualSe)
+}
+
+func TestMarshallEventVoidEvent(t *testing.T) {
+	voidEvent := VoidEvent{
+		Source:  t.Name(),
+		EventID: "1526919030474-55",
+		Reason:  "walk by read",
+	}
+
+	testEvent := Event{
+		EventTime: time.Now().UTC(),
+		Data:      voidEvent,
+	}
+
+	data, err := json.Marshal(testEvent)
+	assert.Nil(t, err)
+
+	var loaded Event
+	err = json.Unmarshal(data, &loaded)
+	assert.Nil(t, err)
+	actualVe, typeOk := loaded.Data.(VoidEvent)
+	assert.True(t, typeOk)
+	assert.Equal(t, voidEvent, actualVe)
+}
+
+func TestMarshallEventChipStartEvent(t *testing.T) {
+	testTime := time.Now().UTC()
+	chipStart := ChipStartEvent{
+		Source:    t.Name(),
+		Bib:       101,
+		StartTime: testTime,
+	}
+
+	data, err := json.Marshal(Event{EventTime: testTime, Data: chipStart})
+	assert.Nil(t, err)
+
+	var loaded Event
+	err = json.Unmarshal(data, &loaded)
+	assert.Nil(t, err)
+	assert.Equal(t, chipStart, loaded.Data)
+}
+
+func TestMarshallEventSplitEvent(t *testing.T) {
+	testTime := time.Now().UTC()
+	split := SplitEvent{
+		Source:      t.Name(),
+		Bib:         101,
+		TimingPoint: "2 mile",
+		SplitTime:   testTime,
+	}
+
+	data, err := json.Marshal(Event{EventTime: testTime, Data: split})
+	assert.Nil(t, err)
+
+	var loaded Event
+	err = json.Unmarshal(data, &loaded)
+	assert.Nil(t, err)
+	assert.Equal(t, split, loaded.Data)
+}
+
+func TestMarshallEventRejectedReadEvent(t *testing.T) {
+	testTime := time.Now().UTC()
+	rejected := RejectedReadEvent{
+		Source:   t.Name(),
+		Bib:      101,
+		ReadTime: testTime,
+		Reason:   "debounce",
+	}
+
+	data, err := json.Marshal(Event{EventTime: testTime, Data: rejected})
+	assert.Nil(t, err)
+
+	var loaded Event
+	err = json.Unmarshal(data, &loaded)
+	assert.Nil(t, err)
+	assert.Equal(t, rejected, loaded.Data)
+}
@@ -0,0 +1,170 @@
+package raceweb
+
+import (
+	"blreynolds4/event-race-timer/internal/competitors"
+	"blreynolds4/event-race-timer/internal/config"
+	"blreynolds4/event-race-timer/internal/meets"
+	"blreynolds4/event-race-timer/internal/raceevents"
+	"blreynolds4/event-race-timer/internal/raceweb/handler"
+	"blreynolds4/event-race-timer/internal/workouts"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// shutdownTimeout is how long requests in flight get to finish when the server stops
+const shutdownTimeout = 5 * time.Second
+
+type Application interface {
+	// Run serves until ctx is cancelled, then finishes the requests in flight
+	Run(ctx context.Context, address string) error
+}
+
+// Workout is a practice session timed with the same readers, its reads go to the session's stream
+type Workout struct {
+	Session  workouts.Session
+	Athletes competitors.CompetitorLookup
+	Events   raceevents.EventStream
+}
+
+type application struct {
+	router *gin.Engine
+	// stopping is closed when the server stops so long lived connections close
+	stopping chan struct{}
+}
+
+// Services are what the application serves, the routes for a nil service aren't served
+type Services struct {
+	// Sources are the readers sending race and workout reads
+	Sources config.SourceConfig
+	// Races are the races being timed, reads of other bibs are saved to Orphans
+	Races   handler.RaceRouter
+	Orphans meets.OrphanReadStore
+	// Streams are the races being timed, manual events are sent to them
+	Streams handler.RaceStreams
+	Meets   meets.MeetReader
+	// Admin creates and edits meets, races and their athletes
+	Admin   handler.MeetAdmin
+	Results handler.RaceResults
+	// Conflicts finds duplicate, missing and unfinished places in the saved results
+	Conflicts handler.PlaceConflicts
+	Live      handler.LiveResults
+	Workout   *Workout
+}
+
+// NewApplication serves the routes for each of the services
+func NewApplication(services Services, logger *slog.Logger) Application {
+	router := gin.Default()
+	stopping := make(chan struct{})
+
+	// Setup route group for the API
+	api := router.Group("/api")
+	api.GET("/timingEvents", handler.NewVerifyTimingHandler(logger))
+	if services.Races != nil {
+		api.POST("/timingEvents/finishes", handler.NewRaceTimingHandler(services.Sources, services.Races, services.Orphans, logger))
+	}
+
+	// manual timing api, it's only served with tokens for sending manual events
+	if services.Streams != nil && len(services.Sources.Manual.Tokens) > 0 {
+		manual := api.Group("/races/:race/manual", handler.NewManualAuth(services.Sources.Manual, logger))
+		manual.POST("/start", handler.NewManualStartHandler(services.Streams, logger))
+		manual.POST("/finish", handler.NewManualFinishHandler(services.Streams, logger))
+		manual.POST("/place", handler.NewManualPlaceHandler(services.Streams, logger))
+		manual.POST("/placeRange", handler.NewManualPlaceRangeHandler(services.Streams, logger))
+		manual.POST("/addBib", handler.NewManualAddBibHandler(services.Streams, logger))
+	}
+
+	// workout api
+	if workout := services.Workout; workout != nil {
+		api.POST("/timingEvents/workouts", handler.NewWorkoutHandler(services.Sources, workout.Athletes, workout.Events, logger))
+		api.GET("/workouts/reps", handler.NewWorkoutRepsHandler(workout.Session, workout.Athletes, workout.Events, logger))
+	}
+
+	// meet api
+	if services.Meets != nil {
+		api.GET("/meets", handler.NewMeetListHandler(services.Meets, logger))
+	}
+
+	// meet admin api, athletes are entered in races by bib.  It's only served to admin tokens.
+	if admin := services.Admin; admin != nil && len(services.Sources.Admin.Tokens) > 0 {
+		meets := api.Group("", handler.NewAdminAuth(services.Sources.Admin, logger))
+		meets.POST("/meets", handler.NewCreateMeetHandler(admin, logger))
+		meets.GET("/meets/:meet", handler.NewGetMeetHandler(admin, logger))
+		meets.PUT("/meets/:meet", handler.NewRenameMeetHandler(admin, logger))
+		meets.POST("/meets/:meet/races", handler.NewCreateRaceHandler(admin, logger))
+		meets.PUT("/meets/:meet/races/:race", handler.NewRenameRaceHandler(admin, logger))
+		meets.GET("/meets/:meet/races/:race/entries", handler.NewEntriesHandler(admin, logger))
+		meets.POST("/meets/:meet/races/:race/entries", handler.NewAddEntryHandler(admin, logger))
+		meets.PUT("/meets/:meet/races/:race/entries/:bib", handler.NewMoveEntryHandler(admin, logger))
+		meets.DELETE("/meets/:meet/races/:race/entries/:bib", handler.NewRemoveEntryHandler(admin, logger))
+		meets.GET("/athletes/:daId", handler.NewGetAthleteHandler(admin, logger))
+		meets.PUT("/athletes/:daId", handler.NewUpdateAthleteHandler(admin, logger))
+	}
+
+	// results api, filtered with the gender, grade and team query parameters
+	if services.Results != nil {
+		race := api.Group("/meets/:meet/races/:race")
+		race.GET("/results", handler.NewRaceResultsHandler(services.Results, logger))
+		race.GET("/teams", handler.NewTeamScoresHandler(services.Results, logger))
+		race.GET("/teams/:team", handler.NewTeamHandler(services.Results, logger))
+		race.GET("/athletes/:bib", handler.NewAthleteResultHandler(services.Results, logger))
+	}
+
+	// place conflicts, they're fixed by sending corrective places with a manual token
+	if services.Conflicts != nil {
+		api.GET("/meets/:meet/races/:race/conflicts", handler.NewPlaceConflictsHandler(services.Conflicts, logger))
+	}
+	if services.Results != nil && services.Streams != nil && len(services.Sources.Manual.Tokens) > 0 {
+		places := api.Group("/meets/:meet/races/:race/places", handler.NewManualAuth(services.Sources.Manual, logger))
+		places.POST("/renumber", handler.NewRenumberPlacesHandler(services.Results, services.Streams, logger))
+		places.POST("/move", handler.NewMovePlaceHandler(services.Results, services.Streams, logger))
+		places.POST("/remove", handler.NewRemovePlaceHandler(services.Results, services.Streams, logger))
+	}
+
+	// live results, server-sent events or a websocket
+	if services.Live != nil {
+		api.GET("/races/:race/results/live", handler.NewLiveResultsHandler(services.Live, stopping, logger))
+	}
+
+	// results paths
+	router.GET("/overall/:race", handler.NewOverallHTMLHandler())
+
+	return &application{
+		router:   router,
+		stopping: stopping,
+	}
+}
+
+func (a *application) Run(ctx context.Context, address string) error {
+	server := &http.Server{
+		Addr:    address,
+		Handler: a.router,
+	}
+	// shutdown doesn't wait for streams or websockets, they're told to close
+	server.RegisterOnShutdown(func() {
+		close(a.stopping)
+	})
+
+	served := make(chan error, 1)
+	go func() {
+		served <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-served:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), shutdownTimeout)
+	defer cancel()
+	err := server.Shutdown(shutdownCtx)
+	if serveErr := <-served; !errors.Is(serveErr, http.ErrServerClosed) {
+		err = errors.Join(err, serveErr)
+	}
+	return err
+}
@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	// ErrNotFound is returned by the stores handlers use when what was asked for doesn't exist, it's a 404
+	ErrNotFound = errors.New("not found")
+	// ErrConflict is returned when a change clashes with what's saved, like a bib already in the meet, it's a 409
+	ErrConflict = errors.New("conflict")
+	// ErrInvalid is returned when a request refers to something that can't be used, like an unknown race, it's a 400
+	ErrInvalid = errors.New("invalid")
+)
+
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// errorStatus is the response status for an error returned by a store
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, ErrInvalid):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// respondError responds with the status for err, errors that aren't the caller's are logged with msg
+func respondError(c *gin.Context, err error, logger *slog.Logger, msg string) {
+	status := errorStatus(err)
+	if status == http.StatusInternalServerError {
+		logger.Error(msg, "path", c.Request.URL.Path, "error", err)
+	}
+	c.IndentedJSON(status, ErrorResponse{Error: err.Error()})
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"blreynolds4/event-race-timer/internal/liveresults"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/websocket"
+)
+
+// LiveResults sends a race's results as they're saved
+type LiveResults interface {
+	Subscribe(raceName string) (*liveresults.Subscription, error)
+}
+
+// NewLiveResultsHandler pushes a race's results, a snapshot first then the changes as the
+// results are saved.  Requests that ask to upgrade get a websocket, the rest get server-sent
+// events.  Connections are closed when stopping is closed.
+func NewLiveResultsHandler(live LiveResults, stopping <-chan struct{}, logger *slog.Logger) gin.HandlerFunc {
+	fn := func(c *gin.Context) {
+		raceName := c.Param("race")
+		sub, err := live.Subscribe(raceName)
+		if errors.Is(err, liveresults.ErrUnknownRace) {
+			c.IndentedJSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+			return
+		}
+		if err != nil {
+			logger.Error("error subscribing to live results", "race", raceName, "error", err)
+			c.IndentedJSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+			return
+		}
+		defer sub.Close()
+
+		if strings.EqualFold(c.GetHeader("Upgrade"), "websocket") {
+			websocket.Handler(func(ws *websocket.Conn) {
+				sendWebSocket(ws, sub, stopping)
+			}).ServeHTTP(c.Writer, c.Request)
+			logger.Info("live results websocket closed", "race", raceName, "remote", c.Request.RemoteAddr)
+			return
+		}
+
+		c.Stream(func(w io.Writer) bool {
+			select {
+			case update, ok := <-sub.Updates:
+				if !ok {
+					return false
+				}
+				c.SSEvent(update.Type, update)
+				return true
+			case <-c.Request.Context().Done():
+				return false
+			case <-stopping:
+				return false
+			}
+		})
+		logger.Info("live results stream closed", "race", raceName, "remote", c.Request.RemoteAddr)
+	}
+
+	return gin.HandlerFunc(fn)
+}
+
+func sendWebSocket(ws *websocket.Conn, sub *liveresults.Subscription, stopping <-chan struct{}) {
+	// the client only listens, receiving fails when it closes the connection
+	closed := make(chan struct{})
+	go func() {
+		var ignored []byte
+		for websocket.Message.Receive(ws, &ignored) == nil {
+		}
+		close(closed)
+	}()
+
+	for {
+		select {
+		case update, ok := <-sub.Updates:
+			if !ok {
+				return
+			}
+			if websocket.JSON.Send(ws, update) != nil {
+				return
+			}
+		case <-closed:
+			return
+		case <-stopping:
+			return
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"blreynolds4/event-race-timer/internal/liveresults"
+	"blreynolds4/event-race-timer/internal/meets"
+	"bufio"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/websocket"
+)
+
+func newLiveResultsServer(t *testing.T, reader *meets.MockResultReader) (*httptest.Server, *liveresults.Hub) {
+	hub := liveresults.NewHub(func(raceName string) (meets.RaceResultReader, error) {
+		if raceName != "Varsity" {
+			return nil, nil
+		}
+		return reader, nil
+	}, slog.New(slog.DiscardHandler))
+
+	stopping := make(chan struct{})
+	router := gin.Default()
+	router.GET("/api/races/:race/results/live", NewLiveResultsHandler(hub, stopping, slog.New(slog.DiscardHandler)))
+	server := httptest.NewServer(router)
+	t.Cleanup(func() {
+		close(stopping)
+		server.Close()
+	})
+	return server, hub
+}
+
+func TestLiveResultsServerSentEvents(t *testing.T) {
+	reader := &meets.MockResultReader{Results: []*meets.RaceResult{
+		{Bib: 1, Athlete: meets.NewAthlete("D", "R", "WPI", "", 12, "m"), Place: 1, GunTime: 16 * time.Minute},
+	}}
+	server, hub := newLiveResultsServer(t, reader)
+
+	resp, err := http.Get(server.URL + "/api/races/Varsity/results/live")
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	events := bufio.NewScanner(resp.Body)
+	readEvent := func() (string, liveresults.Update) {
+		var event string
+		var update liveresults.Update
+		for events.Scan() {
+			line := events.Text()
+			if name, found := strings.CutPrefix(line, "event:"); found {
+				event = name
+			}
+			if data, found := strings.CutPrefix(line, "data:"); found {
+				assert.NoError(t, json.Unmarshal([]byte(data), &update))
+				return event, update
+			}
+		}
+		t.Fatal("stream ended")
+		return "", update
+	}
+
+	event, update := readEvent()
+	assert.Equal(t, liveresults.UpdateSnapshot, event)
+	assert.Equal(t, 1, len(update.Results))
+	assert.Equal(t, "D", update.Results[0].FirstName)
+
+	reader.Results = append(reader.Results, &meets.RaceResult{Bib: 2, Athlete: meets.NewAthlete("A", "O", "ORHS", "", 11, "m"), Place: 2, GunTime: 17 * time.Minute})
+	hub.Changed("Varsity")
+	event, update = readEvent()
+	assert.Equal(t, liveresults.UpdateChanges, event)
+	assert.Equal(t, 1, len(update.Results))
+	assert.Equal(t, 2, update.Results[0].Bib)
+}
+
+func TestLiveResultsWebSocket(t *testing.T) {
+	reader := &meets.MockResultReader{Results: []*meets.RaceResult{
+		{Bib: 1, Athlete: meets.NewAthlete("D", "R", "WPI", "", 12, "m"), Place: 1, GunTime: 16 * time.Minute},
+	}}
+	server, hub := newLiveResultsServer(t, reader)
+
+	ws, err := websocket.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/api/races/Varsity/results/live", "", server.URL)
+	assert.NoError(t, err)
+	defer ws.Close()
+
+	var update liveresults.Update
+	assert.NoError(t, websocket.JSON.Receive(ws, &update))
+	assert.Equal(t, liveresults.UpdateSnapshot, update.Type)
+	assert.Equal(t, 1, len(update.Results))
+
+	reader.Results = reader.Results[:0]
+	hub.Changed("Varsity")
+	assert.NoError(t, websocket.JSON.Receive(ws, &update))
+	assert.Equal(t, liveresults.UpdateChanges, update.Type)
+	assert.Equal(t, []int{1}, update.Removed)
+}
+
+func TestLiveResultsUnknownRace(t *testing.T) {
+	server, _ := newLiveResultsServer(t, &meets.MockResultReader{})
+
+	resp, err := http.Get(server.URL + "/api/races/JV/results/live")
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
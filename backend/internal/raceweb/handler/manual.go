@@ -0,0 +1,321 @@
+package handler
+
+import (
+	"blreynolds4/event-race-timer/internal/config"
+	"blreynolds4/event-race-timer/internal/raceevents"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// manualSourceKey is where NewManualAuth keeps the request's source name
+const manualSourceKey = "manualSource"
+
+// RaceStreams finds the event streams of the races being timed
+type RaceStreams interface {
+	// RaceStream returns the race's event stream, false when the race isn't being timed
+	RaceStream(raceName string) (raceevents.EventStream, bool)
+}
+
+// StartRequest starts a race or a wave like the cli's start [seed duration] [wave]
+type StartRequest struct {
+	// Seed is how long ago the race started, like 10s, the race starts now when it's empty
+	Seed string `json:"seed"`
+	Wave string `json:"wave"`
+}
+
+// FinishRequest is a manual finish, the bib can be left out and added later with addBib
+type FinishRequest struct {
+	Bib *int `json:"bib"`
+	// Time is the finish time, now when it's left out
+	Time *time.Time `json:"time"`
+}
+
+type PlaceRequest struct {
+	Bib   int `json:"bib"`
+	Place int `json:"place"`
+}
+
+// PlaceRangeRequest places bibs in the order they're listed starting at First, 1 when it's left out
+type PlaceRangeRequest struct {
+	First int   `json:"first"`
+	Bibs  []int `json:"bibs"`
+}
+
+// AddBibRequest sends a finish without a bib again with the bib
+type AddBibRequest struct {
+	EventID string `json:"eventId"`
+	Bib     int    `json:"bib"`
+}
+
+type ManualResponse struct {
+	Race   string `json:"race"`
+	Source string `json:"source"`
+}
+
+type StartResponse struct {
+	ManualResponse
+	Wave      string    `json:"wave"`
+	StartTime time.Time `json:"startTime"`
+}
+
+type FinishResponse struct {
+	ManualResponse
+	Bib        int       `json:"bib"`
+	FinishTime time.Time `json:"finishTime"`
+}
+
+type Place struct {
+	Bib   int `json:"bib"`
+	Place int `json:"place"`
+}
+
+type PlacesResponse struct {
+	ManualResponse
+	Places []Place `json:"places"`
+}
+
+// NewManualAuth only lets through requests with one of the manual tokens as their bearer
+// token, the token's source name is what the manual handlers send events with
+func NewManualAuth(manual config.ManualConfig, logger *slog.Logger) gin.HandlerFunc {
+	fn := func(c *gin.Context) {
+		token, _ := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		source, allowed := manual.Source(token)
+		if !allowed {
+			logger.Warn("rejected manual request without a token", "path", c.Request.URL.Path, "client", c.ClientIP())
+			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrorResponse{Error: "a manual token is required"})
+			return
+		}
+		c.Set(manualSourceKey, source)
+		c.Next()
+	}
+	return gin.HandlerFunc(fn)
+}
+
+// manualRace is the source name and stream for a manual request, it responds with a 404 for
+// a race that isn't being timed
+func manualRace(c *gin.Context, races RaceStreams) (ManualResponse, raceevents.EventStream, bool) {
+	raceName := c.Param("race")
+	eventStream, found := races.RaceStream(raceName)
+	if !found {
+		c.IndentedJSON(http.StatusNotFound, ErrorResponse{Error: fmt.Sprintf("race %s isn't being timed", raceName)})
+		return ManualResponse{}, nil, false
+	}
+	return ManualResponse{Race: raceName, Source: c.GetString(manualSourceKey)}, eventStream, true
+}
+
+func NewManualStartHandler(races RaceStreams, logger *slog.Logger) gin.HandlerFunc {
+	fn := func(c *gin.Context) {
+		// an event that arrived is sent even if the client hangs up
+		ctx := context.WithoutCancel(c.Request.Context())
+		startTime := time.Now().UTC()
+
+		var request StartRequest
+		if !bindJSON(c, &request) {
+			return
+		}
+		if request.Seed != "" {
+			seed, err := time.ParseDuration(request.Seed)
+			if err != nil {
+				c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+				return
+			}
+			startTime = startTime.Add(-seed)
+		}
+		response, eventStream, ok := manualRace(c, races)
+		if !ok {
+			return
+		}
+
+		err := eventStream.SendStartEvent(ctx, raceevents.StartEvent{
+			Source:    response.Source,
+			StartTime: startTime,
+			Wave:      request.Wave,
+		})
+		if err != nil {
+			respondError(c, err, logger, "error sending manual start")
+			return
+		}
+
+		logger.Info("sent manual start", "race", response.Race, "wave", request.Wave, "time", startTime, "source", response.Source)
+		c.IndentedJSON(http.StatusCreated, StartResponse{ManualResponse: response, Wave: request.Wave, StartTime: startTime})
+	}
+	return gin.HandlerFunc(fn)
+}
+
+func NewManualFinishHandler(races RaceStreams, logger *slog.Logger) gin.HandlerFunc {
+	fn := func(c *gin.Context) {
+		ctx := context.WithoutCancel(c.Request.Context())
+		finishTime := time.Now().UTC()
+
+		var request FinishRequest
+		if !bindJSON(c, &request) {
+			return
+		}
+		bib := raceevents.NoBib
+		if request.Bib != nil {
+			bib = *request.Bib
+			if bib <= 0 {
+				c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: fmt.Sprintf("bad bib %d", bib)})
+				return
+			}
+		}
+		if request.Time != nil {
+			finishTime = request.Time.UTC()
+		}
+		response, eventStream, ok := manualRace(c, races)
+		if !ok {
+			return
+		}
+
+		err := eventStream.SendFinishEvent(ctx, raceevents.FinishEvent{
+			Source:     response.Source,
+			Bib:        bib,
+			FinishTime: finishTime,
+		})
+		if err != nil {
+			respondError(c, err, logger, "error sending manual finish")
+			return
+		}
+
+		logger.Info("sent manual finish", "race", response.Race, "bib", bib, "time", finishTime, "source", response.Source)
+		c.IndentedJSON(http.StatusCreated, FinishResponse{ManualResponse: response, Bib: bib, FinishTime: finishTime})
+	}
+	return gin.HandlerFunc(fn)
+}
+
+func NewManualPlaceHandler(races RaceStreams, logger *slog.Logger) gin.HandlerFunc {
+	fn := func(c *gin.Context) {
+		ctx := context.WithoutCancel(c.Request.Context())
+
+		var request PlaceRequest
+		if !bindJSON(c, &request) {
+			return
+		}
+		if request.Bib <= 0 || request.Place <= 0 {
+			c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: fmt.Sprintf("bad bib %d or place %d", request.Bib, request.Place)})
+			return
+		}
+		response, eventStream, ok := manualRace(c, races)
+		if !ok {
+			return
+		}
+
+		err := eventStream.SendPlaceEvent(ctx, raceevents.PlaceEvent{
+			Source: response.Source,
+			Bib:    request.Bib,
+			Place:  request.Place,
+		})
+		if err != nil {
+			respondError(c, err, logger, "error sending manual place")
+			return
+		}
+
+		logger.Info("sent manual place", "race", response.Race, "bib", request.Bib, "place", request.Place, "source", response.Source)
+		c.IndentedJSON(http.StatusCreated, PlacesResponse{ManualResponse: response, Places: []Place{{Bib: request.Bib, Place: request.Place}}})
+	}
+	return gin.HandlerFunc(fn)
+}
+
+// NewManualPlaceRangeHandler places a run of bibs read off the chute, like the cli's placeRange
+func NewManualPlaceRangeHandler(races RaceStreams, logger *slog.Logger) gin.HandlerFunc {
+	fn := func(c *gin.Context) {
+		ctx := context.WithoutCancel(c.Request.Context())
+
+		var request PlaceRangeRequest
+		if !bindJSON(c, &request) {
+			return
+		}
+		if request.First == 0 {
+			request.First = 1
+		}
+		if request.First < 0 || len(request.Bibs) == 0 {
+			c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: "bibs and a first place of 1 or more are required"})
+			return
+		}
+		for _, bib := range request.Bibs {
+			if bib <= 0 {
+				c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: fmt.Sprintf("bad bib %d", bib)})
+				return
+			}
+		}
+		response, eventStream, ok := manualRace(c, races)
+		if !ok {
+			return
+		}
+
+		places := make([]Place, 0, len(request.Bibs))
+		for i, bib := range request.Bibs {
+			place := Place{Bib: bib, Place: request.First + i}
+			err := eventStream.SendPlaceEvent(ctx, raceevents.PlaceEvent{
+				Source: response.Source,
+				Bib:    place.Bib,
+				Place:  place.Place,
+			})
+			if err != nil {
+				// the places already sent stay sent, the rest can be sent again from place.Place
+				respondError(c, fmt.Errorf("sent %d of %d places: %w", len(places), len(request.Bibs), err), logger, "error sending manual place range")
+				return
+			}
+			places = append(places, place)
+		}
+
+		logger.Info("sent manual place range", "race", response.Race, "first", request.First, "count", len(places), "source", response.Source)
+		c.IndentedJSON(http.StatusCreated, PlacesResponse{ManualResponse: response, Places: places})
+	}
+	return gin.HandlerFunc(fn)
+}
+
+// NewManualAddBibHandler sends a finish again with a bib, like the cli's addBib the finish
+// keeps its source and time
+func NewManualAddBibHandler(races RaceStreams, logger *slog.Logger) gin.HandlerFunc {
+	fn := func(c *gin.Context) {
+		ctx := context.WithoutCancel(c.Request.Context())
+
+		var request AddBibRequest
+		if !bindJSON(c, &request) {
+			return
+		}
+		if request.EventID == "" || request.Bib <= 0 {
+			c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: "an eventId and bib are required"})
+			return
+		}
+		response, eventStream, ok := manualRace(c, races)
+		if !ok {
+			return
+		}
+
+		events := make([]raceevents.Event, 1)
+		count, err := eventStream.GetRaceEventRange(ctx, request.EventID, request.EventID, events)
+		if err != nil {
+			respondError(c, err, logger, "error reading finish for add bib")
+			return
+		}
+		if count != 1 {
+			respondError(c, fmt.Errorf("event %s %w", request.EventID, ErrNotFound), logger, "error reading finish for add bib")
+			return
+		}
+		finish, isFinish := events[0].Data.(raceevents.FinishEvent)
+		if !isFinish {
+			respondError(c, fmt.Errorf("event %s isn't a finish %w", request.EventID, ErrInvalid), logger, "error reading finish for add bib")
+			return
+		}
+
+		finish.Bib = request.Bib
+		err = eventStream.SendFinishEvent(ctx, finish)
+		if err != nil {
+			respondError(c, err, logger, "error sending finish with bib")
+			return
+		}
+
+		response.Source = finish.Source
+		logger.Info("added bib to finish", "race", response.Race, "eventId", request.EventID, "bib", request.Bib, "source", finish.Source)
+		c.IndentedJSON(http.StatusCreated, FinishResponse{ManualResponse: response, Bib: finish.Bib, FinishTime: finish.FinishTime})
+	}
+	return gin.HandlerFunc(fn)
+}
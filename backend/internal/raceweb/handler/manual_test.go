@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"blreynolds4/event-race-timer/internal/config"
+	"blreynolds4/event-race-timer/internal/raceevents"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockRaceStreams map[string]*raceevents.MockEventStream
+
+func (mrs mockRaceStreams) RaceStream(raceName string) (raceevents.EventStream, bool) {
+	eventStream, found := mrs[raceName]
+	return eventStream, found
+}
+
+func newManualRouter(races RaceStreams) *gin.Engine {
+	logger := slog.New(slog.DiscardHandler)
+	manual := config.ManualConfig{Tokens: map[string]string{"chute-secret": "chute tablet", "timer-secret": ""}}
+
+	router := gin.Default()
+	group := router.Group("/api/races/:race/manual", NewManualAuth(manual, logger))
+	group.POST("/start", NewManualStartHandler(races, logger))
+	group.POST("/finish", NewManualFinishHandler(races, logger))
+	group.POST("/place", NewManualPlaceHandler(races, logger))
+	group.POST("/placeRange", NewManualPlaceRangeHandler(races, logger))
+	group.POST("/addBib", NewManualAddBibHandler(races, logger))
+	return router
+}
+
+func manualRequest(router *gin.Engine, token, path, body string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest("POST", path, strings.NewReader(body))
+	if token != "" {
+		r.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	return w
+}
+
+func TestManualAuth(t *testing.T) {
+	races := mockRaceStreams{"Varsity": &raceevents.MockEventStream{}}
+	router := newManualRouter(races)
+
+	assert.Equal(t, http.StatusUnauthorized, manualRequest(router, "", "/api/races/Varsity/manual/finish", `{}`).Code)
+	assert.Equal(t, http.StatusUnauthorized, manualRequest(router, "guess", "/api/races/Varsity/manual/finish", `{}`).Code)
+	assert.Empty(t, races["Varsity"].Events)
+
+	// the token's source name is sent, the default without one
+	assert.Equal(t, http.StatusCreated, manualRequest(router, "chute-secret", "/api/races/Varsity/manual/finish", `{}`).Code)
+	assert.Equal(t, http.StatusCreated, manualRequest(router, "timer-secret", "/api/races/Varsity/manual/finish", `{}`).Code)
+	assert.Equal(t, 2, len(races["Varsity"].Events))
+	assert.Equal(t, "chute tablet", races["Varsity"].Events[0].Data.(raceevents.FinishEvent).Source)
+	assert.Equal(t, config.DefaultManualSource, races["Varsity"].Events[1].Data.(raceevents.FinishEvent).Source)
+}
+
+func TestManualStartAndFinish(t *testing.T) {
+	races := mockRaceStreams{"Varsity": &raceevents.MockEventStream{}}
+	router := newManualRouter(races)
+
+	before := time.Now().UTC()
+	w := manualRequest(router, "chute-secret", "/api/races/Varsity/manual/start", `{"seed": "10s", "wave": "girls"}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	start := races["Varsity"].Events[0].Data.(raceevents.StartEvent)
+	assert.Equal(t, "girls", start.Wave)
+	assert.Equal(t, "chute tablet", start.Source)
+	assert.WithinDuration(t, before.Add(-10*time.Second), start.StartTime, time.Second)
+
+	assert.Equal(t, http.StatusBadRequest, manualRequest(router, "chute-secret", "/api/races/Varsity/manual/start", `{"seed": "soon"}`).Code)
+	assert.Equal(t, http.StatusNotFound, manualRequest(router, "chute-secret", "/api/races/JV/manual/start", `{}`).Code)
+
+	// a finish without a bib gets one later with addBib
+	assert.Equal(t, http.StatusCreated, manualRequest(router, "chute-secret", "/api/races/Varsity/manual/finish", `{}`).Code)
+	assert.Equal(t, raceevents.NoBib, races["Varsity"].Events[1].Data.(raceevents.FinishEvent).Bib)
+	w = manualRequest(router, "chute-secret", "/api/races/Varsity/manual/finish", `{"bib": 101, "time": "2025-10-18T14:16:05.5Z"}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, raceevents.FinishEvent{Source: "chute tablet", Bib: 101, FinishTime: time.Date(2025, 10, 18, 14, 16, 5, 500_000_000, time.UTC)},
+		races["Varsity"].Events[2].Data)
+	assert.Equal(t, http.StatusBadRequest, manualRequest(router, "chute-secret", "/api/races/Varsity/manual/finish", `{"bib": -5}`).Code)
+	assert.Equal(t, 3, len(races["Varsity"].Events))
+}
+
+func TestManualPlaces(t *testing.T) {
+	races := mockRaceStreams{"Varsity": &raceevents.MockEventStream{}}
+	router := newManualRouter(races)
+
+	assert.Equal(t, http.StatusCreated, manualRequest(router, "chute-secret", "/api/races/Varsity/manual/place", `{"bib": 101, "place": 1}`).Code)
+	assert.Equal(t, http.StatusBadRequest, manualRequest(router, "chute-secret", "/api/races/Varsity/manual/place", `{"bib": 101}`).Code)
+
+	w := manualRequest(router, "chute-secret", "/api/races/Varsity/manual/placeRange", `{"first": 2, "bibs": [107, 103, 112]}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.JSONEq(t, `{"race": "Varsity", "source": "chute tablet", "places": [{"bib": 107, "place": 2}, {"bib": 103, "place": 3}, {"bib": 112, "place": 4}]}`, w.Body.String())
+	assert.Equal(t, http.StatusBadRequest, manualRequest(router, "chute-secret", "/api/races/Varsity/manual/placeRange", `{"bibs": []}`).Code)
+
+	places := make([]raceevents.PlaceEvent, 0)
+	for _, e := range races["Varsity"].Events {
+		places = append(places, e.Data.(raceevents.PlaceEvent))
+	}
+	assert.Equal(t, []raceevents.PlaceEvent{
+		{Source: "chute tablet", Bib: 101, Place: 1},
+		{Source: "chute tablet", Bib: 107, Place: 2},
+		{Source: "chute tablet", Bib: 103, Place: 3},
+		{Source: "chute tablet", Bib: 112, Place: 4},
+	}, places)
+}
+
+func TestManualAddBib(t *testing.T) {
+	finishTime := time.Date(2025, 10, 18, 14, 16, 5, 0, time.UTC)
+	saved := map[string]raceevents.Event{
+		"1-0": {ID: "1-0", Data: raceevents.FinishEvent{Source: "chute", Bib: raceevents.NoBib, FinishTime: finishTime}},
+		"2-0": {ID: "2-0", Data: raceevents.PlaceEvent{Source: "chute", Bib: 101, Place: 1}},
+	}
+	varsity := &raceevents.MockEventStream{
+		Range: func(ctx context.Context, startId, endId string, events []raceevents.Event) (int, error) {
+			if e, found := saved[startId]; found {
+				events[0] = e
+				return 1, nil
+			}
+			return 0, nil
+		},
+	}
+	router := newManualRouter(mockRaceStreams{"Varsity": varsity})
+
+	// the finish keeps its source and time
+	assert.Equal(t, http.StatusCreated, manualRequest(router, "chute-secret", "/api/races/Varsity/manual/addBib", `{"eventId": "1-0", "bib": 101}`).Code)
+	assert.Equal(t, []raceevents.Event{{EventTime: finishTime, Data: raceevents.FinishEvent{Source: "chute", Bib: 101, FinishTime: finishTime}}}, varsity.Events)
+
+	assert.Equal(t, http.StatusBadRequest, manualRequest(router, "chute-secret", "/api/races/Varsity/manual/addBib", `{"eventId": "2-0", "bib": 101}`).Code)
+	assert.Equal(t, http.StatusNotFound, manualRequest(router, "chute-secret", "/api/races/Varsity/manual/addBib", `{"eventId": "3-0", "bib": 101}`).Code)
+	assert.Equal(t, http.StatusBadRequest, manualRequest(router, "chute-secret", "/api/races/Varsity/manual/addBib", `{"eventId": "1-0"}`).Code)
+	assert.Equal(t, 1, len(varsity.Events))
+}
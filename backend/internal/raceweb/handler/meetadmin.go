@@ -0,0 +1,305 @@
+package handler
+
+import (
+	"blreynolds4/event-race-timer/internal/config"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MeetAdmin creates and edits meets, races and the athletes in them.  Errors wrap
+// ErrNotFound for an unknown meet, race, bib or athlete, ErrConflict for a name or bib
+// that's taken and ErrInvalid for a request that can't be done.
+type MeetAdmin interface {
+	GetMeet(meetName string) (Meet, error)
+	CreateMeet(meetName string) (Meet, error)
+	RenameMeet(meetName, newName string) (Meet, error)
+	CreateRace(meetName, raceName string) (Meet, error)
+	RenameRace(meetName, raceName, newName string) (Meet, error)
+	// GetEntries returns the athletes in a race in bib order
+	GetEntries(meetName, raceName string) ([]Entry, error)
+	// AddEntry adds an athlete to a race, an athlete that isn't saved yet is created
+	AddEntry(meetName, raceName string, entry Entry) (Entry, error)
+	// MoveEntry moves the athlete with bib to another race in the meet, a new bib or wave
+	MoveEntry(meetName, raceName string, bib int, move EntryMove) (Entry, error)
+	RemoveEntry(meetName, raceName string, bib int) error
+	GetAthlete(daID string) (Athlete, error)
+	// UpdateAthlete changes an athlete's details in every race they're in
+	UpdateAthlete(daID string, athlete Athlete) (Athlete, error)
+}
+
+type Meet struct {
+	Name  string   `json:"name"`
+	Races []string `json:"races"`
+}
+
+type Athlete struct {
+	DaID      string `json:"daId"`
+	FirstName string `json:"firstName"`
+	LastName  string `json:"lastName"`
+	Team      string `json:"team"`
+	Grade     int    `json:"grade"`
+	Gender    string `json:"gender"`
+}
+
+// Entry is an athlete in a race
+type Entry struct {
+	Bib     int     `json:"bib"`
+	Wave    string  `json:"wave"`
+	Athlete Athlete `json:"athlete"`
+}
+
+// EntryMove is where an athlete is moved, the race, bib and wave they have are kept when
+// they aren't set
+type EntryMove struct {
+	Race string  `json:"race"`
+	Bib  int     `json:"bib"`
+	Wave *string `json:"wave"`
+}
+
+type NameRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+type EntriesResponse struct {
+	Meet    string  `json:"meet"`
+	Race    string  `json:"race"`
+	Entries []Entry `json:"entries"`
+}
+
+// bindJSON binds the request body responding with a 400 when it can't
+func bindJSON(c *gin.Context, data any) bool {
+	if err := c.ShouldBindJSON(data); err != nil {
+		c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return false
+	}
+	return true
+}
+
+// bibParam is the bib in the request's path, it responds with a 400 when it isn't a number
+func bibParam(c *gin.Context) (int, bool) {
+	bib, err := strconv.Atoi(c.Param("bib"))
+	if err != nil || bib <= 0 {
+		c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: fmt.Sprintf("bad bib %s", c.Param("bib"))})
+		return 0, false
+	}
+	return bib, true
+}
+
+// NewAdminAuth only lets through requests with one of the admin tokens as their bearer token
+func NewAdminAuth(admin config.AdminConfig, logger *slog.Logger) gin.HandlerFunc {
+	fn := func(c *gin.Context) {
+		token, _ := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		user, allowed := admin.User(token)
+		if !allowed {
+			logger.Warn("rejected admin request without a token", "path", c.Request.URL.Path, "client", c.ClientIP())
+			c.AbortWithStatusJSON(http.StatusUnauthorized, ErrorResponse{Error: "an admin token is required"})
+			return
+		}
+		logger.Info("admin request", "user", user, "method", c.Request.Method, "path", c.Request.URL.Path)
+		c.Next()
+	}
+	return gin.HandlerFunc(fn)
+}
+
+func NewGetMeetHandler(admin MeetAdmin, logger *slog.Logger) gin.HandlerFunc {
+	fn := func(c *gin.Context) {
+		meet, err := admin.GetMeet(c.Param("meet"))
+		if err != nil {
+			respondError(c, err, logger, "error getting meet")
+			return
+		}
+		c.IndentedJSON(http.StatusOK, meet)
+	}
+	return gin.HandlerFunc(fn)
+}
+
+func NewCreateMeetHandler(admin MeetAdmin, logger *slog.Logger) gin.HandlerFunc {
+	fn := func(c *gin.Context) {
+		var request NameRequest
+		if !bindJSON(c, &request) {
+			return
+		}
+		logger.Info("creating meet", "meet", request.Name)
+		meet, err := admin.CreateMeet(request.Name)
+		if err != nil {
+			respondError(c, err, logger, "error creating meet")
+			return
+		}
+		c.IndentedJSON(http.StatusCreated, meet)
+	}
+	return gin.HandlerFunc(fn)
+}
+
+func NewRenameMeetHandler(admin MeetAdmin, logger *slog.Logger) gin.HandlerFunc {
+	fn := func(c *gin.Context) {
+		var request NameRequest
+		if !bindJSON(c, &request) {
+			return
+		}
+		logger.Info("renaming meet", "meet", c.Param("meet"), "name", request.Name)
+		meet, err := admin.RenameMeet(c.Param("meet"), request.Name)
+		if err != nil {
+			respondError(c, err, logger, "error renaming meet")
+			return
+		}
+		c.IndentedJSON(http.StatusOK, meet)
+	}
+	return gin.HandlerFunc(fn)
+}
+
+func NewCreateRaceHandler(admin MeetAdmin, logger *slog.Logger) gin.HandlerFunc {
+	fn := func(c *gin.Context) {
+		var request NameRequest
+		if !bindJSON(c, &request) {
+			return
+		}
+		logger.Info("creating race", "meet", c.Param("meet"), "race", request.Name)
+		meet, err := admin.CreateRace(c.Param("meet"), request.Name)
+		if err != nil {
+			respondError(c, err, logger, "error creating race")
+			return
+		}
+		c.IndentedJSON(http.StatusCreated, meet)
+	}
+	return gin.HandlerFunc(fn)
+}
+
+func NewRenameRaceHandler(admin MeetAdmin, logger *slog.Logger) gin.HandlerFunc {
+	fn := func(c *gin.Context) {
+		var request NameRequest
+		if !bindJSON(c, &request) {
+			return
+		}
+		logger.Info("renaming race", "meet", c.Param("meet"), "race", c.Param("race"), "name", request.Name)
+		meet, err := admin.RenameRace(c.Param("meet"), c.Param("race"), request.Name)
+		if err != nil {
+			respondError(c, err, logger, "error renaming race")
+			return
+		}
+		c.IndentedJSON(http.StatusOK, meet)
+	}
+	return gin.HandlerFunc(fn)
+}
+
+func NewEntriesHandler(admin MeetAdmin, logger *slog.Logger) gin.HandlerFunc {
+	fn := func(c *gin.Context) {
+		entries, err := admin.GetEntries(c.Param("meet"), c.Param("race"))
+		if err != nil {
+			respondError(c, err, logger, "error getting race entries")
+			return
+		}
+		c.IndentedJSON(http.StatusOK, EntriesResponse{Meet: c.Param("meet"), Race: c.Param("race"), Entries: entries})
+	}
+	return gin.HandlerFunc(fn)
+}
+
+func NewAddEntryHandler(admin MeetAdmin, logger *slog.Logger) gin.HandlerFunc {
+	fn := func(c *gin.Context) {
+		var entry Entry
+		if !bindJSON(c, &entry) {
+			return
+		}
+		if entry.Bib <= 0 {
+			c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: fmt.Sprintf("bad bib %d", entry.Bib)})
+			return
+		}
+		if entry.Athlete.DaID == "" {
+			c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: "athlete daId is required"})
+			return
+		}
+
+		logger.Info("adding athlete to race", "meet", c.Param("meet"), "race", c.Param("race"), "bib", entry.Bib, "daId", entry.Athlete.DaID)
+		entry, err := admin.AddEntry(c.Param("meet"), c.Param("race"), entry)
+		if err != nil {
+			respondError(c, err, logger, "error adding athlete to race")
+			return
+		}
+		c.IndentedJSON(http.StatusCreated, entry)
+	}
+	return gin.HandlerFunc(fn)
+}
+
+func NewMoveEntryHandler(admin MeetAdmin, logger *slog.Logger) gin.HandlerFunc {
+	fn := func(c *gin.Context) {
+		bib, ok := bibParam(c)
+		if !ok {
+			return
+		}
+		var move EntryMove
+		if !bindJSON(c, &move) {
+			return
+		}
+		if move.Bib < 0 {
+			c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: fmt.Sprintf("bad bib %d", move.Bib)})
+			return
+		}
+
+		logger.Info("moving athlete", "meet", c.Param("meet"), "race", c.Param("race"), "bib", bib, "toRace", move.Race, "toBib", move.Bib)
+		entry, err := admin.MoveEntry(c.Param("meet"), c.Param("race"), bib, move)
+		if err != nil {
+			respondError(c, err, logger, "error moving athlete")
+			return
+		}
+		c.IndentedJSON(http.StatusOK, entry)
+	}
+	return gin.HandlerFunc(fn)
+}
+
+func NewRemoveEntryHandler(admin MeetAdmin, logger *slog.Logger) gin.HandlerFunc {
+	fn := func(c *gin.Context) {
+		bib, ok := bibParam(c)
+		if !ok {
+			return
+		}
+
+		logger.Info("removing athlete from race", "meet", c.Param("meet"), "race", c.Param("race"), "bib", bib)
+		err := admin.RemoveEntry(c.Param("meet"), c.Param("race"), bib)
+		if err != nil {
+			respondError(c, err, logger, "error removing athlete from race")
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+	return gin.HandlerFunc(fn)
+}
+
+func NewGetAthleteHandler(admin MeetAdmin, logger *slog.Logger) gin.HandlerFunc {
+	fn := func(c *gin.Context) {
+		athlete, err := admin.GetAthlete(c.Param("daId"))
+		if err != nil {
+			respondError(c, err, logger, "error getting athlete")
+			return
+		}
+		c.IndentedJSON(http.StatusOK, athlete)
+	}
+	return gin.HandlerFunc(fn)
+}
+
+// NewUpdateAthleteHandler replaces an athlete's details, the daId in the path picks the
+// athlete and can't be changed
+func NewUpdateAthleteHandler(admin MeetAdmin, logger *slog.Logger) gin.HandlerFunc {
+	fn := func(c *gin.Context) {
+		var athlete Athlete
+		if !bindJSON(c, &athlete) {
+			return
+		}
+		if athlete.FirstName == "" || athlete.LastName == "" || athlete.Gender == "" {
+			c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: "firstName, lastName and gender are required"})
+			return
+		}
+
+		logger.Info("updating athlete", "daId", c.Param("daId"))
+		athlete, err := admin.UpdateAthlete(c.Param("daId"), athlete)
+		if err != nil {
+			respondError(c, err, logger, "error updating athlete")
+			return
+		}
+		c.IndentedJSON(http.StatusOK, athlete)
+	}
+	return gin.HandlerFunc(fn)
+}
@@ -0,0 +1,157 @@
+package handler
+
+import (
+	"blreynolds4/event-race-timer/internal/config"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockMeetAdmin has one meet with a varsity race, bib 100 is in it and bib 200 is taken
+type mockMeetAdmin struct {
+	added   []Entry
+	moved   []EntryMove
+	removed []int
+}
+
+func (mma *mockMeetAdmin) GetMeet(meetName string) (Meet, error) {
+	if meetName != "Invitational" {
+		return Meet{}, fmt.Errorf("meet %s %w", meetName, ErrNotFound)
+	}
+	return Meet{Name: meetName, Races: []string{"Varsity"}}, nil
+}
+func (mma *mockMeetAdmin) CreateMeet(meetName string) (Meet, error) {
+	if meetName == "Invitational" {
+		return Meet{}, fmt.Errorf("meet %s already exists %w", meetName, ErrConflict)
+	}
+	return Meet{Name: meetName, Races: []string{}}, nil
+}
+func (mma *mockMeetAdmin) RenameMeet(meetName, newName string) (Meet, error) {
+	return Meet{Name: newName}, nil
+}
+func (mma *mockMeetAdmin) CreateRace(meetName, raceName string) (Meet, error) {
+	return mma.GetMeet(meetName)
+}
+func (mma *mockMeetAdmin) RenameRace(meetName, raceName, newName string) (Meet, error) {
+	return Meet{Name: meetName, Races: []string{newName}}, nil
+}
+func (mma *mockMeetAdmin) GetEntries(meetName, raceName string) ([]Entry, error) {
+	return []Entry{{Bib: 100}}, nil
+}
+func (mma *mockMeetAdmin) AddEntry(meetName, raceName string, entry Entry) (Entry, error) {
+	if entry.Bib == 200 {
+		return Entry{}, fmt.Errorf("bib 200 is already in race JV %w", ErrConflict)
+	}
+	mma.added = append(mma.added, entry)
+	return entry, nil
+}
+func (mma *mockMeetAdmin) MoveEntry(meetName, raceName string, bib int, move EntryMove) (Entry, error) {
+	if move.Race == "Girls" {
+		return Entry{}, fmt.Errorf("race Girls isn't in meet %s %w", meetName, ErrInvalid)
+	}
+	mma.moved = append(mma.moved, move)
+	return Entry{Bib: move.Bib}, nil
+}
+func (mma *mockMeetAdmin) RemoveEntry(meetName, raceName string, bib int) error {
+	if bib != 100 {
+		return fmt.Errorf("bib %d in race %s %w", bib, raceName, ErrNotFound)
+	}
+	mma.removed = append(mma.removed, bib)
+	return nil
+}
+func (mma *mockMeetAdmin) GetAthlete(daID string) (Athlete, error) {
+	return Athlete{DaID: daID}, nil
+}
+func (mma *mockMeetAdmin) UpdateAthlete(daID string, athlete Athlete) (Athlete, error) {
+	return Athlete{}, fmt.Errorf("database is down")
+}
+
+func newMeetAdminRouter(admin MeetAdmin) *gin.Engine {
+	logger := slog.New(slog.DiscardHandler)
+	router := gin.Default()
+	router.POST("/api/meets", NewCreateMeetHandler(admin, logger))
+	router.GET("/api/meets/:meet", NewGetMeetHandler(admin, logger))
+	router.POST("/api/meets/:meet/races", NewCreateRaceHandler(admin, logger))
+	router.POST("/api/meets/:meet/races/:race/entries", NewAddEntryHandler(admin, logger))
+	router.PUT("/api/meets/:meet/races/:race/entries/:bib", NewMoveEntryHandler(admin, logger))
+	router.DELETE("/api/meets/:meet/races/:race/entries/:bib", NewRemoveEntryHandler(admin, logger))
+	router.PUT("/api/athletes/:daId", NewUpdateAthleteHandler(admin, logger))
+	return router
+}
+
+func request(router *gin.Engine, method, path, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(method, path, strings.NewReader(body)))
+	return w
+}
+
+func TestAdminAuth(t *testing.T) {
+	logger := slog.New(slog.DiscardHandler)
+	router := gin.Default()
+	admin := config.AdminConfig{Tokens: map[string]string{"admin-secret": "meet director"}}
+	router.POST("/api/meets", NewAdminAuth(admin, logger), NewCreateMeetHandler(&mockMeetAdmin{}, logger))
+
+	create := func(token string) int {
+		r := httptest.NewRequest("POST", "/api/meets", strings.NewReader(`{"name": "Dual"}`))
+		if token != "" {
+			r.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		return w.Code
+	}
+	assert.Equal(t, http.StatusUnauthorized, create(""))
+	assert.Equal(t, http.StatusUnauthorized, create("guess"))
+	assert.Equal(t, http.StatusCreated, create("admin-secret"))
+}
+
+func TestMeetAdminHandlersMeets(t *testing.T) {
+	router := newMeetAdminRouter(&mockMeetAdmin{})
+
+	assert.Equal(t, http.StatusCreated, request(router, "POST", "/api/meets", `{"name": "Dual"}`).Code)
+	assert.Equal(t, http.StatusConflict, request(router, "POST", "/api/meets", `{"name": "Invitational"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, request(router, "POST", "/api/meets", `{}`).Code)
+	assert.Equal(t, http.StatusBadRequest, request(router, "POST", "/api/meets", `not json`).Code)
+
+	w := request(router, "GET", "/api/meets/Invitational", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"name": "Invitational", "races": ["Varsity"]}`, w.Body.String())
+	assert.Equal(t, http.StatusNotFound, request(router, "GET", "/api/meets/Dual", "").Code)
+	assert.Equal(t, http.StatusNotFound, request(router, "POST", "/api/meets/Dual/races", `{"name": "JV"}`).Code)
+}
+
+func TestMeetAdminHandlersEntries(t *testing.T) {
+	admin := &mockMeetAdmin{}
+	router := newMeetAdminRouter(admin)
+	entries := "/api/meets/Invitational/races/Varsity/entries"
+
+	w := request(router, "POST", entries, `{"bib": 101, "wave": "A", "athlete": {"daId": "dr1", "firstName": "David"}}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, []Entry{{Bib: 101, Wave: "A", Athlete: Athlete{DaID: "dr1", FirstName: "David"}}}, admin.added)
+	assert.Equal(t, http.StatusConflict, request(router, "POST", entries, `{"bib": 200, "athlete": {"daId": "dr2"}}`).Code)
+	assert.Equal(t, http.StatusBadRequest, request(router, "POST", entries, `{"bib": 0, "athlete": {"daId": "dr2"}}`).Code)
+	assert.Equal(t, http.StatusBadRequest, request(router, "POST", entries, `{"bib": 102}`).Code)
+
+	assert.Equal(t, http.StatusOK, request(router, "PUT", entries+"/100", `{"race": "JV", "bib": 7}`).Code)
+	assert.Equal(t, []EntryMove{{Race: "JV", Bib: 7}}, admin.moved)
+	assert.Equal(t, http.StatusBadRequest, request(router, "PUT", entries+"/100", `{"race": "Girls"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, request(router, "PUT", entries+"/abc", `{}`).Code)
+
+	assert.Equal(t, http.StatusNoContent, request(router, "DELETE", entries+"/100", "").Code)
+	assert.Equal(t, http.StatusNotFound, request(router, "DELETE", entries+"/101", "").Code)
+	assert.Equal(t, []int{100}, admin.removed)
+}
+
+func TestMeetAdminHandlersAthletes(t *testing.T) {
+	router := newMeetAdminRouter(&mockMeetAdmin{})
+
+	assert.Equal(t, http.StatusBadRequest, request(router, "PUT", "/api/athletes/dr1", `{"firstName": "Dave"}`).Code)
+	// the store failing isn't the caller's fault
+	assert.Equal(t, http.StatusInternalServerError, request(router, "PUT", "/api/athletes/dr1", `{"firstName": "Dave", "lastName": "Reynolds", "gender": "m"}`).Code)
+}
@@ -0,0 +1,152 @@
+package handler
+
+import (
+	"blreynolds4/event-race-timer/internal/liveresults"
+	"blreynolds4/event-race-timer/internal/meets"
+	"blreynolds4/event-race-timer/internal/places"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PlaceConflicts finds the places in a race's results that need fixing
+type PlaceConflicts interface {
+	// GetPlaceConflicts returns the conflicts in place order, ErrNotFound when there's no such meet or race
+	GetPlaceConflicts(meetName, raceName string) ([]meets.PlaceConflict, error)
+}
+
+type PlaceConflict struct {
+	// Kind is duplicate, gap, noPlace or noFinish
+	Kind  string `json:"kind"`
+	Place int    `json:"place"`
+	// Results are the runners in the conflict, a gap has none
+	Results []liveresults.Result `json:"results"`
+}
+
+type PlaceConflictsResponse struct {
+	Meet      string          `json:"meet"`
+	Race      string          `json:"race"`
+	Conflicts []PlaceConflict `json:"conflicts"`
+}
+
+type MovePlaceRequest struct {
+	Bib   int `json:"bib"`
+	Place int `json:"place"`
+}
+
+type RemovePlaceRequest struct {
+	Bib int `json:"bib"`
+}
+
+type CorrectionsResponse struct {
+	ManualResponse
+	// Corrections are the place events sent, in the order they were sent
+	Corrections []places.Correction `json:"corrections"`
+}
+
+func NewPlaceConflictsHandler(placeConflicts PlaceConflicts, logger *slog.Logger) gin.HandlerFunc {
+	fn := func(c *gin.Context) {
+		conflicts, err := placeConflicts.GetPlaceConflicts(c.Param("meet"), c.Param("race"))
+		if err != nil {
+			respondError(c, err, logger, "error getting place conflicts")
+			return
+		}
+
+		response := PlaceConflictsResponse{
+			Meet:      c.Param("meet"),
+			Race:      c.Param("race"),
+			Conflicts: make([]PlaceConflict, 0, len(conflicts)),
+		}
+		for _, pc := range conflicts {
+			conflict := PlaceConflict{Kind: pc.Kind, Place: pc.Place, Results: make([]liveresults.Result, 0, len(pc.Results))}
+			for _, rr := range pc.Results {
+				conflict.Results = append(conflict.Results, liveresults.NewResult(rr))
+			}
+			response.Conflicts = append(response.Conflicts, conflict)
+		}
+		c.IndentedJSON(http.StatusOK, response)
+	}
+	return gin.HandlerFunc(fn)
+}
+
+// correctPlaces works out the corrections from the race's saved results and sends them to
+// the race's stream, a correct error is a bad request
+func correctPlaces(c *gin.Context, raceResults RaceResults, races RaceStreams, logger *slog.Logger,
+	correct func(results []*meets.RaceResult, source string) ([]places.Correction, error)) {
+	// corrections that were worked out are sent even if the client hangs up
+	ctx := context.WithoutCancel(c.Request.Context())
+
+	response, eventStream, ok := manualRace(c, races)
+	if !ok {
+		return
+	}
+	results, ok := readResults(c, raceResults, logger)
+	if !ok {
+		return
+	}
+	corrections, err := correct(results, response.Source)
+	if err != nil {
+		c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	err = places.SendCorrections(ctx, eventStream, corrections)
+	if err != nil {
+		respondError(c, err, logger, "error sending place corrections")
+		return
+	}
+
+	logger.Info("sent place corrections", "meet", c.Param("meet"), "race", response.Race, "count", len(corrections), "source", response.Source)
+	c.IndentedJSON(http.StatusCreated, CorrectionsResponse{ManualResponse: response, Corrections: corrections})
+}
+
+// NewRenumberPlacesHandler places the runners with a place 1 to n, like the cli's renumber
+func NewRenumberPlacesHandler(raceResults RaceResults, races RaceStreams, logger *slog.Logger) gin.HandlerFunc {
+	fn := func(c *gin.Context) {
+		correctPlaces(c, raceResults, races, logger, func(results []*meets.RaceResult, source string) ([]places.Correction, error) {
+			return places.Renumber(results, source), nil
+		})
+	}
+	return gin.HandlerFunc(fn)
+}
+
+// NewMovePlaceHandler gives a bib a place, like the cli's movePlace
+func NewMovePlaceHandler(raceResults RaceResults, races RaceStreams, logger *slog.Logger) gin.HandlerFunc {
+	fn := func(c *gin.Context) {
+		var request MovePlaceRequest
+		if !bindJSON(c, &request) {
+			return
+		}
+		if request.Bib <= 0 || request.Place <= 0 {
+			c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: fmt.Sprintf("bad bib %d or place %d", request.Bib, request.Place)})
+			return
+		}
+
+		correctPlaces(c, raceResults, races, logger, func(results []*meets.RaceResult, source string) ([]places.Correction, error) {
+			return places.MovePlace(results, request.Bib, request.Place, source)
+		})
+	}
+	return gin.HandlerFunc(fn)
+}
+
+// NewRemovePlaceHandler takes a bib's place away, like the cli's removePlace
+func NewRemovePlaceHandler(raceResults RaceResults, races RaceStreams, logger *slog.Logger) gin.HandlerFunc {
+	fn := func(c *gin.Context) {
+		var request RemovePlaceRequest
+		if !bindJSON(c, &request) {
+			return
+		}
+		if request.Bib <= 0 {
+			c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: fmt.Sprintf("bad bib %d", request.Bib)})
+			return
+		}
+
+		correctPlaces(c, raceResults, races, logger, func(results []*meets.RaceResult, source string) ([]places.Correction, error) {
+			return places.RemovePlace(results, request.Bib, source)
+		})
+	}
+	return gin.HandlerFunc(fn)
+}
@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"blreynolds4/event-race-timer/internal/config"
+	"blreynolds4/event-race-timer/internal/liveresults"
+	"blreynolds4/event-race-timer/internal/meets"
+	"blreynolds4/event-race-timer/internal/raceevents"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockPlaceConflicts has the conflicts of races in one meet by race name
+type mockPlaceConflicts struct {
+	meet  string
+	races map[string][]meets.PlaceConflict
+}
+
+func (mpc *mockPlaceConflicts) GetPlaceConflicts(meetName, raceName string) ([]meets.PlaceConflict, error) {
+	conflicts, found := mpc.races[raceName]
+	if meetName != mpc.meet || !found {
+		return nil, fmt.Errorf("race %s %w", raceName, ErrNotFound)
+	}
+	return conflicts, nil
+}
+
+// tiedRace has 101 and 102 tied for 1st and nobody 2nd
+func tiedRace() *mockRaceResults {
+	athlete := meets.NewAthlete("Test", "Runner", "Team", "", 10, "f")
+	return &mockRaceResults{meet: "Invitational", races: map[string][]*meets.RaceResult{"Varsity": {
+		{Bib: 101, Athlete: athlete, Place: 1, PlaceSource: "default-placer", GunTime: 15 * time.Minute},
+		{Bib: 102, Athlete: athlete, Place: 1, GunTime: 16 * time.Minute},
+		{Bib: 103, Athlete: athlete, Place: 3, PlaceSource: "default-placer", GunTime: 17 * time.Minute},
+	}}}
+}
+
+func newCorrectionsRouter(raceResults RaceResults, races RaceStreams) *gin.Engine {
+	logger := slog.New(slog.DiscardHandler)
+	manual := config.ManualConfig{Tokens: map[string]string{"chute-secret": "chute tablet"}}
+
+	router := gin.Default()
+	group := router.Group("/api/meets/:meet/races/:race/places", NewManualAuth(manual, logger))
+	group.POST("/renumber", NewRenumberPlacesHandler(raceResults, races, logger))
+	group.POST("/move", NewMovePlaceHandler(raceResults, races, logger))
+	group.POST("/remove", NewRemovePlaceHandler(raceResults, races, logger))
+	return router
+}
+
+func TestPlaceConflictsHandler(t *testing.T) {
+	results := tiedRace().races["Varsity"]
+	router := gin.Default()
+	router.GET("/api/meets/:meet/races/:race/conflicts", NewPlaceConflictsHandler(&mockPlaceConflicts{
+		meet: "Invitational",
+		races: map[string][]meets.PlaceConflict{"Varsity": {
+			{Kind: meets.DuplicatePlace, Place: 1, Results: results[:2]},
+			{Kind: meets.PlaceGap, Place: 2},
+		}},
+	}, slog.New(slog.DiscardHandler)))
+
+	var response PlaceConflictsResponse
+	assert.Equal(t, http.StatusOK, getJSON(t, router, "/api/meets/Invitational/races/Varsity/conflicts", &response))
+	assert.Equal(t, 2, len(response.Conflicts))
+	assert.Equal(t, meets.DuplicatePlace, response.Conflicts[0].Kind)
+	assert.Equal(t, []int{101, 102}, []int{response.Conflicts[0].Results[0].Bib, response.Conflicts[0].Results[1].Bib})
+	assert.Equal(t, PlaceConflict{Kind: meets.PlaceGap, Place: 2, Results: []liveresults.Result{}}, response.Conflicts[1])
+
+	assert.Equal(t, http.StatusNotFound, getJSON(t, router, "/api/meets/Invitational/races/JV/conflicts", &response))
+}
+
+func TestCorrectPlacesHandlers(t *testing.T) {
+	races := mockRaceStreams{"Varsity": &raceevents.MockEventStream{}}
+	router := newCorrectionsRouter(tiedRace(), races)
+
+	assert.Equal(t, http.StatusUnauthorized, manualRequest(router, "", "/api/meets/Invitational/races/Varsity/places/renumber", `{}`).Code)
+	assert.Equal(t, http.StatusNotFound, manualRequest(router, "chute-secret", "/api/meets/Invitational/races/JV/places/renumber", `{}`).Code)
+	assert.Empty(t, races["Varsity"].Events)
+
+	// the corrections are sent with the token's source so the placer can't undo them
+	w := manualRequest(router, "chute-secret", "/api/meets/Invitational/races/Varsity/places/renumber", `{}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.JSONEq(t, `{"race": "Varsity", "source": "chute tablet", "corrections": [
+		{"bib": 102, "place": 2, "oldPlace": 1, "source": "chute tablet"}]}`, w.Body.String())
+
+	w = manualRequest(router, "chute-secret", "/api/meets/Invitational/races/Varsity/places/move", `{"bib": 103, "place": 1}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, raceevents.PlaceEvent{Source: "chute tablet", Bib: 103, Place: 1}, races["Varsity"].Events[1].Data)
+	assert.Equal(t, http.StatusBadRequest, manualRequest(router, "chute-secret", "/api/meets/Invitational/races/Varsity/places/move", `{"bib": 103}`).Code)
+	assert.Equal(t, http.StatusBadRequest, manualRequest(router, "chute-secret", "/api/meets/Invitational/races/Varsity/places/move", `{"bib": 99, "place": 1}`).Code)
+
+	w = manualRequest(router, "chute-secret", "/api/meets/Invitational/races/Varsity/places/remove", `{"bib": 101}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, raceevents.PlaceEvent{Source: "chute tablet", Bib: 101, Place: 0}, races["Varsity"].Events[4].Data)
+	assert.Equal(t, http.StatusBadRequest, manualRequest(router, "chute-secret", "/api/meets/Invitational/races/Varsity/places/remove", `{}`).Code)
+}
@@ -0,0 +1,309 @@
+package handler
+
+import (
+	"blreynolds4/event-race-timer/internal/liveresults"
+	"blreynolds4/event-race-timer/internal/meets"
+	"blreynolds4/event-race-timer/internal/overall"
+	"blreynolds4/event-race-timer/internal/xc"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RaceResults reads the results of a race in a meet
+type RaceResults interface {
+	// GetRaceResults returns the results in place order, ErrNotFound when there's no such meet or race
+	GetRaceResults(meetName, raceName string) ([]*meets.RaceResult, error)
+}
+
+type RaceResultsResponse struct {
+	Meet   string `json:"meet"`
+	Race   string `json:"race"`
+	RankBy string `json:"rankBy"`
+	// Results are the placed runners in overall order, Unplaced have a DNF, DNS or DQ
+	Results  []liveresults.Result `json:"results"`
+	Unplaced []liveresults.Result `json:"unplaced"`
+}
+
+type TeamFinisher struct {
+	liveresults.Result
+	// Score is the runner's xc score, 0 for a runner that didn't score
+	Score int `json:"score"`
+}
+
+type TeamScore struct {
+	// Place is the team's place, 0 for a team with fewer than 5 finishers
+	Place     int            `json:"place"`
+	Team      string         `json:"team"`
+	Score     int            `json:"score"`
+	TotalTime int64          `json:"totalTime"`
+	Top5Avg   int64          `json:"top5Avg"`
+	Finishers []TeamFinisher `json:"finishers"`
+}
+
+type TeamScoresResponse struct {
+	Meet string `json:"meet"`
+	Race string `json:"race"`
+	// Teams are scored in team order, Incomplete teams have fewer than 5 finishers
+	Teams      []TeamScore `json:"teams"`
+	Incomplete []TeamScore `json:"incomplete"`
+}
+
+type TeamResponse struct {
+	Meet     string               `json:"meet"`
+	Race     string               `json:"race"`
+	Team     TeamScore            `json:"team"`
+	Unplaced []liveresults.Result `json:"unplaced"`
+}
+
+// resultFilter keeps the results matching the gender, grade and team query parameters
+type resultFilter struct {
+	gender string
+	grade  int
+	team   string
+}
+
+func newResultFilter(c *gin.Context) (resultFilter, error) {
+	filter := resultFilter{
+		gender: c.Query("gender"),
+		team:   c.Query("team"),
+	}
+	if grade := c.Query("grade"); grade != "" {
+		var err error
+		filter.grade, err = strconv.Atoi(grade)
+		if err != nil {
+			return filter, fmt.Errorf("bad grade %s", grade)
+		}
+	}
+	return filter, nil
+}
+
+func (rf resultFilter) matches(athlete *meets.Athlete) bool {
+	if athlete == nil {
+		return false
+	}
+	return (rf.gender == "" || strings.EqualFold(rf.gender, athlete.Gender)) &&
+		(rf.grade == 0 || rf.grade == athlete.Grade) &&
+		(rf.team == "" || strings.EqualFold(rf.team, athlete.Team))
+}
+
+// readResults reads the race in the request's path and responds with the error when it can't
+func readResults(c *gin.Context, raceResults RaceResults, logger *slog.Logger) ([]*meets.RaceResult, bool) {
+	meetName, raceName := c.Param("meet"), c.Param("race")
+	results, err := raceResults.GetRaceResults(meetName, raceName)
+	if err != nil {
+		respondError(c, err, logger, "error getting race results")
+		return nil, false
+	}
+	return results, true
+}
+
+// NewRaceResultsHandler responds with a race's overall results.  Places are the places in the
+// whole race, the gender, grade and team query parameters only pick the runners shown.
+func NewRaceResultsHandler(raceResults RaceResults, logger *slog.Logger) gin.HandlerFunc {
+	fn := func(c *gin.Context) {
+		filter, err := newResultFilter(c)
+		if err != nil {
+			c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+		rankBy := c.DefaultQuery("rankBy", meets.RankByGun)
+		if !meets.IsValidRankBy(rankBy) {
+			c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: fmt.Sprintf("bad rankBy %s", rankBy)})
+			return
+		}
+
+		results, ok := readResults(c, raceResults, logger)
+		if !ok {
+			return
+		}
+
+		placed, unplaced := overall.RankResults(results, rankBy)
+		response := RaceResultsResponse{
+			Meet:     c.Param("meet"),
+			Race:     c.Param("race"),
+			RankBy:   rankBy,
+			Results:  overallResults(placed, filter),
+			Unplaced: overallResults(unplaced, filter),
+		}
+		c.IndentedJSON(http.StatusOK, response)
+	}
+	return gin.HandlerFunc(fn)
+}
+
+// NewTeamScoresHandler responds with a race's xc team scores.  The teams are scored from
+// the runners matching the gender and grade query parameters, so the girls in a mixed race
+// can be scored on their own, and the team query parameter picks the teams shown.
+func NewTeamScoresHandler(raceResults RaceResults, logger *slog.Logger) gin.HandlerFunc {
+	fn := func(c *gin.Context) {
+		filter, err := newResultFilter(c)
+		if err != nil {
+			c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+
+		results, ok := readResults(c, raceResults, logger)
+		if !ok {
+			return
+		}
+
+		scored, incomplete, _ := xc.ScoreTeams(filterResults(results, resultFilter{gender: filter.gender, grade: filter.grade}))
+		response := TeamScoresResponse{
+			Meet:       c.Param("meet"),
+			Race:       c.Param("race"),
+			Teams:      make([]TeamScore, 0, len(scored)),
+			Incomplete: make([]TeamScore, 0, len(incomplete)),
+		}
+		for i, team := range scored {
+			if filter.team == "" || strings.EqualFold(filter.team, team.Name) {
+				response.Teams = append(response.Teams, teamScore(i+1, team))
+			}
+		}
+		for _, team := range incomplete {
+			if filter.team == "" || strings.EqualFold(filter.team, team.Name) {
+				response.Incomplete = append(response.Incomplete, teamScore(0, team))
+			}
+		}
+		c.IndentedJSON(http.StatusOK, response)
+	}
+	return gin.HandlerFunc(fn)
+}
+
+// NewTeamHandler responds with one team's runners and score in a race, the gender and grade
+// query parameters pick the runners scored like NewTeamScoresHandler
+func NewTeamHandler(raceResults RaceResults, logger *slog.Logger) gin.HandlerFunc {
+	fn := func(c *gin.Context) {
+		filter, err := newResultFilter(c)
+		if err != nil {
+			c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+		teamName := c.Param("team")
+
+		results, ok := readResults(c, raceResults, logger)
+		if !ok {
+			return
+		}
+
+		scored, incomplete, unplaced := xc.ScoreTeams(filterResults(results, resultFilter{gender: filter.gender, grade: filter.grade}))
+		response := TeamResponse{
+			Meet:     c.Param("meet"),
+			Race:     c.Param("race"),
+			Unplaced: make([]liveresults.Result, 0),
+		}
+		found := false
+		for i, team := range scored {
+			if strings.EqualFold(teamName, team.Name) {
+				response.Team, found = teamScore(i+1, team), true
+			}
+		}
+		for _, team := range incomplete {
+			if strings.EqualFold(teamName, team.Name) {
+				response.Team, found = teamScore(0, team), true
+			}
+		}
+		for _, result := range unplaced {
+			if result.Athlete != nil && strings.EqualFold(teamName, result.Athlete.Team) {
+				response.Unplaced = append(response.Unplaced, liveresults.NewResult(&result))
+				found = true
+			}
+		}
+		if !found {
+			c.IndentedJSON(http.StatusNotFound, ErrorResponse{Error: fmt.Sprintf("team %s has no runners in the race", teamName)})
+			return
+		}
+		if response.Team.Team == "" {
+			response.Team = TeamScore{Team: teamName, Finishers: make([]TeamFinisher, 0)}
+		}
+		c.IndentedJSON(http.StatusOK, response)
+	}
+	return gin.HandlerFunc(fn)
+}
+
+// NewAthleteResultHandler responds with the result of the bib in the request's path
+func NewAthleteResultHandler(raceResults RaceResults, logger *slog.Logger) gin.HandlerFunc {
+	fn := func(c *gin.Context) {
+		bib, err := strconv.Atoi(c.Param("bib"))
+		if err != nil {
+			c.IndentedJSON(http.StatusBadRequest, ErrorResponse{Error: fmt.Sprintf("bad bib %s", c.Param("bib"))})
+			return
+		}
+
+		results, ok := readResults(c, raceResults, logger)
+		if !ok {
+			return
+		}
+
+		for _, result := range results {
+			if result.Bib == bib {
+				c.IndentedJSON(http.StatusOK, liveresults.NewResult(result))
+				return
+			}
+		}
+		c.IndentedJSON(http.StatusNotFound, ErrorResponse{Error: fmt.Sprintf("no result for bib %d", bib)})
+	}
+	return gin.HandlerFunc(fn)
+}
+
+// NewOverallHTMLHandler serves the html the race's overall scorer writes, a race that
+// hasn't been scored is a 404
+func NewOverallHTMLHandler() gin.HandlerFunc {
+	fn := func(c *gin.Context) {
+		htmlFile := overall.HTMLFile(c.Param("race"))
+		if _, err := os.Stat(htmlFile); err != nil {
+			c.IndentedJSON(http.StatusNotFound, ErrorResponse{Error: fmt.Sprintf("no overall results for race %s", c.Param("race"))})
+			return
+		}
+		c.File(htmlFile)
+	}
+	return gin.HandlerFunc(fn)
+}
+
+func filterResults(results []*meets.RaceResult, filter resultFilter) []*meets.RaceResult {
+	filtered := make([]*meets.RaceResult, 0, len(results))
+	for _, result := range results {
+		if filter.matches(result.Athlete) {
+			filtered = append(filtered, result)
+		}
+	}
+	return filtered
+}
+
+func overallResults(results []overall.OverallResult, filter resultFilter) []liveresults.Result {
+	shown := make([]liveresults.Result, 0, len(results))
+	for _, r := range results {
+		if !filter.matches(r.Athlete) {
+			continue
+		}
+		shown = append(shown, liveresults.NewResult(&meets.RaceResult{
+			Bib:     r.Bib,
+			Athlete: r.Athlete,
+			Place:   r.Place,
+			GunTime: r.Finishtime,
+			NetTime: r.NetTime,
+			Status:  r.Status,
+			Splits:  r.Splits,
+		}))
+	}
+	return shown
+}
+
+func teamScore(place int, team *xc.XCTeamResult) TeamScore {
+	score := TeamScore{
+		Place:     place,
+		Team:      team.Name,
+		Score:     int(team.TeamScore),
+		TotalTime: team.TotalTime.Milliseconds(),
+		Top5Avg:   team.Top5Avg.Milliseconds(),
+		Finishers: make([]TeamFinisher, len(team.Finishers)),
+	}
+	for i, finisher := range team.Finishers {
+		score.Finishers[i] = TeamFinisher{Result: liveresults.NewResult(&finisher.Result), Score: int(finisher.Score)}
+	}
+	return score
+}
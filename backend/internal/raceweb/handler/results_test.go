@@ -0,0 +1,192 @@
+package handler
+
+import (
+	"blreynolds4/event-race-timer/internal/meets"
+	"blreynolds4/event-race-timer/internal/overall"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockRaceResults has the results of races in one meet by race name
+type mockRaceResults struct {
+	meet  string
+	races map[string][]*meets.RaceResult
+}
+
+func (mrr *mockRaceResults) GetRaceResults(meetName, raceName string) ([]*meets.RaceResult, error) {
+	results, found := mrr.races[raceName]
+	if meetName != mrr.meet || !found {
+		return nil, fmt.Errorf("race %s %w", raceName, ErrNotFound)
+	}
+	return results, nil
+}
+
+// testRace has two full teams of boys and a team of girls that's short a runner
+func testRace() *mockRaceResults {
+	results := make([]*meets.RaceResult, 0)
+	add := func(team, gender string, grade int, status string) {
+		place := len(results) + 1
+		result := &meets.RaceResult{
+			Bib:     100 + place,
+			Athlete: meets.NewAthlete(fmt.Sprint("Runner", place), team, team, "", grade, gender),
+			Place:   place,
+			GunTime: 15*time.Minute + time.Duration(place)*time.Second,
+			Status:  status,
+		}
+		if status != "" {
+			result.Place = 0
+		}
+		results = append(results, result)
+	}
+	for i := 0; i < 5; i++ {
+		add("WPI", "m", 12, "")
+		add("ORHS", "m", 11, "")
+	}
+	for i := 0; i < 4; i++ {
+		add("ORHS", "f", 10, "")
+	}
+	add("WPI", "m", 9, "DNF")
+
+	return &mockRaceResults{meet: "Invitational", races: map[string][]*meets.RaceResult{"Varsity": results}}
+}
+
+func getJSON(t *testing.T, router *gin.Engine, path string, response any) int {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+	if w.Code == http.StatusOK {
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), response))
+	}
+	return w.Code
+}
+
+func newResultsRouter(raceResults RaceResults) *gin.Engine {
+	logger := slog.New(slog.DiscardHandler)
+	router := gin.Default()
+	race := router.Group("/api/meets/:meet/races/:race")
+	race.GET("/results", NewRaceResultsHandler(raceResults, logger))
+	race.GET("/teams", NewTeamScoresHandler(raceResults, logger))
+	race.GET("/teams/:team", NewTeamHandler(raceResults, logger))
+	race.GET("/athletes/:bib", NewAthleteResultHandler(raceResults, logger))
+	return router
+}
+
+func TestRaceResultsHandler(t *testing.T) {
+	router := newResultsRouter(testRace())
+
+	var response RaceResultsResponse
+	assert.Equal(t, http.StatusOK, getJSON(t, router, "/api/meets/Invitational/races/Varsity/results", &response))
+	assert.Equal(t, "Varsity", response.Race)
+	assert.Equal(t, meets.RankByGun, response.RankBy)
+	assert.Equal(t, 14, len(response.Results))
+	assert.Equal(t, 1, len(response.Unplaced))
+	assert.Equal(t, "DNF", response.Unplaced[0].Status)
+
+	// the girls keep their places in the whole race
+	response = RaceResultsResponse{}
+	assert.Equal(t, http.StatusOK, getJSON(t, router, "/api/meets/Invitational/races/Varsity/results?gender=F&grade=10", &response))
+	assert.Equal(t, 4, len(response.Results))
+	assert.Equal(t, 11, response.Results[0].Place)
+	assert.Equal(t, 0, len(response.Unplaced))
+
+	response = RaceResultsResponse{}
+	assert.Equal(t, http.StatusOK, getJSON(t, router, "/api/meets/Invitational/races/Varsity/results?team=wpi", &response))
+	assert.Equal(t, 5, len(response.Results))
+	assert.Equal(t, 1, len(response.Unplaced))
+
+	assert.Equal(t, http.StatusBadRequest, getJSON(t, router, "/api/meets/Invitational/races/Varsity/results?grade=senior", &response))
+	assert.Equal(t, http.StatusBadRequest, getJSON(t, router, "/api/meets/Invitational/races/Varsity/results?rankBy=chip", &response))
+	assert.Equal(t, http.StatusNotFound, getJSON(t, router, "/api/meets/Invitational/races/JV/results", &response))
+	assert.Equal(t, http.StatusNotFound, getJSON(t, router, "/api/meets/Dual/races/Varsity/results", &response))
+}
+
+func TestTeamScoresHandler(t *testing.T) {
+	router := newResultsRouter(testRace())
+
+	// WPI's runners are 1st, 3rd, 5th, 7th and 9th
+	var response TeamScoresResponse
+	assert.Equal(t, http.StatusOK, getJSON(t, router, "/api/meets/Invitational/races/Varsity/teams", &response))
+	assert.Equal(t, 2, len(response.Teams))
+	assert.Equal(t, TeamScore{Place: 1, Team: "WPI", Score: 25}, withoutFinishers(response.Teams[0]))
+	assert.Equal(t, 2, response.Teams[1].Place)
+	assert.Equal(t, "ORHS", response.Teams[1].Team)
+	assert.Equal(t, 9, len(response.Teams[1].Finishers))
+	assert.Equal(t, 0, len(response.Incomplete))
+
+	// the girls are scored on their own
+	response = TeamScoresResponse{}
+	assert.Equal(t, http.StatusOK, getJSON(t, router, "/api/meets/Invitational/races/Varsity/teams?gender=f", &response))
+	assert.Equal(t, 0, len(response.Teams))
+	assert.Equal(t, 1, len(response.Incomplete))
+	assert.Equal(t, 4, len(response.Incomplete[0].Finishers))
+
+	response = TeamScoresResponse{}
+	assert.Equal(t, http.StatusOK, getJSON(t, router, "/api/meets/Invitational/races/Varsity/teams?team=ORHS", &response))
+	assert.Equal(t, 1, len(response.Teams))
+	assert.Equal(t, "ORHS", response.Teams[0].Team)
+}
+
+func TestTeamHandler(t *testing.T) {
+	router := newResultsRouter(testRace())
+
+	var response TeamResponse
+	assert.Equal(t, http.StatusOK, getJSON(t, router, "/api/meets/Invitational/races/Varsity/teams/WPI", &response))
+	assert.Equal(t, 1, response.Team.Place)
+	assert.Equal(t, 5, len(response.Team.Finishers))
+	assert.Equal(t, 1, response.Team.Finishers[0].Score)
+	assert.Equal(t, 1, len(response.Unplaced))
+
+	assert.Equal(t, http.StatusNotFound, getJSON(t, router, "/api/meets/Invitational/races/Varsity/teams/Coe-Brown", &response))
+}
+
+func TestAthleteResultHandler(t *testing.T) {
+	router := newResultsRouter(testRace())
+
+	var response struct {
+		Bib   int `json:"bib"`
+		Place int `json:"place"`
+	}
+	assert.Equal(t, http.StatusOK, getJSON(t, router, "/api/meets/Invitational/races/Varsity/athletes/102", &response))
+	assert.Equal(t, 102, response.Bib)
+	assert.Equal(t, 2, response.Place)
+
+	assert.Equal(t, http.StatusNotFound, getJSON(t, router, "/api/meets/Invitational/races/Varsity/athletes/999", &response))
+	assert.Equal(t, http.StatusBadRequest, getJSON(t, router, "/api/meets/Invitational/races/Varsity/athletes/abc", &response))
+}
+
+func withoutFinishers(team TeamScore) TeamScore {
+	team.Finishers = nil
+	team.TotalTime = 0
+	team.Top5Avg = 0
+	return team
+}
+
+func TestOverallHTMLHandler(t *testing.T) {
+	// each race's scorer writes its own html to the working directory
+	t.Chdir(t.TempDir())
+	assert.NoError(t, os.WriteFile(overall.HTMLFile("Varsity"), []byte("<html>Varsity</html>"), 0644))
+	assert.NoError(t, os.WriteFile(overall.HTMLFile("JV Girls"), []byte("<html>JV Girls</html>"), 0644))
+
+	router := gin.Default()
+	router.GET("/overall/:race", NewOverallHTMLHandler())
+
+	for _, race := range []string{"Varsity", "JV Girls"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/overall/"+url.PathEscape(race), nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "<html>"+race+"</html>", w.Body.String())
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/overall/Novice", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
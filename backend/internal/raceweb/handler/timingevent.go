@@ -0,0 +1,182 @@
+package handler
+
+import (
+	"blreynolds4/event-race-timer/internal/config"
+	"blreynolds4/event-race-timer/internal/meets"
+	"blreynolds4/event-race-timer/internal/raceevents"
+	"blreynolds4/event-race-timer/internal/readfilter"
+	"context"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// captureModeStart is sent by readers on the start mat, any other capture mode is a finish
+const captureModeStart = "start"
+
+// RaceTiming is a race the timing handler sends reads to
+type RaceTiming struct {
+	Athletes meets.AthleteLookup
+	Events   raceevents.EventStream
+	Filter   *readfilter.FinishFilter
+}
+
+// RaceRouter finds the race a bib is running when several races are timed with the same readers
+type RaceRouter interface {
+	// RaceForBib returns the race's name and timing, false when the bib isn't in a race being timed
+	RaceForBib(bib int) (string, RaceTiming, bool)
+}
+
+// StaticRaces routes bibs to races that don't change while they're timed
+type StaticRaces struct {
+	BibRaces meets.BibRaceLookup
+	Races    map[string]RaceTiming
+}
+
+func (sr StaticRaces) RaceForBib(bib int) (string, RaceTiming, bool) {
+	raceName, found := sr.BibRaces[bib]
+	if !found {
+		return "", RaceTiming{}, false
+	}
+	race, found := sr.Races[raceName]
+	if _, running := race.Athletes[bib]; !running {
+		return "", RaceTiming{}, false
+	}
+	return raceName, race, found
+}
+
+// NewTimingHandler sends reads to the race stream, finish reads the filter rejects are sent as rejected reads for review
+func NewTimingHandler(sourceLookup config.SourceConfig, athletes meets.AthleteLookup, eventStream raceevents.EventStream, finishFilter *readfilter.FinishFilter, logger *slog.Logger) gin.HandlerFunc {
+	bibRaces := make(meets.BibRaceLookup, len(athletes))
+	bibRaces.AddRace("", athletes)
+	return NewRaceTimingHandler(sourceLookup, StaticRaces{
+		BibRaces: bibRaces,
+		Races:    map[string]RaceTiming{"": {Athletes: athletes, Events: eventStream, Filter: finishFilter}},
+	}, nil, logger)
+}
+
+// NewRaceTimingHandler times several races with the same readers, each read is sent to
+// the race its bib is in like NewTimingHandler does for one race.  Reads of bibs that
+// aren't in a race are saved to orphans, when it isn't nil, so they can be reassigned.
+func NewRaceTimingHandler(sourceLookup config.SourceConfig, races RaceRouter, orphans meets.OrphanReadStore, logger *slog.Logger) gin.HandlerFunc {
+	fn := func(c *gin.Context) {
+		// a read that arrived is sent even if the reader hangs up or the server is stopping
+		ctx := context.WithoutCancel(c.Request.Context())
+
+		var data OpenSignupsTimingEvent
+		if err := c.BindJSON(&data); err != nil {
+			logger.Error("bind json error", "error", err.Error())
+			return
+		}
+		bib, err := strconv.Atoi(data.Bib)
+		if err != nil {
+			// just drop the bad bib
+			logger.Error("dropping bad bib", "bib", data.Bib, "antenna", data.Antenna, "host", data.Host)
+			return
+		}
+
+		// the read goes to the race the bib is in
+		raceName, race, bibFound := races.RaceForBib(bib)
+		eventStream, finishFilter := race.Events, race.Filter
+
+		if bibFound {
+			// using the event time from the JSON payload as the read time
+			readTime := time.UnixMilli(int64(data.EventTime)).UTC()
+			timingPoint, onCourse := sourceLookup.TimingPoints[data.Host]
+			if data.CaptureMode == captureModeStart {
+				// send the chip start event for the net time
+				err = eventStream.SendChipStartEvent(ctx, raceevents.ChipStartEvent{
+					Source:    sourceLookup.SourceMap[data.Host],
+					Bib:       bib,
+					StartTime: readTime,
+				})
+				if err != nil {
+					respondError(c, err, logger, "error sending chip start")
+					return
+				}
+			} else if onCourse {
+				// send the split event for the reader's timing point
+				err = eventStream.SendSplitEvent(ctx, raceevents.SplitEvent{
+					Source:      sourceLookup.SourceMap[data.Host],
+					Bib:         bib,
+					TimingPoint: timingPoint,
+					SplitTime:   readTime,
+				})
+				if err != nil {
+					respondError(c, err, logger, "error sending split")
+					return
+				}
+			} else {
+				finish := raceevents.FinishEvent{
+					Source:     sourceLookup.SourceMap[data.Host],
+					Bib:        bib,
+					FinishTime: readTime,
+				}
+				reason, err := finishFilter.Check(ctx, finish)
+				if err != nil {
+					// keep the read, a bad finish can be voided but a lost one can't be recovered
+					logger.Error("error filtering finish read", "bib", bib, "error", err)
+				}
+
+				if reason != "" {
+					err = eventStream.SendRejectedReadEvent(ctx, raceevents.RejectedReadEvent{
+						Source:   finish.Source,
+						Bib:      bib,
+						ReadTime: readTime,
+						Reason:   reason,
+					})
+					if err != nil {
+						respondError(c, err, logger, "error sending rejected read")
+						return
+					}
+					c.IndentedJSON(http.StatusAccepted, data)
+					logger.Info("rejected finish read", "race", raceName, "bib", bib, "reason", reason, "time", readTime, "source", finish.Source, "host", data.Host)
+					return
+				}
+
+				// send the finish event
+				err = eventStream.SendFinishEvent(ctx, finish)
+				if err != nil {
+					respondError(c, err, logger, "error sending finish")
+					return
+				}
+			}
+
+			// respond with the data and a 201 created
+			c.IndentedJSON(http.StatusCreated, data)
+			logger.Info("sent race timing event",
+				"race", raceName,
+				"bib", bib,
+				"captureMode", data.CaptureMode,
+				"timingPoint", timingPoint,
+				"time", readTime,
+				"antenna", data.Antenna,
+				"source", sourceLookup.SourceMap[data.Host],
+				"host", data.Host)
+		} else if orphans != nil {
+			orphan, err := orphans.SaveOrphanRead(&meets.OrphanRead{
+				Bib:         bib,
+				Source:      sourceLookup.SourceMap[data.Host],
+				Host:        data.Host,
+				Antenna:     data.Antenna,
+				CaptureMode: data.CaptureMode,
+				TimingPoint: sourceLookup.TimingPoints[data.Host],
+				ReadTime:    time.UnixMilli(int64(data.EventTime)).UTC(),
+			})
+			if err != nil {
+				logger.Error("error saving orphan read", "bib", bib, "antenna", data.Antenna, "host", data.Host, "error", err)
+				c.IndentedJSON(http.StatusInternalServerError, data)
+				return
+			}
+			c.IndentedJSON(http.StatusAccepted, data)
+			logger.Warn("saved orphan read of unknown bib", "id", orphan.ID, "bib", bib, "antenna", data.Antenna, "host", data.Host)
+		} else {
+			logger.Warn("skipping unknown bib", "bib", bib, "antenna", data.Antenna, "host", data.Host)
+		}
+	}
+
+	return gin.HandlerFunc(fn)
+}
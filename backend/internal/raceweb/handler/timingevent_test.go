@@ -0,0 +1,224 @@
+package handler
+
+import (
+	"blreynolds4/event-race-timer/internal/config"
+	"blreynolds4/event-race-timer/internal/meets"
+	"blreynolds4/event-race-timer/internal/raceevents"
+	"blreynolds4/event-race-timer/internal/readfilter"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimingHandlerStartAndFinish(t *testing.T) {
+	logger := slog.New(slog.DiscardHandler)
+
+	athletes := make(meets.AthleteLookup)
+	athletes[123] = meets.NewAthlete("D", "R", "WPI", "DAID", 12, "m")
+	sources := config.SourceConfig{SourceMap: map[string]string{"reader1": "chip"}}
+	events := &raceevents.MockEventStream{Events: make([]raceevents.Event, 0)}
+
+	router := gin.Default()
+	router.POST("/api/timingEvents/finishes", NewTimingHandler(sources, athletes, events, readfilter.NewFinishFilter(nil, nil, events, config.RankingPolicy{}, nil), logger))
+
+	codes := make([]int, 0)
+	for _, body := range []string{
+		`{"timestamp": 1677720000000, "captureMode": "start", "antenna": 1, "bib": "123", "host": "reader1"}`,
+		`{"timestamp": 1677721000000, "captureMode": "finish", "antenna": 1, "bib": "123", "host": "reader1"}`,
+		`{"timestamp": 1677721000000, "captureMode": "start", "antenna": 1, "bib": "999", "host": "reader1"}`,
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("POST", "/api/timingEvents/finishes", strings.NewReader(body)))
+		codes = append(codes, w.Code)
+	}
+
+	// unknown bibs are dropped
+	assert.Equal(t, []int{http.StatusCreated, http.StatusCreated, http.StatusOK}, codes)
+
+	assert.Equal(t, 2, len(events.Events))
+	assert.Equal(t, raceevents.ChipStartEvent{Source: "chip", Bib: 123, StartTime: time.UnixMilli(1677720000000).UTC()}, events.Events[0].Data)
+	assert.Equal(t, raceevents.FinishEvent{Source: "chip", Bib: 123, FinishTime: time.UnixMilli(1677721000000).UTC()}, events.Events[1].Data)
+}
+
+func TestTimingHandlerSplit(t *testing.T) {
+	logger := slog.New(slog.DiscardHandler)
+
+	athletes := make(meets.AthleteLookup)
+	athletes[123] = meets.NewAthlete("D", "R", "WPI", "DAID", 12, "m")
+	sources := config.SourceConfig{
+		SourceMap:    map[string]string{"mile1": "chip"},
+		TimingPoints: map[string]string{"mile1": "1 mile"},
+	}
+	events := &raceevents.MockEventStream{Events: make([]raceevents.Event, 0)}
+
+	router := gin.Default()
+	router.POST("/api/timingEvents/finishes", NewTimingHandler(sources, athletes, events, readfilter.NewFinishFilter(nil, nil, events, config.RankingPolicy{}, nil), logger))
+
+	w := httptest.NewRecorder()
+	body := `{"timestamp": 1677720000000, "captureMode": "finish", "antenna": 1, "bib": "123", "host": "mile1"}`
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/api/timingEvents/finishes", strings.NewReader(body)))
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, []raceevents.Event{{
+		EventTime: time.UnixMilli(1677720000000).UTC(),
+		Data:      raceevents.SplitEvent{Source: "chip", Bib: 123, TimingPoint: "1 mile", SplitTime: time.UnixMilli(1677720000000).UTC()},
+	}}, events.Events)
+}
+
+func TestTimingHandlerRejectsFilteredFinish(t *testing.T) {
+	logger := slog.New(slog.DiscardHandler)
+
+	athletes := make(meets.AthleteLookup)
+	athletes[123] = meets.NewAthlete("D", "R", "WPI", "DAID", 12, "m")
+	sources := config.SourceConfig{
+		SourceMap: map[string]string{"reader1": "chip"},
+		Filters:   map[string]config.ReadFilter{"chip": {DebounceSeconds: 5}},
+	}
+	events := &raceevents.MockEventStream{Events: make([]raceevents.Event, 0)}
+
+	router := gin.Default()
+	router.POST("/api/timingEvents/finishes", NewTimingHandler(sources, athletes, events, readfilter.NewFinishFilter(sources.Filters, nil, events, config.RankingPolicy{}, nil), logger))
+
+	codes := make([]int, 0)
+	for _, body := range []string{
+		`{"timestamp": 1677721000000, "captureMode": "finish", "antenna": 1, "bib": "123", "host": "reader1"}`,
+		`{"timestamp": 1677721001000, "captureMode": "finish", "antenna": 2, "bib": "123", "host": "reader1"}`,
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("POST", "/api/timingEvents/finishes", strings.NewReader(body)))
+		codes = append(codes, w.Code)
+	}
+
+	assert.Equal(t, []int{http.StatusCreated, http.StatusAccepted}, codes)
+	assert.Equal(t, 2, len(events.Events))
+	assert.Equal(t, raceevents.FinishEvent{Source: "chip", Bib: 123, FinishTime: time.UnixMilli(1677721000000).UTC()}, events.Events[0].Data)
+	assert.Equal(t, raceevents.RejectedReadEvent{Source: "chip", Bib: 123, ReadTime: time.UnixMilli(1677721001000).UTC(), Reason: readfilter.ReasonDebounce}, events.Events[1].Data)
+}
+
+func TestTimingHandlerSendFails(t *testing.T) {
+	logger := slog.New(slog.DiscardHandler)
+
+	athletes := make(meets.AthleteLookup)
+	athletes[123] = meets.NewAthlete("D", "R", "WPI", "DAID", 12, "m")
+	sources := config.SourceConfig{
+		SourceMap:    map[string]string{"reader1": "chip", "mile1": "chip"},
+		TimingPoints: map[string]string{"mile1": "1 mile"},
+	}
+	// the mock only uses SendFinish when SendStart is set
+	events := &raceevents.MockEventStream{
+		SendStart: func(ctx context.Context, se raceevents.StartEvent) error {
+			return fmt.Errorf("stream is down")
+		},
+		SendFinish: func(ctx context.Context, fe raceevents.FinishEvent) error {
+			return fmt.Errorf("stream is down")
+		},
+		SendSplit: func(ctx context.Context, sp raceevents.SplitEvent) error {
+			return fmt.Errorf("stream is down")
+		},
+	}
+
+	router := gin.Default()
+	router.POST("/api/timingEvents/finishes", NewTimingHandler(sources, athletes, events, readfilter.NewFinishFilter(nil, nil, events, config.RankingPolicy{}, nil), logger))
+
+	// the reader is told the read wasn't sent
+	for _, body := range []string{
+		`{"timestamp": 1677721000000, "captureMode": "finish", "antenna": 1, "bib": "123", "host": "reader1"}`,
+		`{"timestamp": 1677721000000, "captureMode": "finish", "antenna": 1, "bib": "123", "host": "mile1"}`,
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("POST", "/api/timingEvents/finishes", strings.NewReader(body)))
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.JSONEq(t, `{"error": "stream is down"}`, w.Body.String())
+	}
+}
+
+func TestRaceTimingHandlerRoutesByBib(t *testing.T) {
+	logger := slog.New(slog.DiscardHandler)
+
+	jvAthletes := meets.AthleteLookup{100: meets.NewAthlete("J", "V", "WPI", "JV", 10, "m")}
+	varsityAthletes := meets.AthleteLookup{200: meets.NewAthlete("V", "A", "WPI", "VA", 12, "m")}
+	bibRaces := make(meets.BibRaceLookup)
+	assert.NoError(t, bibRaces.AddRace("JV", jvAthletes))
+	assert.NoError(t, bibRaces.AddRace("Varsity", varsityAthletes))
+	// a race with the readers off isn't timed
+	bibRaces[300] = "Girls"
+
+	jvEvents := &raceevents.MockEventStream{Events: make([]raceevents.Event, 0)}
+	varsityEvents := &raceevents.MockEventStream{Events: make([]raceevents.Event, 0)}
+	sources := config.SourceConfig{SourceMap: map[string]string{"reader1": "chip"}}
+
+	router := gin.Default()
+	router.POST("/api/timingEvents/finishes", NewRaceTimingHandler(sources, StaticRaces{
+		BibRaces: bibRaces,
+		Races: map[string]RaceTiming{
+			"JV":      {Athletes: jvAthletes, Events: jvEvents, Filter: readfilter.NewFinishFilter(nil, nil, jvEvents, config.RankingPolicy{}, nil)},
+			"Varsity": {Athletes: varsityAthletes, Events: varsityEvents, Filter: readfilter.NewFinishFilter(nil, nil, varsityEvents, config.RankingPolicy{}, nil)},
+		},
+	}, nil, logger))
+
+	codes := make([]int, 0)
+	for _, body := range []string{
+		`{"timestamp": 1677720000000, "captureMode": "start", "antenna": 1, "bib": "200", "host": "reader1"}`,
+		`{"timestamp": 1677721000000, "captureMode": "finish", "antenna": 1, "bib": "100", "host": "reader1"}`,
+		`{"timestamp": 1677722000000, "captureMode": "finish", "antenna": 1, "bib": "200", "host": "reader1"}`,
+		`{"timestamp": 1677722000000, "captureMode": "finish", "antenna": 1, "bib": "300", "host": "reader1"}`,
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("POST", "/api/timingEvents/finishes", strings.NewReader(body)))
+		codes = append(codes, w.Code)
+	}
+
+	assert.Equal(t, []int{http.StatusCreated, http.StatusCreated, http.StatusCreated, http.StatusOK}, codes)
+	assert.Equal(t, 1, len(jvEvents.Events))
+	assert.Equal(t, raceevents.FinishEvent{Source: "chip", Bib: 100, FinishTime: time.UnixMilli(1677721000000).UTC()}, jvEvents.Events[0].Data)
+	assert.Equal(t, 2, len(varsityEvents.Events))
+	assert.Equal(t, raceevents.ChipStartEvent{Source: "chip", Bib: 200, StartTime: time.UnixMilli(1677720000000).UTC()}, varsityEvents.Events[0].Data)
+	assert.Equal(t, raceevents.FinishEvent{Source: "chip", Bib: 200, FinishTime: time.UnixMilli(1677722000000).UTC()}, varsityEvents.Events[1].Data)
+}
+
+func TestRaceTimingHandlerSavesOrphanReads(t *testing.T) {
+	logger := slog.New(slog.DiscardHandler)
+
+	athletes := meets.AthleteLookup{100: meets.NewAthlete("J", "V", "WPI", "JV", 10, "m")}
+	bibRaces := make(meets.BibRaceLookup)
+	assert.NoError(t, bibRaces.AddRace("JV", athletes))
+	events := &raceevents.MockEventStream{Events: make([]raceevents.Event, 0)}
+	sources := config.SourceConfig{
+		SourceMap:    map[string]string{"reader1": "chip", "mile1": "chip"},
+		TimingPoints: map[string]string{"mile1": "1 mile"},
+	}
+	orphans := &meets.MockOrphanReadStore{}
+
+	router := gin.Default()
+	router.POST("/api/timingEvents/finishes", NewRaceTimingHandler(sources, StaticRaces{
+		BibRaces: bibRaces,
+		Races:    map[string]RaceTiming{"JV": {Athletes: athletes, Events: events, Filter: readfilter.NewFinishFilter(nil, nil, events, config.RankingPolicy{}, nil)}},
+	}, orphans, logger))
+
+	codes := make([]int, 0)
+	for _, body := range []string{
+		`{"timestamp": 1677721000000, "captureMode": "finish", "antenna": 1, "bib": "100", "host": "reader1"}`,
+		`{"timestamp": 1677721000000, "captureMode": "finish", "antenna": 2, "bib": "999", "host": "reader1"}`,
+		`{"timestamp": 1677720000000, "captureMode": "finish", "antenna": 1, "bib": "998", "host": "mile1"}`,
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("POST", "/api/timingEvents/finishes", strings.NewReader(body)))
+		codes = append(codes, w.Code)
+	}
+
+	// unknown bibs are kept as orphans instead of being sent to a race
+	assert.Equal(t, []int{http.StatusCreated, http.StatusAccepted, http.StatusAccepted}, codes)
+	assert.Equal(t, 1, len(events.Events))
+	assert.Equal(t, []*meets.OrphanRead{
+		{ID: 1, Bib: 999, Source: "chip", Host: "reader1", Antenna: 2, CaptureMode: "finish", ReadTime: time.UnixMilli(1677721000000).UTC()},
+		{ID: 2, Bib: 998, Source: "chip", Host: "mile1", Antenna: 1, CaptureMode: "finish", TimingPoint: "1 mile", ReadTime: time.UnixMilli(1677720000000).UTC()},
+	}, orphans.Reads)
+}
@@ -15,6 +15,9 @@ import (
 
 func NewWorkoutHandler(sourceLookup config.SourceConfig, athletes competitors.CompetitorLookup, eventStream raceevents.EventStream, logger *slog.Logger) gin.HandlerFunc {
 	fn := func(c *gin.Context) {
+		// a read that arrived is sent even if the reader hangs up or the server is stopping
+		ctx := context.WithoutCancel(c.Request.Context())
+
 		var data OpenSignupsTimingEvent
 		if err := c.BindJSON(&data); err != nil {
 			logger.Error("bind json error", "error", err.Error())
@@ -31,7 +34,7 @@ func NewWorkoutHandler(sourceLookup config.SourceConfig, athletes competitors.Co
 			eventTime := time.UnixMilli(int64(data.EventTime))
 
 			// send the finish event
-			eventStream.SendWorkoutEvent(context.TODO(), raceevents.WorkoutEvent{
+			eventStream.SendWorkoutEvent(ctx, raceevents.WorkoutEvent{
 				Source:    sourceLookup.SourceMap[data.Host],
 				Bib:       bib,
 				SplitTime: eventTime,
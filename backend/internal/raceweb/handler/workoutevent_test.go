@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"blreynolds4/event-race-timer/internal/competitors"
+	"blreynolds4/event-race-timer/internal/config"
+	"blreynolds4/event-race-timer/internal/raceevents"
+	"blreynolds4/event-race-timer/internal/workouts"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkoutHandlerAndReps(t *testing.T) {
+	logger := slog.New(slog.DiscardHandler)
+
+	athletes := competitors.CompetitorLookup{123: competitors.NewCompetitor("Dana R", "WPI", 20, 0)}
+	sources := config.SourceConfig{SourceMap: map[string]string{"reader1": "chip"}}
+	events := &raceevents.MockEventStream{Events: make([]raceevents.Event, 0)}
+	session := workouts.Session{Name: "tuesday", Team: "WPI", Intervals: []workouts.Interval{{Name: "400m", Laps: 1}}}
+
+	router := gin.Default()
+	router.POST("/api/timingEvents/workouts", NewWorkoutHandler(sources, athletes, events, logger))
+	router.GET("/api/workouts/reps", NewWorkoutRepsHandler(session, athletes, events, logger))
+
+	codes := make([]int, 0)
+	for _, body := range []string{
+		`{"timestamp": 1677720000000, "antenna": 1, "bib": "123", "host": "reader1"}`,
+		`{"timestamp": 1677720070000, "antenna": 1, "bib": "123", "host": "reader1"}`,
+		`{"timestamp": 1677720070000, "antenna": 1, "bib": "999", "host": "reader1"}`,
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("POST", "/api/timingEvents/workouts", strings.NewReader(body)))
+		codes = append(codes, w.Code)
+	}
+
+	// unknown bibs are dropped
+	assert.Equal(t, []int{http.StatusCreated, http.StatusCreated, http.StatusOK}, codes)
+	assert.Equal(t, 2, len(events.Events))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/workouts/reps", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response WorkoutRepsResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "tuesday", response.Session.Name)
+	assert.Equal(t, 1, len(response.Athletes))
+	assert.Equal(t, "Dana R", response.Athletes[0].Name)
+	assert.Equal(t, []string{"01:10.00"}, response.Athletes[0].Reps[0].Laps)
+	assert.Equal(t, "01:10.00", response.Athletes[0].Reps[0].Time)
+
+	// the fields are camelCase like the rest of the api
+	var fields map[string]any
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &fields))
+	rep := fields["athletes"].([]any)[0].(map[string]any)["reps"].([]any)[0].(map[string]any)
+	assert.Equal(t, "400m", rep["interval"])
+	assert.Equal(t, true, rep["done"])
+	assert.Equal(t, "00:00.00", rep["rest"])
+	assert.Equal(t, "WPI", fields["session"].(map[string]any)["team"])
+}
@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"blreynolds4/event-race-timer/internal/competitors"
+	"blreynolds4/event-race-timer/internal/raceevents"
+	"blreynolds4/event-race-timer/internal/workouts"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WorkoutInterval struct {
+	Name string `json:"name"`
+	Laps int    `json:"laps"`
+	Reps int    `json:"reps"`
+}
+
+type WorkoutSession struct {
+	Name      string            `json:"name"`
+	Team      string            `json:"team"`
+	Date      string            `json:"date"`
+	Intervals []WorkoutInterval `json:"intervals"`
+}
+
+// WorkoutRep is a rep with its times formatted like 01:10.50
+type WorkoutRep struct {
+	Interval string    `json:"interval"`
+	Number   int       `json:"number"`
+	Start    time.Time `json:"start"`
+	Laps     []string  `json:"laps"`
+	Done     bool      `json:"done"`
+	// Time is empty until the rep is done
+	Time string `json:"time"`
+	// Rest is the time from the end of the previous rep to the start of this one
+	Rest string `json:"rest"`
+}
+
+type WorkoutAthleteReps struct {
+	Bib  int          `json:"bib"`
+	Name string       `json:"name"`
+	Team string       `json:"team"`
+	Reps []WorkoutRep `json:"reps"`
+}
+
+type WorkoutRepsResponse struct {
+	Session  WorkoutSession       `json:"session"`
+	Athletes []WorkoutAthleteReps `json:"athletes"`
+}
+
+func newWorkoutSession(session workouts.Session) WorkoutSession {
+	ws := WorkoutSession{
+		Name:      session.Name,
+		Team:      session.Team,
+		Date:      session.Date,
+		Intervals: make([]WorkoutInterval, len(session.Intervals)),
+	}
+	for i, interval := range session.Intervals {
+		ws.Intervals[i] = WorkoutInterval{Name: interval.Name, Laps: interval.Laps, Reps: interval.Reps}
+	}
+	return ws
+}
+
+func newWorkoutReps(reps []workouts.Rep) []WorkoutRep {
+	workoutReps := make([]WorkoutRep, len(reps))
+	for i, rep := range reps {
+		workoutReps[i] = WorkoutRep{
+			Interval: rep.Interval,
+			Number:   rep.Number,
+			Start:    rep.Start,
+			Laps:     make([]string, len(rep.Laps)),
+			Done:     rep.IsDone(),
+			Rest:     workouts.FormatRepTime(rep.Rest),
+		}
+		for j, lap := range rep.Laps {
+			workoutReps[i].Laps[j] = workouts.FormatRepTime(lap)
+		}
+		if rep.IsDone() {
+			workoutReps[i].Time = workouts.FormatRepTime(rep.Time)
+		}
+	}
+	return workoutReps
+}
+
+// NewWorkoutRepsHandler reports each athlete's reps so far in the workout session
+func NewWorkoutRepsHandler(session workouts.Session, athletes competitors.CompetitorLookup, eventStream raceevents.EventStreamReader, logger *slog.Logger) gin.HandlerFunc {
+	fn := func(c *gin.Context) {
+		results, err := workouts.ReadReps(c.Request.Context(), eventStream, session)
+		if err != nil {
+			logger.Error("error reading workout reps", "session", session.Name, "error", err)
+			c.IndentedJSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+			return
+		}
+
+		response := WorkoutRepsResponse{
+			Session:  newWorkoutSession(session),
+			Athletes: make([]WorkoutAthleteReps, len(results)),
+		}
+		for i, result := range results {
+			response.Athletes[i] = WorkoutAthleteReps{Bib: result.Bib, Reps: newWorkoutReps(result.Reps)}
+			if athlete, found := athletes[result.Bib]; found {
+				response.Athletes[i].Name = athlete.Name
+				response.Athletes[i].Team = athlete.Team
+			}
+		}
+		c.IndentedJSON(http.StatusOK, response)
+	}
+	return gin.HandlerFunc(fn)
+}
@@ -0,0 +1,465 @@
+package raceweb
+
+import (
+	"blreynolds4/event-race-timer/internal/meets"
+	"blreynolds4/event-race-timer/internal/raceweb/handler"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// MeetAdmin makes the changes the meet api asks for, checking them against what's saved.
+// A bib can only be in one race in a meet and an athlete can only be in one race in a meet.
+// Changes are made one at a time so two requests can't take the same bib.
+type MeetAdmin struct {
+	mu            sync.Mutex
+	meetReader    meets.MeetReader
+	meetWriter    meets.MeetWriter
+	raceReader    meets.RaceReader
+	raceWriter    meets.RaceWriter
+	athleteReader meets.AthleteReader
+	athleteWriter meets.AthleteWriter
+}
+
+func NewMeetAdmin(meetReader meets.MeetReader, meetWriter meets.MeetWriter, raceReader meets.RaceReader, raceWriter meets.RaceWriter,
+	athleteReader meets.AthleteReader, athleteWriter meets.AthleteWriter) *MeetAdmin {
+	return &MeetAdmin{
+		meetReader:    meetReader,
+		meetWriter:    meetWriter,
+		raceReader:    raceReader,
+		raceWriter:    raceWriter,
+		athleteReader: athleteReader,
+		athleteWriter: athleteWriter,
+	}
+}
+
+// OpenMeetAdmin opens the meet admin's postgres stores
+func OpenMeetAdmin(connectStr string) (*MeetAdmin, error) {
+	meetReader, err := meets.NewMeetReader(connectStr)
+	if err != nil {
+		return nil, err
+	}
+	meetWriter, err := meets.NewMeetWriter(connectStr)
+	if err != nil {
+		return nil, err
+	}
+	raceReader, err := meets.NewRaceReader(connectStr)
+	if err != nil {
+		return nil, err
+	}
+	raceWriter, err := meets.NewRaceWriter(connectStr)
+	if err != nil {
+		return nil, err
+	}
+	athleteReader, err := meets.NewAthleteReader(connectStr)
+	if err != nil {
+		return nil, err
+	}
+	athleteWriter, err := meets.NewAthleteWriter(connectStr)
+	if err != nil {
+		return nil, err
+	}
+	return NewMeetAdmin(meetReader, meetWriter, raceReader, raceWriter, athleteReader, athleteWriter), nil
+}
+
+// Close closes the stores the admin was made with
+func (ma *MeetAdmin) Close() error {
+	return errors.Join(ma.meetReader.Close(), ma.meetWriter.Close(), ma.raceReader.Close(), ma.raceWriter.Close(),
+		ma.athleteReader.Close(), ma.athleteWriter.Close())
+}
+
+// meetEntry is an athlete in a race in the meet
+type meetEntry struct {
+	race    *meets.Race
+	athlete *meets.RaceAthlete
+}
+
+// meetEntries are the races in a meet and the athletes in them by bib
+type meetEntries struct {
+	races []meets.Race
+	bibs  map[int]meetEntry
+}
+
+func (me meetEntries) race(raceName string) *meets.Race {
+	for i := range me.races {
+		if me.races[i].Name == raceName {
+			return &me.races[i]
+		}
+	}
+	return nil
+}
+
+// athlete is the entry for the athlete with daID, false when they aren't in the meet
+func (me meetEntries) athlete(daID string) (meetEntry, bool) {
+	for _, entry := range me.bibs {
+		if entry.athlete.Athlete.DaID == daID {
+			return entry, true
+		}
+	}
+	return meetEntry{}, false
+}
+
+func (ma *MeetAdmin) GetMeet(meetName string) (handler.Meet, error) {
+	meet, err := ma.meet(meetName)
+	if err != nil {
+		return handler.Meet{}, err
+	}
+	return ma.meetResponse(meet)
+}
+
+func (ma *MeetAdmin) CreateMeet(meetName string) (handler.Meet, error) {
+	ma.mu.Lock()
+	defer ma.mu.Unlock()
+
+	existing, err := ma.meetReader.GetMeet(meetName)
+	if err != nil {
+		return handler.Meet{}, err
+	}
+	if existing != nil {
+		return handler.Meet{}, fmt.Errorf("meet %s already exists %w", meetName, handler.ErrConflict)
+	}
+
+	meet, err := ma.meetWriter.SaveMeet(&meets.Meet{Name: meetName})
+	if err != nil {
+		return handler.Meet{}, err
+	}
+	return handler.Meet{Name: meet.Name, Races: []string{}}, nil
+}
+
+func (ma *MeetAdmin) RenameMeet(meetName, newName string) (handler.Meet, error) {
+	ma.mu.Lock()
+	defer ma.mu.Unlock()
+
+	meet, err := ma.meet(meetName)
+	if err != nil {
+		return handler.Meet{}, err
+	}
+	if newName != meetName {
+		existing, err := ma.meetReader.GetMeet(newName)
+		if err != nil {
+			return handler.Meet{}, err
+		}
+		if existing != nil {
+			return handler.Meet{}, fmt.Errorf("meet %s already exists %w", newName, handler.ErrConflict)
+		}
+	}
+
+	meet.Name = newName
+	meet, err = ma.meetWriter.SaveMeet(meet)
+	if err != nil {
+		return handler.Meet{}, err
+	}
+	return ma.meetResponse(meet)
+}
+
+func (ma *MeetAdmin) CreateRace(meetName, raceName string) (handler.Meet, error) {
+	ma.mu.Lock()
+	defer ma.mu.Unlock()
+
+	meet, err := ma.meet(meetName)
+	if err != nil {
+		return handler.Meet{}, err
+	}
+	existing, err := ma.raceReader.GetRace(meet, raceName)
+	if err != nil {
+		return handler.Meet{}, err
+	}
+	if existing != nil {
+		return handler.Meet{}, fmt.Errorf("race %s is already in meet %s %w", raceName, meetName, handler.ErrConflict)
+	}
+
+	_, err = ma.raceWriter.SaveRace(&meets.Race{Name: raceName}, meet)
+	if err != nil {
+		return handler.Meet{}, err
+	}
+	return ma.meetResponse(meet)
+}
+
+func (ma *MeetAdmin) RenameRace(meetName, raceName, newName string) (handler.Meet, error) {
+	ma.mu.Lock()
+	defer ma.mu.Unlock()
+
+	meet, race, err := ma.race(meetName, raceName)
+	if err != nil {
+		return handler.Meet{}, err
+	}
+	if newName != raceName {
+		existing, err := ma.raceReader.GetRace(meet, newName)
+		if err != nil {
+			return handler.Meet{}, err
+		}
+		if existing != nil {
+			return handler.Meet{}, fmt.Errorf("race %s is already in meet %s %w", newName, meetName, handler.ErrConflict)
+		}
+	}
+
+	race.Name = newName
+	_, err = ma.raceWriter.SaveRace(race, meet)
+	if err != nil {
+		return handler.Meet{}, err
+	}
+	return ma.meetResponse(meet)
+}
+
+func (ma *MeetAdmin) GetEntries(meetName, raceName string) ([]handler.Entry, error) {
+	_, race, err := ma.race(meetName, raceName)
+	if err != nil {
+		return nil, err
+	}
+	raceAthletes, err := ma.athleteReader.GetRaceAthletes(race)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]handler.Entry, 0, len(raceAthletes))
+	for _, ra := range raceAthletes {
+		entries = append(entries, newEntry(ra))
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Bib < entries[j].Bib })
+	return entries, nil
+}
+
+func (ma *MeetAdmin) AddEntry(meetName, raceName string, entry handler.Entry) (handler.Entry, error) {
+	ma.mu.Lock()
+	defer ma.mu.Unlock()
+
+	meet, err := ma.meet(meetName)
+	if err != nil {
+		return handler.Entry{}, err
+	}
+	entries, err := ma.entries(meet)
+	if err != nil {
+		return handler.Entry{}, err
+	}
+	race := entries.race(raceName)
+	if race == nil {
+		return handler.Entry{}, fmt.Errorf("race %s in meet %s %w", raceName, meetName, handler.ErrNotFound)
+	}
+	if taken, found := entries.bibs[entry.Bib]; found {
+		return handler.Entry{}, fmt.Errorf("bib %d is already in race %s %w", entry.Bib, taken.race.Name, handler.ErrConflict)
+	}
+	if other, found := entries.athlete(entry.Athlete.DaID); found {
+		return handler.Entry{}, fmt.Errorf("athlete %s is already in race %s with bib %d, move them instead %w",
+			entry.Athlete.DaID, other.race.Name, other.athlete.Bib, handler.ErrConflict)
+	}
+
+	athlete, err := ma.athleteReader.GetAthlete(entry.Athlete.DaID)
+	if err != nil {
+		return handler.Entry{}, err
+	}
+	if athlete == nil {
+		details := entry.Athlete
+		if details.FirstName == "" || details.LastName == "" || details.Gender == "" {
+			return handler.Entry{}, fmt.Errorf("athlete %s isn't saved, firstName, lastName and gender are required %w", details.DaID, handler.ErrInvalid)
+		}
+		athlete, err = ma.athleteWriter.SaveAthlete(meets.NewAthlete(details.FirstName, details.LastName, details.Team, details.DaID, details.Grade, details.Gender))
+		if err != nil {
+			return handler.Entry{}, err
+		}
+	}
+
+	err = ma.raceWriter.AddAthlete(race, athlete, entry.Bib)
+	if err != nil {
+		return handler.Entry{}, err
+	}
+	if entry.Wave != "" {
+		err = ma.raceWriter.SetAthleteWave(race, entry.Bib, entry.Wave)
+		if err != nil {
+			return handler.Entry{}, err
+		}
+	}
+
+	return newEntry(&meets.RaceAthlete{Athlete: *athlete, Bib: entry.Bib, Wave: entry.Wave}), nil
+}
+
+func (ma *MeetAdmin) MoveEntry(meetName, raceName string, bib int, move handler.EntryMove) (handler.Entry, error) {
+	ma.mu.Lock()
+	defer ma.mu.Unlock()
+
+	meet, err := ma.meet(meetName)
+	if err != nil {
+		return handler.Entry{}, err
+	}
+	entries, err := ma.entries(meet)
+	if err != nil {
+		return handler.Entry{}, err
+	}
+	if entries.race(raceName) == nil {
+		return handler.Entry{}, fmt.Errorf("race %s in meet %s %w", raceName, meetName, handler.ErrNotFound)
+	}
+	current, found := entries.bibs[bib]
+	if !found || current.race.Name != raceName {
+		return handler.Entry{}, fmt.Errorf("bib %d in race %s %w", bib, raceName, handler.ErrNotFound)
+	}
+
+	to := current.race
+	if move.Race != "" {
+		to = entries.race(move.Race)
+		if to == nil {
+			return handler.Entry{}, fmt.Errorf("race %s isn't in meet %s %w", move.Race, meetName, handler.ErrInvalid)
+		}
+	}
+	toBib := bib
+	if move.Bib != 0 {
+		toBib = move.Bib
+	}
+	if taken, found := entries.bibs[toBib]; found && toBib != bib {
+		return handler.Entry{}, fmt.Errorf("bib %d is already in race %s %w", toBib, taken.race.Name, handler.ErrConflict)
+	}
+
+	if to != current.race || toBib != bib {
+		err = ma.raceWriter.MoveAthlete(current.race, bib, to, toBib)
+		if err != nil {
+			return handler.Entry{}, err
+		}
+	}
+	wave := current.athlete.Wave
+	if move.Wave != nil {
+		wave = *move.Wave
+		err = ma.raceWriter.SetAthleteWave(to, toBib, wave)
+		if err != nil {
+			return handler.Entry{}, err
+		}
+	}
+
+	return newEntry(&meets.RaceAthlete{Athlete: current.athlete.Athlete, Bib: toBib, Wave: wave}), nil
+}
+
+func (ma *MeetAdmin) RemoveEntry(meetName, raceName string, bib int) error {
+	ma.mu.Lock()
+	defer ma.mu.Unlock()
+
+	meet, err := ma.meet(meetName)
+	if err != nil {
+		return err
+	}
+	entries, err := ma.entries(meet)
+	if err != nil {
+		return err
+	}
+	if entries.race(raceName) == nil {
+		return fmt.Errorf("race %s in meet %s %w", raceName, meetName, handler.ErrNotFound)
+	}
+	current, found := entries.bibs[bib]
+	if !found || current.race.Name != raceName {
+		return fmt.Errorf("bib %d in race %s %w", bib, raceName, handler.ErrNotFound)
+	}
+
+	return ma.raceWriter.RemoveAthlete(current.race, &current.athlete.Athlete)
+}
+
+func (ma *MeetAdmin) GetAthlete(daID string) (handler.Athlete, error) {
+	athlete, err := ma.athlete(daID)
+	if err != nil {
+		return handler.Athlete{}, err
+	}
+	return newAthlete(athlete), nil
+}
+
+func (ma *MeetAdmin) UpdateAthlete(daID string, details handler.Athlete) (handler.Athlete, error) {
+	ma.mu.Lock()
+	defer ma.mu.Unlock()
+
+	athlete, err := ma.athlete(daID)
+	if err != nil {
+		return handler.Athlete{}, err
+	}
+	athlete.FirstName = details.FirstName
+	athlete.LastName = details.LastName
+	athlete.Team = details.Team
+	athlete.Grade = details.Grade
+	athlete.Gender = details.Gender
+
+	athlete, err = ma.athleteWriter.SaveAthlete(athlete)
+	if err != nil {
+		return handler.Athlete{}, err
+	}
+	return newAthlete(athlete), nil
+}
+
+func (ma *MeetAdmin) meet(meetName string) (*meets.Meet, error) {
+	meet, err := ma.meetReader.GetMeet(meetName)
+	if err != nil {
+		return nil, err
+	}
+	if meet == nil {
+		return nil, fmt.Errorf("meet %s %w", meetName, handler.ErrNotFound)
+	}
+	return meet, nil
+}
+
+func (ma *MeetAdmin) race(meetName, raceName string) (*meets.Meet, *meets.Race, error) {
+	meet, err := ma.meet(meetName)
+	if err != nil {
+		return nil, nil, err
+	}
+	race, err := ma.raceReader.GetRace(meet, raceName)
+	if err != nil {
+		return nil, nil, err
+	}
+	if race == nil {
+		return nil, nil, fmt.Errorf("race %s in meet %s %w", raceName, meetName, handler.ErrNotFound)
+	}
+	return meet, race, nil
+}
+
+func (ma *MeetAdmin) athlete(daID string) (*meets.Athlete, error) {
+	athlete, err := ma.athleteReader.GetAthlete(daID)
+	if err != nil {
+		return nil, err
+	}
+	if athlete == nil {
+		return nil, fmt.Errorf("athlete %s %w", daID, handler.ErrNotFound)
+	}
+	return athlete, nil
+}
+
+// entries reads every race in the meet and the athletes in them
+func (ma *MeetAdmin) entries(meet *meets.Meet) (meetEntries, error) {
+	races, err := ma.meetReader.GetMeetRaces(meet)
+	if err != nil {
+		return meetEntries{}, err
+	}
+
+	entries := meetEntries{races: races, bibs: make(map[int]meetEntry)}
+	for i := range entries.races {
+		race := &entries.races[i]
+		raceAthletes, err := ma.athleteReader.GetRaceAthletes(race)
+		if err != nil {
+			return meetEntries{}, err
+		}
+		for _, ra := range raceAthletes {
+			entries.bibs[ra.Bib] = meetEntry{race: race, athlete: ra}
+		}
+	}
+	return entries, nil
+}
+
+func (ma *MeetAdmin) meetResponse(meet *meets.Meet) (handler.Meet, error) {
+	races, err := ma.meetReader.GetMeetRaces(meet)
+	if err != nil {
+		return handler.Meet{}, err
+	}
+
+	response := handler.Meet{Name: meet.Name, Races: make([]string, 0, len(races))}
+	for _, race := range races {
+		response.Races = append(response.Races, race.Name)
+	}
+	return response, nil
+}
+
+func newAthlete(athlete *meets.Athlete) handler.Athlete {
+	return handler.Athlete{
+		DaID:      athlete.DaID,
+		FirstName: athlete.FirstName,
+		LastName:  athlete.LastName,
+		Team:      athlete.Team,
+		Grade:     athlete.Grade,
+		Gender:    athlete.Gender,
+	}
+}
+
+func newEntry(ra *meets.RaceAthlete) handler.Entry {
+	return handler.Entry{Bib: ra.Bib, Wave: ra.Wave, Athlete: newAthlete(&ra.Athlete)}
+}
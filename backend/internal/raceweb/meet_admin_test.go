@@ -0,0 +1,214 @@
+package raceweb
+
+import (
+	"blreynolds4/event-race-timer/internal/meets"
+	"blreynolds4/event-race-timer/internal/raceweb/handler"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// memoryMeets keeps meets, races and athletes in memory, races are told apart by name
+type memoryMeets struct {
+	meets    []*meets.Meet
+	races    map[*meets.Meet][]*meets.Race
+	entries  map[string][]*meets.RaceAthlete
+	athletes map[string]*meets.Athlete
+}
+
+func newMemoryMeets() *memoryMeets {
+	return &memoryMeets{
+		races:    make(map[*meets.Meet][]*meets.Race),
+		entries:  make(map[string][]*meets.RaceAthlete),
+		athletes: make(map[string]*meets.Athlete),
+	}
+}
+
+func (mm *memoryMeets) GetMeet(name string) (*meets.Meet, error) {
+	for _, m := range mm.meets {
+		if m.Name == name {
+			return m, nil
+		}
+	}
+	return nil, nil
+}
+func (mm *memoryMeets) GetMeets() ([]*meets.Meet, error) { return mm.meets, nil }
+func (mm *memoryMeets) GetMeetRaces(m *meets.Meet) ([]meets.Race, error) {
+	races := make([]meets.Race, 0)
+	for _, r := range mm.races[m] {
+		races = append(races, *r)
+	}
+	return races, nil
+}
+func (mm *memoryMeets) SaveMeet(m *meets.Meet) (*meets.Meet, error) {
+	if !slices.Contains(mm.meets, m) {
+		mm.meets = append(mm.meets, m)
+	}
+	return m, nil
+}
+func (mm *memoryMeets) DeleteMeet(m *meets.Meet) error { return nil }
+
+func (mm *memoryMeets) GetRace(m *meets.Meet, raceName string) (*meets.Race, error) {
+	for _, r := range mm.races[m] {
+		if r.Name == raceName {
+			return r, nil
+		}
+	}
+	return nil, nil
+}
+func (mm *memoryMeets) GetRaceByName(raceName string) (*meets.Race, error) { return nil, nil }
+func (mm *memoryMeets) SaveRace(r *meets.Race, m *meets.Meet) (*meets.Race, error) {
+	if !slices.Contains(mm.races[m], r) {
+		mm.races[m] = append(mm.races[m], m.AddRace(r))
+	}
+	return r, nil
+}
+func (mm *memoryMeets) AddAthlete(r *meets.Race, a *meets.Athlete, bib int) error {
+	mm.entries[r.Name] = append(mm.entries[r.Name], &meets.RaceAthlete{Athlete: *a, Bib: bib})
+	return nil
+}
+func (mm *memoryMeets) SetAthleteWave(r *meets.Race, bib int, wave string) error {
+	for _, ra := range mm.entries[r.Name] {
+		if ra.Bib == bib {
+			ra.Wave = wave
+		}
+	}
+	return nil
+}
+func (mm *memoryMeets) MoveAthlete(r *meets.Race, bib int, to *meets.Race, toBib int) error {
+	i := slices.IndexFunc(mm.entries[r.Name], func(ra *meets.RaceAthlete) bool { return ra.Bib == bib })
+	moved := mm.entries[r.Name][i]
+	mm.entries[r.Name] = slices.Delete(mm.entries[r.Name], i, i+1)
+	moved.Bib = toBib
+	mm.entries[to.Name] = append(mm.entries[to.Name], moved)
+	return nil
+}
+func (mm *memoryMeets) RemoveAthlete(r *meets.Race, a *meets.Athlete) error {
+	mm.entries[r.Name] = slices.DeleteFunc(mm.entries[r.Name], func(ra *meets.RaceAthlete) bool { return ra.Athlete.DaID == a.DaID })
+	return nil
+}
+func (mm *memoryMeets) DeleteRace(r *meets.Race) error { return nil }
+
+func (mm *memoryMeets) GetAthlete(daID string) (*meets.Athlete, error) {
+	if a, found := mm.athletes[daID]; found {
+		athlete := *a
+		return &athlete, nil
+	}
+	return nil, nil
+}
+func (mm *memoryMeets) GetRaceAthlete(r *meets.Race, bib int) (*meets.RaceAthlete, error) {
+	return nil, nil
+}
+func (mm *memoryMeets) GetRaceAthletes(r *meets.Race) ([]*meets.RaceAthlete, error) {
+	return mm.entries[r.Name], nil
+}
+func (mm *memoryMeets) SaveAthlete(a *meets.Athlete) (*meets.Athlete, error) {
+	mm.athletes[a.DaID] = a
+	return a, nil
+}
+func (mm *memoryMeets) DeleteAthlete(a *meets.Athlete) error { return nil }
+func (mm *memoryMeets) Close() error                         { return nil }
+
+func newTestMeetAdmin(mm *memoryMeets) *MeetAdmin {
+	return NewMeetAdmin(mm, mm, mm, mm, mm, mm)
+}
+
+func TestMeetAdminMeetsAndRaces(t *testing.T) {
+	admin := newTestMeetAdmin(newMemoryMeets())
+
+	meet, err := admin.CreateMeet("Invitational")
+	assert.NoError(t, err)
+	assert.Equal(t, handler.Meet{Name: "Invitational", Races: []string{}}, meet)
+	_, err = admin.CreateMeet("Invitational")
+	assert.ErrorIs(t, err, handler.ErrConflict)
+
+	_, err = admin.CreateRace("Invitational", "JV")
+	assert.NoError(t, err)
+	meet, err = admin.CreateRace("Invitational", "Varsity")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"JV", "Varsity"}, meet.Races)
+	_, err = admin.CreateRace("Invitational", "JV")
+	assert.ErrorIs(t, err, handler.ErrConflict)
+	_, err = admin.CreateRace("Dual", "JV")
+	assert.ErrorIs(t, err, handler.ErrNotFound)
+
+	_, err = admin.RenameRace("Invitational", "JV", "Varsity")
+	assert.ErrorIs(t, err, handler.ErrConflict)
+	meet, err = admin.RenameRace("Invitational", "JV", "Girls")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Girls", "Varsity"}, meet.Races)
+	_, err = admin.RenameRace("Invitational", "JV", "Boys")
+	assert.ErrorIs(t, err, handler.ErrNotFound)
+
+	meet, err = admin.RenameMeet("Invitational", "Championship")
+	assert.NoError(t, err)
+	assert.Equal(t, "Championship", meet.Name)
+	_, err = admin.GetMeet("Invitational")
+	assert.ErrorIs(t, err, handler.ErrNotFound)
+	meet, err = admin.GetMeet("Championship")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Girls", "Varsity"}, meet.Races)
+}
+
+func TestMeetAdminEntries(t *testing.T) {
+	mm := newMemoryMeets()
+	mm.SaveAthlete(meets.NewAthlete("Andrew", "O'Brien", "Oyster River", "ob1", 10, "m"))
+	admin := newTestMeetAdmin(mm)
+	admin.CreateMeet("Invitational")
+	admin.CreateRace("Invitational", "JV")
+	admin.CreateRace("Invitational", "Varsity")
+
+	// a saved athlete only needs their daId, a new one needs their details
+	entry, err := admin.AddEntry("Invitational", "JV", handler.Entry{Bib: 100, Athlete: handler.Athlete{DaID: "ob1"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "O'Brien", entry.Athlete.LastName)
+	_, err = admin.AddEntry("Invitational", "Varsity", handler.Entry{Bib: 200, Athlete: handler.Athlete{DaID: "dr1"}})
+	assert.ErrorIs(t, err, handler.ErrInvalid)
+	entry, err = admin.AddEntry("Invitational", "Varsity", handler.Entry{Bib: 200, Wave: "A",
+		Athlete: handler.Athlete{DaID: "dr1", FirstName: "David", LastName: "Reynolds", Team: "Merrimack Valley", Grade: 12, Gender: "m"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "A", entry.Wave)
+	assert.NotNil(t, mm.athletes["dr1"])
+
+	// bibs and athletes are only in one race in the meet
+	_, err = admin.AddEntry("Invitational", "Varsity", handler.Entry{Bib: 100, Athlete: handler.Athlete{DaID: "dr1"}})
+	assert.ErrorIs(t, err, handler.ErrConflict)
+	_, err = admin.AddEntry("Invitational", "Varsity", handler.Entry{Bib: 201, Athlete: handler.Athlete{DaID: "ob1"}})
+	assert.ErrorIs(t, err, handler.ErrConflict)
+	_, err = admin.AddEntry("Invitational", "Girls", handler.Entry{Bib: 300, Athlete: handler.Athlete{DaID: "ob1"}})
+	assert.ErrorIs(t, err, handler.ErrNotFound)
+
+	// move jv's runner up to varsity with a new bib, the wave is kept
+	entry, err = admin.MoveEntry("Invitational", "JV", 100, handler.EntryMove{Race: "Varsity", Bib: 201})
+	assert.NoError(t, err)
+	assert.Equal(t, 201, entry.Bib)
+	entries, err := admin.GetEntries("Invitational", "Varsity")
+	assert.NoError(t, err)
+	assert.Equal(t, []int{200, 201}, []int{entries[0].Bib, entries[1].Bib})
+	entries, err = admin.GetEntries("Invitational", "JV")
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+
+	wave := "B"
+	entry, err = admin.MoveEntry("Invitational", "Varsity", 201, handler.EntryMove{Wave: &wave})
+	assert.NoError(t, err)
+	assert.Equal(t, handler.Entry{Bib: 201, Wave: "B", Athlete: entry.Athlete}, entry)
+
+	_, err = admin.MoveEntry("Invitational", "Varsity", 201, handler.EntryMove{Bib: 200})
+	assert.ErrorIs(t, err, handler.ErrConflict)
+	_, err = admin.MoveEntry("Invitational", "Varsity", 201, handler.EntryMove{Race: "Girls"})
+	assert.ErrorIs(t, err, handler.ErrInvalid)
+	_, err = admin.MoveEntry("Invitational", "JV", 201, handler.EntryMove{Race: "Varsity"})
+	assert.ErrorIs(t, err, handler.ErrNotFound)
+
+	assert.NoError(t, admin.RemoveEntry("Invitational", "Varsity", 201))
+	assert.ErrorIs(t, admin.RemoveEntry("Invitational", "Varsity", 201), handler.ErrNotFound)
+
+	// the daId picks the athlete to edit
+	athlete, err := admin.UpdateAthlete("dr1", handler.Athlete{FirstName: "Dave", LastName: "Reynolds", Team: "MV", Grade: 12, Gender: "m"})
+	assert.NoError(t, err)
+	assert.Equal(t, handler.Athlete{DaID: "dr1", FirstName: "Dave", LastName: "Reynolds", Team: "MV", Grade: 12, Gender: "m"}, athlete)
+	_, err = admin.GetAthlete("nobody")
+	assert.ErrorIs(t, err, handler.ErrNotFound)
+}
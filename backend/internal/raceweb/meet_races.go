@@ -0,0 +1,227 @@
+package raceweb
+
+import (
+	"blreynolds4/event-race-timer/internal/config"
+	"blreynolds4/event-race-timer/internal/meets"
+	"blreynolds4/event-race-timer/internal/raceevents"
+	"blreynolds4/event-race-timer/internal/raceweb/handler"
+	"blreynolds4/event-race-timer/internal/readfilter"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+const (
+	// refreshInterval is the least time between reloading the meet's athletes for bibs that aren't found
+	refreshInterval = 10 * time.Second
+	// staleInterval is the most time the athletes are kept, a bib moved to another race is
+	// timed in its new race after this
+	staleInterval = 30 * time.Second
+)
+
+// OpenStream opens a race's event stream
+type OpenStream func(raceName string) (raceevents.EventStream, error)
+
+// MeetRaces sends reads to the races being timed in a meet.  The athletes come from
+// each race in the meet and are reloaded when a bib isn't found, so athletes added to a
+// race during the meet are timed.  They're also reloaded when they're stale so a bib that
+// moved to another race is timed in the new race.  A bib that's only in the meet's other
+// races is logged with the race it's in.
+type MeetRaces struct {
+	meet          *meets.Meet
+	raceNames     []string
+	meetReader    meets.MeetReader
+	athleteReader meets.AthleteReader
+	openStream    OpenStream
+	filters       map[string]config.ReadFilter
+	ranking       config.RankingPolicy
+	offsets       config.ClockOffsets
+	logger        *slog.Logger
+
+	// refreshMu is held while the athletes are reloaded, mu only while they're swapped in
+	// so reads of bibs that are found don't wait for the database
+	refreshMu  sync.Mutex
+	mu         sync.Mutex
+	bibRaces   meets.BibRaceLookup
+	otherRaces map[int]string
+	races      map[string]handler.RaceTiming
+	refreshed  time.Time
+}
+
+// loadedRace is a race's athletes read from the meet
+type loadedRace struct {
+	athletes meets.AthleteLookup
+	waves    meets.WaveLookup
+}
+
+// NewMeetRaces loads the athletes in the meet's raceNames, each race's reads go to the stream from openStream.
+// The ranking and clock offsets pick the start a race's reads are filtered with.
+func NewMeetRaces(meet *meets.Meet, raceNames []string, meetReader meets.MeetReader, athleteReader meets.AthleteReader, openStream OpenStream, filters map[string]config.ReadFilter, ranking config.RankingPolicy, offsets config.ClockOffsets, logger *slog.Logger) (*MeetRaces, error) {
+	mr := &MeetRaces{
+		meet:          meet,
+		raceNames:     raceNames,
+		meetReader:    meetReader,
+		athleteReader: athleteReader,
+		openStream:    openStream,
+		filters:       filters,
+		ranking:       ranking,
+		offsets:       offsets,
+		logger:        logger,
+		races:         make(map[string]handler.RaceTiming),
+	}
+
+	err := mr.Refresh()
+	if err != nil {
+		return nil, err
+	}
+	return mr, nil
+}
+
+// Refresh reloads the athletes in every race
+func (mr *MeetRaces) Refresh() error {
+	mr.refreshMu.Lock()
+	defer mr.refreshMu.Unlock()
+	return mr.refresh()
+}
+
+func (mr *MeetRaces) RaceForBib(bib int) (string, handler.RaceTiming, bool) {
+	mr.mu.Lock()
+	stale := mr.needsRefresh(bib)
+	mr.mu.Unlock()
+	if stale {
+		// the read waits for the reload, it's quicker than the readers send
+		mr.refreshStale(bib)
+	}
+
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+	raceName, found := mr.bibRaces[bib]
+	if !found {
+		if otherRace, entered := mr.otherRaces[bib]; entered {
+			mr.logger.Warn("bib is in a race that isn't being timed", "meet", mr.meet.Name, "bib", bib, "race", otherRace)
+		}
+		return "", handler.RaceTiming{}, false
+	}
+	return raceName, mr.races[raceName], true
+}
+
+// needsRefresh is true when the athletes should be reloaded to find bib, mu is held
+func (mr *MeetRaces) needsRefresh(bib int) bool {
+	_, found := mr.bibRaces[bib]
+	sinceRefresh := time.Since(mr.refreshed)
+	return (!found && sinceRefresh >= refreshInterval) || sinceRefresh >= staleInterval
+}
+
+// refreshStale reloads the athletes unless another read reloaded them while this one waited
+func (mr *MeetRaces) refreshStale(bib int) {
+	mr.refreshMu.Lock()
+	defer mr.refreshMu.Unlock()
+
+	mr.mu.Lock()
+	stale := mr.needsRefresh(bib)
+	mr.mu.Unlock()
+	if !stale {
+		return
+	}
+
+	err := mr.refresh()
+	if err != nil {
+		// keep timing with the athletes already loaded
+		mr.logger.Error("error reloading meet athletes", "meet", mr.meet.Name, "error", err)
+		mr.mu.Lock()
+		mr.refreshed = time.Now()
+		mr.mu.Unlock()
+	}
+}
+
+// RaceStream returns the event stream of a race being timed, false for other races
+func (mr *MeetRaces) RaceStream(raceName string) (raceevents.EventStream, bool) {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	race, found := mr.races[raceName]
+	return race.Events, found
+}
+
+// refresh reads the athletes from the database then swaps them in, refreshMu is held
+func (mr *MeetRaces) refresh() error {
+	meetRaces, err := mr.meetReader.GetMeetRaces(mr.meet)
+	if err != nil {
+		return err
+	}
+	byName := make(map[string]meets.Race, len(meetRaces))
+	for _, race := range meetRaces {
+		byName[race.Name] = race
+	}
+
+	bibRaces := make(meets.BibRaceLookup)
+	loaded := make(map[string]loadedRace, len(mr.raceNames))
+	for _, raceName := range mr.raceNames {
+		race, found := byName[raceName]
+		if !found {
+			return fmt.Errorf("race %s isn't in meet %s", raceName, mr.meet.Name)
+		}
+
+		raceAthletes, err := mr.athleteReader.GetRaceAthletes(&race)
+		if err != nil {
+			return err
+		}
+		athletes := make(meets.AthleteLookup, len(raceAthletes))
+		waves := make(meets.WaveLookup)
+		for _, ra := range raceAthletes {
+			athletes[ra.Bib] = &ra.Athlete
+			if ra.Wave != "" {
+				waves[ra.Bib] = ra.Wave
+			}
+		}
+
+		err = bibRaces.AddRace(raceName, athletes)
+		if err != nil {
+			return err
+		}
+		loaded[raceName] = loadedRace{athletes: athletes, waves: waves}
+	}
+
+	// the meet's other races say where a bib that isn't timed was entered
+	otherRaces := make(map[int]string)
+	for _, race := range meetRaces {
+		if _, timed := loaded[race.Name]; timed {
+			continue
+		}
+		raceAthletes, err := mr.athleteReader.GetRaceAthletes(&race)
+		if err != nil {
+			return err
+		}
+		for _, ra := range raceAthletes {
+			otherRaces[ra.Bib] = race.Name
+		}
+	}
+
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	races := make(map[string]handler.RaceTiming, len(loaded))
+	for raceName, lr := range loaded {
+		// a race keeps its stream and filter, the filter remembers the reads it's seen
+		timing, found := mr.races[raceName]
+		if found {
+			timing.Filter.SetWaves(lr.waves)
+		} else {
+			timing.Events, err = mr.openStream(raceName)
+			if err != nil {
+				return err
+			}
+			timing.Filter = readfilter.NewFinishFilter(mr.filters, lr.waves, timing.Events, mr.ranking, mr.offsets)
+		}
+		timing.Athletes = lr.athletes
+		races[raceName] = timing
+	}
+
+	mr.logger.Info("loaded meet athletes", "meet", mr.meet.Name, "races", len(races), "bibs", len(bibRaces))
+	mr.bibRaces = bibRaces
+	mr.otherRaces = otherRaces
+	mr.races = races
+	mr.refreshed = time.Now()
+	return nil
+}
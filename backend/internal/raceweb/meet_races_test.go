@@ -0,0 +1,170 @@
+package raceweb
+
+import (
+	"blreynolds4/event-race-timer/internal/config"
+	"blreynolds4/event-race-timer/internal/meets"
+	"blreynolds4/event-race-timer/internal/raceevents"
+	"bytes"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockMeetReader struct {
+	races []meets.Race
+}
+
+func (mmr *mockMeetReader) GetMeet(name string) (*meets.Meet, error) { return nil, nil }
+func (mmr *mockMeetReader) GetMeets() ([]*meets.Meet, error)         { return nil, nil }
+func (mmr *mockMeetReader) GetMeetRaces(m *meets.Meet) ([]meets.Race, error) {
+	return mmr.races, nil
+}
+func (mmr *mockMeetReader) Close() error { return nil }
+
+// mockAthleteReader has the athletes in each race by race name, when loading is set each read
+// sends the race name to it and waits for release
+type mockAthleteReader struct {
+	athletes map[string][]*meets.RaceAthlete
+	loading  chan string
+	release  chan struct{}
+}
+
+func (mar *mockAthleteReader) GetAthlete(daID string) (*meets.Athlete, error) { return nil, nil }
+func (mar *mockAthleteReader) GetRaceAthlete(r *meets.Race, bib int) (*meets.RaceAthlete, error) {
+	return nil, nil
+}
+func (mar *mockAthleteReader) GetRaceAthletes(r *meets.Race) ([]*meets.RaceAthlete, error) {
+	if mar.loading != nil {
+		mar.loading <- r.Name
+		<-mar.release
+	}
+	return mar.athletes[r.Name], nil
+}
+func (mar *mockAthleteReader) Close() error { return nil }
+
+func raceAthlete(bib int, wave string) *meets.RaceAthlete {
+	return &meets.RaceAthlete{Athlete: *meets.NewAthlete("A", "B", "WPI", "", 10, "m"), Bib: bib, Wave: wave}
+}
+
+func TestMeetRacesRoutesByBib(t *testing.T) {
+	meetReader := &mockMeetReader{races: []meets.Race{{Name: "JV"}, {Name: "Varsity"}, {Name: "Girls"}}}
+	athleteReader := &mockAthleteReader{athletes: map[string][]*meets.RaceAthlete{
+		"JV":      {raceAthlete(100, "")},
+		"Varsity": {raceAthlete(200, "")},
+		"Girls":   {raceAthlete(300, "")},
+	}}
+	streams := make(map[string]*raceevents.MockEventStream)
+	openStream := func(raceName string) (raceevents.EventStream, error) {
+		streams[raceName] = &raceevents.MockEventStream{}
+		return streams[raceName], nil
+	}
+
+	// girls isn't being timed
+	var logged bytes.Buffer
+	mr, err := NewMeetRaces(&meets.Meet{Name: "Invitational"}, []string{"JV", "Varsity"}, meetReader, athleteReader, openStream, nil, config.RankingPolicy{}, nil, slog.New(slog.NewTextHandler(&logged, nil)))
+	assert.NoError(t, err)
+
+	raceName, race, found := mr.RaceForBib(200)
+	assert.True(t, found)
+	assert.Equal(t, "Varsity", raceName)
+	assert.Equal(t, streams["Varsity"], race.Events)
+
+	_, _, found = mr.RaceForBib(300)
+	assert.False(t, found)
+	assert.Contains(t, logged.String(), "bib=300 race=Girls")
+
+	// an athlete added to jv is found once the athletes are reloaded
+	athleteReader.athletes["JV"] = append(athleteReader.athletes["JV"], raceAthlete(101, "boys"))
+	_, _, found = mr.RaceForBib(101)
+	assert.False(t, found)
+
+	mr.refreshed = time.Now().Add(-refreshInterval)
+	raceName, race, found = mr.RaceForBib(101)
+	assert.True(t, found)
+	assert.Equal(t, "JV", raceName)
+	assert.Equal(t, streams["JV"], race.Events)
+	assert.Equal(t, 2, len(streams))
+
+	// manual events go to the stream of a race being timed
+	stream, found := mr.RaceStream("Varsity")
+	assert.True(t, found)
+	assert.Equal(t, streams["Varsity"], stream)
+	_, found = mr.RaceStream("Girls")
+	assert.False(t, found)
+}
+
+func TestMeetRacesBibInTwoRaces(t *testing.T) {
+	meetReader := &mockMeetReader{races: []meets.Race{{Name: "JV"}, {Name: "Varsity"}}}
+	athleteReader := &mockAthleteReader{athletes: map[string][]*meets.RaceAthlete{
+		"JV":      {raceAthlete(100, "")},
+		"Varsity": {raceAthlete(100, "")},
+	}}
+	openStream := func(raceName string) (raceevents.EventStream, error) {
+		return &raceevents.MockEventStream{}, nil
+	}
+
+	_, err := NewMeetRaces(&meets.Meet{Name: "Invitational"}, []string{"JV", "Varsity"}, meetReader, athleteReader, openStream, nil, config.RankingPolicy{}, nil, slog.New(slog.DiscardHandler))
+	assert.Error(t, err)
+
+	_, err = NewMeetRaces(&meets.Meet{Name: "Invitational"}, []string{"JV", "Middle School"}, meetReader, athleteReader, openStream, nil, config.RankingPolicy{}, nil, slog.New(slog.DiscardHandler))
+	assert.Error(t, err)
+}
+
+func TestMeetRacesMovedBib(t *testing.T) {
+	meetReader := &mockMeetReader{races: []meets.Race{{Name: "JV"}, {Name: "Varsity"}}}
+	athleteReader := &mockAthleteReader{athletes: map[string][]*meets.RaceAthlete{
+		"JV":      {raceAthlete(100, "")},
+		"Varsity": {raceAthlete(200, "")},
+	}}
+	openStream := func(raceName string) (raceevents.EventStream, error) {
+		return &raceevents.MockEventStream{}, nil
+	}
+	mr, err := NewMeetRaces(&meets.Meet{Name: "Invitational"}, []string{"JV", "Varsity"}, meetReader, athleteReader, openStream, nil, config.RankingPolicy{}, nil, slog.New(slog.DiscardHandler))
+	assert.NoError(t, err)
+
+	// 100 moved up to varsity, a found bib keeps its race until the athletes are stale
+	athleteReader.athletes["JV"] = nil
+	athleteReader.athletes["Varsity"] = append(athleteReader.athletes["Varsity"], raceAthlete(100, ""))
+	mr.refreshed = time.Now().Add(-refreshInterval)
+	raceName, _, found := mr.RaceForBib(100)
+	assert.True(t, found)
+	assert.Equal(t, "JV", raceName)
+
+	mr.refreshed = time.Now().Add(-staleInterval)
+	raceName, _, found = mr.RaceForBib(100)
+	assert.True(t, found)
+	assert.Equal(t, "Varsity", raceName)
+}
+
+func TestMeetRacesReadsDuringRefresh(t *testing.T) {
+	meetReader := &mockMeetReader{races: []meets.Race{{Name: "JV"}}}
+	athleteReader := &mockAthleteReader{athletes: map[string][]*meets.RaceAthlete{
+		"JV": {raceAthlete(100, "")},
+	}}
+	openStream := func(raceName string) (raceevents.EventStream, error) {
+		return &raceevents.MockEventStream{}, nil
+	}
+	mr, err := NewMeetRaces(&meets.Meet{Name: "Invitational"}, []string{"JV"}, meetReader, athleteReader, openStream, nil, config.RankingPolicy{}, nil, slog.New(slog.DiscardHandler))
+	assert.NoError(t, err)
+
+	// an unknown bib reloads the athletes, the reload waits in the database
+	athleteReader.loading = make(chan string, 1)
+	athleteReader.release = make(chan struct{})
+	mr.refreshed = time.Now().Add(-refreshInterval)
+	reloaded := make(chan bool)
+	go func() {
+		_, _, found := mr.RaceForBib(101)
+		reloaded <- found
+	}()
+	assert.Equal(t, "JV", <-athleteReader.loading)
+
+	// a bib that's found doesn't wait for the reload
+	raceName, _, found := mr.RaceForBib(100)
+	assert.True(t, found)
+	assert.Equal(t, "JV", raceName)
+
+	close(athleteReader.release)
+	assert.False(t, <-reloaded)
+}
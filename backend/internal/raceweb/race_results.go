@@ -0,0 +1,129 @@
+package raceweb
+
+import (
+	"blreynolds4/event-race-timer/internal/meets"
+	"blreynolds4/event-race-timer/internal/raceweb/handler"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+type meetRace struct {
+	meet string
+	race string
+}
+
+// RaceResults reads race results and place conflicts from postgres, a reader is kept for
+// each race that's read
+type RaceResults struct {
+	mu         sync.Mutex
+	meetReader meets.MeetReader
+	raceReader meets.RaceReader
+	connectStr string
+	readers    map[meetRace]meets.RaceResultReader
+	conflicts  map[meetRace]meets.PlaceConflictReader
+}
+
+func NewRaceResults(meetReader meets.MeetReader, raceReader meets.RaceReader, connectStr string) *RaceResults {
+	return &RaceResults{
+		meetReader: meetReader,
+		raceReader: raceReader,
+		connectStr: connectStr,
+		readers:    make(map[meetRace]meets.RaceResultReader),
+		conflicts:  make(map[meetRace]meets.PlaceConflictReader),
+	}
+}
+
+func (rr *RaceResults) GetRaceResults(meetName, raceName string) ([]*meets.RaceResult, error) {
+	reader, err := rr.reader(meetName, raceName)
+	if err != nil {
+		return nil, err
+	}
+	return reader.GetRaceResults()
+}
+
+func (rr *RaceResults) GetPlaceConflicts(meetName, raceName string) ([]meets.PlaceConflict, error) {
+	reader, err := rr.conflictReader(meetName, raceName)
+	if err != nil {
+		return nil, err
+	}
+	return reader.GetPlaceConflicts()
+}
+
+// Close closes the result and conflict readers
+func (rr *RaceResults) Close() error {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+
+	var errs []error
+	for _, reader := range rr.readers {
+		errs = append(errs, reader.Close())
+	}
+	for _, reader := range rr.conflicts {
+		errs = append(errs, reader.Close())
+	}
+	clear(rr.readers)
+	clear(rr.conflicts)
+	return errors.Join(errs...)
+}
+
+func (rr *RaceResults) reader(meetName, raceName string) (meets.RaceResultReader, error) {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+
+	key := meetRace{meet: meetName, race: raceName}
+	if reader, found := rr.readers[key]; found {
+		return reader, nil
+	}
+
+	race, err := rr.race(meetName, raceName)
+	if err != nil {
+		return nil, err
+	}
+	reader, err := meets.NewRaceResultReader(race, rr.connectStr)
+	if err != nil {
+		return nil, err
+	}
+	rr.readers[key] = reader
+	return reader, nil
+}
+
+func (rr *RaceResults) conflictReader(meetName, raceName string) (meets.PlaceConflictReader, error) {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+
+	key := meetRace{meet: meetName, race: raceName}
+	if reader, found := rr.conflicts[key]; found {
+		return reader, nil
+	}
+
+	race, err := rr.race(meetName, raceName)
+	if err != nil {
+		return nil, err
+	}
+	reader, err := meets.NewPlaceConflictReader(race, rr.connectStr)
+	if err != nil {
+		return nil, err
+	}
+	rr.conflicts[key] = reader
+	return reader, nil
+}
+
+// race finds the race in the meet, an error wrapping handler.ErrNotFound when it's not there
+func (rr *RaceResults) race(meetName, raceName string) (*meets.Race, error) {
+	meet, err := rr.meetReader.GetMeet(meetName)
+	if err != nil {
+		return nil, err
+	}
+	if meet == nil {
+		return nil, fmt.Errorf("meet %s %w", meetName, handler.ErrNotFound)
+	}
+	race, err := rr.raceReader.GetRace(meet, raceName)
+	if err != nil {
+		return nil, err
+	}
+	if race == nil {
+		return nil, fmt.Errorf("race %s in meet %s %w", raceName, meetName, handler.ErrNotFound)
+	}
+	return race, nil
+}
@@ -0,0 +1,94 @@
+package readfilter
+
+import (
+	"blreynolds4/event-race-timer/internal/config"
+	"blreynolds4/event-race-timer/internal/meets"
+	"blreynolds4/event-race-timer/internal/raceevents"
+	"context"
+	"sync"
+	"time"
+)
+
+// reasons a finish read is rejected
+const (
+	ReasonDebounce    = "debounce"
+	ReasonTooSoon     = "before minimum finish time"
+	ReasonAfterFinish = "after first finish"
+)
+
+type sourceBib struct {
+	source string
+	bib    int
+}
+
+// FinishFilter decides which finish reads are real finishes using each source's config.ReadFilter.
+// It's safe to use from concurrent handlers.
+type FinishFilter struct {
+	mu       sync.Mutex
+	filters  map[string]config.ReadFilter
+	waves    meets.WaveLookup
+	starts   *startWatcher
+	lastRead map[sourceBib]time.Time
+	finished map[sourceBib]bool
+}
+
+// NewFinishFilter filters finishes with the starts read from events, athletes not in waves are in the "" wave.
+// The ranking and clock offsets are the race's, they pick each wave's start.
+func NewFinishFilter(filters map[string]config.ReadFilter, waves meets.WaveLookup, events raceevents.EventStreamReader, ranking config.RankingPolicy, offsets config.ClockOffsets) *FinishFilter {
+	return &FinishFilter{
+		filters:  filters,
+		waves:    waves,
+		starts:   newStartWatcher(events, ranking, offsets),
+		lastRead: make(map[sourceBib]time.Time),
+		finished: make(map[sourceBib]bool),
+	}
+}
+
+// SetWaves replaces the athletes' waves, for athletes added after the filter was made
+func (ff *FinishFilter) SetWaves(waves meets.WaveLookup) {
+	ff.mu.Lock()
+	defer ff.mu.Unlock()
+	ff.waves = waves
+}
+
+// Check returns why the read should be rejected, or "" when it's a finish.
+// Reads of an athlete whose wave hasn't started aren't checked against the minimum finish time.
+func (ff *FinishFilter) Check(ctx context.Context, fe raceevents.FinishEvent) (string, error) {
+	ff.mu.Lock()
+	defer ff.mu.Unlock()
+
+	filter, found := ff.filters[fe.Source]
+	if !found {
+		return "", nil
+	}
+
+	key := sourceBib{source: fe.Source, bib: fe.Bib}
+	lastRead, seen := ff.lastRead[key]
+	if !seen || fe.FinishTime.After(lastRead) {
+		ff.lastRead[key] = fe.FinishTime
+	}
+
+	// every read extends the crossing, the reader sees a chip for as long as it's in range
+	debounce := time.Duration(filter.DebounceSeconds * float64(time.Second))
+	if seen && debounce > 0 && fe.FinishTime.Sub(lastRead).Abs() < debounce {
+		return ReasonDebounce, nil
+	}
+
+	if filter.FirstFinishOnly && ff.finished[key] {
+		return ReasonAfterFinish, nil
+	}
+
+	if filter.MinFinishSeconds > 0 {
+		start, started, err := ff.starts.startTime(ctx, ff.waves[fe.Bib])
+		if err != nil {
+			return "", err
+		}
+		minFinish := time.Duration(filter.MinFinishSeconds * float64(time.Second))
+		if started && fe.FinishTime.Sub(start) < minFinish {
+			return ReasonTooSoon, nil
+		}
+	}
+
+	ff.finished[key] = true
+	return "", nil
+}
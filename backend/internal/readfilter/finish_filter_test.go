@@ -0,0 +1,93 @@
+package readfilter
+
+import (
+	"blreynolds4/event-race-timer/internal/config"
+	"blreynolds4/event-race-timer/internal/meets"
+	"blreynolds4/event-race-timer/internal/raceevents"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func finishAt(source string, bib int, start time.Time, seconds float64) raceevents.FinishEvent {
+	return raceevents.FinishEvent{Source: source, Bib: bib, FinishTime: start.Add(time.Duration(seconds * float64(time.Second)))}
+}
+
+func TestFinishFilter(t *testing.T) {
+	start := time.Now().UTC()
+	events := &raceevents.MockEventStream{Events: []raceevents.Event{
+		{ID: "1-0", Data: raceevents.StartEvent{Source: "cli", StartTime: start}},
+	}}
+
+	filter := NewFinishFilter(map[string]config.ReadFilter{
+		"chip": {DebounceSeconds: 2, MinFinishSeconds: 600, FirstFinishOnly: true},
+	}, nil, events, config.RankingPolicy{}, nil)
+
+	for _, tc := range []struct {
+		read     raceevents.FinishEvent
+		expected string
+	}{
+		{finishAt("chip", 1, start, 30), ReasonTooSoon}, // walk by
+		{finishAt("chip", 1, start, 31), ReasonDebounce},
+		{finishAt("chip", 1, start, 1000), ""},
+		{finishAt("chip", 1, start, 1000.5), ReasonDebounce},
+		{finishAt("chip", 1, start, 1001.5), ReasonDebounce}, // still in range of the reader
+		{finishAt("chip", 1, start, 1100), ReasonAfterFinish},
+		{finishAt("chip", 2, start, 1000.2), ""},
+		{finishAt("manual", 1, start, 30), ""}, // no filter for the source
+	} {
+		reason, err := filter.Check(context.TODO(), tc.read)
+		assert.NoError(t, err)
+		assert.Equal(t, tc.expected, reason, "bib %d at %v", tc.read.Bib, tc.read.FinishTime.Sub(start))
+	}
+}
+
+func TestFinishFilterUsesWaveStart(t *testing.T) {
+	start := time.Now().UTC()
+	events := &raceevents.MockEventStream{Events: []raceevents.Event{
+		{ID: "1-0", Data: raceevents.StartEvent{Source: "cli", StartTime: start}},
+		{ID: "2-0", Data: raceevents.StartEvent{Source: "cli", StartTime: start.Add(time.Minute), Wave: "girls"}},
+		{ID: "3-0", Data: raceevents.StartEvent{Source: "cli", StartTime: start.Add(5 * time.Minute), Wave: "girls"}},
+		{ID: "4-0", Data: raceevents.VoidEvent{Source: "cli", EventID: "2-0"}},
+	}}
+
+	filter := NewFinishFilter(map[string]config.ReadFilter{"chip": {MinFinishSeconds: 600}}, meets.WaveLookup{1: "girls", 3: "boys"}, events, config.RankingPolicy{}, nil)
+
+	// 11 minutes after the first start but only 6 after the girls start
+	reason, err := filter.Check(context.TODO(), finishAt("chip", 1, start, 660))
+	assert.NoError(t, err)
+	assert.Equal(t, ReasonTooSoon, reason)
+
+	reason, err = filter.Check(context.TODO(), finishAt("chip", 2, start, 660))
+	assert.NoError(t, err)
+	assert.Equal(t, "", reason)
+
+	// no start for the wave yet
+	reason, err = filter.Check(context.TODO(), finishAt("chip", 3, start, 10))
+	assert.NoError(t, err)
+	assert.Equal(t, "", reason)
+}
+
+func TestFinishFilterStartUsesRankingAndOffsets(t *testing.T) {
+	start := time.Now().UTC()
+	events := &raceevents.MockEventStream{Events: []raceevents.Event{
+		{ID: "1-0", Data: raceevents.StartEvent{Source: "cli", StartTime: start}},
+		// the gun's clock is 2 minutes fast, it started the race a minute after the cli start
+		{ID: "2-0", Data: raceevents.StartEvent{Source: "gun", StartTime: start.Add(3 * time.Minute)}},
+		{ID: "3-0", Data: raceevents.StartEvent{Source: "manual", StartTime: start.Add(10 * time.Minute)}},
+	}}
+
+	filter := NewFinishFilter(map[string]config.ReadFilter{"chip": {MinFinishSeconds: 600}}, nil, events,
+		config.NewRankingPolicy(map[string]int{"gun": 1, "cli": 2, "manual": 3}), config.ClockOffsets{"gun": 120000})
+
+	// 10 minutes after the cli start but only 9 after the gun
+	reason, err := filter.Check(context.TODO(), finishAt("chip", 1, start, 600))
+	assert.NoError(t, err)
+	assert.Equal(t, ReasonTooSoon, reason)
+
+	reason, err = filter.Check(context.TODO(), finishAt("chip", 2, start, 660))
+	assert.NoError(t, err)
+	assert.Equal(t, "", reason)
+}
@@ -0,0 +1,77 @@
+package readfilter
+
+import (
+	"blreynolds4/event-race-timer/internal/config"
+	"blreynolds4/event-race-timer/internal/raceevents"
+	"blreynolds4/event-race-timer/internal/resultbuilder"
+	"context"
+	"time"
+)
+
+const eventBufferSize = 100
+
+// startWatcher keeps up with the start events in the race stream.
+// A wave's start is picked from the starts that aren't voided the same way the race result builder picks it.
+type startWatcher struct {
+	events  raceevents.EventStreamReader
+	ranking config.RankingPolicy
+	offsets config.ClockOffsets
+	lastId  string
+	starts  map[string][]raceevents.Event
+	voided  map[string]bool
+}
+
+func newStartWatcher(events raceevents.EventStreamReader, ranking config.RankingPolicy, offsets config.ClockOffsets) *startWatcher {
+	return &startWatcher{
+		events:  events,
+		ranking: ranking,
+		offsets: offsets,
+		starts:  make(map[string][]raceevents.Event),
+		voided:  make(map[string]bool),
+	}
+}
+
+func (sw *startWatcher) startTime(ctx context.Context, wave string) (time.Time, bool, error) {
+	err := sw.refresh(ctx)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	var start raceevents.StartEvent
+	started := false
+	for _, e := range sw.starts[wave] {
+		se := e.Data.(raceevents.StartEvent)
+		if sw.voided[e.ID] || sw.ranking.UnrankedHandling(config.StartField, se.Source) == config.UnrankedReject {
+			continue
+		}
+		start, _ = resultbuilder.NextStart(sw.ranking, sw.offsets, start, started, se)
+		started = true
+	}
+	return start.StartTime, started, nil
+}
+
+// refresh reads the events sent since the last refresh
+func (sw *startWatcher) refresh(ctx context.Context) error {
+	buffer := make([]raceevents.Event, eventBufferSize)
+	for {
+		startId := sw.events.RangeQueryMin()
+		if sw.lastId != "" {
+			startId = sw.events.ExclusiveQueryStart(sw.lastId)
+		}
+
+		count, err := sw.events.GetRaceEventRange(ctx, startId, sw.events.RangeQueryMax(), buffer)
+		if err != nil || count == 0 {
+			return err
+		}
+
+		for _, e := range buffer[:count] {
+			switch data := e.Data.(type) {
+			case raceevents.StartEvent:
+				sw.starts[data.Wave] = append(sw.starts[data.Wave], e)
+			case raceevents.VoidEvent:
+				sw.voided[data.EventID] = true
+			}
+		}
+		sw.lastId = buffer[count-1].ID
+	}
+}
@@ -0,0 +1,300 @@
+package redis_stream
+
+import (
+	"blreynolds4/event-race-timer/internal/stream"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	redis "github.com/redis/go-redis/v9"
+)
+
+const (
+	// read the whole stream the first time a group is used
+	groupStartId = "0"
+	// XREADGROUP id for messages never delivered to the group
+	newMessagesId = ">"
+	// how many pending entries to ask for at once
+	pendingPageSize = 100
+)
+
+// RedisGroupStream reads a redis stream with XREADGROUP as one consumer of a group.
+// On first use it delivers this consumer's pending (read but not acknowledged)
+// messages before new ones, so a restarted consumer resumes where it left off.
+type RedisGroupStream struct {
+	*RedisStream
+	group        string
+	consumer     string
+	groupCreated bool
+	// pending messages of other consumers idle this long are claimed on startup, 0 turns that off
+	claimIdle time.Duration
+	claimed   bool
+	// while true, GetMessage is re-reading this consumer's pending messages
+	readingPending bool
+	lastPendingId  string
+}
+
+func NewRedisGroupStream(c *redis.Client, name, group, consumer string, claimIdle time.Duration) *RedisGroupStream {
+	return &RedisGroupStream{
+		RedisStream:    NewRedisStream(c, name),
+		group:          group,
+		consumer:       consumer,
+		claimIdle:      claimIdle,
+		readingPending: true,
+		lastPendingId:  groupStartId,
+	}
+}
+
+func (rgs *RedisGroupStream) createGroup(ctx context.Context) error {
+	if rgs.groupCreated {
+		return nil
+	}
+
+	err := rgs.client.XGroupCreateMkStream(ctx, rgs.stream, rgs.group, groupStartId).Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return err
+	}
+
+	rgs.groupCreated = true
+	return nil
+}
+
+func (rgs *RedisGroupStream) GetMessage(ctx context.Context, timeout time.Duration, resultMsg *stream.Message) (bool, error) {
+	return getUntilCancelled(ctx, timeout, resultMsg, rgs.getMessage)
+}
+
+func (rgs *RedisGroupStream) getMessage(ctx context.Context, timeout time.Duration, resultMsg *stream.Message) (bool, error) {
+	err := rgs.createGroup(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	for rgs.readingPending {
+		// pending messages are returned right away, there is nothing to block for
+		data, err := rgs.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    rgs.group,
+			Consumer: rgs.consumer,
+			Streams:  []string{rgs.stream, rgs.lastPendingId},
+			Count:    1,
+		}).Result()
+		if err != nil && err != redis.Nil {
+			return false, err
+		}
+
+		if err == redis.Nil || len(data) == 0 || len(data[0].Messages) == 0 {
+			if rgs.claimIdle > 0 && !rgs.claimed {
+				// take over stalled messages from other consumers and read them as our own pending messages
+				rgs.claimed = true
+				claimedCount, err := rgs.claimStalled(ctx)
+				if err != nil {
+					return false, err
+				}
+				if claimedCount > 0 {
+					rgs.lastPendingId = groupStartId
+					continue
+				}
+			}
+
+			// caught up, switch to new messages
+			rgs.readingPending = false
+			break
+		}
+
+		redisMsg := data[0].Messages[0]
+		rgs.lastPendingId = redisMsg.ID
+		if redisMsg.Values == nil {
+			// the message was deleted from the stream, nothing to process
+			err = rgs.AckMessage(ctx, redisMsg.ID)
+			if err != nil {
+				return false, err
+			}
+			continue
+		}
+
+		return rgs.setResult(redisMsg, resultMsg)
+	}
+
+	data, err := rgs.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    rgs.group,
+		Consumer: rgs.consumer,
+		Streams:  []string{rgs.stream, newMessagesId},
+		Count:    1,
+		Block:    timeout,
+	}).Result()
+	if err != nil && err != redis.Nil {
+		return false, err
+	}
+
+	if err != redis.Nil && len(data) > 0 && len(data[0].Messages) > 0 {
+		return rgs.setResult(data[0].Messages[0], resultMsg)
+	}
+
+	return false, nil
+}
+
+// SeekAfter isn't supported, a group always reads from the group's position
+func (rgs *RedisGroupStream) SeekAfter(id string) error {
+	return fmt.Errorf("group %s reads from the group position, it can't seek", rgs.group)
+}
+
+func (rgs *RedisGroupStream) setResult(redisMsg redis.XMessage, resultMsg *stream.Message) (bool, error) {
+	data, err := rgs.decodeMessageData(redisMsg.Values[dataKey])
+	if err != nil {
+		return false, err
+	}
+
+	resultMsg.ID = redisMsg.ID
+	resultMsg.Data = data
+	return true, nil
+}
+
+func (rgs *RedisGroupStream) AckMessage(ctx context.Context, id string) error {
+	return rgs.client.XAck(ctx, rgs.stream, rgs.group, id).Err()
+}
+
+// ClaimMessages takes over messages another consumer in the group read but
+// hasn't acknowledged for at least minIdle.  The claimed messages are returned
+// and need to be acknowledged once they're processed.
+func (rgs *RedisGroupStream) ClaimMessages(ctx context.Context, minIdle time.Duration, msgs []stream.Message) (int, error) {
+	err := rgs.createGroup(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	pending, err := rgs.client.XPendingExt(ctx, &redis.XPendingExtArgs{
+		Stream: rgs.stream,
+		Group:  rgs.group,
+		Idle:   minIdle,
+		Start:  rgs.RangeQueryMin(),
+		End:    rgs.RangeQueryMax(),
+		Count:  int64(len(msgs)),
+	}).Result()
+	if err != nil && err != redis.Nil {
+		return 0, err
+	}
+
+	ids := make([]string, 0, len(pending))
+	for _, p := range pending {
+		if p.Consumer != rgs.consumer {
+			ids = append(ids, p.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	claimed, err := rgs.client.XClaim(ctx, &redis.XClaimArgs{
+		Stream:   rgs.stream,
+		Group:    rgs.group,
+		Consumer: rgs.consumer,
+		MinIdle:  minIdle,
+		Messages: ids,
+	}).Result()
+	if err != nil && err != redis.Nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, redisMsg := range claimed {
+		_, err := rgs.setResult(redisMsg, &msgs[count])
+		if err != nil {
+			return 0, err
+		}
+		count++
+	}
+
+	return count, nil
+}
+
+// claimStalled moves every message idle longer than claimIdle to this consumer
+func (rgs *RedisGroupStream) claimStalled(ctx context.Context) (int, error) {
+	total := 0
+	start := rgs.RangeQueryMin()
+	for {
+		pending, err := rgs.client.XPendingExt(ctx, &redis.XPendingExtArgs{
+			Stream: rgs.stream,
+			Group:  rgs.group,
+			Idle:   rgs.claimIdle,
+			Start:  start,
+			End:    rgs.RangeQueryMax(),
+			Count:  pendingPageSize,
+		}).Result()
+		if err != nil && err != redis.Nil {
+			return total, err
+		}
+
+		ids := make([]string, 0, len(pending))
+		for _, p := range pending {
+			if p.Consumer != rgs.consumer {
+				ids = append(ids, p.ID)
+			}
+		}
+
+		if len(ids) > 0 {
+			claimedIds, err := rgs.client.XClaimJustID(ctx, &redis.XClaimArgs{
+				Stream:   rgs.stream,
+				Group:    rgs.group,
+				Consumer: rgs.consumer,
+				MinIdle:  rgs.claimIdle,
+				Messages: ids,
+			}).Result()
+			if err != nil && err != redis.Nil {
+				return total, err
+			}
+			total += len(claimedIds)
+		}
+
+		if len(pending) < pendingPageSize {
+			return total, nil
+		}
+		start = rgs.ExclusiveQueryStart(pending[len(pending)-1].ID)
+	}
+}
+
+func (rgs *RedisGroupStream) GetGroupPosition(ctx context.Context) (stream.GroupPosition, error) {
+	position := stream.GroupPosition{
+		LastDeliveredID: groupStartId,
+		Pending:         make(map[string]bool),
+	}
+
+	err := rgs.createGroup(ctx)
+	if err != nil {
+		return position, err
+	}
+
+	groups, err := rgs.client.XInfoGroups(ctx, rgs.stream).Result()
+	if err != nil {
+		return position, err
+	}
+	for _, g := range groups {
+		if g.Name == rgs.group {
+			position.LastDeliveredID = g.LastDeliveredID
+		}
+	}
+
+	start := rgs.RangeQueryMin()
+	for {
+		pending, err := rgs.client.XPendingExt(ctx, &redis.XPendingExtArgs{
+			Stream: rgs.stream,
+			Group:  rgs.group,
+			Start:  start,
+			End:    rgs.RangeQueryMax(),
+			Count:  pendingPageSize,
+		}).Result()
+		if err != nil && err != redis.Nil {
+			return position, err
+		}
+
+		for _, p := range pending {
+			position.Pending[p.ID] = true
+		}
+
+		if len(pending) < pendingPageSize {
+			break
+		}
+		start = rgs.ExclusiveQueryStart(pending[len(pending)-1].ID)
+	}
+
+	return position, nil
+}
@@ -0,0 +1,197 @@
+package redis_stream
+
+import (
+	"blreynolds4/event-race-timer/internal/stream"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redismock/v9"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func groupReadArgs(id string, timeout time.Duration) *redis.XReadGroupArgs {
+	return &redis.XReadGroupArgs{
+		Group:    "group",
+		Consumer: "consumer",
+		Streams:  []string{"stream", id},
+		Count:    1,
+		Block:    timeout,
+	}
+}
+
+func groupReadResult(id string, data any) []redis.XStream {
+	msg := redis.XMessage{ID: id}
+	if data != nil {
+		msg.Values = map[string]interface{}{dataKey: data}
+	}
+	return []redis.XStream{{Stream: "stream", Messages: []redis.XMessage{msg}}}
+}
+
+func TestGroupGetMessagePendingThenNew(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+
+	mock.ExpectXGroupCreateMkStream("stream", "group", groupStartId).SetErr(fmt.Errorf("BUSYGROUP Consumer Group name already exists"))
+	mock.ExpectXReadGroup(groupReadArgs(groupStartId, 0)).SetVal(groupReadResult("1-0", "pending"))
+	mock.ExpectXReadGroup(groupReadArgs("1-0", 0)).SetVal(groupReadResult("2-0", nil))
+	mock.ExpectXAck("stream", "group", "2-0").SetVal(1)
+	mock.ExpectXReadGroup(groupReadArgs("2-0", 0)).SetVal([]redis.XStream{{Stream: "stream"}})
+	mock.ExpectXReadGroup(groupReadArgs(newMessagesId, time.Second)).SetVal(groupReadResult("3-0", "new"))
+
+	rgs := NewRedisGroupStream(db, "stream", "group", "consumer", 0)
+
+	var msg stream.Message
+	gotMsg, err := rgs.GetMessage(context.TODO(), time.Second, &msg)
+	assert.NoError(t, err)
+	assert.True(t, gotMsg)
+	assert.Equal(t, stream.Message{ID: "1-0", Data: []byte("pending")}, msg)
+
+	// the deleted pending message is acked and skipped
+	gotMsg, err = rgs.GetMessage(context.TODO(), time.Second, &msg)
+	assert.NoError(t, err)
+	assert.True(t, gotMsg)
+	assert.Equal(t, stream.Message{ID: "3-0", Data: []byte("new")}, msg)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGroupGetMessageNoMessages(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+
+	mock.ExpectXGroupCreateMkStream("stream", "group", groupStartId).SetVal("OK")
+	mock.ExpectXReadGroup(groupReadArgs(groupStartId, 0)).RedisNil()
+	mock.ExpectXReadGroup(groupReadArgs(newMessagesId, time.Second)).RedisNil()
+
+	rgs := NewRedisGroupStream(db, "stream", "group", "consumer", 0)
+
+	var msg stream.Message
+	gotMsg, err := rgs.GetMessage(context.TODO(), time.Second, &msg)
+	assert.NoError(t, err)
+	assert.False(t, gotMsg)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGroupCreateFails(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+
+	expErr := fmt.Errorf("FAIL")
+	mock.ExpectXGroupCreateMkStream("stream", "group", groupStartId).SetErr(expErr)
+
+	rgs := NewRedisGroupStream(db, "stream", "group", "consumer", 0)
+
+	var msg stream.Message
+	gotMsg, err := rgs.GetMessage(context.TODO(), time.Second, &msg)
+	assert.Equal(t, expErr, err)
+	assert.False(t, gotMsg)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGroupGetMessageClaimsStalled(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+
+	mock.ExpectXGroupCreateMkStream("stream", "group", groupStartId).SetVal("OK")
+	mock.ExpectXReadGroup(groupReadArgs(groupStartId, 0)).RedisNil()
+	mock.ExpectXPendingExt(&redis.XPendingExtArgs{
+		Stream: "stream",
+		Group:  "group",
+		Idle:   time.Minute,
+		Start:  "-",
+		End:    "+",
+		Count:  pendingPageSize,
+	}).SetVal([]redis.XPendingExt{
+		{ID: "1-0", Consumer: "other"},
+		{ID: "2-0", Consumer: "consumer"},
+	})
+	mock.ExpectXClaimJustID(&redis.XClaimArgs{
+		Stream:   "stream",
+		Group:    "group",
+		Consumer: "consumer",
+		MinIdle:  time.Minute,
+		Messages: []string{"1-0"},
+	}).SetVal([]string{"1-0"})
+	mock.ExpectXReadGroup(groupReadArgs(groupStartId, 0)).SetVal(groupReadResult("1-0", "claimed"))
+
+	rgs := NewRedisGroupStream(db, "stream", "group", "consumer", time.Minute)
+
+	var msg stream.Message
+	gotMsg, err := rgs.GetMessage(context.TODO(), time.Second, &msg)
+	assert.NoError(t, err)
+	assert.True(t, gotMsg)
+	assert.Equal(t, stream.Message{ID: "1-0", Data: []byte("claimed")}, msg)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGroupAckMessage(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+
+	mock.ExpectXAck("stream", "group", "1-0").SetVal(1)
+
+	rgs := NewRedisGroupStream(db, "stream", "group", "consumer", 0)
+	assert.NoError(t, rgs.AckMessage(context.TODO(), "1-0"))
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGroupClaimMessages(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+
+	mock.ExpectXGroupCreateMkStream("stream", "group", groupStartId).SetVal("OK")
+	mock.ExpectXPendingExt(&redis.XPendingExtArgs{
+		Stream: "stream",
+		Group:  "group",
+		Idle:   time.Minute,
+		Start:  "-",
+		End:    "+",
+		Count:  2,
+	}).SetVal([]redis.XPendingExt{{ID: "1-0", Consumer: "other"}})
+	mock.ExpectXClaim(&redis.XClaimArgs{
+		Stream:   "stream",
+		Group:    "group",
+		Consumer: "consumer",
+		MinIdle:  time.Minute,
+		Messages: []string{"1-0"},
+	}).SetVal([]redis.XMessage{{ID: "1-0", Values: map[string]interface{}{dataKey: "claimed"}}})
+
+	rgs := NewRedisGroupStream(db, "stream", "group", "consumer", 0)
+
+	msgs := make([]stream.Message, 2)
+	count, err := rgs.ClaimMessages(context.TODO(), time.Minute, msgs)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, stream.Message{ID: "1-0", Data: []byte("claimed")}, msgs[0])
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGroupGetGroupPosition(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+
+	mock.ExpectXGroupCreateMkStream("stream", "group", groupStartId).SetVal("OK")
+	mock.ExpectXInfoGroups("stream").SetVal([]redis.XInfoGroup{
+		{Name: "other", LastDeliveredID: "9-0"},
+		{Name: "group", LastDeliveredID: "5-0"},
+	})
+	mock.ExpectXPendingExt(&redis.XPendingExtArgs{
+		Stream: "stream",
+		Group:  "group",
+		Start:  "-",
+		End:    "+",
+		Count:  pendingPageSize,
+	}).SetVal([]redis.XPendingExt{{ID: "4-0", Consumer: "consumer"}})
+
+	rgs := NewRedisGroupStream(db, "stream", "group", "consumer", 0)
+
+	position, err := rgs.GetGroupPosition(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, stream.GroupPosition{
+		LastDeliveredID: "5-0",
+		Pending:         map[string]bool{"4-0": true},
+	}, position)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -12,6 +12,29 @@ import (
 // dataKey is used to store message payloads in AddXArg Values map
 const dataKey = "data"
 
+// redis doesn't notice a cancelled context while it blocks, so waiting forever
+// blocks this long at a time and checks the context in between
+const waitSlice = time.Second
+
+type messageGetter func(ctx context.Context, timeout time.Duration, resultMsg *stream.Message) (bool, error)
+
+// getUntilCancelled reads with get, a timeout of 0 waits until a message comes or ctx is done
+func getUntilCancelled(ctx context.Context, timeout time.Duration, resultMsg *stream.Message, get messageGetter) (bool, error) {
+	if timeout != 0 {
+		return get(ctx, timeout, resultMsg)
+	}
+
+	for {
+		gotMsg, err := get(ctx, waitSlice, resultMsg)
+		if err != nil || gotMsg {
+			return gotMsg, err
+		}
+		if ctx.Err() != nil {
+			return false, ctx.Err()
+		}
+	}
+}
+
 type RedisStream struct {
 	client    *redis.Client
 	stream    string
@@ -44,6 +67,10 @@ func (rs *RedisStream) SendMessage(ctx context.Context, sm stream.Message) error
 }
 
 func (rs *RedisStream) GetMessage(ctx context.Context, timeout time.Duration, resultMsg *stream.Message) (bool, error) {
+	return getUntilCancelled(ctx, timeout, resultMsg, rs.getMessage)
+}
+
+func (rs *RedisStream) getMessage(ctx context.Context, timeout time.Duration, resultMsg *stream.Message) (bool, error) {
 	data, err := rs.client.XRead(ctx, &redis.XReadArgs{
 		Streams: []string{rs.stream, rs.lastMsgId},
 		//count is number of entries we want to read from redis
@@ -73,6 +100,15 @@ func (rs *RedisStream) GetMessage(ctx context.Context, timeout time.Duration, re
 	return false, nil
 }
 
+func (rs *RedisStream) SeekAfter(id string) error {
+	_, err := stream.ParseMessageID(id)
+	if err != nil {
+		return err
+	}
+	rs.lastMsgId = id
+	return nil
+}
+
 func (rs *RedisStream) decodeMessageData(data any) ([]byte, error) {
 	switch d := data.(type) {
 	case []byte:
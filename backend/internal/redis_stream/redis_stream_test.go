@@ -297,3 +297,52 @@ func TestGetMessageRangeError(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestGetMessageWaitsUntilMessage(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+
+	// waiting forever reads a slice at a time until a message comes
+	expectedArgs := &redis.XReadArgs{
+		Streams: []string{"stream", "0"},
+		Count:   1,
+		Block:   waitSlice,
+	}
+	mock.ExpectXRead(expectedArgs).RedisNil()
+	mock.ExpectXRead(expectedArgs).SetVal([]redis.XStream{
+		{
+			Stream: "stream",
+			Messages: []redis.XMessage{
+				{ID: "1-0", Values: map[string]interface{}{dataKey: []byte("hello")}},
+			},
+		}})
+
+	rs := NewRedisStream(db, "stream")
+
+	var msg stream.Message
+	gotMsg, err := rs.GetMessage(context.TODO(), 0, &msg)
+	assert.NoError(t, err)
+	assert.True(t, gotMsg)
+	assert.Equal(t, "1-0", msg.ID)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestGetMessageWaitStopsWhenCancelled(t *testing.T) {
+	db, mock := redismock.NewClientMock()
+	mock.ExpectXRead(&redis.XReadArgs{
+		Streams: []string{"stream", "0"},
+		Count:   1,
+		Block:   waitSlice,
+	}).RedisNil()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	rs := NewRedisStream(db, "stream")
+	var msg stream.Message
+	gotMsg, err := rs.GetMessage(ctx, 0, &msg)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.False(t, gotMsg)
+}
@@ -0,0 +1,144 @@
+package replay
+
+import (
+	"blreynolds4/event-race-timer/internal/competitors"
+	"blreynolds4/event-race-timer/internal/config"
+	"blreynolds4/event-race-timer/internal/meets"
+	"blreynolds4/event-race-timer/internal/memory_stream"
+	"blreynolds4/event-race-timer/internal/places"
+	"blreynolds4/event-race-timer/internal/raceevents"
+	"blreynolds4/event-race-timer/internal/resultbuilder"
+	"blreynolds4/event-race-timer/internal/stream"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+)
+
+const replayStreamName = "replay"
+
+// Race holds what a replay needs besides the events
+type Race struct {
+	Athletes competitors.CompetitorLookup
+	// bibs not in Waves start in the "" wave
+	Waves  meets.WaveLookup
+	Config config.RaceConfig
+}
+
+// endOfEventsStream never waits for new messages, so the placer and result
+// builder stop when they reach the end of the replayed events
+type endOfEventsStream struct {
+	*memory_stream.MemoryStream
+}
+
+func (es endOfEventsStream) GetMessage(ctx context.Context, timeout time.Duration, resultMsg *stream.Message) (bool, error) {
+	return es.MemoryStream.GetMessage(ctx, -1, resultMsg)
+}
+
+// latestResults keeps the last result saved for each bib
+type latestResults map[int]meets.RaceResult
+
+func (lr latestResults) SaveResult(rr *meets.RaceResult) (*meets.RaceResult, error) {
+	lr[rr.Bib] = *rr
+	return rr, nil
+}
+
+func (lr latestResults) ClearResult(bib int) error {
+	delete(lr, bib)
+	return nil
+}
+
+func (lr latestResults) Close() error {
+	return nil
+}
+
+// Replay runs race events through the placer and the result builder in memory and
+// returns the final results, placed results in place order then the rest by bib.
+// Places sent by the placer when the race was timed are dropped so the places come
+// from this run, manual places and every other event are replayed as they were.
+// Events keep their ids so voids still match the events they void.
+// A cancelled ctx stops the replay with ctx's error.
+func Replay(ctx context.Context, events []raceevents.Event, race Race, l *slog.Logger) ([]meets.RaceResult, error) {
+	store := memory_stream.NewStore()
+	err := sendEvents(ctx, memory_stream.NewMemoryStream(store, replayStreamName), events)
+	if err != nil {
+		return nil, err
+	}
+
+	ranking := race.Config.RankingPolicy()
+
+	placerStream := raceevents.NewEventStream(endOfEventsStream{memory_stream.NewMemoryStream(store, replayStreamName)})
+	err = places.NewPlaceGenerator(placerStream, race.Config.ClockOffsets, l).GeneratePlaces(ctx, race.Athletes, ranking)
+	if err == nil {
+		err = ctx.Err()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(latestResults)
+	builderStream := raceevents.NewEventStream(endOfEventsStream{memory_stream.NewMemoryStream(store, replayStreamName)})
+	builder := resultbuilder.NewRaceResultBuilder(l, nil, race.Waves, race.Config.ClockOffsets)
+	err = builder.BuildRaceResults(ctx, builderStream, athleteLookup(race.Athletes), ranking, results)
+	if err == nil {
+		err = ctx.Err()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return sortResults(results), nil
+}
+
+func sendEvents(ctx context.Context, s stream.Writer, events []raceevents.Event) error {
+	for _, e := range events {
+		pe, isPlace := e.Data.(raceevents.PlaceEvent)
+		if isPlace && pe.Source == places.SourceName {
+			continue
+		}
+
+		data, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		err = s.SendMessage(ctx, stream.Message{ID: e.ID, Data: data})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// athleteLookup makes the result builder's athletes from competitors,
+// the last word of a competitor's name is their last name
+func athleteLookup(athletes competitors.CompetitorLookup) meets.AthleteLookup {
+	lookup := make(meets.AthleteLookup, len(athletes))
+	for bib, c := range athletes {
+		firstName, lastName := c.Name, ""
+		if i := strings.LastIndex(c.Name, " "); i >= 0 {
+			firstName, lastName = c.Name[:i], c.Name[i+1:]
+		}
+		lookup[bib] = meets.NewAthlete(firstName, lastName, c.Team, "", c.Grade, "")
+	}
+	return lookup
+}
+
+func sortResults(results latestResults) []meets.RaceResult {
+	sorted := make([]meets.RaceResult, 0, len(results))
+	for _, rr := range results {
+		sorted = append(sorted, rr)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		pi, pj := sorted[i].Place, sorted[j].Place
+		if (pi > 0) != (pj > 0) {
+			return pi > 0
+		}
+		if pi != pj {
+			return pi < pj
+		}
+		return sorted[i].Bib < sorted[j].Bib
+	})
+	return sorted
+}
@@ -0,0 +1,69 @@
+package replay
+
+import (
+	"blreynolds4/event-race-timer/internal/competitors"
+	"blreynolds4/event-race-timer/internal/config"
+	"blreynolds4/event-race-timer/internal/places"
+	"blreynolds4/event-race-timer/internal/raceevents"
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testRace() Race {
+	return Race{
+		Athletes: competitors.CompetitorLookup{
+			10: competitors.NewCompetitor("Dana Reynolds", "WPI", 0, 12),
+			11: competitors.NewCompetitor("Mary Ann Smith", "WPI", 0, 11),
+			12: competitors.NewCompetitor("Sam", "MVHS", 0, 10),
+		},
+		Config: config.RaceConfig{SourceRanks: map[string]int{"manual": 1, "chip": 2}},
+	}
+}
+
+func TestReplay(t *testing.T) {
+	start := time.Date(2026, 10, 3, 10, 0, 0, 0, time.UTC)
+	events := []raceevents.Event{
+		{ID: "1-0", Data: raceevents.StartEvent{Source: "manual", StartTime: start}},
+		{ID: "2-0", Data: raceevents.FinishEvent{Source: "chip", Bib: 11, FinishTime: start.Add(17 * time.Minute)}},
+		{ID: "3-0", Data: raceevents.FinishEvent{Source: "chip", Bib: 10, FinishTime: start.Add(16 * time.Minute)}},
+		// a place from the live placer is dropped, the replay places bib 12 itself
+		{ID: "4-0", Data: raceevents.PlaceEvent{Source: places.SourceName, Bib: 12, Place: 1}},
+		{ID: "5-0", Data: raceevents.FinishEvent{Source: "chip", Bib: 12, FinishTime: start.Add(15 * time.Minute)}},
+		// bib 12's finish was a bad read
+		{ID: "6-0", Data: raceevents.VoidEvent{Source: "manual", EventID: "5-0"}},
+	}
+
+	results, err := Replay(context.TODO(), events, testRace(), slog.Default())
+	assert.NoError(t, err)
+
+	assert.Equal(t, 3, len(results))
+	assert.Equal(t, 10, results[0].Bib)
+	assert.Equal(t, 1, results[0].Place)
+	assert.Equal(t, 16*time.Minute, results[0].GunTime)
+	assert.Equal(t, "Reynolds", results[0].Athlete.LastName)
+	assert.Equal(t, 11, results[1].Bib)
+	assert.Equal(t, 2, results[1].Place)
+	assert.Equal(t, "Mary Ann", results[1].Athlete.FirstName)
+	assert.Equal(t, 12, results[2].Bib)
+	assert.Equal(t, 0, results[2].Place)
+}
+
+func TestReplayIsRepeatable(t *testing.T) {
+	start := time.Date(2026, 10, 3, 10, 0, 0, 0, time.UTC)
+	events := []raceevents.Event{
+		{ID: "1-0", Data: raceevents.StartEvent{Source: "manual", StartTime: start}},
+		{ID: "2-0", Data: raceevents.FinishEvent{Source: "chip", Bib: 10, FinishTime: start.Add(16 * time.Minute)}},
+		{ID: "3-0", Data: raceevents.FinishEvent{Source: "chip", Bib: 11, FinishTime: start.Add(16 * time.Minute)}},
+		{ID: "4-0", Data: raceevents.StatusEvent{Source: "manual", Bib: 12, Status: "DNS"}},
+	}
+
+	first, err := Replay(context.TODO(), events, testRace(), slog.Default())
+	assert.NoError(t, err)
+	second, err := Replay(context.TODO(), events, testRace(), slog.Default())
+	assert.NoError(t, err)
+	assert.Equal(t, first, second)
+}
@@ -0,0 +1,35 @@
+package replay
+
+import (
+	"blreynolds4/event-race-timer/internal/meets"
+	"fmt"
+	"io"
+	"time"
+)
+
+// WriteResults writes the replayed results in place order
+func WriteResults(w io.Writer, results []meets.RaceResult) {
+	fmt.Fprintln(w, "Place Bib   Name                      Team                 Time       Status")
+	fmt.Fprintln(w, "===== ===== ========================= ==================== ========== ======")
+	for _, rr := range results {
+		place := ""
+		if rr.Place > 0 {
+			place = fmt.Sprint(rr.Place)
+		}
+
+		name, team := "unknown", ""
+		if rr.Athlete != nil {
+			name = rr.Athlete.FirstName + " " + rr.Athlete.LastName
+			team = rr.Athlete.Team
+		}
+
+		fmt.Fprintf(w, "%-5s %-5d %-25s %-20s %-10s %s\n", place, rr.Bib, name, team, formatResultTime(rr.GunTime), rr.Status)
+	}
+}
+
+func formatResultTime(t time.Duration) string {
+	if t <= 0 {
+		return ""
+	}
+	return time.Unix(0, 0).UTC().Add(t).Format("15:04:05.0")
+}
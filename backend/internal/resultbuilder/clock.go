@@ -0,0 +1,26 @@
+package resultbuilder
+
+import (
+	"blreynolds4/event-race-timer/internal/config"
+	"blreynolds4/event-race-timer/internal/raceevents"
+)
+
+// adjustClock returns the event with its times on the reference clock, starts are moved by NextStart
+func adjustClock(offsets config.ClockOffsets, event raceevents.Event) raceevents.Event {
+	if len(offsets) == 0 {
+		return event
+	}
+
+	switch data := event.Data.(type) {
+	case raceevents.ChipStartEvent:
+		data.StartTime = offsets.Adjust(data.Source, data.StartTime)
+		event.Data = data
+	case raceevents.SplitEvent:
+		data.SplitTime = offsets.Adjust(data.Source, data.SplitTime)
+		event.Data = data
+	case raceevents.FinishEvent:
+		data.FinishTime = offsets.Adjust(data.Source, data.FinishTime)
+		event.Data = data
+	}
+	return event
+}
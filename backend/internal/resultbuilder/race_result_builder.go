@@ -0,0 +1,703 @@
+package resultbuilder
+
+import (
+	"blreynolds4/event-race-timer/internal/config"
+	"blreynolds4/event-race-timer/internal/meets"
+	"blreynolds4/event-race-timer/internal/raceevents"
+	"blreynolds4/event-race-timer/internal/stream"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sort"
+	"time"
+)
+
+type RaceResultBuilder interface {
+	BuildRaceResults(ctx context.Context, inputEvents raceevents.EventStream, athletes meets.AthleteLookup, ranking config.RankingPolicy, resultWriter meets.RaceResultWriter) error
+}
+
+// NewRaceResultBuilder creates the builder that folds race events into results and
+// saves every changed result to a sink, see sinks.go for the sinks it can write to.
+// It saves its progress to checkpoints after each event and resumes from the last
+// checkpoint.  Pass nil checkpoints to always build from the start of the stream.  Each athlete's time is from the start of
+// their wave in waves, pass nil waves when everyone starts together.
+// Event times are corrected by each source's clock offset.
+// Building stops without an error when ctx is cancelled, the results and checkpoint
+// for every event read before then are saved.
+func NewRaceResultBuilder(l *slog.Logger, checkpoints meets.CheckpointStore, waves meets.WaveLookup, offsets config.ClockOffsets) RaceResultBuilder {
+	return NewRefreshingRaceResultBuilder(l, checkpoints, waves, offsets, nil)
+}
+
+// NewRefreshingRaceResultBuilder reloads the athletes with refresh when an event has a bib
+// that isn't found, refresh refills waves and the lookup BuildRaceResults is given
+func NewRefreshingRaceResultBuilder(l *slog.Logger, checkpoints meets.CheckpointStore, waves meets.WaveLookup, offsets config.ClockOffsets, refresh *meets.AthleteRefresh) RaceResultBuilder {
+	return &raceResultBuilder{
+		logger:      l.With("app", "result-builder"),
+		checkpoints: checkpoints,
+		waves:       waves,
+		offsets:     offsets,
+		refresh:     refresh,
+	}
+}
+
+const (
+	// a checkpoint is saved once this many events are handled since the last one
+	checkpointEvents = 50
+	// or once this long has passed, the events since the last checkpoint are handled again after a crash
+	checkpointInterval = 5 * time.Second
+)
+
+type raceResultBuilder struct {
+	logger              *slog.Logger
+	waves               meets.WaveLookup
+	offsets             config.ClockOffsets
+	refresh             *meets.AthleteRefresh
+	starts              map[string]raceevents.StartEvent // the first start of each wave
+	resultCache         map[int]*meets.RaceResult        //map of race results, bib number is key
+	pendingFinishEvents map[int]raceevents.FinishEvent
+	finishTimes         map[int]time.Time            // the finish time used for each result
+	chipStarts          map[int]time.Time            // start mat reads for net times
+	splitTimes          map[int]map[string]time.Time // first read at each timing point
+	// every event handled so far, a void rebuilds the results from these without the voided event
+	history []raceevents.Event
+	// the history before this is in the checkpoint store
+	savedHistory int
+	voided       map[string]bool
+	checkpoints  meets.CheckpointStore
+	// events up to this id were handled before the last checkpoint
+	resumeAfter stream.MessageID
+	// the last event handled and how many were handled since the last checkpoint was saved
+	uncheckpointedID     string
+	uncheckpointedEvents int
+	checkpointed         time.Time
+}
+
+// builderState is the part of the builder saved with a checkpoint, the history is saved
+// an event at a time with the checkpoints
+type builderState struct {
+	Starts              map[string]raceevents.StartEvent
+	Results             []meets.RaceResult
+	PendingFinishEvents map[int]raceevents.FinishEvent
+	FinishTimes         map[int]time.Time
+	ChipStarts          map[int]time.Time
+	SplitTimes          map[int]map[string]time.Time
+	Voided              []string
+}
+
+// discardResultWriter drops results, it's used while replaying events whose results are already saved
+type discardResultWriter struct{}
+
+func (discardResultWriter) SaveResult(rr *meets.RaceResult) (*meets.RaceResult, error) {
+	return rr, nil
+}
+
+func (discardResultWriter) ClearResult(bib int) error {
+	return nil
+}
+
+func (discardResultWriter) Close() error {
+	return nil
+}
+
+// getStartTime returns the start of the bib's wave if that wave has started
+func (rb *raceResultBuilder) getStartTime(bib int) (raceevents.StartEvent, bool) {
+	se, started := rb.starts[rb.waves[bib]]
+	return se, started
+}
+
+// setTimes sets the gun and net times for a finish.  The net time starts at the
+// athlete's start mat read unless there isn't one or it was before the gun.
+func (rb *raceResultBuilder) setTimes(result *meets.RaceResult, finishTime time.Time, start raceevents.StartEvent) {
+	result.GunTime = finishTime.Sub(start.StartTime)
+
+	netStart := start.StartTime
+	if chipStart, found := rb.chipStarts[result.Bib]; found && chipStart.After(netStart) {
+		netStart = chipStart
+	}
+	result.NetTime = finishTime.Sub(netStart)
+}
+
+// setSplits sets the result's splits from the start of the athlete's wave
+func (rb *raceResultBuilder) setSplits(result *meets.RaceResult, start raceevents.StartEvent) {
+	splits, found := rb.splitTimes[result.Bib]
+	if !found {
+		return
+	}
+
+	// a new map so results saved earlier keep the splits they had
+	result.Splits = make(map[string]time.Duration, len(splits))
+	for name, splitTime := range splits {
+		result.Splits[name] = splitTime.Sub(start.StartTime)
+	}
+}
+
+func (rb *raceResultBuilder) BuildRaceResults(ctx context.Context,
+	inputEvents raceevents.EventStream,
+	athletes meets.AthleteLookup,
+	ranking config.RankingPolicy,
+	resultWriter meets.RaceResultWriter) (err error) {
+
+	rb.starts = make(map[string]raceevents.StartEvent)
+	rb.resultCache = make(map[int]*meets.RaceResult)
+	rb.pendingFinishEvents = make(map[int]raceevents.FinishEvent)
+	rb.finishTimes = make(map[int]time.Time)
+	rb.chipStarts = make(map[int]time.Time)
+	rb.splitTimes = make(map[int]map[string]time.Time)
+	rb.history = make([]raceevents.Event, 0)
+	rb.savedHistory = 0
+	rb.voided = make(map[string]bool)
+	rb.resumeAfter = stream.MessageID{}
+	rb.uncheckpointedID = ""
+	rb.uncheckpointedEvents = 0
+	rb.checkpointed = time.Now()
+
+	// in a consumer group the results for acknowledged events were saved before a restart,
+	// replay those events to rebuild the cache without saving the results again
+	groupEvents, isGroup := inputEvents.(raceevents.EventGroupReader)
+	if isGroup {
+		err := raceevents.ReplayAcknowledged(ctx, groupEvents, func(e raceevents.Event) error {
+			return rb.processEvent(e, athletes, ranking, discardResultWriter{})
+		})
+		if err != nil {
+			return stopped(ctx, err)
+		}
+	} else if rb.checkpoints != nil {
+		// a group already resumes from its own position, plain streams resume from the checkpoint
+		err := rb.restoreCheckpoint(inputEvents, athletes)
+		if err != nil {
+			return err
+		}
+
+		// the events handled since the last checkpoint are saved however the builder stops
+		defer func() {
+			checkpointErr := rb.flushCheckpoint()
+			if checkpointErr != nil {
+				rb.logger.Error("error saving checkpoint", "eventId", rb.uncheckpointedID, "error", checkpointErr)
+				if err == nil {
+					err = checkpointErr
+				}
+			}
+		}()
+	}
+
+	// an event that was read is always finished, saving it isn't cancelled
+	saveCtx := context.WithoutCancel(ctx)
+
+	var event raceevents.Event
+	gotEvent, err := inputEvents.GetRaceEvent(ctx, 0, &event)
+	if err != nil {
+		return stopped(ctx, err)
+	}
+	rb.logger.Info("GotEvent ", "event", gotEvent)
+
+	for gotEvent {
+		if !rb.handledBeforeCheckpoint(event.ID) {
+			// a result that wasn't saved stops the builder before the event is acknowledged or
+			// checkpointed, restarting handles the event again
+			err = rb.processEvent(event, athletes, ranking, resultWriter)
+			if err != nil {
+				rb.logger.Error("error saving results, stopping before event", "eventId", event.ID, "error", err)
+				return err
+			}
+
+			if isGroup {
+				err = groupEvents.AckRaceEvent(saveCtx, event.ID)
+			} else if rb.checkpoints != nil {
+				err = rb.checkpoint(event.ID)
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		gotEvent, err = inputEvents.GetRaceEvent(ctx, 0, &event)
+		if err != nil {
+			return stopped(ctx, err)
+		}
+	}
+
+	return nil
+}
+
+// NextStart returns a wave's start once se is sent, current is the wave's start when started is true.
+// se is moved onto the reference clock and only replaces current when it's from a better source,
+// replaced is false when the wave's start didn't change.  The finish filter picks starts the same way.
+func NextStart(ranking config.RankingPolicy, offsets config.ClockOffsets, current raceevents.StartEvent, started bool, se raceevents.StartEvent) (start raceevents.StartEvent, replaced bool) {
+	se.StartTime = offsets.Adjust(se.Source, se.StartTime)
+	if started && !replacesStart(ranking, se, current) {
+		return current, false
+	}
+	return se, true
+}
+
+// replacesStart is true when a wave's start is replaced by se, the first start stays unless
+// se is from a better source
+func replacesStart(ranking config.RankingPolicy, se raceevents.StartEvent, current raceevents.StartEvent) bool {
+	return ranking.Compare(config.StartField, se.Source, current.Source) < 0
+}
+
+// knownBib is true for an athlete's bib, the athletes are reloaded for a bib that isn't found
+func (rb *raceResultBuilder) knownBib(athletes meets.AthleteLookup, bib int) bool {
+	_, found := athletes[bib]
+	if !found && rb.refresh.Refresh() {
+		_, found = athletes[bib]
+	}
+	return found
+}
+
+// stopped returns nil for a read that failed because ctx was cancelled, that's a shutdown
+func stopped(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
+
+// restoreCheckpoint loads the builder state from the last checkpoint and moves
+// the input past the events it covers
+func (rb *raceResultBuilder) restoreCheckpoint(inputEvents raceevents.EventStream, athletes meets.AthleteLookup) error {
+	cp, err := rb.checkpoints.LoadCheckpoint()
+	if err != nil || cp == nil {
+		return err
+	}
+
+	var state builderState
+	err = json.Unmarshal(cp.State, &state)
+	if err != nil {
+		return err
+	}
+
+	rb.resumeAfter, err = stream.ParseMessageID(cp.LastEventID)
+	if err != nil {
+		return err
+	}
+
+	for wave, se := range state.Starts {
+		rb.starts[wave] = se
+	}
+	for _, data := range cp.History {
+		var e raceevents.Event
+		err = json.Unmarshal(data, &e)
+		if err != nil {
+			return err
+		}
+		rb.history = append(rb.history, e)
+	}
+	rb.savedHistory = len(rb.history)
+	for _, id := range state.Voided {
+		rb.voided[id] = true
+	}
+	for i := range state.Results {
+		result := state.Results[i]
+		// results only hold athletes still in the race
+		athlete, bibFound := athletes[result.Bib]
+		if !bibFound {
+			continue
+		}
+		result.Athlete = athlete
+		rb.resultCache[result.Bib] = &result
+	}
+	for bib, fe := range state.PendingFinishEvents {
+		if _, bibFound := rb.resultCache[bib]; bibFound {
+			rb.pendingFinishEvents[bib] = fe
+		}
+	}
+	for bib, finishTime := range state.FinishTimes {
+		rb.finishTimes[bib] = finishTime
+	}
+	for bib, startTime := range state.ChipStarts {
+		rb.chipStarts[bib] = startTime
+	}
+	for bib, splits := range state.SplitTimes {
+		rb.splitTimes[bib] = splits
+	}
+
+	// streams that can't seek are read from the start and the handled events skipped
+	if seeker, ok := inputEvents.(raceevents.EventSeeker); ok {
+		err = seeker.SeekAfterEvent(cp.LastEventID)
+		if err != nil {
+			rb.logger.Warn("stream can't seek to checkpoint, skipping handled events", "eventId", cp.LastEventID, "error", err)
+		}
+	}
+
+	rb.logger.Info("Resuming from checkpoint", "eventId", cp.LastEventID, "results", len(rb.resultCache))
+	return nil
+}
+
+func (rb *raceResultBuilder) handledBeforeCheckpoint(eventId string) bool {
+	if rb.resumeAfter.IsZero() {
+		return false
+	}
+
+	id, err := stream.ParseMessageID(eventId)
+	if err != nil {
+		return false
+	}
+	return !rb.resumeAfter.Less(id)
+}
+
+// checkpoint records that eventId was handled, a checkpoint is saved once enough events or time
+// have passed since the last one
+func (rb *raceResultBuilder) checkpoint(eventId string) error {
+	rb.uncheckpointedID = eventId
+	rb.uncheckpointedEvents++
+	if rb.uncheckpointedEvents < checkpointEvents && time.Since(rb.checkpointed) < checkpointInterval {
+		return nil
+	}
+	return rb.flushCheckpoint()
+}
+
+// flushCheckpoint saves a checkpoint for the events handled since the last one
+func (rb *raceResultBuilder) flushCheckpoint() error {
+	if rb.uncheckpointedEvents == 0 {
+		return nil
+	}
+	err := rb.saveCheckpoint(rb.uncheckpointedID)
+	if err != nil {
+		return err
+	}
+	rb.uncheckpointedEvents = 0
+	rb.checkpointed = time.Now()
+	return nil
+}
+
+func (rb *raceResultBuilder) saveCheckpoint(eventId string) error {
+	state := builderState{
+		Starts:              rb.starts,
+		Results:             make([]meets.RaceResult, 0, len(rb.resultCache)),
+		PendingFinishEvents: rb.pendingFinishEvents,
+		FinishTimes:         rb.finishTimes,
+		ChipStarts:          rb.chipStarts,
+		SplitTimes:          rb.splitTimes,
+		Voided:              make([]string, 0, len(rb.voided)),
+	}
+	for id := range rb.voided {
+		state.Voided = append(state.Voided, id)
+	}
+	sort.Strings(state.Voided)
+	for _, result := range rb.resultCache {
+		state.Results = append(state.Results, *result)
+	}
+	sort.Slice(state.Results, func(i, j int) bool {
+		return state.Results[i].Bib < state.Results[j].Bib
+	})
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	// only the events since the last checkpoint, the store has the rest
+	history := make([][]byte, 0, len(rb.history)-rb.savedHistory)
+	for _, e := range rb.history[rb.savedHistory:] {
+		eventData, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		history = append(history, eventData)
+	}
+
+	err = rb.checkpoints.SaveCheckpoint(meets.ResultCheckpoint{
+		LastEventID: eventId,
+		State:       data,
+		History:     history,
+	})
+	if err != nil {
+		return err
+	}
+	rb.savedHistory = len(rb.history)
+	return nil
+}
+
+// processEvent handles an event from the stream, voids rebuild the results.  The error is
+// from saving a result.
+func (rb *raceResultBuilder) processEvent(event raceevents.Event,
+	athletes meets.AthleteLookup,
+	ranking config.RankingPolicy,
+	resultWriter meets.RaceResultWriter) error {
+
+	if ve, isVoid := event.Data.(raceevents.VoidEvent); isVoid {
+		return rb.voidEvent(ve, athletes, ranking, resultWriter)
+	}
+	if rb.voided[event.ID] {
+		return nil
+	}
+
+	rb.history = append(rb.history, event)
+	return rb.handleEvent(event, athletes, ranking, resultWriter)
+}
+
+// voidEvent rebuilds the results as if the voided event never arrived and saves the ones that changed
+func (rb *raceResultBuilder) voidEvent(ve raceevents.VoidEvent,
+	athletes meets.AthleteLookup,
+	ranking config.RankingPolicy,
+	resultWriter meets.RaceResultWriter) error {
+
+	if rb.voided[ve.EventID] {
+		return nil
+	}
+	rb.voided[ve.EventID] = true
+	rb.logger.Info("Voiding event", "eventId", ve.EventID, "source", ve.Source, "reason", ve.Reason)
+	return rb.rebuild(athletes, ranking, resultWriter)
+}
+
+// rebuild folds the history that isn't voided into new results and saves the ones that changed.
+// Only a bib's latest status is used, a status that was cleared doesn't take the bib's place away.
+func (rb *raceResultBuilder) rebuild(athletes meets.AthleteLookup,
+	ranking config.RankingPolicy,
+	resultWriter meets.RaceResultWriter) error {
+
+	latestStatus := make(map[int]string)
+	for _, e := range rb.history {
+		if st, isStatus := e.Data.(raceevents.StatusEvent); isStatus && !rb.voided[e.ID] {
+			latestStatus[st.Bib] = e.ID
+		}
+	}
+
+	previous := make(map[int]meets.RaceResult, len(rb.resultCache))
+	for bib, result := range rb.resultCache {
+		previous[bib] = *result
+	}
+
+	rb.starts = make(map[string]raceevents.StartEvent)
+	rb.resultCache = make(map[int]*meets.RaceResult)
+	rb.pendingFinishEvents = make(map[int]raceevents.FinishEvent)
+	rb.finishTimes = make(map[int]time.Time)
+	rb.chipStarts = make(map[int]time.Time)
+	rb.splitTimes = make(map[int]map[string]time.Time)
+	for _, e := range rb.history {
+		if st, isStatus := e.Data.(raceevents.StatusEvent); isStatus && latestStatus[st.Bib] != e.ID {
+			continue
+		}
+		if !rb.voided[e.ID] {
+			// results aren't saved while rebuilding, there's nothing to fail
+			_ = rb.handleEvent(e, athletes, ranking, discardResultWriter{})
+		}
+	}
+
+	bibs := make([]int, 0, len(previous))
+	for bib := range previous {
+		bibs = append(bibs, bib)
+	}
+	sort.Ints(bibs)
+	for _, bib := range bibs {
+		result, exists := rb.resultCache[bib]
+		if !exists {
+			// nothing is left for the bib, its saved result is removed
+			err := resultWriter.ClearResult(bib)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		if result.Equal(previous[bib]) {
+			continue
+		}
+		if result.Athlete == nil {
+			rb.logger.Warn("skipping result without an athlete", "bib", bib)
+			continue
+		}
+		_, err := resultWriter.SaveResult(result)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// handleEvent folds the event into the results and saves the ones that changed, the error
+// is from saving a result
+func (rb *raceResultBuilder) handleEvent(event raceevents.Event,
+	athletes meets.AthleteLookup,
+	ranking config.RankingPolicy,
+	resultWriter meets.RaceResultWriter) error {
+
+	event = adjustClock(rb.offsets, event)
+	switch event.Data.(type) {
+	case raceevents.StartEvent:
+		se := event.Data.(raceevents.StartEvent)
+		if !acceptSource(ranking, config.StartField, se.Source, rb.logger) {
+			break
+		}
+		current, started := rb.starts[se.Wave]
+		start, replaced := NextStart(ranking, rb.offsets, current, started, se)
+		if !replaced {
+			// a wrong start is corrected by voiding it
+			rb.logger.Info("skipping second start for wave", "wave", se.Wave, "source", se.Source)
+			break
+		}
+		// the wave's first start, or a start from a better source
+		se = start
+		rb.starts[se.Wave] = se
+
+		// time the wave's finishes and splits from the start
+		waveBibs := make([]int, 0, len(rb.finishTimes)+len(rb.splitTimes))
+		for bib := range rb.finishTimes {
+			if rb.waves[bib] == se.Wave {
+				waveBibs = append(waveBibs, bib)
+			}
+		}
+		for bib := range rb.splitTimes {
+			_, finished := rb.finishTimes[bib]
+			if rb.waves[bib] == se.Wave && !finished {
+				waveBibs = append(waveBibs, bib)
+			}
+		}
+		sort.Ints(waveBibs)
+		for _, bib := range waveBibs {
+			if pendingFinish, found := rb.pendingFinishEvents[bib]; found {
+				rb.resultCache[bib].FinishSource = pendingFinish.Source
+				delete(rb.pendingFinishEvents, bib)
+			}
+			if finishTime, finished := rb.finishTimes[bib]; finished {
+				rb.setTimes(rb.resultCache[bib], finishTime, se)
+			}
+			rb.setSplits(rb.resultCache[bib], se)
+
+			_, err := resultWriter.SaveResult(rb.resultCache[bib])
+			if err != nil {
+				return err
+			}
+		}
+
+	case raceevents.ChipStartEvent:
+		cse := event.Data.(raceevents.ChipStartEvent)
+		if rb.knownBib(athletes, cse.Bib) {
+			// the last time over the mat is the real start, earlier reads are warm ups
+			rb.chipStarts[cse.Bib] = cse.StartTime
+
+			// an athlete who already has a time gets a new net time
+			finishTime, finished := rb.finishTimes[cse.Bib]
+			startTime, started := rb.getStartTime(cse.Bib)
+			if finished && started {
+				rb.setTimes(rb.resultCache[cse.Bib], finishTime, startTime)
+				_, err := resultWriter.SaveResult(rb.resultCache[cse.Bib])
+				return err
+			}
+		} else {
+			rb.logger.Info("skipping unknown bib", "bib", cse.Bib)
+		}
+
+	case raceevents.SplitEvent:
+		sp := event.Data.(raceevents.SplitEvent)
+		if rb.knownBib(athletes, sp.Bib) {
+			splits := rb.splitTimes[sp.Bib]
+			if splits == nil {
+				splits = make(map[string]time.Time)
+				rb.splitTimes[sp.Bib] = splits
+			}
+			// readers see a runner more than once going over the mat, the first read is the split
+			if first, found := splits[sp.TimingPoint]; found && !sp.SplitTime.Before(first) {
+				break
+			}
+			splits[sp.TimingPoint] = sp.SplitTime
+
+			result := rb.resultCache[sp.Bib]
+			if result == nil {
+				result = new(meets.RaceResult)
+				result.Bib = sp.Bib
+				result.Athlete = athletes[sp.Bib]
+				rb.resultCache[sp.Bib] = result
+			}
+
+			if startTime, started := rb.getStartTime(sp.Bib); started {
+				rb.setSplits(result, startTime)
+				_, err := resultWriter.SaveResult(result)
+				return err
+			}
+		} else {
+			rb.logger.Info("skipping unknown bib", "bib", sp.Bib)
+		}
+
+	case raceevents.FinishEvent:
+		fe := event.Data.(raceevents.FinishEvent)
+
+		// only handle bibs for athletes that exist
+		if rb.knownBib(athletes, fe.Bib) && acceptSource(ranking, config.FinishField, fe.Source, rb.logger) {
+			result := rb.resultCache[fe.Bib]
+			if result == nil {
+				// the result doesn't exist in the cache
+				result = new(meets.RaceResult)
+				result.Bib = fe.Bib
+				result.Athlete = athletes[fe.Bib]
+				rb.logger.Info("New result created for bib", "bib", fe.Bib, "athlete", result.Athlete.DaID)
+				rb.resultCache[fe.Bib] = result
+			}
+
+			//if the ranking of the new event source is higher than the old create a new result
+			if ranking.Compare(config.FinishField, fe.Source, result.FinishSource) <= 0 {
+				result.FinishSource = fe.Source
+				rb.finishTimes[fe.Bib] = fe.FinishTime
+				if startTime, started := rb.getStartTime(fe.Bib); started {
+					rb.setTimes(result, fe.FinishTime, startTime)
+					rb.logger.Info("Result updated for bib", "bib", fe.Bib, "athlete", result.Athlete.LastName, "time", result.GunTime)
+					_, err := resultWriter.SaveResult(rb.resultCache[fe.Bib])
+					return err
+				} else {
+					// save the whole finish event so we have the time and the source
+					// information needed to build a result when a start time is available
+					rb.pendingFinishEvents[fe.Bib] = fe
+				}
+			}
+		}
+	case raceevents.PlaceEvent:
+		pe := event.Data.(raceevents.PlaceEvent)
+		if !acceptSource(ranking, config.PlaceField, pe.Source, rb.logger) {
+			break
+		}
+		if rb.knownBib(athletes, pe.Bib) {
+			// see if a result exists for this place
+			// get the result for the bib
+			bibResult := rb.resultCache[pe.Bib]
+			if bibResult == nil {
+				// this is a new result
+				bibResult = new(meets.RaceResult)
+				bibResult.Bib = pe.Bib
+				bibResult.Athlete = athletes[pe.Bib]
+				rb.resultCache[pe.Bib] = bibResult
+			}
+
+			if bibResult.HasStatus() {
+				// DNF, DNS and DQ runners don't get a place
+				rb.logger.Info("skipping place for bib with status", "bib", pe.Bib, "status", bibResult.Status)
+			} else if ranking.Compare(config.PlaceField, pe.Source, bibResult.PlaceSource) <= 0 {
+				bibResult.Place = pe.Place
+				bibResult.PlaceSource = pe.Source
+				_, err := resultWriter.SaveResult(rb.resultCache[pe.Bib])
+				return err
+			}
+		} else {
+			rb.logger.Info("skipping unknown bib", "bib", pe.Bib)
+		}
+	case raceevents.StatusEvent:
+		st := event.Data.(raceevents.StatusEvent)
+		if rb.knownBib(athletes, st.Bib) {
+			bibResult := rb.resultCache[st.Bib]
+			if bibResult == nil {
+				bibResult = new(meets.RaceResult)
+				bibResult.Bib = st.Bib
+				bibResult.Athlete = athletes[st.Bib]
+				rb.resultCache[st.Bib] = bibResult
+			}
+
+			if st.Status == "" && bibResult.HasStatus() {
+				// the place the status took away comes back from the history
+				rb.logger.Info("Status cleared for bib", "bib", st.Bib, "status", bibResult.Status, "reason", st.Reason, "official", st.Official)
+				return rb.rebuild(athletes, ranking, resultWriter)
+			}
+
+			bibResult.Status = st.Status
+			if bibResult.HasStatus() {
+				// the placer moves everyone behind this runner up a place
+				bibResult.Place = 0
+				bibResult.PlaceSource = ""
+			}
+			rb.logger.Info("Status updated for bib", "bib", st.Bib, "status", st.Status, "reason", st.Reason, "official", st.Official)
+			_, err := resultWriter.SaveResult(bibResult)
+			return err
+		} else {
+			rb.logger.Info("skipping unknown bib", "bib", st.Bib)
+		}
+	}
+	return nil
+}
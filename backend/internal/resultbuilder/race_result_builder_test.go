@@ -0,0 +1,1249 @@
+package resultbuilder
+
+import (
+	"blreynolds4/event-race-timer/internal/config"
+	"blreynolds4/event-race-timer/internal/meets"
+	"blreynolds4/event-race-timer/internal/raceevents"
+	"context"
+	"fmt"
+	"log/slog"
+
+	"blreynolds4/event-race-timer/internal/stream"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRaceResultBuilderNoStartEventNoResult(t *testing.T) {
+	// read events off a stream and return
+	// result events when they are complete
+	now := time.Now().UTC()
+	// Test data
+	finishTime10 := now.Add(5 * time.Minute)
+
+	// 3 events minimum to build a result:  start, finish and place
+	// if the builder doesn't get all 3 no result for the bib is produced
+	testEvents := []raceevents.Event{
+		{
+			ID:        "1",
+			EventTime: now,
+			Data: raceevents.FinishEvent{
+				Source:     t.Name(),
+				Bib:        10,
+				FinishTime: finishTime10,
+			},
+		},
+	}
+
+	mockInStream := &stream.MockStream{
+		Events: buildEventMessages(testEvents),
+	}
+	inputEvents := raceevents.NewEventStream(mockInStream)
+
+	athletes := make(meets.AthleteLookup)
+	athletes[10] = meets.NewAthlete("D", "R", "WPI", "DAID", 12, "m")
+
+	expectedResults := []meets.RaceResult{}
+
+	builder := NewRaceResultBuilder(slog.Default(), nil, nil, nil)
+	ranking := map[string]int{}
+	ranking[t.Name()] = 1
+
+	mockResults := meets.NewMockResultWriter()
+
+	err := builder.BuildRaceResults(context.TODO(), inputEvents, athletes, config.NewRankingPolicy(ranking), mockResults)
+	assert.NoError(t, err)
+
+	assert.Equal(t, len(expectedResults), len(mockResults.SavedResults))
+}
+
+func TestRaceResultBuilderFinishThenStartSaveTime(t *testing.T) {
+	// read events off a stream and return
+	// result events when they are complete
+	now := time.Now().UTC()
+	// Test data
+	expectedDurationFinishTime := (5 * time.Minute)
+	finishTime10 := now.Add(expectedDurationFinishTime)
+
+	// 3 events minimum to build a result:  start, finish and place
+	// if the builder doesn't get all 3 no result for the bib is produced
+	testEvents := []raceevents.Event{
+		{
+			ID:        "1",
+			EventTime: now,
+			Data: raceevents.FinishEvent{
+				Source:     t.Name(),
+				Bib:        10,
+				FinishTime: finishTime10,
+			},
+		},
+		{
+			ID:        "2",
+			EventTime: now,
+			Data: raceevents.StartEvent{
+				Source:    t.Name(),
+				StartTime: now,
+			},
+		},
+	}
+
+	mockInStream := &stream.MockStream{
+		Events: buildEventMessages(testEvents),
+	}
+	inputEvents := raceevents.NewEventStream(mockInStream)
+
+	athletes := make(meets.AthleteLookup)
+	athletes[10] = meets.NewAthlete("D", "R", "WPI", "DAID", 12, "m")
+
+	expectedResults := []meets.RaceResult{
+		{
+			Bib:          10,
+			Athlete:      athletes[10],
+			Place:        0,
+			GunTime:      expectedDurationFinishTime,
+			NetTime:      expectedDurationFinishTime,
+			FinishSource: t.Name(),
+		},
+	}
+
+	builder := NewRaceResultBuilder(slog.Default(), nil, nil, nil)
+	ranking := map[string]int{}
+	ranking[t.Name()] = 1
+
+	mockResults := meets.NewMockResultWriter()
+
+	err := builder.BuildRaceResults(context.TODO(), inputEvents, athletes, config.NewRankingPolicy(ranking), mockResults)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 1, len(mockResults.SavedResults))
+	assert.Equal(t, expectedResults[0], mockResults.SavedResults[0])
+}
+
+func TestRaceResultBuilderMuiltpleStartsAlwaysSaveResultFromFirstStart(t *testing.T) {
+	// read events off a stream and return
+	// result events when they are complete
+	now := time.Now().UTC()
+	// Test data
+	expectedDurationFinishTime := (5 * time.Minute)
+	finishTime10 := now.Add(expectedDurationFinishTime)
+	startTimeUpdate := now.Add(-expectedDurationFinishTime)
+
+	// 3 events minimum to build a result:  start, finish and place
+	// if the builder doesn't get all 3 no result for the bib is produced
+	testEvents := []raceevents.Event{
+		{
+			ID:        "1",
+			EventTime: now,
+			Data: raceevents.StartEvent{
+				Source:    t.Name(),
+				StartTime: now,
+			},
+		},
+		{
+			ID:        "2",
+			EventTime: now,
+			Data: raceevents.FinishEvent{
+				Source:     t.Name(),
+				Bib:        10,
+				FinishTime: finishTime10,
+			},
+		},
+		{
+			ID:        "3",
+			EventTime: now,
+			Data: raceevents.StartEvent{
+				Source:    t.Name(),
+				StartTime: startTimeUpdate,
+			},
+		},
+	}
+
+	mockInStream := &stream.MockStream{
+		Events: buildEventMessages(testEvents),
+	}
+	inputEvents := raceevents.NewEventStream(mockInStream)
+
+	athletes := make(meets.AthleteLookup)
+	athletes[10] = meets.NewAthlete("D", "R", "WPI", "DAID", 12, "m")
+
+	expectedResults := []meets.RaceResult{
+		{
+			Bib:          10,
+			Athlete:      athletes[10],
+			Place:        0,
+			GunTime:      expectedDurationFinishTime,
+			NetTime:      expectedDurationFinishTime,
+			FinishSource: t.Name(),
+		},
+	}
+
+	builder := NewRaceResultBuilder(slog.Default(), nil, nil, nil)
+	ranking := map[string]int{}
+	ranking[t.Name()] = 1
+
+	mockResults := meets.NewMockResultWriter()
+
+	err := builder.BuildRaceResults(context.TODO(), inputEvents, athletes, config.NewRankingPolicy(ranking), mockResults)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 1, len(mockResults.SavedResults))
+	assert.Equal(t, expectedResults[0], mockResults.SavedResults[0])
+}
+
+func TestRaceResultBuilderStartFinishPlace(t *testing.T) {
+	// read events off a stream and return
+	// result events when they are complete
+	now := time.Now().UTC()
+	// Test data
+	expectedDurationFinishTime := (5 * time.Minute)
+	finishTime10 := now.Add(expectedDurationFinishTime)
+
+	// 3 events minimum to build a result:  start, finish and place
+	// if the builder doesn't get all 3 no result for the bib is produced
+	testEvents := []raceevents.Event{
+		{
+			ID:        "1",
+			EventTime: now,
+			Data: raceevents.StartEvent{
+				Source:    t.Name(),
+				StartTime: now,
+			},
+		},
+		{
+			ID:        "2",
+			EventTime: now,
+			Data: raceevents.FinishEvent{
+				Source:     t.Name(),
+				Bib:        10,
+				FinishTime: finishTime10,
+			},
+		},
+		{
+			ID:        "3",
+			EventTime: now,
+			Data: raceevents.PlaceEvent{
+				Source: t.Name(),
+				Bib:    10,
+				Place:  1,
+			},
+		},
+	}
+
+	mockInStream := &stream.MockStream{
+		Events: buildEventMessages(testEvents),
+	}
+	inputEvents := raceevents.NewEventStream(mockInStream)
+
+	athletes := make(meets.AthleteLookup)
+	athletes[10] = meets.NewAthlete("D", "R", "WPI", "DAID", 12, "m")
+
+	expectedResults := []meets.RaceResult{
+		{
+			Bib:          10,
+			Athlete:      athletes[10],
+			Place:        0,
+			GunTime:      expectedDurationFinishTime,
+			NetTime:      expectedDurationFinishTime,
+			FinishSource: t.Name(),
+		},
+		{
+			Bib:          10,
+			Athlete:      athletes[10],
+			Place:        1,
+			GunTime:      expectedDurationFinishTime,
+			NetTime:      expectedDurationFinishTime,
+			FinishSource: t.Name(),
+			PlaceSource:  t.Name(),
+		},
+	}
+
+	builder := NewRaceResultBuilder(slog.Default(), nil, nil, nil)
+	ranking := map[string]int{}
+	ranking[t.Name()] = 1
+
+	mockResults := meets.NewMockResultWriter()
+
+	err := builder.BuildRaceResults(context.TODO(), inputEvents, athletes, config.NewRankingPolicy(ranking), mockResults)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 2, len(mockResults.SavedResults))
+	assert.Equal(t, expectedResults[0], mockResults.SavedResults[0])
+	assert.Equal(t, expectedResults[1], mockResults.SavedResults[1])
+}
+
+func TestRaceResultBuilderPlaceOnly(t *testing.T) {
+	// read events off a stream and return
+	// result events when they are complete
+	now := time.Now().UTC()
+
+	// 3 events minimum to build a result:  start, finish and place
+	// if the builder doesn't get all 3 no result for the bib is produced
+	testEvents := []raceevents.Event{
+		{
+			ID:        "3",
+			EventTime: now,
+			Data: raceevents.PlaceEvent{
+				Source: t.Name(),
+				Bib:    10,
+				Place:  1,
+			},
+		},
+	}
+
+	mockInStream := &stream.MockStream{
+		Events: buildEventMessages(testEvents),
+	}
+	inputEvents := raceevents.NewEventStream(mockInStream)
+
+	athletes := make(meets.AthleteLookup)
+	athletes[10] = meets.NewAthlete("D", "R", "WPI", "DAID", 12, "m")
+
+	expectedResults := []meets.RaceResult{
+		{
+			Bib:         10,
+			Athlete:     athletes[10],
+			Place:       1,
+			PlaceSource: t.Name(),
+		},
+	}
+
+	builder := NewRaceResultBuilder(slog.Default(), nil, nil, nil)
+	ranking := map[string]int{}
+	ranking[t.Name()] = 1
+
+	mockResults := meets.NewMockResultWriter()
+
+	err := builder.BuildRaceResults(context.TODO(), inputEvents, athletes, config.NewRankingPolicy(ranking), mockResults)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 1, len(mockResults.SavedResults))
+	assert.Equal(t, expectedResults[0], mockResults.SavedResults[0])
+}
+
+func TestRaceResultBuilderFinishTimeOverride(t *testing.T) {
+	// read events off a stream and return
+	// result events when they are complete
+	now := time.Now().UTC()
+	// Test data
+	expected5DurationFinishTime := (5 * time.Minute)
+	expected10DurationFinishTime := (10 * time.Minute)
+	finishTime5 := now.Add(expected5DurationFinishTime)
+	finishTime10 := now.Add(expected10DurationFinishTime)
+
+	// 3 events minimum to build a result:  start, finish and place
+	// if the builder doesn't get all 3 no result for the bib is produced
+	testEvents := []raceevents.Event{
+		{
+			ID:        "1",
+			EventTime: now,
+			Data: raceevents.StartEvent{
+				Source:    t.Name(),
+				StartTime: now,
+			},
+		},
+		{
+			ID:        "2",
+			EventTime: now,
+			Data: raceevents.FinishEvent{
+				Source:     "good",
+				Bib:        10,
+				FinishTime: finishTime10,
+			},
+		},
+		{
+			ID:        "2",
+			EventTime: now,
+			Data: raceevents.FinishEvent{
+				Source:     "better",
+				Bib:        10,
+				FinishTime: finishTime5,
+			},
+		},
+	}
+
+	mockInStream := &stream.MockStream{
+		Events: buildEventMessages(testEvents),
+	}
+	inputEvents := raceevents.NewEventStream(mockInStream)
+
+	athletes := make(meets.AthleteLookup)
+	athletes[10] = meets.NewAthlete("D", "R", "WPI", "DAID", 12, "m")
+
+	expectedResults := []meets.RaceResult{
+		{
+			Bib:          10,
+			Athlete:      athletes[10],
+			Place:        0,
+			GunTime:      expected10DurationFinishTime,
+			NetTime:      expected10DurationFinishTime,
+			FinishSource: "good",
+		},
+		{
+			Bib:          10,
+			Athlete:      athletes[10],
+			Place:        0,
+			GunTime:      expected5DurationFinishTime,
+			NetTime:      expected5DurationFinishTime,
+			FinishSource: "better",
+		},
+	}
+
+	builder := NewRaceResultBuilder(slog.Default(), nil, nil, nil)
+	ranking := map[string]int{}
+	ranking["good"] = 2
+	ranking["better"] = 1
+
+	mockResults := meets.NewMockResultWriter()
+
+	err := builder.BuildRaceResults(context.TODO(), inputEvents, athletes, config.NewRankingPolicy(ranking), mockResults)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 2, len(mockResults.SavedResults))
+	assert.Equal(t, expectedResults[0], mockResults.SavedResults[0])
+	assert.Equal(t, expectedResults[1], mockResults.SavedResults[1])
+}
+
+func TestRaceResultBuilderFinishTimeSkipUpdate(t *testing.T) {
+	// read events off a stream and return
+	// result events when they are complete
+	now := time.Now().UTC()
+	// Test data
+	expected5DurationFinishTime := (5 * time.Minute)
+	expected10DurationFinishTime := (10 * time.Minute)
+	finishTime5 := now.Add(expected5DurationFinishTime)
+	finishTime10 := now.Add(expected10DurationFinishTime)
+
+	// 3 events minimum to build a result:  start, finish and place
+	// if the builder doesn't get all 3 no result for the bib is produced
+	testEvents := []raceevents.Event{
+		{
+			ID:        "1",
+			EventTime: now,
+			Data: raceevents.StartEvent{
+				Source:    t.Name(),
+				StartTime: now,
+			},
+		},
+		{
+			ID:        "2",
+			EventTime: now,
+			Data: raceevents.FinishEvent{
+				Source:     "better",
+				Bib:        10,
+				FinishTime: finishTime10,
+			},
+		},
+		{
+			ID:        "2",
+			EventTime: now,
+			Data: raceevents.FinishEvent{
+				Source:     "good",
+				Bib:        10,
+				FinishTime: finishTime5,
+			},
+		},
+	}
+
+	mockInStream := &stream.MockStream{
+		Events: buildEventMessages(testEvents),
+	}
+	inputEvents := raceevents.NewEventStream(mockInStream)
+
+	athletes := make(meets.AthleteLookup)
+	athletes[10] = meets.NewAthlete("D", "R", "WPI", "DAID", 12, "m")
+
+	expectedResults := []meets.RaceResult{
+		{
+			Bib:          10,
+			Athlete:      athletes[10],
+			Place:        0,
+			GunTime:      expected10DurationFinishTime,
+			NetTime:      expected10DurationFinishTime,
+			FinishSource: "better",
+		},
+	}
+
+	builder := NewRaceResultBuilder(slog.Default(), nil, nil, nil)
+	ranking := map[string]int{}
+	ranking["good"] = 2
+	ranking["better"] = 1
+
+	mockResults := meets.NewMockResultWriter()
+
+	err := builder.BuildRaceResults(context.TODO(), inputEvents, athletes, config.NewRankingPolicy(ranking), mockResults)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 1, len(mockResults.SavedResults))
+	assert.Equal(t, expectedResults[0], mockResults.SavedResults[0])
+}
+
+func TestRaceResultBuilderPlaceOverride(t *testing.T) {
+	// read events off a stream and return
+	// result events when they are complete
+	now := time.Now().UTC()
+
+	// 3 events minimum to build a result:  start, finish and place
+	// if the builder doesn't get all 3 no result for the bib is produced
+	testEvents := []raceevents.Event{
+		{
+			ID:        "3",
+			EventTime: now,
+			Data: raceevents.PlaceEvent{
+				Source: "good",
+				Bib:    10,
+				Place:  2,
+			},
+		},
+		{
+			ID:        "4",
+			EventTime: now,
+			Data: raceevents.PlaceEvent{
+				Source: "better",
+				Bib:    10,
+				Place:  1,
+			},
+		},
+	}
+
+	mockInStream := &stream.MockStream{
+		Events: buildEventMessages(testEvents),
+	}
+	inputEvents := raceevents.NewEventStream(mockInStream)
+
+	athletes := make(meets.AthleteLookup)
+	athletes[10] = meets.NewAthlete("D", "R", "WPI", "DAID", 12, "m")
+
+	expectedResults := []meets.RaceResult{
+		{
+			Bib:         10,
+			Athlete:     athletes[10],
+			Place:       2,
+			PlaceSource: "good",
+		},
+		{
+			Bib:         10,
+			Athlete:     athletes[10],
+			Place:       1,
+			PlaceSource: "better",
+		},
+	}
+
+	builder := NewRaceResultBuilder(slog.Default(), nil, nil, nil)
+	ranking := map[string]int{}
+	ranking["good"] = 2
+	ranking["better"] = 1
+
+	mockResults := meets.NewMockResultWriter()
+
+	err := builder.BuildRaceResults(context.TODO(), inputEvents, athletes, config.NewRankingPolicy(ranking), mockResults)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 2, len(mockResults.SavedResults))
+	assert.Equal(t, expectedResults[0], mockResults.SavedResults[0])
+	assert.Equal(t, expectedResults[1], mockResults.SavedResults[1])
+}
+
+func TestRaceResultBuilderPlaceDoesNotShift(t *testing.T) {
+	testEvents := []raceevents.Event{
+		{ID: "1-0", Data: raceevents.PlaceEvent{Source: t.Name(), Bib: 10, Place: 1}},
+		{ID: "2-0", Data: raceevents.PlaceEvent{Source: t.Name(), Bib: 11, Place: 2}},
+		// 11 moves up, only the result streams move 10 back, here the placer sends 10's new place
+		{ID: "3-0", Data: raceevents.PlaceEvent{Source: t.Name(), Bib: 11, Place: 1}},
+	}
+	inputEvents := raceevents.NewEventStream(&stream.MockStream{Events: buildEventMessages(testEvents)})
+
+	athletes := make(meets.AthleteLookup)
+	athletes[10] = meets.NewAthlete("D", "R", "WPI", "DAID", 12, "m")
+	athletes[11] = meets.NewAthlete("E", "R", "WPI", "DAID2", 12, "m")
+
+	mockResults := meets.NewMockResultWriter()
+	err := NewRaceResultBuilder(slog.Default(), nil, nil, nil).BuildRaceResults(context.TODO(), inputEvents, athletes, config.NewRankingPolicy(map[string]int{t.Name(): 1}), mockResults)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []meets.RaceResult{
+		{Bib: 10, Athlete: athletes[10], Place: 1, PlaceSource: t.Name()},
+		{Bib: 11, Athlete: athletes[11], Place: 2, PlaceSource: t.Name()},
+		{Bib: 11, Athlete: athletes[11], Place: 1, PlaceSource: t.Name()},
+	}, mockResults.SavedResults)
+}
+
+func TestRaceResultBuilderCorrectionSurvivesPlacer(t *testing.T) {
+	testEvents := []raceevents.Event{
+		{ID: "1-0", Data: raceevents.PlaceEvent{Source: "default-placer", Bib: 10, Place: 1}},
+		{ID: "2-0", Data: raceevents.PlaceEvent{Source: "default-placer", Bib: 11, Place: 2}},
+		// the places are corrected by hand, 11 moves up
+		{ID: "3-0", Data: raceevents.PlaceEvent{Source: "manual", Bib: 11, Place: 1}},
+		{ID: "4-0", Data: raceevents.PlaceEvent{Source: "manual", Bib: 10, Place: 2}},
+		// the placer re-places its finishes when another finish arrives
+		{ID: "5-0", Data: raceevents.PlaceEvent{Source: "default-placer", Bib: 10, Place: 1}},
+		{ID: "6-0", Data: raceevents.PlaceEvent{Source: "default-placer", Bib: 11, Place: 2}},
+	}
+	inputEvents := raceevents.NewEventStream(&stream.MockStream{Events: buildEventMessages(testEvents)})
+
+	athletes := make(meets.AthleteLookup)
+	athletes[10] = meets.NewAthlete("D", "R", "WPI", "DAID", 12, "m")
+	athletes[11] = meets.NewAthlete("E", "R", "WPI", "DAID2", 12, "m")
+
+	mockResults := meets.NewMockResultWriter()
+	ranking := config.NewRankingPolicy(map[string]int{"manual": 1, "default-placer": 2})
+	err := NewRaceResultBuilder(slog.Default(), nil, nil, nil).BuildRaceResults(context.TODO(), inputEvents, athletes, ranking, mockResults)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []meets.RaceResult{
+		{Bib: 10, Athlete: athletes[10], Place: 1, PlaceSource: "default-placer"},
+		{Bib: 11, Athlete: athletes[11], Place: 2, PlaceSource: "default-placer"},
+		{Bib: 11, Athlete: athletes[11], Place: 1, PlaceSource: "manual"},
+		{Bib: 10, Athlete: athletes[10], Place: 2, PlaceSource: "manual"},
+	}, mockResults.SavedResults)
+}
+
+func TestRaceResultBuilderPlaceSkipUpdate(t *testing.T) {
+	// read events off a stream and return
+	// result events when they are complete
+	now := time.Now().UTC()
+
+	// 3 events minimum to build a result:  start, finish and place
+	// if the builder doesn't get all 3 no result for the bib is produced
+	testEvents := []raceevents.Event{
+		{
+			ID:        "3",
+			EventTime: now,
+			Data: raceevents.PlaceEvent{
+				Source: "better",
+				Bib:    10,
+				Place:  2,
+			},
+		},
+		{
+			ID:        "4",
+			EventTime: now,
+			Data: raceevents.PlaceEvent{
+				Source: "good",
+				Bib:    10,
+				Place:  1,
+			},
+		},
+	}
+
+	mockInStream := &stream.MockStream{
+		Events: buildEventMessages(testEvents),
+	}
+	inputEvents := raceevents.NewEventStream(mockInStream)
+
+	athletes := make(meets.AthleteLookup)
+	athletes[10] = meets.NewAthlete("D", "R", "WPI", "DAID", 12, "m")
+
+	expectedResults := []meets.RaceResult{
+		{
+			Bib:         10,
+			Athlete:     athletes[10],
+			Place:       2,
+			PlaceSource: "better",
+		},
+	}
+
+	builder := NewRaceResultBuilder(slog.Default(), nil, nil, nil)
+	ranking := map[string]int{}
+	ranking["good"] = 2
+	ranking["better"] = 1
+
+	mockResults := meets.NewMockResultWriter()
+
+	err := builder.BuildRaceResults(context.TODO(), inputEvents, athletes, config.NewRankingPolicy(ranking), mockResults)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 1, len(mockResults.SavedResults))
+	assert.Equal(t, expectedResults[0], mockResults.SavedResults[0])
+}
+
+func TestRaceResultBuilderGroupResumesWithoutResaving(t *testing.T) {
+	now := time.Now().UTC()
+	expectedDurationFinishTime := (5 * time.Minute)
+
+	// the start and finish were handled before a restart, only the place is new
+	acknowledged := []raceevents.Event{
+		{
+			ID:   "1-0",
+			Data: raceevents.StartEvent{Source: t.Name(), StartTime: now},
+		},
+		{
+			ID:   "2-0",
+			Data: raceevents.FinishEvent{Source: t.Name(), Bib: 10, FinishTime: now.Add(expectedDurationFinishTime)},
+		},
+	}
+	inputEvents := &raceevents.MockEventGroupStream{
+		MockEventStream: raceevents.MockEventStream{
+			Range: func(ctx context.Context, startId, endId string, events []raceevents.Event) (int, error) {
+				count := copy(events, acknowledged)
+				acknowledged = acknowledged[count:]
+				return count, nil
+			},
+			Events: []raceevents.Event{
+				{
+					ID:   "3-0",
+					Data: raceevents.PlaceEvent{Source: t.Name(), Bib: 10, Place: 1},
+				},
+			},
+		},
+		Position: stream.GroupPosition{LastDeliveredID: "2-0"},
+	}
+
+	athletes := make(meets.AthleteLookup)
+	athletes[10] = meets.NewAthlete("D", "R", "WPI", "DAID", 12, "m")
+
+	builder := NewRaceResultBuilder(slog.Default(), nil, nil, nil)
+	ranking := map[string]int{t.Name(): 1}
+	mockResults := meets.NewMockResultWriter()
+
+	err := builder.BuildRaceResults(context.TODO(), inputEvents, athletes, config.NewRankingPolicy(ranking), mockResults)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []meets.RaceResult{
+		{
+			Bib:          10,
+			Athlete:      athletes[10],
+			Place:        1,
+			GunTime:      expectedDurationFinishTime,
+			NetTime:      expectedDurationFinishTime,
+			FinishSource: t.Name(),
+			PlaceSource:  t.Name(),
+		},
+	}, mockResults.SavedResults)
+	assert.Equal(t, []string{"3-0"}, inputEvents.Acked)
+}
+
+func TestRaceResultBuilderResumesFromCheckpoint(t *testing.T) {
+	now := time.Now().UTC()
+	expectedDurationFinishTime := (5 * time.Minute)
+
+	testEvents := []raceevents.Event{
+		{
+			ID:   "1-0",
+			Data: raceevents.StartEvent{Source: t.Name(), StartTime: now},
+		},
+		{
+			ID:   "2-0",
+			Data: raceevents.FinishEvent{Source: t.Name(), Bib: 10, FinishTime: now.Add(expectedDurationFinishTime)},
+		},
+	}
+
+	athletes := make(meets.AthleteLookup)
+	athletes[10] = meets.NewAthlete("D", "R", "WPI", "DAID", 12, "m")
+	ranking := map[string]int{t.Name(): 1}
+	checkpoints := &meets.MockCheckpointStore{}
+
+	// first run stops after the finish
+	err := NewRaceResultBuilder(slog.Default(), checkpoints, nil, nil).BuildRaceResults(context.TODO(),
+		raceevents.NewEventStream(&stream.MockStream{Events: buildEventMessages(testEvents)}),
+		athletes, config.NewRankingPolicy(ranking), meets.NewMockResultWriter())
+	assert.NoError(t, err)
+	// the events are saved in one checkpoint when the builder stops
+	assert.Equal(t, 1, checkpoints.Saves)
+	assert.Equal(t, "2-0", checkpoints.Checkpoint.LastEventID)
+
+	// the restarted builder skips what the checkpoint covers and still knows the start
+	testEvents = append(testEvents, raceevents.Event{
+		ID:   "3-0",
+		Data: raceevents.PlaceEvent{Source: t.Name(), Bib: 10, Place: 1},
+	})
+	mockResults := meets.NewMockResultWriter()
+	err = NewRaceResultBuilder(slog.Default(), checkpoints, nil, nil).BuildRaceResults(context.TODO(),
+		raceevents.NewEventStream(&stream.MockStream{Events: buildEventMessages(testEvents)}),
+		athletes, config.NewRankingPolicy(ranking), mockResults)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []meets.RaceResult{
+		{
+			Bib:          10,
+			Athlete:      athletes[10],
+			Place:        1,
+			GunTime:      expectedDurationFinishTime,
+			NetTime:      expectedDurationFinishTime,
+			FinishSource: t.Name(),
+			PlaceSource:  t.Name(),
+		},
+	}, mockResults.SavedResults)
+	assert.Equal(t, "3-0", checkpoints.Checkpoint.LastEventID)
+}
+
+// failingResultWriter fails every save after the first saves
+type failingResultWriter struct {
+	meets.MockResultWriter
+	saves int
+}
+
+func (frw *failingResultWriter) SaveResult(rr *meets.RaceResult) (*meets.RaceResult, error) {
+	if len(frw.SavedResults) >= frw.saves {
+		return rr, fmt.Errorf("database is down")
+	}
+	return frw.MockResultWriter.SaveResult(rr)
+}
+
+func TestRaceResultBuilderStopsWhenResultNotSaved(t *testing.T) {
+	now := time.Now().UTC()
+
+	testEvents := []raceevents.Event{
+		{ID: "1-0", Data: raceevents.StartEvent{Source: t.Name(), StartTime: now}},
+		{ID: "2-0", Data: raceevents.FinishEvent{Source: t.Name(), Bib: 10, FinishTime: now.Add(5 * time.Minute)}},
+		{ID: "3-0", Data: raceevents.FinishEvent{Source: t.Name(), Bib: 11, FinishTime: now.Add(6 * time.Minute)}},
+	}
+
+	athletes := make(meets.AthleteLookup)
+	athletes[10] = meets.NewAthlete("D", "R", "WPI", "DAID", 12, "m")
+	athletes[11] = meets.NewAthlete("E", "R", "WPI", "DAID2", 12, "m")
+	ranking := config.NewRankingPolicy(map[string]int{t.Name(): 1})
+	checkpoints := &meets.MockCheckpointStore{}
+
+	// bib 11's result isn't saved, so the checkpoint stays at bib 10's finish
+	err := NewRaceResultBuilder(slog.Default(), checkpoints, nil, nil).BuildRaceResults(context.TODO(),
+		raceevents.NewEventStream(&stream.MockStream{Events: buildEventMessages(testEvents)}),
+		athletes, ranking, &failingResultWriter{saves: 1})
+	assert.Error(t, err)
+	assert.Equal(t, "2-0", checkpoints.Checkpoint.LastEventID)
+
+	// a restart saves it
+	mockResults := meets.NewMockResultWriter()
+	err = NewRaceResultBuilder(slog.Default(), checkpoints, nil, nil).BuildRaceResults(context.TODO(),
+		raceevents.NewEventStream(&stream.MockStream{Events: buildEventMessages(testEvents)}),
+		athletes, ranking, mockResults)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(mockResults.SavedResults))
+	assert.Equal(t, 11, mockResults.SavedResults[0].Bib)
+	assert.Equal(t, "3-0", checkpoints.Checkpoint.LastEventID)
+
+	// a group doesn't acknowledge the event either
+	inputEvents := &raceevents.MockEventGroupStream{MockEventStream: raceevents.MockEventStream{Events: testEvents}}
+	err = NewRaceResultBuilder(slog.Default(), nil, nil, nil).BuildRaceResults(context.TODO(), inputEvents, athletes, ranking, &failingResultWriter{saves: 1})
+	assert.Error(t, err)
+	assert.Equal(t, []string{"1-0", "2-0"}, inputEvents.Acked)
+}
+
+func TestRaceResultBuilderCheckpointsAddHistory(t *testing.T) {
+	now := time.Now().UTC()
+
+	testEvents := []raceevents.Event{
+		{ID: "1-0", Data: raceevents.StartEvent{Source: t.Name(), StartTime: now}},
+		{ID: "2-0", Data: raceevents.FinishEvent{Source: t.Name(), Bib: 10, FinishTime: now.Add(5 * time.Minute)}},
+		{ID: "3-0", Data: raceevents.FinishEvent{Source: t.Name(), Bib: 10, FinishTime: now.Add(6 * time.Minute)}},
+	}
+
+	athletes := make(meets.AthleteLookup)
+	athletes[10] = meets.NewAthlete("D", "R", "WPI", "DAID", 12, "m")
+	ranking := config.NewRankingPolicy(map[string]int{t.Name(): 1})
+
+	// each checkpoint only has the events since the last one, the store keeps the whole history
+	histories := make([]int, 0)
+	checkpoints := &meets.MockCheckpointStore{}
+	recording := &recordingCheckpointStore{MockCheckpointStore: checkpoints, added: &histories}
+	err := NewRaceResultBuilder(slog.Default(), recording, nil, nil).BuildRaceResults(context.TODO(),
+		raceevents.NewEventStream(&stream.MockStream{Events: buildEventMessages(testEvents)}),
+		athletes, ranking, meets.NewMockResultWriter())
+	assert.NoError(t, err)
+	assert.Equal(t, []int{3}, histories)
+	assert.Equal(t, 3, len(checkpoints.Checkpoint.History))
+
+	// a restarted builder rebuilds from the saved history when the second finish is voided
+	testEvents = append(testEvents, raceevents.Event{ID: "4-0", Data: raceevents.VoidEvent{Source: t.Name(), EventID: "3-0"}})
+	mockResults := meets.NewMockResultWriter()
+	err = NewRaceResultBuilder(slog.Default(), recording, nil, nil).BuildRaceResults(context.TODO(),
+		raceevents.NewEventStream(&stream.MockStream{Events: buildEventMessages(testEvents)}),
+		athletes, ranking, mockResults)
+	assert.NoError(t, err)
+	assert.Equal(t, []meets.RaceResult{
+		{Bib: 10, Athlete: athletes[10], GunTime: 5 * time.Minute, NetTime: 5 * time.Minute, FinishSource: t.Name()},
+	}, mockResults.SavedResults)
+	// the void isn't history
+	assert.Equal(t, []int{3, 0}, histories)
+}
+
+func TestRaceResultBuilderCheckpointsEveryFewEvents(t *testing.T) {
+	now := time.Now().UTC()
+
+	testEvents := []raceevents.Event{{ID: "1-0", Data: raceevents.StartEvent{Source: t.Name(), StartTime: now}}}
+	athletes := make(meets.AthleteLookup)
+	for bib := 1; bib <= checkpointEvents; bib++ {
+		athletes[bib] = meets.NewAthlete("D", "R", "WPI", fmt.Sprint("DAID", bib), 12, "m")
+		testEvents = append(testEvents, raceevents.Event{
+			ID:   fmt.Sprintf("%d-0", bib+1),
+			Data: raceevents.FinishEvent{Source: t.Name(), Bib: bib, FinishTime: now.Add(5*time.Minute + time.Duration(bib)*time.Second)},
+		})
+	}
+
+	histories := make([]int, 0)
+	checkpoints := &meets.MockCheckpointStore{}
+	recording := &recordingCheckpointStore{MockCheckpointStore: checkpoints, added: &histories}
+	err := NewRaceResultBuilder(slog.Default(), recording, nil, nil).BuildRaceResults(context.TODO(),
+		raceevents.NewEventStream(&stream.MockStream{Events: buildEventMessages(testEvents)}),
+		athletes, config.NewRankingPolicy(map[string]int{t.Name(): 1}), meets.NewMockResultWriter())
+	assert.NoError(t, err)
+
+	// a checkpoint once enough events are handled, then the rest when the builder stops
+	assert.Equal(t, []int{checkpointEvents, 1}, histories)
+	assert.Equal(t, fmt.Sprintf("%d-0", checkpointEvents+1), checkpoints.Checkpoint.LastEventID)
+}
+
+// recordingCheckpointStore records how many history events each checkpoint adds
+type recordingCheckpointStore struct {
+	*meets.MockCheckpointStore
+	added *[]int
+}
+
+func (rcs *recordingCheckpointStore) SaveCheckpoint(cp meets.ResultCheckpoint) error {
+	*rcs.added = append(*rcs.added, len(cp.History))
+	return rcs.MockCheckpointStore.SaveCheckpoint(cp)
+}
+
+func TestRaceResultBuilderDisqualifiedLosesPlace(t *testing.T) {
+	now := time.Now().UTC()
+
+	testEvents := []raceevents.Event{
+		{ID: "1", Data: raceevents.StartEvent{Source: t.Name(), StartTime: now}},
+		{ID: "2", Data: raceevents.FinishEvent{Source: t.Name(), Bib: 10, FinishTime: now.Add(5 * time.Minute)}},
+		{ID: "3", Data: raceevents.PlaceEvent{Source: t.Name(), Bib: 10, Place: 1}},
+		{ID: "4", Data: raceevents.StatusEvent{Source: t.Name(), Bib: 10, Status: raceevents.StatusDQ, Reason: "obstruction"}},
+		{ID: "5", Data: raceevents.PlaceEvent{Source: t.Name(), Bib: 10, Place: 1}},
+	}
+	inputEvents := raceevents.NewEventStream(&stream.MockStream{Events: buildEventMessages(testEvents)})
+
+	athletes := make(meets.AthleteLookup)
+	athletes[10] = meets.NewAthlete("D", "R", "WPI", "DAID", 12, "m")
+
+	mockResults := meets.NewMockResultWriter()
+	err := NewRaceResultBuilder(slog.Default(), nil, nil, nil).BuildRaceResults(context.TODO(), inputEvents, athletes, config.NewRankingPolicy(map[string]int{t.Name(): 1}), mockResults)
+	assert.NoError(t, err)
+
+	// the place after the DQ is ignored
+	assert.Equal(t, 3, len(mockResults.SavedResults))
+	assert.Equal(t, meets.RaceResult{
+		Bib:          10,
+		Athlete:      athletes[10],
+		GunTime:      5 * time.Minute,
+		NetTime:      5 * time.Minute,
+		FinishSource: t.Name(),
+		Status:       raceevents.StatusDQ,
+	}, mockResults.SavedResults[2])
+}
+
+func TestRaceResultBuilderClearedStatusGetsPlaceBack(t *testing.T) {
+	now := time.Now().UTC()
+
+	testEvents := []raceevents.Event{
+		{ID: "1", Data: raceevents.StartEvent{Source: t.Name(), StartTime: now}},
+		{ID: "2", Data: raceevents.FinishEvent{Source: t.Name(), Bib: 10, FinishTime: now.Add(5 * time.Minute)}},
+		{ID: "3", Data: raceevents.PlaceEvent{Source: t.Name(), Bib: 10, Place: 1}},
+		{ID: "4", Data: raceevents.StatusEvent{Source: t.Name(), Bib: 10, Status: raceevents.StatusDQ, Reason: "obstruction"}},
+		{ID: "5", Data: raceevents.StatusEvent{Source: t.Name(), Bib: 10, Reason: "appeal upheld"}},
+	}
+	inputEvents := raceevents.NewEventStream(&stream.MockStream{Events: buildEventMessages(testEvents)})
+
+	athletes := make(meets.AthleteLookup)
+	athletes[10] = meets.NewAthlete("D", "R", "WPI", "DAID", 12, "m")
+
+	mockResults := meets.NewMockResultWriter()
+	err := NewRaceResultBuilder(slog.Default(), nil, nil, nil).BuildRaceResults(context.TODO(), inputEvents, athletes, config.NewRankingPolicy(map[string]int{t.Name(): 1}), mockResults)
+	assert.NoError(t, err)
+
+	// clearing the DQ puts the place back
+	assert.Equal(t, 4, len(mockResults.SavedResults))
+	assert.Equal(t, raceevents.StatusDQ, mockResults.SavedResults[2].Status)
+	assert.Equal(t, meets.RaceResult{
+		Bib:          10,
+		Athlete:      athletes[10],
+		Place:        1,
+		GunTime:      5 * time.Minute,
+		NetTime:      5 * time.Minute,
+		FinishSource: t.Name(),
+		PlaceSource:  t.Name(),
+	}, mockResults.SavedResults[3])
+}
+
+func TestRaceResultBuilderVoidedFinishIsUndone(t *testing.T) {
+	now := time.Now().UTC()
+
+	testEvents := []raceevents.Event{
+		{ID: "1-0", Data: raceevents.StartEvent{Source: t.Name(), StartTime: now}},
+		{ID: "2-0", Data: raceevents.FinishEvent{Source: t.Name(), Bib: 10, FinishTime: now.Add(5 * time.Minute)}},
+		{ID: "3-0", Data: raceevents.FinishEvent{Source: t.Name(), Bib: 10, FinishTime: now.Add(6 * time.Minute)}},
+		{ID: "4-0", Data: raceevents.FinishEvent{Source: t.Name(), Bib: 11, FinishTime: now.Add(7 * time.Minute)}},
+		{ID: "5-0", Data: raceevents.VoidEvent{Source: t.Name(), EventID: "3-0"}},
+		{ID: "6-0", Data: raceevents.VoidEvent{Source: t.Name(), EventID: "4-0"}},
+	}
+	inputEvents := raceevents.NewEventStream(&stream.MockStream{Events: buildEventMessages(testEvents)})
+
+	athletes := make(meets.AthleteLookup)
+	athletes[10] = meets.NewAthlete("D", "R", "WPI", "DAID", 12, "m")
+	athletes[11] = meets.NewAthlete("E", "R", "WPI", "DAID2", 12, "m")
+
+	mockResults := meets.NewMockResultWriter()
+	err := NewRaceResultBuilder(slog.Default(), nil, nil, nil).BuildRaceResults(context.TODO(), inputEvents, athletes, config.NewRankingPolicy(map[string]int{t.Name(): 1}), mockResults)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 4, len(mockResults.SavedResults))
+	// bib 10 goes back to the first finish
+	assert.Equal(t, meets.RaceResult{Bib: 10, Athlete: athletes[10], GunTime: 5 * time.Minute, NetTime: 5 * time.Minute, FinishSource: t.Name()}, mockResults.SavedResults[3])
+	// bib 11 has nothing left so its result is cleared
+	assert.Equal(t, []int{11}, mockResults.ClearedBibs)
+}
+
+func TestRaceResultBuilderVoidedOnlyFinish(t *testing.T) {
+	now := time.Now().UTC()
+
+	testEvents := []raceevents.Event{
+		{ID: "1-0", Data: raceevents.StartEvent{Source: t.Name(), StartTime: now}},
+		{ID: "2-0", Data: raceevents.FinishEvent{Source: t.Name(), Bib: 10, FinishTime: now.Add(5 * time.Minute)}},
+		{ID: "3-0", Data: raceevents.VoidEvent{Source: t.Name(), EventID: "2-0"}},
+	}
+	inputEvents := raceevents.NewEventStream(&stream.MockStream{Events: buildEventMessages(testEvents)})
+
+	athletes := make(meets.AthleteLookup)
+	athletes[10] = meets.NewAthlete("D", "R", "WPI", "DAID", 12, "m")
+
+	mockResults := meets.NewMockResultWriter()
+	err := NewRaceResultBuilder(slog.Default(), nil, nil, nil).BuildRaceResults(context.TODO(), inputEvents, athletes, config.NewRankingPolicy(map[string]int{t.Name(): 1}), mockResults)
+	assert.NoError(t, err)
+
+	// only the finish is saved, the void clears it rather than saving an empty result
+	assert.Equal(t, 1, len(mockResults.SavedResults))
+	assert.Equal(t, 10, mockResults.SavedResults[0].Bib)
+	assert.Equal(t, []int{10}, mockResults.ClearedBibs)
+}
+
+func TestRaceResultBuilderWaveStarts(t *testing.T) {
+	now := time.Now().UTC()
+	girlsStart := now.Add(10 * time.Minute)
+
+	testEvents := []raceevents.Event{
+		{ID: "1-0", Data: raceevents.StartEvent{Source: t.Name(), StartTime: now, Wave: "boys"}},
+		{ID: "2-0", Data: raceevents.FinishEvent{Source: t.Name(), Bib: 10, FinishTime: now.Add(17 * time.Minute)}},
+		// the girls wave started late on a watch, its start is sent after the first girl finished
+		{ID: "3-0", Data: raceevents.FinishEvent{Source: t.Name(), Bib: 20, FinishTime: now.Add(28 * time.Minute)}},
+		{ID: "4-0", Data: raceevents.StartEvent{Source: t.Name(), StartTime: now.Add(11 * time.Minute), Wave: "girls"}},
+		// the start was wrong, void it and send the right one
+		{ID: "5-0", Data: raceevents.VoidEvent{Source: t.Name(), EventID: "4-0"}},
+		{ID: "6-0", Data: raceevents.StartEvent{Source: t.Name(), StartTime: girlsStart, Wave: "girls"}},
+	}
+	inputEvents := raceevents.NewEventStream(&stream.MockStream{Events: buildEventMessages(testEvents)})
+
+	athletes := make(meets.AthleteLookup)
+	athletes[10] = meets.NewAthlete("D", "R", "WPI", "DAID", 12, "m")
+	athletes[20] = meets.NewAthlete("E", "R", "WPI", "DAID2", 12, "f")
+	waves := meets.WaveLookup{10: "boys", 20: "girls"}
+
+	mockResults := meets.NewMockResultWriter()
+	err := NewRaceResultBuilder(slog.Default(), nil, waves, nil).BuildRaceResults(context.TODO(), inputEvents, athletes, config.NewRankingPolicy(map[string]int{t.Name(): 1}), mockResults)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 4, len(mockResults.SavedResults))
+	assert.Equal(t, 17*time.Minute, mockResults.SavedResults[0].GunTime)
+	assert.Equal(t, 17*time.Minute, mockResults.SavedResults[1].GunTime)
+	assert.Equal(t, 20, mockResults.SavedResults[1].Bib)
+	// voiding the start leaves the girl without a time until the corrected start
+	assert.Equal(t, meets.RaceResult{Bib: 20, Athlete: athletes[20], FinishSource: t.Name()}, mockResults.SavedResults[2])
+	assert.Equal(t, meets.RaceResult{Bib: 20, Athlete: athletes[20], GunTime: 18 * time.Minute, NetTime: 18 * time.Minute, FinishSource: t.Name()}, mockResults.SavedResults[3])
+}
+
+func TestRaceResultBuilderReloadsUnknownBib(t *testing.T) {
+	now := time.Now().UTC()
+
+	testEvents := []raceevents.Event{
+		{ID: "1-0", Data: raceevents.StartEvent{Source: t.Name(), StartTime: now, Wave: "boys"}},
+		{ID: "2-0", Data: raceevents.FinishEvent{Source: t.Name(), Bib: 10, FinishTime: now.Add(17 * time.Minute)}},
+		{ID: "3-0", Data: raceevents.FinishEvent{Source: t.Name(), Bib: 11, FinishTime: now.Add(18 * time.Minute)}},
+		{ID: "4-0", Data: raceevents.FinishEvent{Source: t.Name(), Bib: 12, FinishTime: now.Add(19 * time.Minute)}},
+	}
+	inputEvents := raceevents.NewEventStream(&stream.MockStream{Events: buildEventMessages(testEvents)})
+
+	athletes := meets.AthleteLookup{10: meets.NewAthlete("D", "R", "WPI", "DAID", 12, "m")}
+	waves := meets.WaveLookup{10: "boys"}
+
+	// 11 was added to the boys wave after the builder started, 12 never was
+	reloads := 0
+	refresh := meets.NewAthleteRefresh(func() error {
+		reloads++
+		athletes[11] = meets.NewAthlete("E", "R", "WPI", "DAID2", 12, "m")
+		waves[11] = "boys"
+		return nil
+	}, 0, slog.Default())
+
+	mockResults := meets.NewMockResultWriter()
+	builder := NewRefreshingRaceResultBuilder(slog.Default(), nil, waves, nil, refresh)
+	err := builder.BuildRaceResults(context.TODO(), inputEvents, athletes, config.NewRankingPolicy(map[string]int{t.Name(): 1}), mockResults)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 2, reloads)
+	assert.Equal(t, []meets.RaceResult{
+		{Bib: 10, Athlete: athletes[10], GunTime: 17 * time.Minute, NetTime: 17 * time.Minute, FinishSource: t.Name()},
+		{Bib: 11, Athlete: athletes[11], GunTime: 18 * time.Minute, NetTime: 18 * time.Minute, FinishSource: t.Name()},
+	}, mockResults.SavedResults)
+}
+
+func TestRaceResultBuilderChipStartNetTime(t *testing.T) {
+	now := time.Now().UTC()
+
+	testEvents := []raceevents.Event{
+		// a warm up read before the gun doesn't count
+		{ID: "1-0", Data: raceevents.ChipStartEvent{Source: "chip", Bib: 10, StartTime: now.Add(-5 * time.Minute)}},
+		{ID: "2-0", Data: raceevents.StartEvent{Source: t.Name(), StartTime: now}},
+		{ID: "3-0", Data: raceevents.ChipStartEvent{Source: "chip", Bib: 11, StartTime: now.Add(30 * time.Second)}},
+		{ID: "4-0", Data: raceevents.FinishEvent{Source: "chip", Bib: 10, FinishTime: now.Add(20 * time.Minute)}},
+		{ID: "5-0", Data: raceevents.FinishEvent{Source: "chip", Bib: 11, FinishTime: now.Add(21 * time.Minute)}},
+		// a late start mat read updates the net time
+		{ID: "6-0", Data: raceevents.ChipStartEvent{Source: "chip", Bib: 10, StartTime: now.Add(10 * time.Second)}},
+	}
+	inputEvents := raceevents.NewEventStream(&stream.MockStream{Events: buildEventMessages(testEvents)})
+
+	athletes := make(meets.AthleteLookup)
+	athletes[10] = meets.NewAthlete("D", "R", "WPI", "DAID", 12, "m")
+	athletes[11] = meets.NewAthlete("E", "R", "WPI", "DAID2", 12, "m")
+
+	mockResults := meets.NewMockResultWriter()
+	err := NewRaceResultBuilder(slog.Default(), nil, nil, nil).BuildRaceResults(context.TODO(), inputEvents, athletes, config.NewRankingPolicy(map[string]int{"chip": 1}), mockResults)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []meets.RaceResult{
+		{Bib: 10, Athlete: athletes[10], GunTime: 20 * time.Minute, NetTime: 20 * time.Minute, FinishSource: "chip"},
+		{Bib: 11, Athlete: athletes[11], GunTime: 21 * time.Minute, NetTime: 21*time.Minute - 30*time.Second, FinishSource: "chip"},
+		{Bib: 10, Athlete: athletes[10], GunTime: 20 * time.Minute, NetTime: 20*time.Minute - 10*time.Second, FinishSource: "chip"},
+	}, mockResults.SavedResults)
+}
+
+func TestRaceResultBuilderSplits(t *testing.T) {
+	now := time.Now().UTC()
+
+	testEvents := []raceevents.Event{
+		// split before the start is sent
+		{ID: "1-0", Data: raceevents.SplitEvent{Source: "chip", Bib: 10, TimingPoint: "1 mile", SplitTime: now.Add(5 * time.Minute)}},
+		{ID: "2-0", Data: raceevents.StartEvent{Source: t.Name(), StartTime: now}},
+		// a second read at the same point is ignored
+		{ID: "3-0", Data: raceevents.SplitEvent{Source: "chip", Bib: 10, TimingPoint: "1 mile", SplitTime: now.Add(5*time.Minute + time.Second)}},
+		{ID: "4-0", Data: raceevents.SplitEvent{Source: "chip", Bib: 10, TimingPoint: "2 mile", SplitTime: now.Add(11 * time.Minute)}},
+		{ID: "5-0", Data: raceevents.FinishEvent{Source: "chip", Bib: 10, FinishTime: now.Add(17 * time.Minute)}},
+	}
+	inputEvents := raceevents.NewEventStream(&stream.MockStream{Events: buildEventMessages(testEvents)})
+
+	athletes := make(meets.AthleteLookup)
+	athletes[10] = meets.NewAthlete("D", "R", "WPI", "DAID", 12, "m")
+
+	mockResults := meets.NewMockResultWriter()
+	err := NewRaceResultBuilder(slog.Default(), nil, nil, nil).BuildRaceResults(context.TODO(), inputEvents, athletes, config.NewRankingPolicy(map[string]int{"chip": 1}), mockResults)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 3, len(mockResults.SavedResults))
+	assert.Equal(t, map[string]time.Duration{"1 mile": 5 * time.Minute}, mockResults.SavedResults[0].Splits)
+	assert.Equal(t, map[string]time.Duration{"1 mile": 5 * time.Minute, "2 mile": 11 * time.Minute}, mockResults.SavedResults[1].Splits)
+	assert.Equal(t, meets.RaceResult{
+		Bib:          10,
+		Athlete:      athletes[10],
+		GunTime:      17 * time.Minute,
+		NetTime:      17 * time.Minute,
+		FinishSource: "chip",
+		Splits:       map[string]time.Duration{"1 mile": 5 * time.Minute, "2 mile": 11 * time.Minute},
+	}, mockResults.SavedResults[2])
+}
+
+func TestRaceResultBuilderClockOffsets(t *testing.T) {
+	now := time.Now().UTC()
+
+	testEvents := []raceevents.Event{
+		// the cli laptop is the reference clock
+		{ID: "1-0", Data: raceevents.StartEvent{Source: "cli", StartTime: now}},
+		// the mat is 400ms ahead and the chute reader 1s behind
+		{ID: "2-0", Data: raceevents.ChipStartEvent{Source: "mat", Bib: 10, StartTime: now.Add(10*time.Second + 400*time.Millisecond)}},
+		{ID: "3-0", Data: raceevents.FinishEvent{Source: "chute", Bib: 10, FinishTime: now.Add(20*time.Minute - time.Second)}},
+	}
+	inputEvents := raceevents.NewEventStream(&stream.MockStream{Events: buildEventMessages(testEvents)})
+
+	athletes := make(meets.AthleteLookup)
+	athletes[10] = meets.NewAthlete("D", "R", "WPI", "DAID", 12, "m")
+
+	offsets := config.ClockOffsets{"mat": 400, "chute": -1000}
+	mockResults := meets.NewMockResultWriter()
+	err := NewRaceResultBuilder(slog.Default(), nil, nil, offsets).BuildRaceResults(context.TODO(), inputEvents, athletes, config.NewRankingPolicy(map[string]int{"chute": 1}), mockResults)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []meets.RaceResult{
+		{Bib: 10, Athlete: athletes[10], GunTime: 20 * time.Minute, NetTime: 20*time.Minute - 10*time.Second, FinishSource: "chute"},
+	}, mockResults.SavedResults)
+}
+
+func TestRaceResultBuilderRankingPolicy(t *testing.T) {
+	now := time.Now().UTC()
+
+	testEvents := []raceevents.Event{
+		{ID: "1-0", Data: raceevents.StartEvent{Source: "cli", StartTime: now}},
+		{ID: "2-0", Data: raceevents.FinishEvent{Source: "chip", Bib: 10, FinishTime: now.Add(20 * time.Minute)}},
+		// a worse finish source doesn't replace the chip time
+		{ID: "3-0", Data: raceevents.FinishEvent{Source: "manual", Bib: 10, FinishTime: now.Add(20*time.Minute + time.Second)}},
+		// the gun is a better start than the cli and re-times the wave
+		{ID: "4-0", Data: raceevents.StartEvent{Source: "gun", StartTime: now.Add(time.Second)}},
+		// unranked sources are rejected
+		{ID: "5-0", Data: raceevents.PlaceEvent{Source: "unknown", Bib: 10, Place: 3}},
+		{ID: "6-0", Data: raceevents.FinishEvent{Source: "unknown", Bib: 10, FinishTime: now.Add(time.Minute)}},
+		{ID: "7-0", Data: raceevents.PlaceEvent{Source: "placer", Bib: 10, Place: 1}},
+	}
+	inputEvents := raceevents.NewEventStream(&stream.MockStream{Events: buildEventMessages(testEvents)})
+
+	athletes := make(meets.AthleteLookup)
+	athletes[10] = meets.NewAthlete("D", "R", "WPI", "DAID", 12, "m")
+
+	ranking := config.RankingPolicy{
+		Finish:   map[string]int{"chip": 1, "manual": 2},
+		Place:    map[string]int{"placer": 1},
+		Start:    map[string]int{"gun": 1, "cli": 2},
+		Unranked: config.UnrankedReject,
+	}
+	mockResults := meets.NewMockResultWriter()
+	err := NewRaceResultBuilder(slog.Default(), nil, nil, nil).BuildRaceResults(context.TODO(), inputEvents, athletes, ranking, mockResults)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []meets.RaceResult{
+		{Bib: 10, Athlete: athletes[10], GunTime: 20 * time.Minute, NetTime: 20 * time.Minute, FinishSource: "chip"},
+		{Bib: 10, Athlete: athletes[10], GunTime: 20*time.Minute - time.Second, NetTime: 20*time.Minute - time.Second, FinishSource: "chip"},
+		{Bib: 10, Athlete: athletes[10], Place: 1, GunTime: 20*time.Minute - time.Second, NetTime: 20*time.Minute - time.Second, FinishSource: "chip", PlaceSource: "placer"},
+	}, mockResults.SavedResults)
+}
+
+func TestRaceResultBuilderStopsWhenCancelled(t *testing.T) {
+	now := time.Now().UTC()
+
+	messages := buildEventMessages([]raceevents.Event{
+		{ID: "1-0", Data: raceevents.StartEvent{Source: t.Name(), StartTime: now}},
+		{ID: "2-0", Data: raceevents.FinishEvent{Source: t.Name(), Bib: 10, FinishTime: now.Add(5 * time.Minute)}},
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	inputEvents := raceevents.NewEventStream(&stream.MockStream{
+		Get: func(ctx context.Context, timeout time.Duration, msg *stream.Message) (bool, error) {
+			if len(messages) == 0 {
+				// ctrl-c while waiting for more events
+				cancel()
+				return false, ctx.Err()
+			}
+			*msg = messages[0]
+			messages = messages[1:]
+			return true, nil
+		},
+	})
+
+	athletes := make(meets.AthleteLookup)
+	athletes[10] = meets.NewAthlete("D", "R", "WPI", "DAID", 12, "m")
+
+	// everything read before the cancel is saved
+	checkpoints := &meets.MockCheckpointStore{}
+	mockResults := meets.NewMockResultWriter()
+	err := NewRaceResultBuilder(slog.Default(), checkpoints, nil, nil).BuildRaceResults(ctx, inputEvents, athletes, config.NewRankingPolicy(map[string]int{t.Name(): 1}), mockResults)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(mockResults.SavedResults))
+	assert.Equal(t, 5*time.Minute, mockResults.SavedResults[0].GunTime)
+	assert.Equal(t, "2-0", checkpoints.Checkpoint.LastEventID)
+
+	// a read error that isn't a shutdown is still returned
+	expErr := fmt.Errorf("read failed")
+	inputEvents = raceevents.NewEventStream(&stream.MockStream{
+		Get: func(ctx context.Context, timeout time.Duration, msg *stream.Message) (bool, error) {
+			return false, expErr
+		},
+	})
+	err = NewRaceResultBuilder(slog.Default(), nil, nil, nil).BuildRaceResults(context.Background(), inputEvents, athletes, config.NewRankingPolicy(map[string]int{t.Name(): 1}), mockResults)
+	assert.Equal(t, expErr, err)
+}
@@ -0,0 +1,18 @@
+package resultbuilder
+
+import (
+	"blreynolds4/event-race-timer/internal/config"
+	"log/slog"
+)
+
+// acceptSource is false when the ranking rejects events from a source that isn't ranked for field
+func acceptSource(ranking config.RankingPolicy, field config.RankedField, source string, logger *slog.Logger) bool {
+	switch ranking.UnrankedHandling(field, source) {
+	case config.UnrankedReject:
+		logger.Info("skipping event from unranked source", "field", field, "source", source)
+		return false
+	case config.UnrankedLog:
+		logger.Warn("using event from unranked source", "field", field, "source", source)
+	}
+	return true
+}
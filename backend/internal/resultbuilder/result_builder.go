@@ -0,0 +1,36 @@
+package resultbuilder
+
+import (
+	"blreynolds4/event-race-timer/internal/config"
+	"blreynolds4/event-race-timer/internal/meets"
+	"blreynolds4/event-race-timer/internal/raceevents"
+	"blreynolds4/event-race-timer/internal/results"
+	"context"
+	"log/slog"
+)
+
+type ResultBuilder interface {
+	BuildResults(ctx context.Context, inputEvents raceevents.EventStream, athletes meets.AthleteLookup, results results.ResultStream, ranking config.RankingPolicy) error
+}
+
+// NewResultBuilder creates a builder that sends results to a result stream.
+// It's the race result builder with a result stream sink, see NewRaceResultBuilder, so the
+// stream gets the same results as every other sink.
+func NewResultBuilder(l *slog.Logger, waves meets.WaveLookup, offsets config.ClockOffsets) ResultBuilder {
+	return &resultBuilder{
+		engine: NewRaceResultBuilder(l, nil, waves, offsets),
+	}
+}
+
+type resultBuilder struct {
+	engine RaceResultBuilder
+}
+
+func (rb *resultBuilder) BuildResults(ctx context.Context,
+	inputEvents raceevents.EventStream,
+	athletes meets.AthleteLookup,
+	resultOutput results.ResultStream,
+	ranking config.RankingPolicy) error {
+
+	return rb.engine.BuildRaceResults(ctx, inputEvents, athletes, ranking, NewResultStreamSink(resultOutput))
+}
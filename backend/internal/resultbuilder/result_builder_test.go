@@ -1,9 +1,11 @@
 package resultbuilder
 
 import (
+	"blreynolds4/event-race-timer/internal/config"
 	"blreynolds4/event-race-timer/internal/meets"
 	"blreynolds4/event-race-timer/internal/raceevents"
 	"blreynolds4/event-race-timer/internal/results"
+	"context"
 	"log/slog"
 
 	"blreynolds4/event-race-timer/internal/stream"
@@ -65,7 +67,8 @@ func TestResultBuilderSimplest(t *testing.T) {
 			Bib:          10,
 			Athlete:      athletes[10],
 			Place:        1,
-			Time:         finishTime10.Sub(now),
+			GunTime:      finishTime10.Sub(now),
+			NetTime:      finishTime10.Sub(now),
 			FinishSource: t.Name(),
 			PlaceSource:  t.Name(),
 		},
@@ -76,10 +79,10 @@ func TestResultBuilderSimplest(t *testing.T) {
 	}
 	actualResults := results.NewResultStream(mockOutStream)
 
-	builder := NewResultBuilder(slog.Default())
+	builder := NewResultBuilder(slog.Default(), nil, nil)
 	ranking := map[string]int{}
 	ranking[t.Name()] = 1
-	err := builder.BuildResults(inputEvents, athletes, actualResults, ranking)
+	err := builder.BuildResults(context.TODO(), inputEvents, athletes, actualResults, config.NewRankingPolicy(ranking))
 	assert.NoError(t, err)
 	assert.Equal(t, expectedResults, buildActualResults(mockOutStream))
 }
@@ -123,10 +126,10 @@ func TestResultBuilderPlaceCausesResult(t *testing.T) {
 	}
 	actualResults := results.NewResultStream(mockOutStream)
 
-	builder := NewResultBuilder(slog.Default())
+	builder := NewResultBuilder(slog.Default(), nil, nil)
 	ranking := map[string]int{}
 	ranking[t.Name()] = 1
-	err := builder.BuildResults(inputEvents, athletes, actualResults, ranking)
+	err := builder.BuildResults(context.TODO(), inputEvents, athletes, actualResults, config.NewRankingPolicy(ranking))
 	assert.NoError(t, err)
 	assert.Equal(t, expectedResults, buildActualResults(mockOutStream))
 }
@@ -190,7 +193,8 @@ func TestResultBuilderFinishUpdated(t *testing.T) {
 			Bib:          10,
 			Athlete:      athletes[10],
 			Place:        1,
-			Time:         finishTime10.Sub(now),
+			GunTime:      finishTime10.Sub(now),
+			NetTime:      finishTime10.Sub(now),
 			FinishSource: t.Name(),
 			PlaceSource:  t.Name(),
 		},
@@ -198,7 +202,8 @@ func TestResultBuilderFinishUpdated(t *testing.T) {
 			Bib:          10,
 			Athlete:      athletes[10],
 			Place:        1,
-			Time:         finishTime10updated.Sub(now),
+			GunTime:      finishTime10updated.Sub(now),
+			NetTime:      finishTime10updated.Sub(now),
 			FinishSource: t.Name(),
 			PlaceSource:  t.Name(),
 		},
@@ -209,10 +214,10 @@ func TestResultBuilderFinishUpdated(t *testing.T) {
 	}
 	actualResults := results.NewResultStream(mockOutStream)
 
-	builder := NewResultBuilder(slog.Default())
+	builder := NewResultBuilder(slog.Default(), nil, nil)
 	ranking := map[string]int{}
 	ranking[t.Name()] = 1
-	err := builder.BuildResults(inputEvents, athletes, actualResults, ranking)
+	err := builder.BuildResults(context.TODO(), inputEvents, athletes, actualResults, config.NewRankingPolicy(ranking))
 	assert.NoError(t, err)
 	assert.Equal(t, expectedResults, buildActualResults(mockOutStream))
 }
@@ -267,23 +272,16 @@ func TestResultBuilderStartUpdated(t *testing.T) {
 	athletes := make(meets.AthleteLookup)
 	athletes[10] = meets.NewAthlete("D", "R", "WPI", "DAID", 12, "m")
 
-	// when the first place event comes in the builder should produce a result
-	// when the updated start time comes, it should produce a new result for the
-	// same bib
+	// when the first place event comes in the builder should produce a result.
+	// A second start from the same source is skipped like it is for every sink,
+	// a wrong start is corrected by voiding it.
 	expectedResults := []meets.RaceResult{
 		{
 			Bib:          10,
 			Athlete:      athletes[10],
 			Place:        1,
-			Time:         finishTime10.Sub(now),
-			FinishSource: t.Name(),
-			PlaceSource:  t.Name(),
-		},
-		{
-			Bib:          10,
-			Athlete:      athletes[10],
-			Place:        1,
-			Time:         finishTime10.Sub(startUpdated),
+			GunTime:      finishTime10.Sub(now),
+			NetTime:      finishTime10.Sub(now),
 			FinishSource: t.Name(),
 			PlaceSource:  t.Name(),
 		},
@@ -294,10 +292,10 @@ func TestResultBuilderStartUpdated(t *testing.T) {
 	}
 	actualResults := results.NewResultStream(mockOutStream)
 
-	builder := NewResultBuilder(slog.Default())
+	builder := NewResultBuilder(slog.Default(), nil, nil)
 	ranking := map[string]int{}
 	ranking[t.Name()] = 1
-	err := builder.BuildResults(inputEvents, athletes, actualResults, ranking)
+	err := builder.BuildResults(context.TODO(), inputEvents, athletes, actualResults, config.NewRankingPolicy(ranking))
 	assert.NoError(t, err)
 	assert.Equal(t, expectedResults, buildActualResults(mockOutStream))
 }
@@ -370,15 +368,14 @@ func TestResultBuilderPlaceUpdated(t *testing.T) {
 	athletes[10] = meets.NewAthlete("D", "R", "WPI", "DAID", 12, "m")
 	athletes[11] = meets.NewAthlete("M", "R", "WPI", "DAID2", 12, "m")
 
-	// when the first place event comes in the builder should produce a result
-	// when the updated start time comes, it should produce a new result for the
-	// same bib
+	// the builder only changes the placed bib, the placer sends the new places of the runners it moves
 	expectedResults := []meets.RaceResult{
 		{
 			Bib:          10,
 			Athlete:      athletes[10],
 			Place:        1,
-			Time:         finishTime10.Sub(now),
+			GunTime:      finishTime10.Sub(now),
+			NetTime:      finishTime10.Sub(now),
 			FinishSource: t.Name(),
 			PlaceSource:  t.Name(),
 		},
@@ -386,7 +383,8 @@ func TestResultBuilderPlaceUpdated(t *testing.T) {
 			Bib:          11,
 			Athlete:      athletes[11],
 			Place:        2,
-			Time:         finishTime11.Sub(now),
+			GunTime:      finishTime11.Sub(now),
+			NetTime:      finishTime11.Sub(now),
 			FinishSource: t.Name(),
 			PlaceSource:  t.Name(),
 		},
@@ -394,15 +392,8 @@ func TestResultBuilderPlaceUpdated(t *testing.T) {
 			Bib:          11,
 			Athlete:      athletes[11],
 			Place:        1,
-			Time:         finishTime11.Sub(now),
-			FinishSource: t.Name(),
-			PlaceSource:  t.Name(),
-		},
-		{
-			Bib:          10,
-			Athlete:      athletes[10],
-			Place:        2,
-			Time:         finishTime10.Sub(now),
+			GunTime:      finishTime11.Sub(now),
+			NetTime:      finishTime11.Sub(now),
 			FinishSource: t.Name(),
 			PlaceSource:  t.Name(),
 		},
@@ -413,10 +404,10 @@ func TestResultBuilderPlaceUpdated(t *testing.T) {
 	}
 	actualResults := results.NewResultStream(mockOutStream)
 
-	builder := NewResultBuilder(slog.Default())
+	builder := NewResultBuilder(slog.Default(), nil, nil)
 	ranking := map[string]int{}
 	ranking[t.Name()] = 1
-	err := builder.BuildResults(inputEvents, athletes, actualResults, ranking)
+	err := builder.BuildResults(context.TODO(), inputEvents, athletes, actualResults, config.NewRankingPolicy(ranking))
 	assert.NoError(t, err)
 	assert.Equal(t, expectedResults, buildActualResults(mockOutStream))
 }
@@ -494,12 +485,6 @@ func TestResultBuilderPlaceSwap(t *testing.T) {
 			Place:       1,
 			PlaceSource: t.Name(),
 		},
-		{
-			Bib:         10,
-			Athlete:     athletes[10],
-			Place:       2,
-			PlaceSource: t.Name(),
-		},
 	}
 
 	mockOutStream := &stream.MockStream{
@@ -507,10 +492,10 @@ func TestResultBuilderPlaceSwap(t *testing.T) {
 	}
 	actualResults := results.NewResultStream(mockOutStream)
 
-	builder := NewResultBuilder(slog.Default())
+	builder := NewResultBuilder(slog.Default(), nil, nil)
 	ranking := map[string]int{}
 	ranking[t.Name()] = 1
-	err := builder.BuildResults(inputEvents, athletes, actualResults, ranking)
+	err := builder.BuildResults(context.TODO(), inputEvents, athletes, actualResults, config.NewRankingPolicy(ranking))
 	assert.NoError(t, err)
 	assert.Equal(t, expectedResults, buildActualResults(mockOutStream))
 }
@@ -543,10 +528,10 @@ func TestResultBuilderUnknownPlaceBib(t *testing.T) {
 	}
 	actualResults := results.NewResultStream(mockOutStream)
 
-	builder := NewResultBuilder(slog.Default())
+	builder := NewResultBuilder(slog.Default(), nil, nil)
 	ranking := map[string]int{}
 	ranking[t.Name()] = 1
-	err := builder.BuildResults(inputEvents, athletes, actualResults, ranking)
+	err := builder.BuildResults(context.TODO(), inputEvents, athletes, actualResults, config.NewRankingPolicy(ranking))
 	assert.NoError(t, err)
 	assert.Equal(t, expectedResults, buildActualResults(mockOutStream))
 }
@@ -590,10 +575,10 @@ func TestResultBuilderNoPlaceNoResult(t *testing.T) {
 	}
 	actualResults := results.NewResultStream(mockOutStream)
 
-	builder := NewResultBuilder(slog.Default())
+	builder := NewResultBuilder(slog.Default(), nil, nil)
 	ranking := map[string]int{}
 	ranking[t.Name()] = 1
-	err := builder.BuildResults(inputEvents, athletes, actualResults, ranking)
+	err := builder.BuildResults(context.TODO(), inputEvents, athletes, actualResults, config.NewRankingPolicy(ranking))
 	assert.NoError(t, err)
 	assert.Equal(t, expectedResults, buildActualResults(mockOutStream))
 }
@@ -715,7 +700,8 @@ func TestResultBuilder(t *testing.T) {
 			Bib:          12,
 			Athlete:      athletes[12],
 			Place:        1,
-			Time:         finishTime12.Sub(now),
+			GunTime:      finishTime12.Sub(now),
+			NetTime:      finishTime12.Sub(now),
 			FinishSource: t.Name(),
 			PlaceSource:  t.Name(),
 		},
@@ -723,7 +709,8 @@ func TestResultBuilder(t *testing.T) {
 			Bib:          10,
 			Athlete:      athletes[10],
 			Place:        2,
-			Time:         finishTime10.Sub(now),
+			GunTime:      finishTime10.Sub(now),
+			NetTime:      finishTime10.Sub(now),
 			FinishSource: t.Name(),
 			PlaceSource:  t.Name(),
 		},
@@ -731,7 +718,8 @@ func TestResultBuilder(t *testing.T) {
 			Bib:          11,
 			Athlete:      athletes[11],
 			Place:        3,
-			Time:         finishTime11.Sub(now),
+			GunTime:      finishTime11.Sub(now),
+			NetTime:      finishTime11.Sub(now),
 			FinishSource: t.Name(),
 			PlaceSource:  t.Name(),
 		},
@@ -739,7 +727,8 @@ func TestResultBuilder(t *testing.T) {
 			Bib:          13,
 			Athlete:      athletes[13],
 			Place:        4,
-			Time:         finishTime13.Sub(now),
+			GunTime:      finishTime13.Sub(now),
+			NetTime:      finishTime13.Sub(now),
 			FinishSource: t.Name(),
 			PlaceSource:  t.Name(),
 		},
@@ -747,7 +736,8 @@ func TestResultBuilder(t *testing.T) {
 			Bib:          14,
 			Athlete:      athletes[14],
 			Place:        5,
-			Time:         finishTime14.Sub(now),
+			GunTime:      finishTime14.Sub(now),
+			NetTime:      finishTime14.Sub(now),
 			FinishSource: t.Name(),
 			PlaceSource:  t.Name(),
 		},
@@ -758,10 +748,10 @@ func TestResultBuilder(t *testing.T) {
 	}
 	actualResults := results.NewResultStream(mockOutStream)
 
-	builder := NewResultBuilder(slog.Default())
+	builder := NewResultBuilder(slog.Default(), nil, nil)
 	ranking := map[string]int{}
 	ranking[t.Name()] = 1
-	err := builder.BuildResults(inputEvents, athletes, actualResults, ranking)
+	err := builder.BuildResults(context.TODO(), inputEvents, athletes, actualResults, config.NewRankingPolicy(ranking))
 	assert.NoError(t, err)
 	assert.Equal(t, expectedResults, buildActualResults(mockOutStream))
 }
@@ -826,14 +816,16 @@ func TestResultBuilderRankUpdates(t *testing.T) {
 			Athlete:      athletes[10],
 			Place:        1,
 			PlaceSource:  t.Name(),
-			Time:         time.Duration(0),
+			GunTime:      time.Duration(0),
+			NetTime:      time.Duration(0),
 			FinishSource: "",
 		},
 		{
 			Bib:          10,
 			Athlete:      athletes[10],
 			Place:        1,
-			Time:         finishTime10.Sub(now),
+			GunTime:      finishTime10.Sub(now),
+			NetTime:      finishTime10.Sub(now),
 			FinishSource: "worse",
 			PlaceSource:  t.Name(),
 		},
@@ -841,7 +833,8 @@ func TestResultBuilderRankUpdates(t *testing.T) {
 			Bib:          10,
 			Athlete:      athletes[10],
 			Place:        1,
-			Time:         finishTime10better.Sub(now),
+			GunTime:      finishTime10better.Sub(now),
+			NetTime:      finishTime10better.Sub(now),
 			FinishSource: "better",
 			PlaceSource:  t.Name(),
 		},
@@ -852,11 +845,11 @@ func TestResultBuilderRankUpdates(t *testing.T) {
 	}
 	actualResults := results.NewResultStream(mockOutStream)
 
-	builder := NewResultBuilder(slog.Default())
+	builder := NewResultBuilder(slog.Default(), nil, nil)
 	ranking := map[string]int{}
 	ranking["better"] = 1
 	ranking["worse"] = 2
-	err := builder.BuildResults(inputEvents, athletes, actualResults, ranking)
+	err := builder.BuildResults(context.TODO(), inputEvents, athletes, actualResults, config.NewRankingPolicy(ranking))
 	assert.NoError(t, err)
 	assert.Equal(t, expectedResults, buildActualResults(mockOutStream))
 }
@@ -920,14 +913,16 @@ func TestResultBuilderRankIgnores(t *testing.T) {
 			Athlete:      athletes[10],
 			Place:        1,
 			PlaceSource:  t.Name(),
-			Time:         time.Duration(0),
+			GunTime:      time.Duration(0),
+			NetTime:      time.Duration(0),
 			FinishSource: "",
 		},
 		{
 			Bib:          10,
 			Athlete:      athletes[10],
 			Place:        1,
-			Time:         finishTime10.Sub(now),
+			GunTime:      finishTime10.Sub(now),
+			NetTime:      finishTime10.Sub(now),
 			FinishSource: "better",
 			PlaceSource:  t.Name(),
 		},
@@ -938,11 +933,11 @@ func TestResultBuilderRankIgnores(t *testing.T) {
 	}
 	actualResults := results.NewResultStream(mockOutStream)
 
-	builder := NewResultBuilder(slog.Default())
+	builder := NewResultBuilder(slog.Default(), nil, nil)
 	ranking := map[string]int{}
 	ranking["better"] = 1
 	ranking["worse"] = 2
-	err := builder.BuildResults(inputEvents, athletes, actualResults, ranking)
+	err := builder.BuildResults(context.TODO(), inputEvents, athletes, actualResults, config.NewRankingPolicy(ranking))
 	assert.NoError(t, err)
 	assert.Equal(t, expectedResults, buildActualResults(mockOutStream))
 }
@@ -1003,7 +998,8 @@ func TestResultBuilderRankPlaceUpdate(t *testing.T) {
 			Bib:          10,
 			Athlete:      athletes[10],
 			Place:        2,
-			Time:         finishTime10.Sub(now),
+			GunTime:      finishTime10.Sub(now),
+			NetTime:      finishTime10.Sub(now),
 			FinishSource: "better",
 			PlaceSource:  "worsePlace",
 		},
@@ -1011,7 +1007,8 @@ func TestResultBuilderRankPlaceUpdate(t *testing.T) {
 			Bib:          10,
 			Athlete:      athletes[10],
 			Place:        1,
-			Time:         finishTime10.Sub(now),
+			GunTime:      finishTime10.Sub(now),
+			NetTime:      finishTime10.Sub(now),
 			FinishSource: "better",
 			PlaceSource:  "betterPlace",
 		},
@@ -1022,13 +1019,13 @@ func TestResultBuilderRankPlaceUpdate(t *testing.T) {
 	}
 	actualResults := results.NewResultStream(mockOutStream)
 
-	builder := NewResultBuilder(slog.Default())
+	builder := NewResultBuilder(slog.Default(), nil, nil)
 	ranking := map[string]int{}
 	ranking["better"] = 1
 	ranking["worse"] = 2
 	ranking["betterPlace"] = 1
 	ranking["worsePlace"] = 2
-	err := builder.BuildResults(inputEvents, athletes, actualResults, ranking)
+	err := builder.BuildResults(context.TODO(), inputEvents, athletes, actualResults, config.NewRankingPolicy(ranking))
 	assert.NoError(t, err)
 	assert.Equal(t, expectedResults, buildActualResults(mockOutStream))
 }
@@ -1091,7 +1088,8 @@ func TestResultBuilderRankPlaceIgnore(t *testing.T) {
 			Bib:          10,
 			Athlete:      athletes[10],
 			Place:        1,
-			Time:         finishTime10.Sub(now),
+			GunTime:      finishTime10.Sub(now),
+			NetTime:      finishTime10.Sub(now),
 			FinishSource: "better",
 			PlaceSource:  "betterPlace",
 		},
@@ -1102,13 +1100,13 @@ func TestResultBuilderRankPlaceIgnore(t *testing.T) {
 	}
 	actualResults := results.NewResultStream(mockOutStream)
 
-	builder := NewResultBuilder(slog.Default())
+	builder := NewResultBuilder(slog.Default(), nil, nil)
 	ranking := map[string]int{}
 	ranking["better"] = 1
 	ranking["worse"] = 2
 	ranking["betterPlace"] = 1
 	ranking["worsePlace"] = 2
-	err := builder.BuildResults(inputEvents, athletes, actualResults, ranking)
+	err := builder.BuildResults(context.TODO(), inputEvents, athletes, actualResults, config.NewRankingPolicy(ranking))
 	assert.NoError(t, err)
 	assert.Equal(t, expectedResults, buildActualResults(mockOutStream))
 }
@@ -1201,24 +1199,6 @@ func TestResultBuilderPlaceLastToFirst(t *testing.T) {
 			Place:       1,
 			PlaceSource: t.Name(),
 		},
-		{
-			Bib:         10,
-			Athlete:     athletes[10],
-			Place:       2,
-			PlaceSource: t.Name(),
-		},
-		{
-			Bib:         11,
-			Athlete:     athletes[11],
-			Place:       3,
-			PlaceSource: t.Name(),
-		},
-		{
-			Bib:         12,
-			Athlete:     athletes[12],
-			Place:       4,
-			PlaceSource: t.Name(),
-		},
 	}
 
 	mockOutStream := &stream.MockStream{
@@ -1226,10 +1206,10 @@ func TestResultBuilderPlaceLastToFirst(t *testing.T) {
 	}
 	actualResults := results.NewResultStream(mockOutStream)
 
-	builder := NewResultBuilder(slog.Default())
+	builder := NewResultBuilder(slog.Default(), nil, nil)
 	ranking := map[string]int{}
 	ranking[t.Name()] = 1
-	err := builder.BuildResults(inputEvents, athletes, actualResults, ranking)
+	err := builder.BuildResults(context.TODO(), inputEvents, athletes, actualResults, config.NewRankingPolicy(ranking))
 	assert.NoError(t, err)
 	assert.Equal(t, expectedResults, buildActualResults(mockOutStream))
 }
@@ -1284,12 +1264,6 @@ func TestResultBuilderPlaceSwapDemote(t *testing.T) {
 			Place:       2,
 			PlaceSource: t.Name(),
 		},
-		{
-			Bib:         11,
-			Athlete:     athletes[11],
-			Place:       1,
-			PlaceSource: t.Name(),
-		},
 		{
 			Bib:         10,
 			Athlete:     athletes[10],
@@ -1303,10 +1277,10 @@ func TestResultBuilderPlaceSwapDemote(t *testing.T) {
 	}
 	actualResults := results.NewResultStream(mockOutStream)
 
-	builder := NewResultBuilder(slog.Default())
+	builder := NewResultBuilder(slog.Default(), nil, nil)
 	ranking := map[string]int{}
 	ranking[t.Name()] = 1
-	err := builder.BuildResults(inputEvents, athletes, actualResults, ranking)
+	err := builder.BuildResults(context.TODO(), inputEvents, athletes, actualResults, config.NewRankingPolicy(ranking))
 	assert.NoError(t, err)
 	assert.Equal(t, expectedResults, buildActualResults(mockOutStream))
 }
@@ -1376,12 +1350,6 @@ func TestResultBuilderDemote(t *testing.T) {
 			Place:       3,
 			PlaceSource: t.Name(),
 		},
-		{
-			Bib:         11,
-			Athlete:     athletes[11],
-			Place:       1,
-			PlaceSource: t.Name(),
-		},
 		{
 			Bib:         10,
 			Athlete:     athletes[10],
@@ -1395,10 +1363,10 @@ func TestResultBuilderDemote(t *testing.T) {
 	}
 	actualResults := results.NewResultStream(mockOutStream)
 
-	builder := NewResultBuilder(slog.Default())
+	builder := NewResultBuilder(slog.Default(), nil, nil)
 	ranking := map[string]int{}
 	ranking[t.Name()] = 1
-	err := builder.BuildResults(inputEvents, athletes, actualResults, ranking)
+	err := builder.BuildResults(context.TODO(), inputEvents, athletes, actualResults, config.NewRankingPolicy(ranking))
 	assert.NoError(t, err)
 	assert.Equal(t, expectedResults, buildActualResults(mockOutStream))
 }
@@ -1485,24 +1453,6 @@ func TestResultBuilderPlaceFirstToLast(t *testing.T) {
 			Place:       4,
 			PlaceSource: t.Name(),
 		},
-		{
-			Bib:         11,
-			Athlete:     athletes[11],
-			Place:       1,
-			PlaceSource: t.Name(),
-		},
-		{
-			Bib:         12,
-			Athlete:     athletes[12],
-			Place:       2,
-			PlaceSource: t.Name(),
-		},
-		{
-			Bib:         13,
-			Athlete:     athletes[13],
-			Place:       3,
-			PlaceSource: t.Name(),
-		},
 		{
 			Bib:         10,
 			Athlete:     athletes[10],
@@ -1516,10 +1466,10 @@ func TestResultBuilderPlaceFirstToLast(t *testing.T) {
 	}
 	actualResults := results.NewResultStream(mockOutStream)
 
-	builder := NewResultBuilder(slog.Default())
+	builder := NewResultBuilder(slog.Default(), nil, nil)
 	ranking := map[string]int{}
 	ranking[t.Name()] = 1
-	err := builder.BuildResults(inputEvents, athletes, actualResults, ranking)
+	err := builder.BuildResults(context.TODO(), inputEvents, athletes, actualResults, config.NewRankingPolicy(ranking))
 	assert.NoError(t, err)
 	assert.Equal(t, expectedResults, buildActualResults(mockOutStream))
 }
@@ -1551,3 +1501,73 @@ func buildActualResults(rawOutput *stream.MockStream) []meets.RaceResult {
 
 	return result
 }
+
+func TestResultBuilderVoidedFinish(t *testing.T) {
+	now := time.Now().UTC()
+
+	testEvents := []raceevents.Event{
+		{ID: "1-0", Data: raceevents.StartEvent{Source: "manual", StartTime: now}},
+		{ID: "2-0", Data: raceevents.FinishEvent{Source: "manual", Bib: 10, FinishTime: now.Add(5 * time.Minute)}},
+		{ID: "3-0", Data: raceevents.PlaceEvent{Source: "manual", Bib: 10, Place: 1}},
+		{ID: "4-0", Data: raceevents.FinishEvent{Source: "chip", Bib: 10, FinishTime: now.Add(6 * time.Minute)}},
+		{ID: "5-0", Data: raceevents.VoidEvent{Source: "manual", EventID: "4-0", Reason: "bad read"}},
+	}
+	inputEvents := raceevents.NewEventStream(&stream.MockStream{Events: buildEventMessages(testEvents)})
+
+	athletes := make(meets.AthleteLookup)
+	athletes[10] = meets.NewAthlete("D", "R", "WPI", "DAID", 12, "m")
+
+	mockOutStream := &stream.MockStream{Events: make([]stream.Message, 0, 10)}
+	ranking := map[string]int{"chip": 1, "manual": 2}
+	err := NewResultBuilder(slog.Default(), nil, nil).BuildResults(context.TODO(), inputEvents, athletes, results.NewResultStream(mockOutStream), config.NewRankingPolicy(ranking))
+	assert.NoError(t, err)
+
+	actual := buildActualResults(mockOutStream)
+	assert.Equal(t, 3, len(actual))
+	assert.Equal(t, 6*time.Minute, actual[1].GunTime)
+	assert.Equal(t, "chip", actual[1].FinishSource)
+	// the void puts the manual finish back
+	assert.Equal(t, meets.RaceResult{
+		Bib:          10,
+		Athlete:      athletes[10],
+		Place:        1,
+		GunTime:      5 * time.Minute,
+		NetTime:      5 * time.Minute,
+		FinishSource: "manual",
+		PlaceSource:  "manual",
+	}, actual[2])
+}
+
+func TestResultBuilderWaveStarts(t *testing.T) {
+	now := time.Now().UTC()
+
+	testEvents := []raceevents.Event{
+		{ID: "1-0", Data: raceevents.StartEvent{Source: t.Name(), StartTime: now}},
+		{ID: "2-0", Data: raceevents.StartEvent{Source: t.Name(), StartTime: now.Add(5 * time.Minute), Wave: "jv"}},
+		{ID: "3-0", Data: raceevents.FinishEvent{Source: t.Name(), Bib: 10, FinishTime: now.Add(16 * time.Minute)}},
+		{ID: "4-0", Data: raceevents.FinishEvent{Source: t.Name(), Bib: 20, FinishTime: now.Add(17 * time.Minute)}},
+		{ID: "5-0", Data: raceevents.PlaceEvent{Source: t.Name(), Bib: 10, Place: 1}},
+		{ID: "6-0", Data: raceevents.PlaceEvent{Source: t.Name(), Bib: 20, Place: 2}},
+		// the gun's jv start is better, only the jv result changes
+		{ID: "7-0", Data: raceevents.StartEvent{Source: "gun", StartTime: now.Add(6 * time.Minute), Wave: "jv"}},
+	}
+	inputEvents := raceevents.NewEventStream(&stream.MockStream{Events: buildEventMessages(testEvents)})
+
+	athletes := make(meets.AthleteLookup)
+	athletes[10] = meets.NewAthlete("D", "R", "WPI", "DAID", 12, "m")
+	athletes[20] = meets.NewAthlete("E", "R", "WPI", "DAID2", 10, "m")
+	waves := meets.WaveLookup{20: "jv"}
+
+	mockOutStream := &stream.MockStream{Events: make([]stream.Message, 0, 10)}
+	err := NewResultBuilder(slog.Default(), waves, nil).BuildResults(context.TODO(), inputEvents, athletes, results.NewResultStream(mockOutStream), config.NewRankingPolicy(map[string]int{"gun": 1, t.Name(): 2}))
+	assert.NoError(t, err)
+
+	actual := buildActualResults(mockOutStream)
+	assert.Equal(t, 3, len(actual))
+	assert.Equal(t, 10, actual[0].Bib)
+	assert.Equal(t, 16*time.Minute, actual[0].GunTime)
+	assert.Equal(t, 20, actual[1].Bib)
+	assert.Equal(t, 12*time.Minute, actual[1].GunTime)
+	assert.Equal(t, 20, actual[2].Bib)
+	assert.Equal(t, 11*time.Minute, actual[2].GunTime)
+}
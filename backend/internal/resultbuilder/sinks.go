@@ -0,0 +1,142 @@
+package resultbuilder
+
+import (
+	"blreynolds4/event-race-timer/internal/meets"
+	"blreynolds4/event-race-timer/internal/results"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"sort"
+	"sync"
+)
+
+// resultStreamSink sends results to a result stream.  Readers of the stream only
+// want results they can publish, so a result is sent once it's complete and then
+// every time it changes, even if a change makes it incomplete again.  A cleared result
+// that was sent is sent again as a meets.RemovedResult.
+type resultStreamSink struct {
+	output results.ResultWriter
+	sent   map[int]bool
+}
+
+func NewResultStreamSink(output results.ResultWriter) meets.RaceResultWriter {
+	return &resultStreamSink{
+		output: output,
+		sent:   make(map[int]bool),
+	}
+}
+
+func (rss *resultStreamSink) SaveResult(rr *meets.RaceResult) (*meets.RaceResult, error) {
+	if !rr.IsComplete() && !rss.sent[rr.Bib] {
+		return rr, nil
+	}
+
+	rss.sent[rr.Bib] = true
+	// a saved result is always sent, stopping the builder doesn't cut it off
+	return rr, rss.output.SendResult(context.Background(), *rr)
+}
+
+func (rss *resultStreamSink) ClearResult(bib int) error {
+	if !rss.sent[bib] {
+		return nil
+	}
+	delete(rss.sent, bib)
+	return rss.output.SendResult(context.Background(), meets.RemovedResult(bib))
+}
+
+func (rss *resultStreamSink) Close() error {
+	return nil
+}
+
+// jsonFileSink keeps the latest result for each bib in a json file, in bib order
+type jsonFileSink struct {
+	mu      sync.Mutex
+	path    string
+	results map[int]meets.RaceResult
+}
+
+func NewJSONFileSink(path string) meets.RaceResultWriter {
+	return &jsonFileSink{
+		path:    path,
+		results: make(map[int]meets.RaceResult),
+	}
+}
+
+func (jfs *jsonFileSink) SaveResult(rr *meets.RaceResult) (*meets.RaceResult, error) {
+	jfs.mu.Lock()
+	defer jfs.mu.Unlock()
+
+	jfs.results[rr.Bib] = *rr
+	return rr, jfs.write()
+}
+
+func (jfs *jsonFileSink) ClearResult(bib int) error {
+	jfs.mu.Lock()
+	defer jfs.mu.Unlock()
+
+	delete(jfs.results, bib)
+	return jfs.write()
+}
+
+// write replaces the file with the results
+func (jfs *jsonFileSink) write() error {
+	ordered := make([]meets.RaceResult, 0, len(jfs.results))
+	for _, result := range jfs.results {
+		ordered = append(ordered, result)
+	}
+	sort.Slice(ordered, func(i, j int) bool {
+		return ordered[i].Bib < ordered[j].Bib
+	})
+
+	data, err := json.MarshalIndent(ordered, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// write the whole file then rename it so readers never see part of it
+	tempPath := jfs.path + ".tmp"
+	err = os.WriteFile(tempPath, data, 0644)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tempPath, jfs.path)
+}
+
+func (jfs *jsonFileSink) Close() error {
+	return nil
+}
+
+// multiSink saves each result to every sink
+type multiSink []meets.RaceResultWriter
+
+func NewMultiSink(sinks ...meets.RaceResultWriter) meets.RaceResultWriter {
+	return multiSink(sinks)
+}
+
+func (ms multiSink) SaveResult(rr *meets.RaceResult) (*meets.RaceResult, error) {
+	var errs []error
+	for _, sink := range ms {
+		_, err := sink.SaveResult(rr)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return rr, errors.Join(errs...)
+}
+
+func (ms multiSink) ClearResult(bib int) error {
+	var errs []error
+	for _, sink := range ms {
+		errs = append(errs, sink.ClearResult(bib))
+	}
+	return errors.Join(errs...)
+}
+
+func (ms multiSink) Close() error {
+	var errs []error
+	for _, sink := range ms {
+		errs = append(errs, sink.Close())
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,168 @@
+package resultbuilder
+
+import (
+	"blreynolds4/event-race-timer/internal/config"
+	"blreynolds4/event-race-timer/internal/meets"
+	"blreynolds4/event-race-timer/internal/raceevents"
+	"blreynolds4/event-race-timer/internal/results"
+	"blreynolds4/event-race-timer/internal/stream"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResultStreamSinkSendsOnceComplete(t *testing.T) {
+	mockOutStream := &stream.MockStream{Events: make([]stream.Message, 0, 10)}
+	sink := NewResultStreamSink(results.NewResultStream(mockOutStream))
+
+	athlete := meets.NewAthlete("D", "R", "WPI", "DAID", 12, "m")
+	finishOnly := &meets.RaceResult{Bib: 10, Athlete: athlete, GunTime: time.Minute, FinishSource: t.Name()}
+	_, err := sink.SaveResult(finishOnly)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(mockOutStream.Events))
+
+	placed := &meets.RaceResult{Bib: 10, Athlete: athlete, Place: 1, PlaceSource: t.Name(), GunTime: time.Minute}
+	_, err = sink.SaveResult(placed)
+	assert.NoError(t, err)
+
+	// once sent, changes are sent even if they aren't complete
+	unplaced := &meets.RaceResult{Bib: 10, Athlete: athlete, GunTime: time.Minute}
+	_, err = sink.SaveResult(unplaced)
+	assert.NoError(t, err)
+
+	actual := buildActualResults(mockOutStream)
+	assert.Equal(t, 2, len(actual))
+	assert.Equal(t, 1, actual[0].Place)
+	assert.Equal(t, 0, actual[1].Place)
+}
+
+func TestResultStreamSinkClearResult(t *testing.T) {
+	mockOutStream := &stream.MockStream{Events: make([]stream.Message, 0, 10)}
+	sink := NewResultStreamSink(results.NewResultStream(mockOutStream))
+
+	// nothing was sent for the bib so there's nothing to remove
+	assert.NoError(t, sink.ClearResult(10))
+	assert.Equal(t, 0, len(mockOutStream.Events))
+
+	athlete := meets.NewAthlete("D", "R", "WPI", "DAID", 12, "m")
+	_, err := sink.SaveResult(&meets.RaceResult{Bib: 10, Athlete: athlete, Place: 1, PlaceSource: t.Name(), GunTime: time.Minute})
+	assert.NoError(t, err)
+	assert.NoError(t, sink.ClearResult(10))
+
+	actual := buildActualResults(mockOutStream)
+	assert.Equal(t, 2, len(actual))
+	assert.True(t, actual[1].IsRemoved())
+	assert.Equal(t, 10, actual[1].Bib)
+}
+
+func TestJSONFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "results.json")
+	sink := NewJSONFileSink(path)
+
+	for _, rr := range []meets.RaceResult{
+		{Bib: 20, Place: 1, PlaceSource: t.Name()},
+		{Bib: 10, Place: 2, PlaceSource: t.Name()},
+		{Bib: 20, Place: 3, PlaceSource: t.Name()},
+	} {
+		_, err := sink.SaveResult(&rr)
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, sink.Close())
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	var saved []meets.RaceResult
+	assert.NoError(t, json.Unmarshal(data, &saved))
+
+	// the latest result for each bib, in bib order
+	assert.Equal(t, []meets.RaceResult{
+		{Bib: 10, Place: 2, PlaceSource: t.Name()},
+		{Bib: 20, Place: 3, PlaceSource: t.Name()},
+	}, saved)
+}
+
+type failingSink struct{}
+
+func (fs failingSink) SaveResult(rr *meets.RaceResult) (*meets.RaceResult, error) {
+	return rr, fmt.Errorf("save failed")
+}
+
+func (fs failingSink) ClearResult(bib int) error {
+	return fmt.Errorf("clear failed")
+}
+
+func (fs failingSink) Close() error {
+	return nil
+}
+
+func TestMultiSink(t *testing.T) {
+	first := meets.NewMockResultWriter()
+	second := meets.NewMockResultWriter()
+	sink := NewMultiSink(first, failingSink{}, second)
+
+	_, err := sink.SaveResult(&meets.RaceResult{Bib: 10, Place: 1})
+	assert.Error(t, err)
+
+	// a failing sink doesn't stop the others getting the result
+	assert.Equal(t, []meets.RaceResult{{Bib: 10, Place: 1}}, first.SavedResults)
+	assert.Equal(t, []meets.RaceResult{{Bib: 10, Place: 1}}, second.SavedResults)
+	assert.NoError(t, sink.Close())
+}
+
+func TestSinksGetTheSameResults(t *testing.T) {
+	now := time.Now().UTC()
+	testEvents := []raceevents.Event{
+		{ID: "1-0", Data: raceevents.StartEvent{Source: "gun", StartTime: now}},
+		// a worse start doesn't replace the gun for any sink
+		{ID: "2-0", Data: raceevents.StartEvent{Source: "manual", StartTime: now.Add(2 * time.Second)}},
+		{ID: "3-0", Data: raceevents.ChipStartEvent{Source: "mat", Bib: 11, StartTime: now.Add(3 * time.Second)}},
+		{ID: "4-0", Data: raceevents.FinishEvent{Source: "manual", Bib: 10, FinishTime: now.Add(5 * time.Minute)}},
+		{ID: "5-0", Data: raceevents.FinishEvent{Source: "manual", Bib: 11, FinishTime: now.Add(5*time.Minute + 10*time.Second)}},
+		{ID: "6-0", Data: raceevents.FinishEvent{Source: "manual", Bib: 12, FinishTime: now.Add(5*time.Minute + 20*time.Second)}},
+		{ID: "7-0", Data: raceevents.PlaceEvent{Source: "manual", Bib: 10, Place: 1}},
+		{ID: "8-0", Data: raceevents.PlaceEvent{Source: "manual", Bib: 11, Place: 2}},
+		{ID: "9-0", Data: raceevents.PlaceEvent{Source: "manual", Bib: 12, Place: 3}},
+		// 12 moves up, only 12 changes until the other places are sent
+		{ID: "10-0", Data: raceevents.PlaceEvent{Source: "manual", Bib: 12, Place: 1}},
+	}
+	athletes := make(meets.AthleteLookup)
+	for _, bib := range []int{10, 11, 12} {
+		athletes[bib] = meets.NewAthlete("D", "R", "WPI", fmt.Sprint("DAID", bib), 12, "m")
+	}
+	ranking := config.NewRankingPolicy(map[string]int{"gun": 1, "manual": 2, "mat": 3})
+
+	// the result stream's builder
+	streamOut := &stream.MockStream{Events: make([]stream.Message, 0, 10)}
+	err := NewResultBuilder(slog.Default(), nil, nil).BuildResults(context.TODO(),
+		raceevents.NewEventStream(&stream.MockStream{Events: buildEventMessages(testEvents)}), athletes, results.NewResultStream(streamOut), ranking)
+	assert.NoError(t, err)
+	streamed := make(map[int]meets.RaceResult)
+	for _, rr := range buildActualResults(streamOut) {
+		streamed[rr.Bib] = rr
+	}
+
+	// the race result builder saving to a file
+	path := filepath.Join(t.TempDir(), "results.json")
+	err = NewRaceResultBuilder(slog.Default(), nil, nil, nil).BuildRaceResults(context.TODO(),
+		raceevents.NewEventStream(&stream.MockStream{Events: buildEventMessages(testEvents)}), athletes, ranking, NewJSONFileSink(path))
+	assert.NoError(t, err)
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	var saved []meets.RaceResult
+	assert.NoError(t, json.Unmarshal(data, &saved))
+
+	assert.Equal(t, 3, len(saved))
+	for _, rr := range saved {
+		assert.Equal(t, rr, streamed[rr.Bib], "bib %d", rr.Bib)
+	}
+	assert.Equal(t, 5*time.Minute, saved[0].GunTime)
+	assert.Equal(t, 5*time.Minute+7*time.Second, saved[1].NetTime)
+	assert.Equal(t, []int{1, 2, 1}, []int{saved[0].Place, saved[1].Place, saved[2].Place})
+}
@@ -34,14 +34,14 @@ func TestIsCompleteFalse(t *testing.T) {
 				Grade:     1,
 				Gender:    "m",
 			},
-			Place: 1,
-			Time:  time.Second,
+			Place:   1,
+			GunTime: time.Second,
 		},
 		{
 			// Athlete is nil
-			Bib:   1,
-			Place: 1,
-			Time:  time.Second,
+			Bib:     1,
+			Place:   1,
+			GunTime: time.Second,
 		},
 		{
 			// place is 0
@@ -53,7 +53,7 @@ func TestIsCompleteFalse(t *testing.T) {
 				Grade:     1,
 				Gender:    "m",
 			},
-			Time: time.Second,
+			GunTime: time.Second,
 		},
 		{
 			// place source is ""
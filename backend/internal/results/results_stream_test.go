@@ -21,7 +21,7 @@ func TestSendResult(t *testing.T) {
 		Bib:     1,
 		Athlete: meets.NewAthlete(t.Name(), t.Name(), t.Name(), "DAID", 1, "m"),
 		Place:   1,
-		Time:    time.Second,
+		GunTime: time.Second,
 	}
 
 	err := evStream.SendResult(context.TODO(), rr)
@@ -48,7 +48,7 @@ func TestSendResultFails(t *testing.T) {
 		Bib:     1,
 		Athlete: meets.NewAthlete(t.Name(), t.Name(), t.Name(), "DAID", 1, "m"),
 		Place:   1,
-		Time:    time.Second,
+		GunTime: time.Second,
 	}
 
 	err := evStream.SendResult(context.TODO(), rr)
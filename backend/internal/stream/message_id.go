@@ -0,0 +1,128 @@
+package stream
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// range query markers, these match redis so callers can use
+	// RangeQueryMin/Max and ExclusiveQueryStart with any stream
+	rangeMin       = "-"
+	rangeMax       = "+"
+	exclusiveStart = "("
+)
+
+// MessageID is a redis style stream id: <milliseconds>-<sequence>.
+// Streams that aren't backed by redis use it so ids look and sort the same
+// no matter where a race was timed.
+type MessageID struct {
+	Ms  uint64
+	Seq uint64
+}
+
+func (id MessageID) String() string {
+	return fmt.Sprintf("%d-%d", id.Ms, id.Seq)
+}
+
+func (id MessageID) Less(other MessageID) bool {
+	if id.Ms == other.Ms {
+		return id.Seq < other.Seq
+	}
+	return id.Ms < other.Ms
+}
+
+func (id MessageID) IsZero() bool {
+	return id.Ms == 0 && id.Seq == 0
+}
+
+// NextMessageID returns an id for a new message that is always greater than last.
+// The time part comes from now unless the clock went backwards.
+func NextMessageID(last MessageID, now time.Time) MessageID {
+	ms := uint64(now.UnixMilli())
+	if ms > last.Ms {
+		return MessageID{Ms: ms}
+	}
+	return MessageID{Ms: last.Ms, Seq: last.Seq + 1}
+}
+
+// ParseMessageID parses a complete id or a time only id, ie "1526919030474-55" or "1526919030474".
+// A missing sequence is 0.
+func ParseMessageID(id string) (MessageID, error) {
+	msPart, seqPart, hasSeq := strings.Cut(id, "-")
+	ms, err := strconv.ParseUint(msPart, 10, 64)
+	if err != nil {
+		return MessageID{}, fmt.Errorf("invalid stream id %q", id)
+	}
+
+	var seq uint64
+	if hasSeq {
+		seq, err = strconv.ParseUint(seqPart, 10, 64)
+		if err != nil {
+			return MessageID{}, fmt.Errorf("invalid stream id %q", id)
+		}
+	}
+
+	return MessageID{Ms: ms, Seq: seq}, nil
+}
+
+// RangeBounds converts range query start and end ids into inclusive ids.
+// It understands the same syntax as redis XRANGE: "-", "+", "(" exclusive starts
+// and ids without a sequence.
+func RangeBounds(startId, endId string) (MessageID, MessageID, error) {
+	var start, end MessageID
+	var err error
+
+	switch {
+	case startId == rangeMin:
+		start = MessageID{}
+	case strings.HasPrefix(startId, exclusiveStart):
+		start, err = ParseMessageID(strings.TrimPrefix(startId, exclusiveStart))
+		if err != nil {
+			return start, end, err
+		}
+		if start.Seq == math.MaxUint64 {
+			start = MessageID{Ms: start.Ms + 1}
+		} else {
+			start.Seq++
+		}
+	default:
+		start, err = ParseMessageID(startId)
+		if err != nil {
+			return start, end, err
+		}
+	}
+
+	switch {
+	case endId == rangeMax:
+		end = MessageID{Ms: math.MaxUint64, Seq: math.MaxUint64}
+	default:
+		end, err = ParseMessageID(endId)
+		if err != nil {
+			return start, end, err
+		}
+		if !strings.Contains(endId, "-") {
+			// a time only end includes every sequence in that millisecond
+			end.Seq = math.MaxUint64
+		}
+	}
+
+	return start, end, nil
+}
+
+// RangeQueryMin, RangeQueryMax and ExclusiveQueryStart return the
+// range syntax shared by all the stream implementations.
+func RangeQueryMin() string {
+	return rangeMin
+}
+
+func RangeQueryMax() string {
+	return rangeMax
+}
+
+func ExclusiveQueryStart(id string) string {
+	return exclusiveStart + id
+}
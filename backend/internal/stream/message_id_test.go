@@ -0,0 +1,57 @@
+package stream
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNextMessageID(t *testing.T) {
+	now := time.UnixMilli(1000)
+
+	id := NextMessageID(MessageID{}, now)
+	assert.Equal(t, MessageID{Ms: 1000}, id)
+
+	// same millisecond bumps the sequence
+	id = NextMessageID(id, now)
+	assert.Equal(t, MessageID{Ms: 1000, Seq: 1}, id)
+
+	// clock going backwards still increases
+	id = NextMessageID(id, time.UnixMilli(10))
+	assert.Equal(t, MessageID{Ms: 1000, Seq: 2}, id)
+	assert.Equal(t, "1000-2", id.String())
+}
+
+func TestParseMessageID(t *testing.T) {
+	id, err := ParseMessageID("12-3")
+	assert.NoError(t, err)
+	assert.Equal(t, MessageID{Ms: 12, Seq: 3}, id)
+
+	id, err = ParseMessageID("12")
+	assert.NoError(t, err)
+	assert.Equal(t, MessageID{Ms: 12}, id)
+
+	for _, bad := range []string{"", "x", "1-x", "-1"} {
+		_, err = ParseMessageID(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestRangeBounds(t *testing.T) {
+	start, end, err := RangeBounds(RangeQueryMin(), RangeQueryMax())
+	assert.NoError(t, err)
+	assert.Equal(t, MessageID{}, start)
+	assert.Equal(t, MessageID{Ms: math.MaxUint64, Seq: math.MaxUint64}, end)
+
+	start, end, err = RangeBounds(ExclusiveQueryStart("5-1"), "7")
+	assert.NoError(t, err)
+	assert.Equal(t, MessageID{Ms: 5, Seq: 2}, start)
+	assert.Equal(t, MessageID{Ms: 7, Seq: math.MaxUint64}, end)
+
+	_, _, err = RangeBounds("bad", RangeQueryMax())
+	assert.Error(t, err)
+	_, _, err = RangeBounds(RangeQueryMin(), "bad")
+	assert.Error(t, err)
+}
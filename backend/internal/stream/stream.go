@@ -24,7 +24,37 @@ type Reader interface {
 	RangeQueryMax() string
 }
 
+// Seeker is a Reader whose GetMessage position can be moved.
+// After SeekAfter(id) GetMessage returns the first message with an id greater than id.
+type Seeker interface {
+	SeekAfter(id string) error
+}
+
 type ReaderWriter interface {
 	Reader
 	Writer
 }
+
+// GroupPosition is how far a consumer group has read a stream.
+// Every message up to LastDeliveredID has been handed to a consumer in the group,
+// the ones in Pending haven't been acknowledged yet.
+type GroupPosition struct {
+	LastDeliveredID string
+	Pending         map[string]bool
+}
+
+// GroupReader reads a stream as one consumer of a consumer group.
+// Messages from GetMessage stay pending until they are acknowledged, so a
+// consumer that restarts gets its unacknowledged messages again and another
+// consumer in the group can claim messages from one that stalled.
+type GroupReader interface {
+	Reader
+	AckMessage(ctx context.Context, id string) error
+	ClaimMessages(ctx context.Context, minIdle time.Duration, msgs []Message) (int, error)
+	GetGroupPosition(ctx context.Context) (GroupPosition, error)
+}
+
+type GroupReaderWriter interface {
+	GroupReader
+	Writer
+}
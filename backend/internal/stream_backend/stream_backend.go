@@ -0,0 +1,181 @@
+package stream_backend
+
+import (
+	"blreynolds4/event-race-timer/internal/config"
+	"blreynolds4/event-race-timer/internal/file_stream"
+	"blreynolds4/event-race-timer/internal/memory_stream"
+	"blreynolds4/event-race-timer/internal/redis_stream"
+	"blreynolds4/event-race-timer/internal/stream"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	redis "github.com/redis/go-redis/v9"
+)
+
+const (
+	RedisBackend  = "redis"
+	FileBackend   = "file"
+	MemoryBackend = "memory"
+
+	DefaultStreamDir = "race_streams"
+)
+
+// Options say where race streams live
+type Options struct {
+	Backend       string
+	RedisAddress  string
+	RedisDbNumber int
+	StreamDir     string
+}
+
+// OptionsFromConfig fills in any options not set on the command line from the race config
+func OptionsFromConfig(o Options, rc config.RaceConfig) Options {
+	if o.Backend == "" {
+		o.Backend = rc.StreamBackend
+	}
+	if o.StreamDir == "" {
+		o.StreamDir = rc.StreamDir
+	}
+	if o.RedisAddress == "" {
+		o.RedisAddress = rc.RedisAddress
+	}
+	if o.RedisDbNumber == 0 {
+		o.RedisDbNumber = rc.RedisDbNumber
+	}
+	return o
+}
+
+// ErrGroupsNotSupported is returned by backends that can't read a stream as a consumer group
+var ErrGroupsNotSupported = errors.New("consumer groups are only supported by the redis stream backend")
+
+// DefaultConsumerName names a group consumer after the host it runs on
+func DefaultConsumerName() string {
+	host, err := os.Hostname()
+	if err != nil {
+		return "consumer"
+	}
+	return host
+}
+
+// Backend creates the raw stream for a race name
+type Backend interface {
+	Stream(name string) (stream.ReaderWriter, error)
+	// GroupStream reads the race stream as consumer of group, claiming messages
+	// other consumers left idle for claimIdle (0 doesn't claim)
+	GroupStream(name, group, consumer string, claimIdle time.Duration) (stream.GroupReaderWriter, error)
+	Ping(ctx context.Context) (string, error)
+	io.Closer
+}
+
+func NewBackend(o Options) (Backend, error) {
+	switch o.Backend {
+	case "", RedisBackend:
+		return &redisBackend{
+			client: redis.NewClient(&redis.Options{
+				Addr:     o.RedisAddress,
+				Password: "", // no password set
+				DB:       o.RedisDbNumber,
+			}),
+		}, nil
+	case FileBackend:
+		dir := o.StreamDir
+		if dir == "" {
+			dir = DefaultStreamDir
+		}
+		return &fileBackend{dir: dir}, nil
+	case MemoryBackend:
+		return &memoryBackend{store: memory_stream.NewStore()}, nil
+	default:
+		return nil, fmt.Errorf("unknown stream backend %q", o.Backend)
+	}
+}
+
+type redisBackend struct {
+	client *redis.Client
+}
+
+func (rb *redisBackend) Stream(name string) (stream.ReaderWriter, error) {
+	return redis_stream.NewRedisStream(rb.client, name), nil
+}
+
+func (rb *redisBackend) GroupStream(name, group, consumer string, claimIdle time.Duration) (stream.GroupReaderWriter, error) {
+	return redis_stream.NewRedisGroupStream(rb.client, name, group, consumer, claimIdle), nil
+}
+
+func (rb *redisBackend) Ping(ctx context.Context) (string, error) {
+	cmdResult := rb.client.Ping(ctx)
+	return cmdResult.String(), cmdResult.Err()
+}
+
+func (rb *redisBackend) Close() error {
+	return rb.client.Close()
+}
+
+type fileBackend struct {
+	mu      sync.Mutex
+	dir     string
+	streams []*file_stream.FileStream
+}
+
+func (fb *fileBackend) Stream(name string) (stream.ReaderWriter, error) {
+	fs, err := file_stream.NewFileStream(fb.dir, name)
+	if err != nil {
+		return nil, err
+	}
+
+	fb.mu.Lock()
+	defer fb.mu.Unlock()
+	fb.streams = append(fb.streams, fs)
+	return fs, nil
+}
+
+func (fb *fileBackend) GroupStream(name, group, consumer string, claimIdle time.Duration) (stream.GroupReaderWriter, error) {
+	return nil, ErrGroupsNotSupported
+}
+
+func (fb *fileBackend) Ping(ctx context.Context) (string, error) {
+	_, err := os.Stat(fb.dir)
+	if err != nil {
+		return "", err
+	}
+	return "file streams in " + fb.dir, nil
+}
+
+func (fb *fileBackend) Close() error {
+	fb.mu.Lock()
+	defer fb.mu.Unlock()
+
+	var err error
+	for _, fs := range fb.streams {
+		if closeErr := fs.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	fb.streams = nil
+	return err
+}
+
+type memoryBackend struct {
+	store *memory_stream.Store
+}
+
+func (mb *memoryBackend) Stream(name string) (stream.ReaderWriter, error) {
+	return memory_stream.NewMemoryStream(mb.store, name), nil
+}
+
+func (mb *memoryBackend) GroupStream(name, group, consumer string, claimIdle time.Duration) (stream.GroupReaderWriter, error) {
+	return nil, ErrGroupsNotSupported
+}
+
+func (mb *memoryBackend) Ping(ctx context.Context) (string, error) {
+	return "memory streams", nil
+}
+
+func (mb *memoryBackend) Close() error {
+	return nil
+}
@@ -0,0 +1,77 @@
+package stream_backend
+
+import (
+	"blreynolds4/event-race-timer/internal/config"
+	"blreynolds4/event-race-timer/internal/stream"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOptionsFromConfig(t *testing.T) {
+	rc := config.RaceConfig{
+		RedisAddress:  "redis:6379",
+		RedisDbNumber: 2,
+		StreamBackend: FileBackend,
+		StreamDir:     "/tmp/race",
+	}
+
+	// nothing on the command line
+	o := OptionsFromConfig(Options{}, rc)
+	assert.Equal(t, Options{Backend: FileBackend, RedisAddress: "redis:6379", RedisDbNumber: 2, StreamDir: "/tmp/race"}, o)
+
+	// command line wins
+	o = OptionsFromConfig(Options{Backend: RedisBackend, RedisAddress: "localhost:6379"}, rc)
+	assert.Equal(t, RedisBackend, o.Backend)
+	assert.Equal(t, "localhost:6379", o.RedisAddress)
+}
+
+func TestUnknownBackend(t *testing.T) {
+	_, err := NewBackend(Options{Backend: "nope"})
+	assert.Error(t, err)
+}
+
+func TestFileAndMemoryBackendsShareStreams(t *testing.T) {
+	for _, o := range []Options{{Backend: FileBackend, StreamDir: t.TempDir()}, {Backend: MemoryBackend}} {
+		backend, err := NewBackend(o)
+		assert.NoError(t, err)
+
+		_, err = backend.Ping(context.TODO())
+		assert.NoError(t, err)
+
+		writer, err := backend.Stream(t.Name())
+		assert.NoError(t, err)
+		reader, err := backend.Stream(t.Name())
+		assert.NoError(t, err)
+
+		assert.NoError(t, writer.SendMessage(context.TODO(), stream.Message{Data: []byte("hello")}))
+
+		var msg stream.Message
+		gotMsg, err := reader.GetMessage(context.TODO(), -1, &msg)
+		assert.NoError(t, err)
+		assert.True(t, gotMsg, o.Backend)
+		assert.Equal(t, []byte("hello"), msg.Data)
+
+		assert.NoError(t, backend.Close())
+	}
+}
+
+func TestGroupStreamOnlyRedis(t *testing.T) {
+	for _, o := range []Options{{Backend: FileBackend, StreamDir: t.TempDir()}, {Backend: MemoryBackend}} {
+		backend, err := NewBackend(o)
+		assert.NoError(t, err)
+
+		_, err = backend.GroupStream(t.Name(), "group", "consumer", 0)
+		assert.ErrorIs(t, err, ErrGroupsNotSupported)
+	}
+
+	backend, err := NewBackend(Options{Backend: RedisBackend})
+	assert.NoError(t, err)
+	defer backend.Close()
+
+	// creating the stream doesn't talk to redis
+	_, err = backend.GroupStream(t.Name(), "group", "consumer", time.Minute)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,99 @@
+package supervisor
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// RunFunc runs a service until ctx is cancelled, it's given the shared logger
+// with the service's name on every line
+type RunFunc func(ctx context.Context, l *slog.Logger) error
+
+type service struct {
+	name string
+	run  RunFunc
+}
+
+// Supervisor runs services in their own goroutines and restarts the ones that fail.
+// A service fails when it returns an error or panics before it's stopped, it's
+// restarted after a delay that doubles with each failure in a row up to maxDelay.
+// A service that returns nil is finished and isn't restarted.
+type Supervisor struct {
+	logger   *slog.Logger
+	minDelay time.Duration
+	maxDelay time.Duration
+	services []service
+}
+
+func NewSupervisor(l *slog.Logger, minDelay, maxDelay time.Duration) *Supervisor {
+	return &Supervisor{
+		logger:   l,
+		minDelay: minDelay,
+		maxDelay: maxDelay,
+	}
+}
+
+// Add a service to run, services added after Run has started aren't run
+func (s *Supervisor) Add(name string, run RunFunc) {
+	s.services = append(s.services, service{name: name, run: run})
+}
+
+// Run starts every service and returns when ctx is cancelled and they've all stopped
+func (s *Supervisor) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, svc := range s.services {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.supervise(ctx, svc)
+		}()
+	}
+	wg.Wait()
+}
+
+func (s *Supervisor) supervise(ctx context.Context, svc service) {
+	logger := s.logger.With("service", svc.name)
+	delay := s.minDelay
+	for {
+		logger.Info("starting service")
+		started := time.Now()
+		err := runService(ctx, svc.run, logger)
+		if ctx.Err() != nil {
+			logger.Info("service stopped")
+			return
+		}
+		if err == nil {
+			logger.Info("service finished")
+			return
+		}
+
+		// a service that ran a while before failing starts over with a short delay
+		if time.Since(started) > s.maxDelay {
+			delay = s.minDelay
+		}
+		logger.Error("service failed, restarting", "error", err, "delay", delay)
+
+		select {
+		case <-ctx.Done():
+			logger.Info("service stopped")
+			return
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, s.maxDelay)
+	}
+}
+
+// runService runs a service turning a panic into an error
+func runService(ctx context.Context, run RunFunc, l *slog.Logger) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+			l.Error("service panicked", "panic", r, "stack", string(debug.Stack()))
+		}
+	}()
+	return run(ctx, l)
+}
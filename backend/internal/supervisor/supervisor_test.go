@@ -0,0 +1,102 @@
+package supervisor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func TestSupervisorRestartsFailedServices(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var failures, panics atomic.Int32
+	s := NewSupervisor(testLogger(), time.Millisecond, 4*time.Millisecond)
+	s.Add("fails", func(ctx context.Context, l *slog.Logger) error {
+		if failures.Add(1) < 3 {
+			return fmt.Errorf("failed")
+		}
+		<-ctx.Done()
+		return nil
+	})
+	s.Add("panics", func(ctx context.Context, l *slog.Logger) error {
+		if panics.Add(1) < 3 {
+			panic("oops")
+		}
+		<-ctx.Done()
+		return nil
+	})
+
+	done := make(chan struct{})
+	go func() {
+		s.Run(ctx)
+		close(done)
+	}()
+
+	assert.Eventually(t, func() bool {
+		return failures.Load() == 3 && panics.Load() == 3
+	}, time.Second, time.Millisecond)
+
+	// Run waits for every service to stop
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("supervisor didn't stop")
+	}
+	assert.Equal(t, int32(3), failures.Load())
+	assert.Equal(t, int32(3), panics.Load())
+}
+
+func TestSupervisorDoesntRestartFinishedServices(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var runs atomic.Int32
+	s := NewSupervisor(testLogger(), time.Millisecond, time.Millisecond)
+	s.Add("finishes", func(ctx context.Context, l *slog.Logger) error {
+		runs.Add(1)
+		return nil
+	})
+
+	// every service finished so Run returns without a cancel
+	s.Run(ctx)
+	assert.Equal(t, int32(1), runs.Load())
+}
+
+func TestSupervisorStopsDuringRestartDelay(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var runs atomic.Int32
+	s := NewSupervisor(testLogger(), time.Hour, time.Hour)
+	s.Add("fails", func(ctx context.Context, l *slog.Logger) error {
+		runs.Add(1)
+		return fmt.Errorf("failed")
+	})
+
+	done := make(chan struct{})
+	go func() {
+		s.Run(ctx)
+		close(done)
+	}()
+
+	// the service is waiting an hour to restart when it's stopped
+	assert.Eventually(t, func() bool { return runs.Load() == 1 }, time.Second, time.Millisecond)
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("supervisor didn't stop")
+	}
+	assert.Equal(t, int32(1), runs.Load())
+}
@@ -0,0 +1,43 @@
+package workouts
+
+import (
+	"blreynolds4/event-race-timer/internal/competitors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// WriteReport writes each athlete's reps with their lap and rest times
+func WriteReport(w io.Writer, session Session, athletes competitors.CompetitorLookup, results []AthleteReps) {
+	fmt.Fprintf(w, "%s %s %s\n\n", session.Name, session.Team, session.Date)
+
+	for _, athlete := range results {
+		name := "unknown"
+		if competitor, found := athletes[athlete.Bib]; found {
+			name = competitor.Name
+		}
+		fmt.Fprintf(w, "%d %s\n", athlete.Bib, name)
+		fmt.Fprintln(w, "Rep Interval         Time     Rest     Laps")
+		fmt.Fprintln(w, "=== ================ ======== ======== ====")
+		for _, rep := range athlete.Reps {
+			repTime := "running"
+			if rep.IsDone() {
+				repTime = FormatRepTime(rep.Time)
+			}
+
+			laps := make([]string, len(rep.Laps))
+			for i, lap := range rep.Laps {
+				laps[i] = FormatRepTime(lap)
+			}
+
+			fmt.Fprintf(w, "%-3d %-16s %-8s %-8s %s\n", rep.Number, rep.Interval, repTime, FormatRepTime(rep.Rest), strings.Join(laps, " "))
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+// FormatRepTime is a lap, rep or rest time as minutes, seconds and hundredths
+func FormatRepTime(t time.Duration) string {
+	return time.Unix(0, 0).UTC().Add(t).Format("04:05.00")
+}
@@ -0,0 +1,154 @@
+package workouts
+
+import (
+	"blreynolds4/event-race-timer/internal/raceevents"
+	"context"
+	"sort"
+	"time"
+)
+
+const eventBufferSize = 100
+
+// Rep is one run of an interval.  The first read starts the rep and each
+// read after it is a lap, the rep is done when all of the interval's laps are run.
+type Rep struct {
+	Interval string
+	Number   int
+	Start    time.Time
+	Laps     []time.Duration
+	// Time is 0 until the rep is done
+	Time time.Duration
+	// Rest is the time from the end of the previous rep to the start of this one
+	Rest time.Duration
+}
+
+func (r Rep) IsDone() bool {
+	return r.Time > 0
+}
+
+// AthleteReps is every rep a bib ran in the session, the last one may not be done
+type AthleteReps struct {
+	Bib  int
+	Reps []Rep
+}
+
+type athleteProgress struct {
+	// lastRead is the last read that was used, lastSeen is the last read of the reader's
+	// pass even when it was dropped
+	lastRead time.Time
+	lastSeen time.Time
+	reps     []Rep
+}
+
+// RepBuilder turns a session's workout reads into reps for each bib
+type RepBuilder struct {
+	session  Session
+	athletes map[int]*athleteProgress
+}
+
+func NewRepBuilder(session Session) *RepBuilder {
+	return &RepBuilder{
+		session:  session,
+		athletes: make(map[int]*athleteProgress),
+	}
+}
+
+// AddRead adds a bib's read, it returns false when the read was dropped as
+// another read of the same pass or arrived out of order.
+func (rb *RepBuilder) AddRead(we raceevents.WorkoutEvent) bool {
+	progress, found := rb.athletes[we.Bib]
+	if !found {
+		progress = &athleteProgress{}
+		rb.athletes[we.Bib] = progress
+	}
+
+	// a pass lasts as long as the reads keep coming, an athlete resting at the reader is one pass
+	if !progress.lastSeen.IsZero() && we.SplitTime.Before(progress.lastSeen.Add(rb.session.debounce())) {
+		if we.SplitTime.After(progress.lastSeen) {
+			progress.lastSeen = we.SplitTime
+		}
+		return false
+	}
+	progress.lastSeen = we.SplitTime
+
+	count := len(progress.reps)
+	if count == 0 || progress.reps[count-1].IsDone() {
+		// start the next rep
+		interval := rb.session.interval(count)
+		rep := Rep{
+			Interval: interval.Name,
+			Number:   count + 1,
+			Start:    we.SplitTime,
+			Laps:     make([]time.Duration, 0, interval.Laps),
+		}
+		if count > 0 {
+			rep.Rest = we.SplitTime.Sub(progress.lastRead)
+		}
+		progress.reps = append(progress.reps, rep)
+	} else {
+		// a lap of the rep in progress
+		rep := &progress.reps[count-1]
+		rep.Laps = append(rep.Laps, we.SplitTime.Sub(progress.lastRead))
+		if len(rep.Laps) == rb.session.interval(count-1).Laps {
+			rep.Time = we.SplitTime.Sub(rep.Start)
+		}
+	}
+
+	progress.lastRead = we.SplitTime
+	return true
+}
+
+// Results returns each bib's reps in bib order
+func (rb *RepBuilder) Results() []AthleteReps {
+	results := make([]AthleteReps, 0, len(rb.athletes))
+	for bib, progress := range rb.athletes {
+		reps := make([]Rep, len(progress.reps))
+		copy(reps, progress.reps)
+		results = append(results, AthleteReps{Bib: bib, Reps: reps})
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Bib < results[j].Bib
+	})
+	return results
+}
+
+// ReadReps builds the reps from every workout read in the session's stream.
+// Voided reads are left out.
+func ReadReps(ctx context.Context, events raceevents.EventStreamReader, session Session) ([]AthleteReps, error) {
+	reads := make([]raceevents.Event, 0)
+	voided := make(map[string]bool)
+
+	buffer := make([]raceevents.Event, eventBufferSize)
+	startId := events.RangeQueryMin()
+	count, err := events.GetRaceEventRange(ctx, startId, events.RangeQueryMax(), buffer)
+	if err != nil {
+		return nil, err
+	}
+
+	for count > 0 {
+		for _, event := range buffer[:count] {
+			switch data := event.Data.(type) {
+			case raceevents.WorkoutEvent:
+				reads = append(reads, event)
+			case raceevents.VoidEvent:
+				voided[data.EventID] = true
+			}
+		}
+
+		startId = events.ExclusiveQueryStart(buffer[count-1].ID)
+		count, err = events.GetRaceEventRange(ctx, startId, events.RangeQueryMax(), buffer)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	builder := NewRepBuilder(session)
+	for _, read := range reads {
+		if !voided[read.ID] {
+			builder.AddRead(read.Data.(raceevents.WorkoutEvent))
+		}
+	}
+
+	return builder.Results(), nil
+}
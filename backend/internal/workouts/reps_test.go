@@ -0,0 +1,122 @@
+package workouts
+
+import (
+	"blreynolds4/event-race-timer/internal/competitors"
+	"blreynolds4/event-race-timer/internal/raceevents"
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func read(bib int, start time.Time, seconds int) raceevents.WorkoutEvent {
+	return raceevents.WorkoutEvent{Source: "chip", Bib: bib, SplitTime: start.Add(time.Duration(seconds) * time.Second)}
+}
+
+func TestRepBuilder(t *testing.T) {
+	session := Session{
+		Name: "tuesday",
+		Intervals: []Interval{
+			{Name: "800m", Laps: 2, Reps: 2},
+			{Name: "400m", Laps: 1, Reps: 1},
+		},
+	}
+	start := time.Now()
+
+	builder := NewRepBuilder(session)
+	for _, tc := range []struct {
+		read     raceevents.WorkoutEvent
+		expected bool
+	}{
+		{read(1, start, 0), true},
+		{read(1, start, 2), false}, // same pass of the reader
+		{read(1, start, 70), true},
+		{read(1, start, 142), true},
+		{read(1, start, 262), true}, // 2 minute rest
+		{read(1, start, 332), true},
+		{read(1, start, 330), false}, // out of order
+		{read(1, start, 404), true},
+		{read(1, start, 464), true},
+		{read(1, start, 530), true},
+		{read(1, start, 600), true}, // another 400
+		{read(2, start, 1), true},
+	} {
+		assert.Equal(t, tc.expected, builder.AddRead(tc.read), "read %v", tc.read.SplitTime.Sub(start))
+	}
+
+	results := builder.Results()
+	assert.Equal(t, 2, len(results))
+	assert.Equal(t, []Rep{
+		{Interval: "800m", Number: 1, Start: start, Laps: []time.Duration{70 * time.Second, 72 * time.Second}, Time: 142 * time.Second},
+		{Interval: "800m", Number: 2, Start: start.Add(262 * time.Second), Laps: []time.Duration{70 * time.Second, 72 * time.Second}, Time: 142 * time.Second, Rest: 120 * time.Second},
+		{Interval: "400m", Number: 3, Start: start.Add(464 * time.Second), Laps: []time.Duration{66 * time.Second}, Time: 66 * time.Second, Rest: 60 * time.Second},
+		{Interval: "400m", Number: 4, Start: start.Add(600 * time.Second), Laps: []time.Duration{}, Rest: 70 * time.Second},
+	}, results[0].Reps)
+	assert.False(t, results[0].Reps[3].IsDone())
+
+	assert.Equal(t, 2, results[1].Bib)
+	assert.Equal(t, 1, len(results[1].Reps))
+}
+
+func TestRepBuilderRestingAtReader(t *testing.T) {
+	start := time.Now()
+
+	builder := NewRepBuilder(Session{Name: "tuesday"})
+	for _, tc := range []struct {
+		read     raceevents.WorkoutEvent
+		expected bool
+	}{
+		{read(1, start, 0), true},
+		{read(1, start, 60), true},
+		// resting next to the reader after the rep, every read is within the debounce of the one before
+		{read(1, start, 65), false},
+		{read(1, start, 72), false},
+		{read(1, start, 79), false},
+		{read(1, start, 86), false},
+		{read(1, start, 180), true},
+	} {
+		assert.Equal(t, tc.expected, builder.AddRead(tc.read), "read %v", tc.read.SplitTime.Sub(start))
+	}
+
+	assert.Equal(t, []Rep{
+		{Number: 1, Start: start, Laps: []time.Duration{60 * time.Second}, Time: 60 * time.Second},
+		{Number: 2, Start: start.Add(180 * time.Second), Laps: []time.Duration{}, Rest: 120 * time.Second},
+	}, builder.Results()[0].Reps)
+}
+
+func TestRepBuilderNoIntervals(t *testing.T) {
+	start := time.Now()
+	builder := NewRepBuilder(Session{Name: "laps", DebounceSeconds: 30})
+
+	assert.True(t, builder.AddRead(read(1, start, 0)))
+	assert.False(t, builder.AddRead(read(1, start, 20)))
+	assert.True(t, builder.AddRead(read(1, start, 90)))
+
+	results := builder.Results()
+	assert.Equal(t, []Rep{{Number: 1, Start: start, Laps: []time.Duration{90 * time.Second}, Time: 90 * time.Second}}, results[0].Reps)
+}
+
+func TestReadRepsSkipsVoidedReads(t *testing.T) {
+	start := time.Now()
+	events := &raceevents.MockEventStream{Events: []raceevents.Event{
+		{ID: "1", Data: read(1, start, 0)},
+		{ID: "2", Data: read(1, start, 15)}, // bad read
+		{ID: "3", Data: raceevents.StartEvent{Source: "cli", StartTime: start}},
+		{ID: "4", Data: read(1, start, 75)},
+		{ID: "5", Data: raceevents.VoidEvent{Source: "cli", EventID: "2"}},
+	}}
+
+	results, err := ReadReps(context.TODO(), events, Session{Name: "laps"})
+	assert.NoError(t, err)
+	assert.Equal(t, []AthleteReps{{Bib: 1, Reps: []Rep{
+		{Number: 1, Start: start, Laps: []time.Duration{75 * time.Second}, Time: 75 * time.Second},
+	}}}, results)
+
+	var report bytes.Buffer
+	athletes := competitors.CompetitorLookup{1: competitors.NewCompetitor("Dana R", "WPI", 20, 0)}
+	WriteReport(&report, Session{Name: "laps", Team: "WPI", Date: "2026-10-18"}, athletes, results)
+	assert.Contains(t, report.String(), "1 Dana R")
+	assert.Contains(t, report.String(), "01:15.00")
+}
@@ -0,0 +1,83 @@
+package workouts
+
+import (
+	"blreynolds4/event-race-timer/internal/config"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultDebounce is used when a session doesn't set one.  A reader sees a
+// chip many times as it passes, reads closer together than this to the read before
+// them are one pass.
+const DefaultDebounce = 10 * time.Second
+
+// Session is one practice: the team, the day and the intervals run in order.
+// Workout reads for the session are sent to a stream named after it.
+type Session struct {
+	Name      string
+	Team      string
+	Date      string // YYYY-MM-DD
+	Intervals []Interval
+	// DebounceSeconds overrides DefaultDebounce
+	DebounceSeconds int
+}
+
+// Interval is a set of reps that are all the same, ie 4 x 800m run as 2 laps past the reader
+type Interval struct {
+	Name string
+	Laps int
+	Reps int
+}
+
+func LoadSession(path string, session *Session) error {
+	err := config.LoadAnyConfigData[Session](path, session)
+	if err != nil {
+		return err
+	}
+
+	if strings.TrimSpace(session.Name) == "" {
+		return fmt.Errorf("workout session in %s has no name", path)
+	}
+	if session.Date != "" {
+		if _, err := time.Parse(time.DateOnly, session.Date); err != nil {
+			return fmt.Errorf("workout session date %q is not YYYY-MM-DD", session.Date)
+		}
+	}
+	for _, interval := range session.Intervals {
+		if interval.Laps < 0 || interval.Reps < 0 {
+			return fmt.Errorf("workout interval %q can't have negative laps or reps", interval.Name)
+		}
+	}
+
+	return nil
+}
+
+func (s Session) debounce() time.Duration {
+	if s.DebounceSeconds > 0 {
+		return time.Duration(s.DebounceSeconds) * time.Second
+	}
+	return DefaultDebounce
+}
+
+// interval returns the interval for a rep (0 based), reps past the end of the
+// session are more of the last interval.  Without intervals every rep is one lap.
+func (s Session) interval(rep int) Interval {
+	if len(s.Intervals) == 0 {
+		return Interval{Laps: 1}
+	}
+
+	for _, interval := range s.Intervals {
+		reps := max(interval.Reps, 1)
+		if rep < reps {
+			return interval.withLaps()
+		}
+		rep -= reps
+	}
+	return s.Intervals[len(s.Intervals)-1].withLaps()
+}
+
+func (i Interval) withLaps() Interval {
+	i.Laps = max(i.Laps, 1)
+	return i
+}
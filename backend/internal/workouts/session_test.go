@@ -0,0 +1,39 @@
+package workouts
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeSession(t *testing.T, data string) string {
+	path := filepath.Join(t.TempDir(), "session.json")
+	assert.NoError(t, os.WriteFile(path, []byte(data), 0644))
+	return path
+}
+
+func TestLoadSession(t *testing.T) {
+	var session Session
+	err := LoadSession(writeSession(t, `{"Name": "tuesday", "Team": "WPI", "Date": "2026-10-18", "Intervals": [{"Name": "800m", "Laps": 2, "Reps": 4}]}`), &session)
+	assert.NoError(t, err)
+	assert.Equal(t, Session{Name: "tuesday", Team: "WPI", Date: "2026-10-18", Intervals: []Interval{{Name: "800m", Laps: 2, Reps: 4}}}, session)
+	assert.Equal(t, DefaultDebounce, session.debounce())
+
+	err = LoadSession(writeSession(t, `{"Team": "WPI"}`), &Session{})
+	assert.Error(t, err)
+
+	err = LoadSession(writeSession(t, `{"Name": "tuesday", "Date": "10/18/2026"}`), &Session{})
+	assert.Error(t, err)
+}
+
+func TestSessionInterval(t *testing.T) {
+	session := Session{Intervals: []Interval{{Name: "800m", Laps: 2, Reps: 2}, {Name: "mile"}}}
+
+	assert.Equal(t, "800m", session.interval(1).Name)
+	assert.Equal(t, Interval{Name: "mile", Laps: 1}, session.interval(2))
+	// extra reps are more of the last interval
+	assert.Equal(t, "mile", session.interval(5).Name)
+	assert.Equal(t, Interval{Laps: 1}, Session{}.interval(0))
+}
@@ -51,8 +51,12 @@ func (xcs *XCScorer) ScoreResults(ctx context.Context, source results.ResultStre
 	dedupedResults := make(map[int]meets.RaceResult)
 	for resultCount > 0 {
 		for i := 0; i < resultCount; i++ {
+			if resultBuffer[i].IsRemoved() {
+				delete(dedupedResults, resultBuffer[i].Bib)
+				continue
+			}
 			dedupedResults[resultBuffer[i].Bib] = resultBuffer[i]
-			xcs.logger.Debug("xc scorer adding result", "team", resultBuffer[i].Athlete.Team, "bib", resultBuffer[i].Bib, "time", resultBuffer[i].Time, "place", resultBuffer[i].Place)
+			xcs.logger.Debug("xc scorer adding result", "team", resultBuffer[i].Athlete.Team, "bib", resultBuffer[i].Bib, "time", resultBuffer[i].GunTime, "place", resultBuffer[i].Place)
 		}
 
 		resultCount, err = source.GetResults(ctx, resultBuffer)
@@ -171,7 +175,7 @@ func (xcs *XCScorer) ScoreResults(ctx context.Context, source results.ResultStre
 func getTeamAverage(t *XCTeamResult) time.Duration {
 	total := time.Duration(0)
 	for i := 0; i < 5; i++ {
-		total += t.Finishers[i].Result.Time
+		total += t.Finishers[i].Result.GunTime
 	}
 
 	return total / 5
@@ -180,7 +184,7 @@ func getTeamAverage(t *XCTeamResult) time.Duration {
 func getTeamTime(t *XCTeamResult) time.Duration {
 	total := time.Duration(0)
 	for i := 0; i < 5; i++ {
-		total += t.Finishers[i].Result.Time
+		total += t.Finishers[i].Result.GunTime
 	}
 
 	return total
@@ -20,11 +20,14 @@ type XCTeamScorer struct {
 	Race    *meets.Race
 	logger  *slog.Logger
 	Results []*XCTeamResult
+	// runners with a DNF, DNS or DQ, they don't score for their team
+	Unplaced []meets.RaceResult
+	// Quiet logs the team scores instead of clearing stdout and printing them, for
+	// scoring in a process that has other output like racetimer's cli
+	Quiet bool
 }
 
 func (xcs *XCTeamScorer) ScoreResults(resultsReader meets.RaceResultReader) error {
-	teams := make(map[string]*XCTeamResult)
-
 	// results are returned in place order
 	raceResults, err := resultsReader.GetRaceResults()
 	if err != nil {
@@ -32,9 +35,64 @@ func (xcs *XCTeamScorer) ScoreResults(resultsReader meets.RaceResultReader) erro
 		return err
 	}
 
+	var sorted, dnf []*XCTeamResult
+	sorted, dnf, xcs.Unplaced = ScoreTeams(raceResults)
+	xcs.Results = sorted
+
+	if xcs.Quiet {
+		for i, teamResult := range sorted {
+			xcs.logger.Debug("xc team score", "place", i+1, "team", teamResult.Name, "score", teamResult.TeamScore)
+		}
+		xcs.logger.Info("scored xc teams", "teams", len(sorted), "incomplete", len(dnf), "unplaced", len(xcs.Unplaced))
+		return nil
+	}
+
+	fmt.Printf("%s", "\x1Bc") // clear stdout
+	fmt.Printf("Last Updated: %s\n", time.Now().Format("2006-01-02 15:04:05"))
+	fmt.Printf("\n\n\n")
+	fmt.Println("Plc Team                             Score     1    2    3    4    5    6*   7*   8*   9*")
+	fmt.Println("=== ================================ =====   ==============================================")
+	for i, teamResult := range sorted {
+		fmt.Printf("%-3d %-32s %-5d   ", i+1, teamResult.Name, teamResult.TeamScore)
+		for f := 0; f < len(teamResult.Finishers); f++ {
+			fmt.Printf("%4d ", teamResult.Finishers[f].Score)
+		}
+		fmt.Printf("\n")
+		fmt.Printf("     Total Time: %s\n", time.Unix(0, 0).UTC().Add(teamResult.TotalTime).Format("15:04:05.00"))
+		fmt.Printf("        Average: %s\n", time.Unix(0, 0).UTC().Add(teamResult.Top5Avg).Format("04:05.00"))
+	}
+
+	for _, dnfTeam := range dnf {
+		fmt.Printf("%d/5 %-32s\n", len(dnfTeam.Finishers), dnfTeam.Name)
+	}
+
+	if len(xcs.Unplaced) > 0 {
+		fmt.Printf("\n")
+		fmt.Println("Bib   Name                             Team                             Status")
+		fmt.Println("===== ================================ ================================ ======")
+		for _, r := range xcs.Unplaced {
+			fmt.Printf("%-5d %-32s %-32s %-6s\n", r.Bib, r.Athlete.Name(), r.Athlete.Team, r.Status)
+		}
+	}
+
+	return nil
+}
+
+// ScoreTeams scores the teams in a race from its results in place order.  Teams with 5 or
+// more finishers are scored in team order, teams with fewer are incomplete in order of their
+// finisher count, and runners with a DNF, DNS or DQ are unplaced and don't score.
+func ScoreTeams(raceResults []*meets.RaceResult) (scored []*XCTeamResult, incomplete []*XCTeamResult, unplaced []meets.RaceResult) {
+	teams := make(map[string]*XCTeamResult)
+
 	// pass one through the results is to create an XC result for each result
 	xcResults := make([]*XCResult, 0, len(raceResults))
+	unplaced = make([]meets.RaceResult, 0)
 	for _, result := range raceResults {
+		if result.HasStatus() {
+			unplaced = append(unplaced, *result)
+			continue
+		}
+
 		xcr := new(XCResult)
 		// this should copy the pointer contents into the Result
 		xcr.Result = *result
@@ -42,7 +100,7 @@ func (xcs *XCTeamScorer) ScoreResults(resultsReader meets.RaceResultReader) erro
 	}
 
 	// group results by team
-	for i := 0; i < len(raceResults); i++ {
+	for i := 0; i < len(xcResults); i++ {
 		var teamResult *XCTeamResult
 		teamResult, exists := teams[xcResults[i].Result.Athlete.Team]
 		if !exists {
@@ -73,7 +131,6 @@ func (xcs *XCTeamScorer) ScoreResults(resultsReader meets.RaceResultReader) erro
 	}
 
 	// pass two is to sort the teams by score
-	xcs.Results = make([]*XCTeamResult, 0)
 	sorted := make([]*XCTeamResult, 0, len(teams))
 	dnf := make([]*XCTeamResult, 0)
 	for _, xcteam := range teams {
@@ -112,27 +169,9 @@ func (xcs *XCTeamScorer) ScoreResults(resultsReader meets.RaceResultReader) erro
 		return len(dnf[i].Finishers) > len(dnf[j].Finishers)
 	})
 
-	fmt.Printf("%s", "\x1Bc") // clear stdout
-	fmt.Printf("Last Updated: %s\n", time.Now().Format("2006-01-02 15:04:05"))
-	fmt.Printf("\n\n\n")
-	fmt.Println("Plc Team                             Score     1    2    3    4    5    6*   7*   8*   9*")
-	fmt.Println("=== ================================ =====   ==============================================")
-	for i, teamResult := range sorted {
+	for _, teamResult := range sorted {
 		teamResult.TotalTime = getTeamTime(teamResult)
 		teamResult.Top5Avg = getTeamAverage(teamResult)
-		xcs.Results = append(xcs.Results, teamResult)
-		fmt.Printf("%-3d %-32s %-5d   ", i+1, teamResult.Name, teamResult.TeamScore)
-		for f := 0; f < len(teamResult.Finishers); f++ {
-			fmt.Printf("%4d ", teamResult.Finishers[f].Score)
-		}
-		fmt.Printf("\n")
-		fmt.Printf("     Total Time: %s\n", time.Unix(0, 0).UTC().Add(teamResult.TotalTime).Format("15:04:05.00"))
-		fmt.Printf("        Average: %s\n", time.Unix(0, 0).UTC().Add(teamResult.Top5Avg).Format("04:05.00"))
 	}
-
-	for _, dnfTeam := range dnf {
-		fmt.Printf("%d/5 %-32s\n", len(dnfTeam.Finishers), dnfTeam.Name)
-	}
-
-	return nil
+	return sorted, dnf, unplaced
 }
@@ -0,0 +1,70 @@
+package xc
+
+import (
+	"blreynolds4/event-race-timer/internal/meets"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTeamScoringSkipsRunnersWithStatus(t *testing.T) {
+	results := make([]*meets.RaceResult, 0)
+	place := 1
+	addResult := func(team string, status string) {
+		r := &meets.RaceResult{
+			Bib:     len(results) + 1,
+			Athlete: meets.NewAthlete("F", "L", team, "DAID", 12, "m"),
+			GunTime: time.Duration(15+len(results)) * time.Minute,
+			Status:  status,
+		}
+		if status == "" {
+			r.Place = place
+			place++
+		}
+		results = append(results, r)
+	}
+
+	// team A has a DQ runner so only 5 score, team B has 5
+	for i := 0; i < 5; i++ {
+		addResult("A", "")
+		addResult("B", "")
+	}
+	addResult("A", "DQ")
+	addResult("B", "DNF")
+
+	scorer := NewXCTeamScorer(&meets.Race{Name: t.Name()}, slog.Default())
+	err := scorer.ScoreResults(&meets.MockResultReader{Results: results})
+	assert.NoError(t, err)
+
+	assert.Equal(t, 2, len(scorer.Results))
+	assert.Equal(t, "A", scorer.Results[0].Name)
+	assert.Equal(t, int16(1+3+5+7+9), scorer.Results[0].TeamScore)
+	assert.Equal(t, 5, len(scorer.Results[0].Finishers))
+	assert.Equal(t, int16(2+4+6+8+10), scorer.Results[1].TeamScore)
+
+	assert.Equal(t, 2, len(scorer.Unplaced))
+	assert.Equal(t, "DQ", scorer.Unplaced[0].Status)
+	assert.Equal(t, "DNF", scorer.Unplaced[1].Status)
+}
+
+func TestTeamScoringQuiet(t *testing.T) {
+	results := make([]*meets.RaceResult, 0)
+	for i := 0; i < 5; i++ {
+		results = append(results, &meets.RaceResult{
+			Bib:     i + 1,
+			Athlete: meets.NewAthlete("F", "L", "A", "DAID", 12, "m"),
+			Place:   i + 1,
+			GunTime: time.Duration(15+i) * time.Minute,
+		})
+	}
+
+	// quiet scores the same, the scores are logged
+	scorer := NewXCTeamScorer(&meets.Race{Name: t.Name()}, slog.New(slog.DiscardHandler))
+	scorer.Quiet = true
+	err := scorer.ScoreResults(&meets.MockResultReader{Results: results})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(scorer.Results))
+	assert.Equal(t, int16(1+2+3+4+5), scorer.Results[0].TeamScore)
+}
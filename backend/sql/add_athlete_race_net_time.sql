@@ -0,0 +1,2 @@
+-- Add the chip (net) time to an existing athlete_race table, finish_time is the gun time
+alter table athlete_race add column net_time integer DEFAULT null;
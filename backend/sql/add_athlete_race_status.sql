@@ -0,0 +1,2 @@
+-- Add the DNF/DNS/DQ status to an existing athlete_race table
+alter table athlete_race add column status varchar(3) NOT NULL DEFAULT '';
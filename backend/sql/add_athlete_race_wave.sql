@@ -0,0 +1,2 @@
+-- Add the start wave to an existing athlete_race table
+alter table athlete_race add column wave varchar(50) NOT NULL DEFAULT '';
@@ -14,10 +14,13 @@ create table athlete_race (
   race_id INTEGER NOT NULL,
   bib integer NOT NULL,
   finish_time integer DEFAULT null,
+  net_time integer DEFAULT null,
   place integer DEFAULT null,
   xc_place integer DEFAULT null,
   finish_source varchar(50) DEFAULT null,
   place_source varchar(50) DEFAULT null,
+  status varchar(3) NOT NULL DEFAULT '',
+  wave varchar(50) NOT NULL DEFAULT '',
   FOREIGN KEY (athlete_id) REFERENCES athlete(id),
   FOREIGN KEY (race_id) REFERENCES race(id)
 );
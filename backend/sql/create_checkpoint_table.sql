@@ -0,0 +1,8 @@
+-- Create the result_checkpoint table, where the result builder got to in each race's event stream
+create table result_checkpoint (
+  race_id INTEGER PRIMARY KEY,
+  last_event_id varchar(50) NOT NULL,
+  state jsonb NOT NULL,
+  updated_at timestamp NOT NULL DEFAULT now(),
+  FOREIGN KEY (race_id) REFERENCES race(id)
+);
@@ -0,0 +1,13 @@
+-- Create the orphan_read table, reads of bibs that weren't in any race being timed in the meet
+create table orphan_read (
+  id SERIAL PRIMARY KEY,
+  meet_id INTEGER NOT NULL,
+  bib integer NOT NULL,
+  source varchar(50) NOT NULL,
+  host varchar(255) NOT NULL,
+  antenna integer NOT NULL,
+  capture_mode varchar(50) NOT NULL,
+  timing_point varchar(50) NOT NULL,
+  read_time timestamp NOT NULL,
+  FOREIGN KEY (meet_id) REFERENCES meet(id)
+);
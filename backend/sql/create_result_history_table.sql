@@ -0,0 +1,9 @@
+-- Create the result_history table, the events the result builder rebuilds a race's results from
+-- when one is voided.  A row is added with each checkpoint so checkpoints don't grow with the race.
+create table result_history (
+  id BIGSERIAL PRIMARY KEY,
+  race_id INTEGER NOT NULL,
+  event jsonb NOT NULL,
+  FOREIGN KEY (race_id) REFERENCES race(id)
+);
+create index result_history_race on result_history (race_id, id);
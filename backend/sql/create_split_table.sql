@@ -0,0 +1,12 @@
+-- Create split table, the time each athlete passed each timing point on the course
+create table split (
+  race_id INTEGER NOT NULL,
+  athlete_id INTEGER NOT NULL,
+  bib integer NOT NULL,
+  timing_point varchar(50) NOT NULL,
+  split_time integer NOT NULL,
+  FOREIGN KEY (athlete_id) REFERENCES athlete(id),
+  FOREIGN KEY (race_id) REFERENCES race(id)
+);
+-- one split per athlete per timing point
+create unique index idx_split on split(race_id, athlete_id, timing_point);